@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/lifecycle"
+	"hiyab-tutor/internal/logging"
 	"hiyab-tutor/internal/server"
 
 	"github.com/joho/godotenv"
 )
 
-func gracefulShutdown(apiServer *http.Server, done chan bool) {
+func gracefulShutdown(lc *lifecycle.Manager, done chan bool) {
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -22,18 +26,15 @@ func gracefulShutdown(apiServer *http.Server, done chan bool) {
 	// Listen for the interrupt signal.
 	<-ctx.Done()
 
-	log.Println("shutting down gracefully, press Ctrl+C again to force")
+	slog.Info("shutting down gracefully, press Ctrl+C again to force")
 	stop() // Allow Ctrl+C to force shutdown
 
-	// The context is used to inform the server it has 5 seconds to finish
-	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if err := apiServer.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
+	// Each phase is bounded by SHUTDOWN_DRAIN_TIMEOUT_SECONDS
+	if err := lc.Shutdown(context.Background()); err != nil {
+		slog.Error("shutdown incomplete", "error", err)
 	}
 
-	log.Println("Server exiting")
+	slog.Info("server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
 	done <- true
@@ -57,19 +58,25 @@ func main() {
 	if err := godotenv.Load(".env"); err != nil {
 		log.Fatalf("error loading .env file %v", err)
 	}
-	server := server.NewServer()
+	// Report every missing or invalid setting before touching the database
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logging.New(cfg, os.Stdout))
+	server, lc := server.NewServer(cfg)
 	// Create a done channel to signal when the shutdown is complete
 	done := make(chan bool, 1)
 	// Run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(lc, done)
 
-	log.Printf("Starting server on %s", server.Addr)
-	err := server.ListenAndServe()
+	slog.Info("starting server", "addr", server.Addr)
+	err = server.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		panic(fmt.Sprintf("http server error: %s", err))
 	}
 
 	// Wait for the graceful shutdown to complete
 	<-done
-	log.Println("Graceful shutdown complete.")
+	slog.Info("graceful shutdown complete")
 }
@@ -0,0 +1,127 @@
+// Command backfill-phones rewrites the phone numbers of existing bookings and
+// tutors to E.164, the form new records are stored in. Numbers that can't be
+// parsed are left alone and listed so they can be fixed by hand, and numbers
+// shared by several rows are reported as possible duplicates.
+//
+//	go run ./cmd/backfill-phones -dry-run
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/phone"
+
+	"gorm.io/gorm"
+)
+
+const batchSize = 500
+
+// report collects what happened to one table.
+type report struct {
+	table     string
+	updated   int
+	unchanged int
+	invalid   []string
+	byNumber  map[string][]uint
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	db := database.New(cfg).Gorm()
+	for _, backfill := range []func(*gorm.DB, bool) (*report, error){backfillBookings, backfillTutors} {
+		r, err := backfill(db, *dryRun)
+		if err != nil {
+			log.Fatalf("backfill failed: %v", err)
+		}
+		r.print(*dryRun)
+	}
+}
+
+func backfillBookings(db *gorm.DB, dryRun bool) (*report, error) {
+	r := newReport("bookings")
+	var batch []domain.Booking
+	err := db.Model(&domain.Booking{}).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		for _, b := range batch {
+			if err := r.fix(db, &domain.Booking{}, b.ID, b.PhoneNumber, dryRun); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	return r, err
+}
+
+func backfillTutors(db *gorm.DB, dryRun bool) (*report, error) {
+	r := newReport("tutors")
+	var batch []domain.Tutor
+	err := db.Model(&domain.Tutor{}).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		for _, t := range batch {
+			if err := r.fix(db, &domain.Tutor{}, t.ID, t.PhoneNumber, dryRun); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	return r, err
+}
+
+func newReport(table string) *report {
+	return &report{table: table, byNumber: map[string][]uint{}}
+}
+
+// fix normalizes one row's number, writing it back unless dryRun is set.
+func (r *report) fix(db *gorm.DB, model any, id uint, number string, dryRun bool) error {
+	if number == "" {
+		r.unchanged++
+		return nil
+	}
+	normalized, err := phone.Normalize(number)
+	if err != nil {
+		r.invalid = append(r.invalid, fmt.Sprintf("#%d %q", id, number))
+		return nil
+	}
+	r.byNumber[normalized] = append(r.byNumber[normalized], id)
+	if normalized == number {
+		r.unchanged++
+		return nil
+	}
+	r.updated++
+	if dryRun {
+		return nil
+	}
+	// UpdateColumn keeps updated_at as it was; only the format changed
+	return db.Model(model).Where("id = ?", id).UpdateColumn("phone_number", normalized).Error
+}
+
+func (r *report) print(dryRun bool) {
+	verb := "updated"
+	if dryRun {
+		verb = "would update"
+	}
+	log.Printf("%s: %s %d, unchanged %d, invalid %d", r.table, verb, r.updated, r.unchanged, len(r.invalid))
+	for _, row := range r.invalid {
+		log.Printf("  invalid %s", row)
+	}
+	numbers := make([]string, 0, len(r.byNumber))
+	for number, ids := range r.byNumber {
+		if len(ids) > 1 {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Strings(numbers)
+	for _, number := range numbers {
+		log.Printf("  duplicate %s: ids %v", number, r.byNumber[number])
+	}
+}
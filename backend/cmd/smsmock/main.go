@@ -0,0 +1,24 @@
+// Command smsmock runs a fake SMS gateway for local development. Point
+// SMS_GATEWAY_URL at http://localhost:9090/sms and add "sms" to
+// NOTIFY_CHANNELS to see the messages the API would send.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"hiyab-tutor/internal/notify"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	apiKey := flag.String("api-key", "", "API key the client must send; empty accepts any")
+	failRate := flag.Float64("fail-rate", 0, "share of messages to reject with 503, between 0 and 1")
+	flag.Parse()
+
+	log.Printf("Mock SMS gateway listening on %s", *addr)
+	if err := http.ListenAndServe(*addr, notify.NewMockSMSGateway(*apiKey, *failRate)); err != nil {
+		log.Fatal(err)
+	}
+}
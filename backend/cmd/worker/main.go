@@ -0,0 +1,57 @@
+// Command worker runs background jobs outside the API process. Run as
+// many as needed next to the API with JOB_WORKERS=0 set on the API; the
+// jobs table hands every job to exactly one of them.
+//
+//	go run ./cmd/worker -workers 8
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/lifecycle"
+	"hiyab-tutor/internal/logging"
+)
+
+func main() {
+	workers := flag.Int("workers", 4, "number of jobs to run at once")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logging.New(cfg, os.Stdout))
+	if *workers < 1 {
+		log.Fatal("-workers must be at least 1")
+	}
+
+	db := database.New(cfg)
+	a, err := app.New(cfg, db.Gorm())
+	if err != nil {
+		log.Fatal(err)
+	}
+	a.Lifecycle.OnShutdown(lifecycle.PhaseClose, "database", func(context.Context) error {
+		return db.Close()
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	a.StartWorkers(*workers)
+	slog.Info("worker started", "workers", *workers)
+	<-ctx.Done()
+	stop() // Allow Ctrl+C to force shutdown
+
+	if err := a.Lifecycle.Shutdown(context.Background()); err != nil {
+		slog.Error("shutdown incomplete", "error", err)
+		os.Exit(1)
+	}
+}
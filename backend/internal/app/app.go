@@ -0,0 +1,379 @@
+// Package app is the composition root. It builds the repositories,
+// usecases and services once from the configuration, so route registration
+// only wires handlers and tests can hand it fakes instead.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/captcha"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/health"
+	"hiyab-tutor/internal/jobs"
+	"hiyab-tutor/internal/lifecycle"
+	"hiyab-tutor/internal/maintenance"
+	"hiyab-tutor/internal/metrics"
+	"hiyab-tutor/internal/notify"
+	"hiyab-tutor/internal/ratelimit"
+	"hiyab-tutor/internal/repository"
+	"hiyab-tutor/internal/scheduler"
+	"hiyab-tutor/internal/server/middlewares"
+	"hiyab-tutor/internal/storage"
+	"hiyab-tutor/internal/tracing"
+	"hiyab-tutor/internal/usecases"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// smsRetryInterval is how often the outbox looks for messages to retry
+	smsRetryInterval = 30 * time.Second
+	// schedulerLockKey is the advisory lock the scheduler leader holds
+	schedulerLockKey = 0x68697961627363 // "hiyabsc"
+	// migrateLockKey is the advisory lock held while migrating, so
+	// instances starting together take turns
+	migrateLockKey = 0x68697961626d67 // "hiyabmg"
+	// orphanGrace keeps fresh uploads whose record isn't saved yet
+	orphanGrace = 24 * time.Hour
+)
+
+// analyticsTables are the tables the dashboard totals count
+var analyticsTables = map[string]bool{
+	"bookings": true, "tutors": true, "partners": true, "testimonials": true, "other_services": true,
+}
+
+// App holds everything the HTTP layer needs. Fields left nil are simply
+// not available, which is enough for tests that only touch some routes.
+type App struct {
+	Config *config.Config
+	DB     *gorm.DB
+	// Logger is the base for the request loggers
+	Logger *slog.Logger
+	// Metrics is nil when no metrics should be recorded
+	Metrics *metrics.Metrics
+	// Tracing records nothing unless TRACING_EXPORTER is set
+	Tracing tracing.Provider
+	// Liveness backs /livez and Readiness /readyz; nil checkers are
+	// always up
+	Liveness  *health.Checker
+	Readiness *health.Checker
+	// Lifecycle runs the shutdown hooks; the server adds its own to the
+	// ones New registers
+	Lifecycle *lifecycle.Manager
+
+	Tokens  *auth.Tokens
+	Limits  *middlewares.RateLimits
+	Captcha captcha.Verifier
+	Files   domain.FileStorage
+	Events  domain.EventPublisher
+	// Queue takes background jobs; Jobs is its admin view
+	Queue domain.JobEnqueuer
+
+	Admins        domain.AdminUsecase
+	Analytics     domain.AnalyticsUsecase
+	Audit         domain.AuditUsecase
+	Bookings      domain.BookingUsecase
+	Tutors        domain.TutorUsecase
+	Partners      domain.PartnerUsecase
+	Testimonials  domain.TestimonialUsecase
+	OtherServices domain.OtherServiceUsecase
+	SMS           domain.SMSUsecase
+	Exports       domain.ExportUsecase
+	Imports       domain.ImportUsecase
+	Jobs          domain.JobUsecase
+	Scheduler     domain.SchedulerUsecase
+
+	queue       *jobs.Queue
+	scheduler   *scheduler.Scheduler
+	uploads     *storage.Draining
+	dispatcher  *notify.Dispatcher
+	outbox      *notify.SMSOutbox
+	stopRetries context.CancelFunc
+}
+
+// New migrates the database and builds the application from c.
+func New(c *config.Config, db *gorm.DB) (*App, error) {
+	// Checked here rather than by config, which can't see the policy, so a
+	// weak seed password stops the start instead of the seeding
+	if err := auth.NewPasswordPolicy(c).Validate(c.AdminPassword); err != nil {
+		return nil, fmt.Errorf("ADMIN_PASSWORD: %w", err)
+	}
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("migrating: %w", err)
+	}
+	a := &App{
+		Config:  c,
+		DB:      db,
+		Logger:  slog.Default(),
+		Metrics: metrics.New(),
+		Tokens:  auth.NewTokensFromConfig(c),
+	}
+	a.uploads = storage.NewDraining(storage.NewLocal(c.UploadDir))
+	a.Files = a.Metrics.CountUploads(a.uploads)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if a.Tracing, err = tracing.New(context.Background(), c, os.Stdout); err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+	if err := tracing.InstrumentGORM(db, a.Tracing); err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
+	}
+	if err := a.Metrics.RegisterDB("primary", sqlDB); err != nil {
+		return nil, fmt.Errorf("metrics: %w", err)
+	}
+	a.Liveness = health.NewChecker()
+	a.Readiness = health.NewChecker(
+		health.Database(db),
+		health.Migrations(db, models()...),
+		health.Writable("storage", c.UploadDir),
+		health.DiskSpace(c.UploadDir, uint64(c.HealthMinFreeDiskMB)<<20),
+	)
+	if a.Limits, err = newRateLimits(c); err != nil {
+		return nil, fmt.Errorf("rate limits: %w", err)
+	}
+	if a.Captcha, err = captcha.NewVerifier(c); err != nil {
+		return nil, fmt.Errorf("captcha: %w", err)
+	}
+
+	a.outbox = notify.NewSMSOutboxFromConfig(c, repository.NewSMSRepository(db))
+	if a.dispatcher, err = notify.NewDispatcherFromConfig(c, a.outbox); err != nil {
+		return nil, fmt.Errorf("notifications: %w", err)
+	}
+	a.Events = a.Metrics.CountEvents(a.dispatcher)
+	a.SMS = tracing.SMS(a.outbox, a.Tracing)
+
+	a.queue = jobs.NewQueueFromConfig(c, repository.NewJobRepository(db))
+	a.registerJobs()
+	a.Queue = a.queue
+	a.Jobs = tracing.Jobs(a.queue, a.Tracing)
+
+	resetNotifier, err := notify.NewChannel(c.PasswordResetChannel, c)
+	if err != nil {
+		return nil, fmt.Errorf("password reset channel: %w", err)
+	}
+	a.Admins = tracing.Admins(usecases.NewAdminUsecase(db, repository.NewAdminRepository(db), repository.NewPasswordResetRepository(db), auth.NewPasswordPolicy(c), usecases.PasswordResetSettings{
+		Jobs:     a.Queue,
+		Notifier: resetNotifier,
+		TokenTTL: time.Duration(c.PasswordResetTTLMinutes) * time.Minute,
+		URL:      resetPasswordURL(c.WebAppUrl),
+	}), a.Tracing)
+	bookingRepo := repository.NewBookingRepository(db)
+	tutorRepo := repository.NewTutorRepository(db)
+	a.Analytics = tracing.Analytics(usecases.NewAnalyticsUsecase(repository.NewAnalyticsRepository(db), time.Duration(c.AnalyticsSnapshotTTLSeconds)*time.Second), a.Tracing)
+	if err := database.OnWrite(db, "analytics", func(table string) {
+		if analyticsTables[table] {
+			a.Analytics.Invalidate()
+		}
+	}); err != nil {
+		return nil, fmt.Errorf("analytics: %w", err)
+	}
+	a.Audit = tracing.Audit(usecases.NewAuditUsecase(repository.NewAuditRepository(db)), a.Tracing)
+	a.Bookings = tracing.Bookings(usecases.NewBookingUsecase(bookingRepo, tutorRepo, a.Events), a.Tracing)
+	a.Tutors = tracing.Tutors(usecases.NewTutorUsecase(tutorRepo, a.Events), a.Tracing)
+	a.Partners = tracing.Partners(usecases.NewPartnerUsecase(repository.NewPartnerRepository(db)), a.Tracing)
+	a.Testimonials = tracing.Testimonials(usecases.NewTestimonialService(repository.NewTestimonialRepository(db)), a.Tracing)
+	a.OtherServices = tracing.OtherServices(usecases.NewOtherServiceService(repository.NewServiceRepository(db)), a.Tracing)
+	a.Exports = tracing.Exports(usecases.NewExportUsecase(repository.NewExportRepository(db), bookingRepo, tutorRepo, a.Queue, usecases.ExportSettings{
+		Dir:       c.ExportDir,
+		SyncLimit: c.ExportSyncLimit,
+		Retention: time.Duration(c.ExportRetentionHours) * time.Hour,
+	}), a.Tracing)
+	a.Imports = tracing.Imports(usecases.NewImportUsecase(bookingRepo, tutorRepo), a.Tracing)
+
+	a.scheduler = scheduler.New(repository.NewScheduledTaskRepository(db), database.NewAdvisoryLock(db, schedulerLockKey))
+	if err := a.registerTasks(); err != nil {
+		return nil, fmt.Errorf("scheduler: %w", err)
+	}
+	a.Scheduler = tracing.Scheduler(a.scheduler, a.Tracing)
+
+	a.Lifecycle = lifecycle.New(time.Duration(c.ShutdownDrainTimeoutSeconds)*time.Second, a.Logger)
+	a.registerShutdown()
+	return a, nil
+}
+
+// Migrate creates or updates the tables and adds the search columns, one
+// instance at a time.
+func Migrate(db *gorm.DB) error {
+	ctx := context.Background()
+	lock := database.NewAdvisoryLock(db, migrateLockKey)
+	if err := lock.Acquire(ctx); err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+	if err := db.AutoMigrate(models()...); err != nil {
+		return err
+	}
+	repository.MigrateSearch(ctx, db)
+	return nil
+}
+
+// models lists every table the application reads; Migrate creates them
+// and readiness checks them.
+func models() []any {
+	return []any{
+		&domain.Admin{}, &domain.PasswordHistory{}, &domain.PasswordResetToken{},
+		&domain.Partner{},
+		&domain.Testimonial{}, &domain.TestimonialTranslation{},
+		&domain.OtherService{}, &domain.OtherServiceTranslation{},
+		&domain.Booking{}, &domain.Tutor{}, &domain.AuditLog{}, &domain.SMSMessage{},
+		&domain.Job{}, &domain.ScheduledTask{}, &domain.Export{},
+	}
+}
+
+// SeedSuperAdmin creates the super admin from ADMIN_USERNAME and
+// ADMIN_PASSWORD unless it already exists. A super admin seeded before
+// passwords had to be changed, whose password is still ADMIN_PASSWORD, is
+// flagged to change it too.
+func (a *App) SeedSuperAdmin(ctx context.Context) error {
+	existing, err := a.Admins.GetByUsername(ctx, a.Config.AdminUsername)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil {
+		if existing.MustChangePassword {
+			return nil
+		}
+		if _, err := a.Admins.Login(ctx, existing.Username, a.Config.AdminPassword); errors.Is(err, domain.ErrInvalidCredentials) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		existing.MustChangePassword = true
+		_, err = repository.NewAdminRepository(a.DB).Update(ctx, existing)
+		return err
+	}
+	_, err = a.Admins.Create(ctx, &domain.Admin{
+		Username: a.Config.AdminUsername,
+		Password: a.Config.AdminPassword,
+		Role:     "superadmin",
+		Name:     "Super Admin",
+		// The seeded password lives in the environment, so replace it
+		// on first login
+		MustChangePassword: true,
+	})
+	return err
+}
+
+// Start runs the background work: retrying failed text messages and, unless
+// JOB_WORKERS is 0, the job workers.
+func (a *App) Start() {
+	if a.outbox != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		a.stopRetries = cancel
+		go a.outbox.Run(ctx, smsRetryInterval)
+	}
+	if a.queue != nil && a.Config.JobWorkers > 0 {
+		a.StartWorkers(a.Config.JobWorkers)
+	}
+	if a.scheduler != nil && a.Config.SchedulerEnabled {
+		a.scheduler.Start()
+	}
+}
+
+// StartWorkers runs n job workers until shutdown.
+func (a *App) StartWorkers(n int) {
+	a.queue.Start(n)
+}
+
+// registerTasks sets up the maintenance tasks from their schedules.
+func (a *App) registerTasks() error {
+	c := a.Config
+	days := func(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
+	for _, task := range []scheduler.Task{
+		{Name: "orphan_files", Schedule: c.CronOrphanFiles,
+			Run: maintenance.OrphanFiles(a.DB, c.UploadDir, a.queue, orphanGrace)},
+		{Name: "unassigned_bookings", Schedule: c.CronUnassignedReminder,
+			Run: maintenance.UnassignedBookings(a.DB, a.Events, time.Duration(c.UnassignedReminderHours)*time.Hour)},
+		{Name: "expiring_documents", Schedule: c.CronDocumentExpiry,
+			Run: maintenance.ExpiringDocuments(a.DB, a.Events, days(c.DocumentExpiryNoticeDays))},
+		{Name: "trash_purge", Schedule: c.CronTrashPurge,
+			Run: maintenance.PurgeTrash(a.DB, days(c.TrashRetentionDays),
+				&domain.Booking{}, &domain.Tutor{}, &domain.Partner{}, &domain.Testimonial{}, &domain.OtherService{})},
+		{Name: "expired_exports", Schedule: c.CronExportCleanup,
+			Run: maintenance.ExpiredExports(a.DB, c.ExportDir, time.Duration(c.ExportRetentionHours)*time.Hour)},
+	} {
+		if err := a.scheduler.Add(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerJobs sets up the handlers for every job type.
+func (a *App) registerJobs() {
+	jobs.Handle(a.queue, domain.JobRemoveFile, func(ctx context.Context, p domain.RemoveFilePayload) error {
+		return a.Files.Remove(p.Path)
+	})
+	jobs.Handle(a.queue, domain.JobGenerateExport, func(ctx context.Context, p domain.GenerateExportPayload) error {
+		return a.Exports.Generate(ctx, p.ExportID)
+	})
+	jobs.Handle(a.queue, domain.JobSendPasswordReset, func(ctx context.Context, p domain.SendPasswordResetPayload) error {
+		return a.Admins.SendPasswordReset(ctx, p.Username)
+	})
+}
+
+// registerShutdown takes the instance out of rotation, waits for uploads,
+// stops the background work, delivers the notifications still queued and
+// flushes the buffered spans.
+func (a *App) registerShutdown() {
+	a.Lifecycle.OnShutdown(lifecycle.PhaseStopTraffic, "readiness", func(context.Context) error {
+		a.Readiness.Register(health.Check{Name: "shutdown", Critical: true, Run: func(context.Context) (map[string]any, error) {
+			return nil, errors.New("shutting down")
+		}})
+		return nil
+	})
+	a.Lifecycle.OnShutdown(lifecycle.PhaseDrain, "uploads", a.uploads.Drain)
+	a.Lifecycle.OnShutdown(lifecycle.PhaseDrain, "scheduler", a.scheduler.Stop)
+	a.Lifecycle.OnShutdown(lifecycle.PhaseFlush, "sms retries", func(context.Context) error {
+		if a.stopRetries != nil {
+			a.stopRetries()
+		}
+		return nil
+	})
+	// Jobs still running publish events, so the dispatcher closes after them
+	a.Lifecycle.OnShutdown(lifecycle.PhaseFlush, "jobs", a.queue.Stop)
+	a.Lifecycle.OnShutdown(lifecycle.PhaseFlush, "notifications", a.dispatcher.Close)
+	a.Lifecycle.OnShutdown(lifecycle.PhaseClose, "tracing", a.Tracing.Shutdown)
+}
+
+// newRateLimits reads the per route group policies.
+func newRateLimits(c *config.Config) (*middlewares.RateLimits, error) {
+	store, err := ratelimit.NewStore(c)
+	if err != nil {
+		return nil, err
+	}
+	limits := &middlewares.RateLimits{Store: store}
+	for _, p := range []struct {
+		policy *ratelimit.Policy
+		name   string
+		spec   string
+	}{
+		{&limits.PublicRead, "public_read", c.RateLimitPublicRead},
+		{&limits.PublicWrite, "public_write", c.RateLimitPublicWrite},
+		{&limits.Admin, "admin", c.RateLimitAdmin},
+	} {
+		if *p.policy, err = ratelimit.ParsePolicy(p.name, p.spec); err != nil {
+			return nil, err
+		}
+	}
+	limits.Submissions = ratelimit.Policy{Name: "submissions", Limit: c.SpamMaxSubmissionsPerHour, Period: time.Hour}
+	return limits, nil
+}
+
+// resetPasswordURL is the dashboard page that accepts reset tokens.
+func resetPasswordURL(webAppURL string) string {
+	if webAppURL == "" {
+		return ""
+	}
+	return strings.TrimRight(webAppURL, "/") + "/reset-password"
+}
@@ -8,17 +8,14 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-const (
-	RefreshTokenDuration = time.Hour * 24 * 3 // 3 days
-	AccessTokenDuration  = time.Hour * 24     // 1 day
-)
-
 type UserClaims struct {
 	jwt.RegisteredClaims
 	UserID    uint   `json:"user_id"`
 	Username  string `json:"username"`
 	Role      string `json:"role"`
 	TokenType string `json:"token_type"`
+	// MustChangePassword restricts the token to the change-password flow.
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 }
 
 const (
@@ -26,46 +23,60 @@ const (
 	TokenTypeAccess  = "access"
 )
 
-func GenerateToken(user *domain.Admin, tokenType string) (string, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return "", err
-	}
+// Tokens issues and checks the admin session tokens.
+type Tokens struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokens(secret string, accessTTL, refreshTTL time.Duration) *Tokens {
+	return &Tokens{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// NewTokensFromConfig uses JWT_SECRET and the configured token lifetimes.
+func NewTokensFromConfig(c *config.Config) *Tokens {
+	return NewTokens(c.JwtSecret,
+		time.Duration(c.AccessTokenTTLMinutes)*time.Minute,
+		time.Duration(c.RefreshTokenTTLMinutes)*time.Minute)
+}
+
+func (t *Tokens) Generate(user *domain.Admin, tokenType string) (string, error) {
 	claims := UserClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenDuration)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(t.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
-		UserID:    user.ID,
-		Username:  user.Username,
-		Role:      user.Role,
-		TokenType: tokenType,
+		UserID:             user.ID,
+		Username:           user.Username,
+		Role:               user.Role,
+		TokenType:          tokenType,
+		MustChangePassword: user.MustChangePassword,
 	}
 	if tokenType == TokenTypeRefresh {
-		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(RefreshTokenDuration))
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(t.refreshTTL))
 	} else if tokenType != TokenTypeAccess {
 		return "", domain.ErrInvalidTokenType
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(cfg.JwtSecret))
+	tokenString, err := token.SignedString(t.secret)
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
 
-func ValidateToken(tokenString, tokenType string) (*UserClaims, error) {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return nil, err
-	}
+func (t *Tokens) Validate(tokenString, tokenType string) (*UserClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(cfg.JwtSecret), nil
+		return t.secret, nil
 	})
+	if err != nil || !token.Valid {
+		return nil, domain.ErrTokenInvalid
+	}
 	userClaims, ok := token.Claims.(*UserClaims)
-	if err != nil || !token.Valid || !ok {
+	if !ok {
 		return nil, domain.ErrTokenInvalid
 	}
 	if userClaims.TokenType != tokenType {
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokens_RoundTrip(t *testing.T) {
+	tokens := NewTokens("0123456789abcdef0123456789abcdef", time.Hour, 3*time.Hour)
+	admin := &domain.Admin{Username: "abebe", Role: "admin"}
+	admin.ID = 7
+
+	access, err := tokens.Generate(admin, TokenTypeAccess)
+	require.NoError(t, err)
+	claims, err := tokens.Validate(access, TokenTypeAccess)
+	require.NoError(t, err)
+	require.Equal(t, uint(7), claims.UserID)
+	require.Equal(t, "admin", claims.Role)
+	require.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	refresh, err := tokens.Generate(admin, TokenTypeRefresh)
+	require.NoError(t, err)
+	claims, err = tokens.Validate(refresh, TokenTypeRefresh)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(3*time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	// A refresh token is not an access token
+	_, err = tokens.Validate(refresh, TokenTypeAccess)
+	require.ErrorIs(t, err, domain.ErrInvalidTokenType)
+
+	_, err = tokens.Generate(admin, "other")
+	require.ErrorIs(t, err, domain.ErrInvalidTokenType)
+}
+
+func TestTokens_Rejects(t *testing.T) {
+	admin := &domain.Admin{Username: "abebe", Role: "admin"}
+	tokens := NewTokens("0123456789abcdef0123456789abcdef", time.Hour, time.Hour)
+	other := NewTokens("fedcba9876543210fedcba9876543210", time.Hour, time.Hour)
+
+	signed, err := other.Generate(admin, TokenTypeAccess)
+	require.NoError(t, err)
+	_, err = tokens.Validate(signed, TokenTypeAccess)
+	require.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	expired, err := NewTokens("0123456789abcdef0123456789abcdef", -time.Minute, time.Hour).Generate(admin, TokenTypeAccess)
+	require.NoError(t, err)
+	_, err = tokens.Validate(expired, TokenTypeAccess)
+	require.Error(t, err)
+
+	_, err = tokens.Validate("not a token", TokenTypeAccess)
+	require.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
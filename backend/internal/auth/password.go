@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"bufio"
+	_ "embed"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/domain"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var (
+	commonPasswordsOnce sync.Once
+	commonPasswords     map[string]struct{}
+)
+
+// PasswordPolicy describes the rules a new admin password has to satisfy.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize is how many previous passwords are remembered and refused.
+	HistorySize int
+}
+
+// DefaultPasswordPolicy is used when no configuration is available.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:    8,
+		RequireLower: true,
+		RequireDigit: true,
+		HistorySize:  5,
+	}
+}
+
+// NewPasswordPolicy builds a policy from the loaded configuration.
+func NewPasswordPolicy(c *config.Config) PasswordPolicy {
+	if c == nil {
+		return DefaultPasswordPolicy()
+	}
+	return PasswordPolicy{
+		MinLength:     c.PasswordMinLength,
+		RequireUpper:  c.PasswordRequireUpper,
+		RequireLower:  c.PasswordRequireLower,
+		RequireDigit:  c.PasswordRequireDigit,
+		RequireSymbol: c.PasswordRequireSymbol,
+		HistorySize:   c.PasswordHistorySize,
+	}
+}
+
+// Validate checks length, character classes and the bundled list of common
+// and breached passwords. Reuse is checked by the caller since it needs the
+// stored history.
+func (p PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return domain.ErrPasswordTooShort
+	}
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		default:
+			// Letters without case (e.g. Ge'ez script) count as lower case
+			// so Amharic passwords aren't rejected outright.
+			if unicode.IsLetter(r) {
+				hasLower = true
+			}
+		}
+	}
+	if (p.RequireUpper && !hasUpper) || (p.RequireLower && !hasLower) ||
+		(p.RequireDigit && !hasDigit) || (p.RequireSymbol && !hasSymbol) {
+		return domain.ErrPasswordTooWeak
+	}
+	if IsCommonPassword(password) {
+		return domain.ErrPasswordCommon
+	}
+	return nil
+}
+
+// IsCommonPassword reports whether the password is on the bundled offline
+// list of common and breached passwords. The comparison ignores case.
+func IsCommonPassword(password string) bool {
+	commonPasswordsOnce.Do(func() {
+		commonPasswords = make(map[string]struct{})
+		scanner := bufio.NewScanner(strings.NewReader(commonPasswordsFile))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			commonPasswords[strings.ToLower(line)] = struct{}{}
+		}
+	})
+	_, found := commonPasswords[strings.ToLower(password)]
+	return found
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"hiyab-tutor/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	strict := PasswordPolicy{
+		MinLength:     10,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	cases := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		want     error
+	}{
+		{"default accepts letters and digits", DefaultPasswordPolicy(), "tutoring42", nil},
+		{"too short", DefaultPasswordPolicy(), "ab12", domain.ErrPasswordTooShort},
+		{"missing digit", DefaultPasswordPolicy(), "onlyletters", domain.ErrPasswordTooWeak},
+		{"common password", DefaultPasswordPolicy(), "Password123", domain.ErrPasswordCommon},
+		{"strict accepts all classes", strict, "Hiyab-Tutor-2024", nil},
+		{"strict missing symbol", strict, "HiyabTutor2024", domain.ErrPasswordTooWeak},
+		{"strict missing upper", strict, "hiyab-tutor-2024", domain.ErrPasswordTooWeak},
+		{"amharic letters count as lower case", DefaultPasswordPolicy(), "ሰላምሰላም2024", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.policy.Validate(tc.password))
+		})
+	}
+}
+
+func TestIsCommonPassword(t *testing.T) {
+	require.True(t, IsCommonPassword("qwerty123"))
+	require.True(t, IsCommonPassword("QWERTY123"))
+	require.False(t, IsCommonPassword("# Common and breached passwords rejected by the password policy."))
+	require.False(t, IsCommonPassword("correct-horse-battery-staple"))
+}
@@ -0,0 +1,61 @@
+package captcha
+
+import (
+	"context"
+	"hiyab-tutor/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteVerifier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "secret", r.PostForm.Get("secret"))
+		require.Equal(t, "10.0.0.1", r.PostForm.Get("remoteip"))
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+	}))
+	defer srv.Close()
+
+	v := NewSiteVerifier(srv.URL, "secret")
+	require.NoError(t, v.Verify(context.Background(), "good", "10.0.0.1"))
+	err := v.Verify(context.Background(), "bad", "10.0.0.1")
+	require.ErrorIs(t, err, ErrFailed)
+	require.Contains(t, err.Error(), "invalid-input-response")
+	require.ErrorIs(t, v.Verify(context.Background(), "", "10.0.0.1"), ErrFailed)
+}
+
+func TestSiteVerifier_ProviderDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := NewSiteVerifier(srv.URL, "secret").Verify(context.Background(), "token", "")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrFailed)
+}
+
+func TestNewVerifier(t *testing.T) {
+	v, err := NewVerifier(&config.Config{})
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	v, err = NewVerifier(&config.Config{CaptchaProvider: ProviderFake})
+	require.NoError(t, err)
+	require.NoError(t, v.Verify(context.Background(), "pass", ""))
+	require.ErrorIs(t, v.Verify(context.Background(), "nope", ""), ErrFailed)
+
+	v, err = NewVerifier(&config.Config{CaptchaProvider: ProviderTurnstile, CaptchaSecret: "s"})
+	require.NoError(t, err)
+	require.Equal(t, turnstileVerifyURL, v.(*SiteVerifier).URL)
+
+	_, err = NewVerifier(&config.Config{CaptchaProvider: "recaptcha"})
+	require.ErrorIs(t, err, ErrUnknownProvider)
+}
@@ -17,23 +17,144 @@ type Config struct {
 	DBHost        string `mapstructure:"BLUEPRINT_DB_HOST"`
 	DBPort        string `mapstructure:"BLUEPRINT_DB_PORT"`
 	Schema        string `mapstructure:"BLUEPRINT_DB_SCHEMA"`
+
+	// Admin session token lifetimes
+	AccessTokenTTLMinutes  int `mapstructure:"ACCESS_TOKEN_TTL_MINUTES"`
+	RefreshTokenTTLMinutes int `mapstructure:"REFRESH_TOKEN_TTL_MINUTES"`
+
+	// Uploaded files are written to and served from UploadDir. Browsers may
+	// call the API from the comma separated CORS origins
+	UploadDir          string `mapstructure:"UPLOAD_DIR"`
+	CORSAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+
+	// AppEnv is development or production and picks the CORS profile. The
+	// CORS methods and headers are comma separated lists
+	AppEnv             string `mapstructure:"APP_ENV"`
+	CORSAllowedMethods string `mapstructure:"CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders string `mapstructure:"CORS_ALLOWED_HEADERS"`
+	CORSMaxAgeSeconds  int    `mapstructure:"CORS_MAX_AGE_SECONDS"`
+
+	// Logs are written as json or text from LogLevel (debug, info, warn or
+	// error) up
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+
+	// MetricsToken, when set, is required as a bearer token on /metrics
+	MetricsToken string `mapstructure:"METRICS_TOKEN"`
+
+	// Traces go to TracingExporter: otlp, stdout or none. The OTLP endpoint
+	// falls back to the standard OTEL_* variables when empty
+	TracingExporter    string  `mapstructure:"TRACING_EXPORTER"`
+	OTLPEndpoint       string  `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+
+	// /readyz reports degraded when less than this is free under UploadDir
+	HealthMinFreeDiskMB int `mapstructure:"HEALTH_MIN_FREE_DISK_MB"`
+
+	// Each shutdown phase (stop traffic, drain, flush, close) gets this long
+	ShutdownDrainTimeoutSeconds int `mapstructure:"SHUTDOWN_DRAIN_TIMEOUT_SECONDS"`
+
+	// Password policy
+	PasswordMinLength     int  `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireUpper  bool `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower  bool `mapstructure:"PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit  bool `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol bool `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+	PasswordHistorySize   int  `mapstructure:"PASSWORD_HISTORY_SIZE"`
+
+	// Forgot-password flow; the channel is one of smtp, sms or log
+	PasswordResetChannel    string `mapstructure:"PASSWORD_RESET_CHANNEL"`
+	PasswordResetTTLMinutes int    `mapstructure:"PASSWORD_RESET_TTL_MINUTES"`
+
+	// Notification transports
+	SMTPHost      string `mapstructure:"SMTP_HOST"`
+	SMTPPort      int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername  string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword  string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom      string `mapstructure:"SMTP_FROM"`
+	SMSGatewayURL string `mapstructure:"SMS_GATEWAY_URL"`
+	SMSAPIKey     string `mapstructure:"SMS_API_KEY"`
+	SMSSenderID   string `mapstructure:"SMS_SENDER_ID"`
+	// Failed text messages are retried with exponential backoff
+	SMSMaxAttempts       int `mapstructure:"SMS_MAX_ATTEMPTS"`
+	SMSRetryDelaySeconds int `mapstructure:"SMS_RETRY_DELAY_SECONDS"`
+
+	// Background jobs; JobWorkers run inside the API process, set it to 0
+	// when cmd/worker runs them instead. Failed jobs are retried with
+	// exponential backoff
+	JobWorkers           int `mapstructure:"JOB_WORKERS"`
+	JobMaxAttempts       int `mapstructure:"JOB_MAX_ATTEMPTS"`
+	JobRetryDelaySeconds int `mapstructure:"JOB_RETRY_DELAY_SECONDS"`
+
+	// Maintenance tasks run on five field cron schedules by whichever
+	// instance holds the scheduler lock; an empty schedule disables a task
+	SchedulerEnabled         bool   `mapstructure:"SCHEDULER_ENABLED"`
+	CronOrphanFiles          string `mapstructure:"CRON_ORPHAN_FILES"`
+	CronUnassignedReminder   string `mapstructure:"CRON_UNASSIGNED_REMINDER"`
+	CronDocumentExpiry       string `mapstructure:"CRON_DOCUMENT_EXPIRY"`
+	CronTrashPurge           string `mapstructure:"CRON_TRASH_PURGE"`
+	CronExportCleanup        string `mapstructure:"CRON_EXPORT_CLEANUP"`
+	UnassignedReminderHours  int    `mapstructure:"UNASSIGNED_REMINDER_HOURS"`
+	DocumentExpiryNoticeDays int    `mapstructure:"DOCUMENT_EXPIRY_NOTICE_DAYS"`
+	TrashRetentionDays       int    `mapstructure:"TRASH_RETENTION_DAYS"`
+
+	// AnalyticsSnapshotTTLSeconds bounds how stale the dashboard totals
+	// get; writes through this instance refresh them sooner
+	AnalyticsSnapshotTTLSeconds int `mapstructure:"ANALYTICS_SNAPSHOT_TTL_SECONDS"`
+
+	// Exports matching more than ExportSyncLimit rows are written to
+	// ExportDir by a job and kept for ExportRetentionHours. The directory
+	// is not served publicly and must be shared with cmd/worker
+	ExportDir            string `mapstructure:"EXPORT_DIR"`
+	ExportSyncLimit      int    `mapstructure:"EXPORT_SYNC_LIMIT"`
+	ExportRetentionHours int    `mapstructure:"EXPORT_RETENTION_HOURS"`
+
+	// Event notifications; channels is a comma separated list of smtp, sms,
+	// telegram and log
+	NotifyChannels        string `mapstructure:"NOTIFY_CHANNELS"`
+	NotifyAdminEmails     string `mapstructure:"NOTIFY_ADMIN_EMAILS"`
+	NotifyAdminPhones     string `mapstructure:"NOTIFY_ADMIN_PHONES"`
+	NotifyTemplateDir     string `mapstructure:"NOTIFY_TEMPLATE_DIR"`
+	NotifyDefaultLanguage string `mapstructure:"NOTIFY_DEFAULT_LANGUAGE"`
+	TelegramBotToken      string `mapstructure:"BOT_TOKEN"`
+	TelegramChatID        string `mapstructure:"TELEGRAM_CHAT_ID"`
+
+	// Spam protection for the public forms; the CAPTCHA provider is one of
+	// hcaptcha, turnstile or fake, and empty disables it
+	CaptchaProvider           string `mapstructure:"CAPTCHA_PROVIDER"`
+	CaptchaSecret             string `mapstructure:"CAPTCHA_SECRET"`
+	HoneypotField             string `mapstructure:"HONEYPOT_FIELD"`
+	SpamMaxSubmissionsPerHour int    `mapstructure:"SPAM_MAX_SUBMISSIONS_PER_HOUR"`
+
+	// Rate limits per route group as "<limit>/<period>", e.g. "120/1m"; an
+	// empty value disables the group's limit. The store is memory or redis
+	RateLimitStore       string `mapstructure:"RATE_LIMIT_STORE"`
+	RedisURL             string `mapstructure:"REDIS_URL"`
+	RateLimitPublicRead  string `mapstructure:"RATE_LIMIT_PUBLIC_READ"`
+	RateLimitPublicWrite string `mapstructure:"RATE_LIMIT_PUBLIC_WRITE"`
+	RateLimitAdmin       string `mapstructure:"RATE_LIMIT_ADMIN"`
 }
 
+// LoadConfig reads .env (or config.yaml) and the environment once and
+// validates the result. Call it at startup and pass the Config on; when
+// settings are missing or invalid the returned error lists all of them.
 func LoadConfig() (*Config, error) {
-	viper.SetConfigFile(".env")
-	viper.SetConfigType("env")
-	viper.AddConfigPath(".")
-	viper.AutomaticEnv() // Allow environment variables to override
-	
+	v := viper.New()
+	v.SetConfigFile(".env")
+	v.SetConfigType("env")
+	v.AddConfigPath(".")
+	v.AutomaticEnv() // Allow environment variables to override
+	setDefaults(v)
+
 	// Try to read .env file, but don't fail if it doesn't exist
 	// Environment variables will be used instead (for Docker)
-	err := viper.ReadInConfig()
+	err := v.ReadInConfig()
 	if err != nil {
 		// If .env doesn't exist, try config.yaml
-		viper.SetConfigName("config")
-		viper.SetConfigType("yaml")
-		viper.AddConfigPath(".")
-		err = viper.ReadInConfig()
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		err = v.ReadInConfig()
 		if err != nil {
 			// If no config file exists, that's OK - we'll use environment variables
 			// Just make sure AutomaticEnv is set (already done above)
@@ -41,8 +162,104 @@ func LoadConfig() (*Config, error) {
 	}
 
 	var c Config
-	if err := viper.Unmarshal(&c); err != nil {
+	if err := v.Unmarshal(&c); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(); err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
+
+// Defaults returns the configuration with every setting at its default, as
+// a starting point for tests.
+func Defaults() *Config {
+	v := viper.New()
+	setDefaults(v)
+	var c Config
+	_ = v.Unmarshal(&c)
+	return &c
+}
+
+// setDefaults registers fallback values. Registering a key also lets
+// AutomaticEnv pick it up during Unmarshal when no config file mentions it.
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("JWT_SECRET", "")
+	v.SetDefault("SERVER_PORT", "8080")
+	v.SetDefault("ADMIN_USERNAME", "")
+	v.SetDefault("ADMIN_PASSWORD", "")
+	v.SetDefault("BLUEPRINT_DB_URL", "")
+	v.SetDefault("BLUEPRINT_DB_USERNAME", "")
+	v.SetDefault("BLUEPRINT_DB_PASSWORD", "")
+	v.SetDefault("BLUEPRINT_DB_DATABASE", "")
+	v.SetDefault("BLUEPRINT_DB_HOST", "")
+	v.SetDefault("BLUEPRINT_DB_PORT", "5432")
+	v.SetDefault("BLUEPRINT_DB_SCHEMA", "public")
+	v.SetDefault("ACCESS_TOKEN_TTL_MINUTES", 24*60)
+	v.SetDefault("REFRESH_TOKEN_TTL_MINUTES", 3*24*60)
+	v.SetDefault("UPLOAD_DIR", "uploads")
+	v.SetDefault("CORS_ALLOWED_ORIGINS", "")
+	v.SetDefault("APP_ENV", EnvProduction)
+	v.SetDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
+	v.SetDefault("CORS_ALLOWED_HEADERS", "Accept,Authorization,Content-Type,X-Captcha-Token,X-Request-ID")
+	v.SetDefault("CORS_MAX_AGE_SECONDS", 600)
+	v.SetDefault("LOG_LEVEL", "info")
+	v.SetDefault("LOG_FORMAT", LogFormatJSON)
+	v.SetDefault("METRICS_TOKEN", "")
+	v.SetDefault("TRACING_EXPORTER", TracingNone)
+	v.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	v.SetDefault("HEALTH_MIN_FREE_DISK_MB", 512)
+	v.SetDefault("SHUTDOWN_DRAIN_TIMEOUT_SECONDS", 30)
+	v.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	v.SetDefault("PASSWORD_REQUIRE_UPPER", false)
+	v.SetDefault("PASSWORD_REQUIRE_LOWER", true)
+	v.SetDefault("PASSWORD_REQUIRE_DIGIT", true)
+	v.SetDefault("PASSWORD_REQUIRE_SYMBOL", false)
+	v.SetDefault("PASSWORD_HISTORY_SIZE", 5)
+	v.SetDefault("PASSWORD_RESET_CHANNEL", "log")
+	v.SetDefault("PASSWORD_RESET_TTL_MINUTES", 30)
+	v.SetDefault("SMTP_HOST", "")
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("SMTP_USERNAME", "")
+	v.SetDefault("SMTP_PASSWORD", "")
+	v.SetDefault("SMTP_FROM", "")
+	v.SetDefault("SMS_GATEWAY_URL", "")
+	v.SetDefault("SMS_API_KEY", "")
+	v.SetDefault("SMS_SENDER_ID", "")
+	v.SetDefault("SMS_MAX_ATTEMPTS", 5)
+	v.SetDefault("SMS_RETRY_DELAY_SECONDS", 60)
+	v.SetDefault("JOB_WORKERS", 4)
+	v.SetDefault("JOB_MAX_ATTEMPTS", 5)
+	v.SetDefault("JOB_RETRY_DELAY_SECONDS", 30)
+	v.SetDefault("SCHEDULER_ENABLED", true)
+	v.SetDefault("CRON_ORPHAN_FILES", "0 3 * * *")
+	v.SetDefault("CRON_UNASSIGNED_REMINDER", "0 9 * * *")
+	v.SetDefault("CRON_DOCUMENT_EXPIRY", "0 8 * * *")
+	v.SetDefault("CRON_TRASH_PURGE", "30 3 * * *")
+	v.SetDefault("CRON_EXPORT_CLEANUP", "15 * * * *")
+	v.SetDefault("UNASSIGNED_REMINDER_HOURS", 24)
+	v.SetDefault("DOCUMENT_EXPIRY_NOTICE_DAYS", 30)
+	v.SetDefault("TRASH_RETENTION_DAYS", 30)
+	v.SetDefault("ANALYTICS_SNAPSHOT_TTL_SECONDS", 300)
+	v.SetDefault("EXPORT_DIR", "exports")
+	v.SetDefault("EXPORT_SYNC_LIMIT", 5000)
+	v.SetDefault("EXPORT_RETENTION_HOURS", 24)
+	v.SetDefault("NOTIFY_CHANNELS", "log")
+	v.SetDefault("NOTIFY_ADMIN_EMAILS", "")
+	v.SetDefault("NOTIFY_ADMIN_PHONES", "")
+	v.SetDefault("NOTIFY_TEMPLATE_DIR", "")
+	v.SetDefault("NOTIFY_DEFAULT_LANGUAGE", "en")
+	v.SetDefault("BOT_TOKEN", "")
+	v.SetDefault("TELEGRAM_CHAT_ID", "")
+	v.SetDefault("CAPTCHA_PROVIDER", "")
+	v.SetDefault("CAPTCHA_SECRET", "")
+	v.SetDefault("HONEYPOT_FIELD", "website")
+	v.SetDefault("SPAM_MAX_SUBMISSIONS_PER_HOUR", 10)
+	v.SetDefault("RATE_LIMIT_STORE", "memory")
+	v.SetDefault("REDIS_URL", "")
+	v.SetDefault("RATE_LIMIT_PUBLIC_READ", "300/1m")
+	v.SetDefault("RATE_LIMIT_PUBLIC_WRITE", "30/1m")
+	v.SetDefault("RATE_LIMIT_ADMIN", "600/1m")
+	v.SetDefault("WEB_APP_URL", "")
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Environments for APP_ENV
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Formats for LOG_FORMAT
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// Exporters for TRACING_EXPORTER
+const (
+	TracingNone   = "none"
+	TracingOTLP   = "otlp"
+	TracingStdout = "stdout"
+)
+
+// MinJWTSecretLength is the shortest accepted JWT_SECRET; HS256 keys should
+// carry at least 256 bits.
+const MinJWTSecretLength = 32
+
+// Bounds for PASSWORD_MIN_LENGTH: shorter passwords are too easy to guess,
+// and bcrypt ignores whatever comes after 72 bytes.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// ValidationError lists every missing or invalid setting, so they can all be
+// fixed in one go instead of one restart at a time.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// Validate checks the settings the server can't run without.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(key, format string, args ...any) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	switch {
+	case c.JwtSecret == "":
+		add("JWT_SECRET", "is required")
+	case len(c.JwtSecret) < MinJWTSecretLength:
+		add("JWT_SECRET", "must be at least %d characters, got %d", MinJWTSecretLength, len(c.JwtSecret))
+	}
+	if n, err := strconv.Atoi(c.ServerPort); err != nil || n < 1 || n > 65535 {
+		add("SERVER_PORT", "%q is not a port number", c.ServerPort)
+	}
+	if c.AdminUsername == "" {
+		add("ADMIN_USERNAME", "is required to seed the super admin")
+	}
+	if c.AdminPassword == "" {
+		add("ADMIN_PASSWORD", "is required to seed the super admin")
+	}
+
+	if c.DBHost == "" && c.DBUrl == "" {
+		add("BLUEPRINT_DB_HOST", "is required unless BLUEPRINT_DB_URL is set")
+	}
+	if c.DBHost != "" {
+		if c.DBName == "" {
+			add("BLUEPRINT_DB_DATABASE", "is required")
+		}
+		if c.DBUsername == "" {
+			add("BLUEPRINT_DB_USERNAME", "is required")
+		}
+		if _, err := strconv.Atoi(c.DBPort); err != nil {
+			add("BLUEPRINT_DB_PORT", "%q is not a port number", c.DBPort)
+		}
+	} else if c.DBUrl != "" {
+		if u, err := url.Parse(c.DBUrl); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+			add("BLUEPRINT_DB_URL", "must be a postgres:// URL")
+		}
+	}
+
+	if c.AccessTokenTTLMinutes <= 0 {
+		add("ACCESS_TOKEN_TTL_MINUTES", "must be positive")
+	}
+	if c.RefreshTokenTTLMinutes < c.AccessTokenTTLMinutes {
+		add("REFRESH_TOKEN_TTL_MINUTES", "must not be shorter than ACCESS_TOKEN_TTL_MINUTES")
+	}
+
+	if c.UploadDir == "" {
+		add("UPLOAD_DIR", "is required")
+	} else if info, err := os.Stat(c.UploadDir); err == nil && !info.IsDir() {
+		add("UPLOAD_DIR", "%s is not a directory", c.UploadDir)
+	}
+	if c.ExportDir == "" {
+		add("EXPORT_DIR", "is required")
+	}
+	if c.AppEnv != EnvDevelopment && c.AppEnv != EnvProduction {
+		add("APP_ENV", "must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.AppEnv)
+	}
+	if c.PasswordMinLength < MinPasswordLength || c.PasswordMinLength > MaxPasswordLength {
+		add("PASSWORD_MIN_LENGTH", "must be between %d and %d, got %d", MinPasswordLength, MaxPasswordLength, c.PasswordMinLength)
+	}
+	if c.PasswordHistorySize < 0 {
+		add("PASSWORD_HISTORY_SIZE", "must not be negative")
+	}
+	if c.PasswordResetTTLMinutes <= 0 {
+		add("PASSWORD_RESET_TTL_MINUTES", "must be positive")
+	}
+	// Reset links must reach only the admin, never the logs. Admins have no
+	// Telegram chat on file, so that channel could never deliver them.
+	switch c.PasswordResetChannel {
+	case "smtp", "sms":
+	case "log", "":
+		if c.AppEnv != EnvDevelopment {
+			add("PASSWORD_RESET_CHANNEL", "must be smtp or sms outside %s", EnvDevelopment)
+		}
+	default:
+		add("PASSWORD_RESET_CHANNEL", "must be smtp, sms or log, got %q", c.PasswordResetChannel)
+	}
+	for _, origin := range c.CORSOrigins() {
+		if err := checkOrigin(origin); err != nil {
+			add("CORS_ALLOWED_ORIGINS", "%q %v", origin, err)
+		}
+	}
+	if c.CORSMaxAgeSeconds < 0 {
+		add("CORS_MAX_AGE_SECONDS", "must not be negative")
+	}
+	if _, err := c.SlogLevel(); err != nil {
+		add("LOG_LEVEL", "must be debug, info, warn or error, got %q", c.LogLevel)
+	}
+	if c.LogFormat != LogFormatJSON && c.LogFormat != LogFormatText {
+		add("LOG_FORMAT", "must be %s or %s, got %q", LogFormatJSON, LogFormatText, c.LogFormat)
+	}
+	switch c.TracingExporter {
+	case TracingNone, TracingOTLP, TracingStdout:
+	default:
+		add("TRACING_EXPORTER", "must be %s, %s or %s, got %q", TracingOTLP, TracingStdout, TracingNone, c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		add("TRACING_SAMPLE_RATIO", "must be between 0 and 1")
+	}
+	if c.HealthMinFreeDiskMB < 0 {
+		add("HEALTH_MIN_FREE_DISK_MB", "must not be negative")
+	}
+	if c.ShutdownDrainTimeoutSeconds <= 0 {
+		add("SHUTDOWN_DRAIN_TIMEOUT_SECONDS", "must be positive")
+	}
+	if c.JobWorkers < 0 {
+		add("JOB_WORKERS", "must not be negative")
+	}
+	if c.JobMaxAttempts < 1 {
+		add("JOB_MAX_ATTEMPTS", "must be at least 1")
+	}
+	for _, s := range []struct{ key, spec string }{
+		{"CRON_ORPHAN_FILES", c.CronOrphanFiles},
+		{"CRON_UNASSIGNED_REMINDER", c.CronUnassignedReminder},
+		{"CRON_DOCUMENT_EXPIRY", c.CronDocumentExpiry},
+		{"CRON_TRASH_PURGE", c.CronTrashPurge},
+		{"CRON_EXPORT_CLEANUP", c.CronExportCleanup},
+	} {
+		if _, err := cron.ParseStandard(s.spec); s.spec != "" && err != nil {
+			add(s.key, "%v", err)
+		}
+	}
+	for _, n := range []struct {
+		key   string
+		value int
+	}{
+		{"UNASSIGNED_REMINDER_HOURS", c.UnassignedReminderHours},
+		{"DOCUMENT_EXPIRY_NOTICE_DAYS", c.DocumentExpiryNoticeDays},
+		{"TRASH_RETENTION_DAYS", c.TrashRetentionDays},
+		{"ANALYTICS_SNAPSHOT_TTL_SECONDS", c.AnalyticsSnapshotTTLSeconds},
+		{"EXPORT_SYNC_LIMIT", c.ExportSyncLimit},
+		{"EXPORT_RETENTION_HOURS", c.ExportRetentionHours},
+	} {
+		if n.value < 1 {
+			add(n.key, "must be at least 1")
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// DSN is the Postgres connection string. The individual BLUEPRINT_DB_*
+// settings win over BLUEPRINT_DB_URL when a host is given.
+func (c *Config) DSN() string {
+	if c.DBHost == "" {
+		return c.DBUrl
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.DBUsername, c.DBPassword),
+		Host:     c.DBHost + ":" + c.DBPort,
+		Path:     "/" + c.DBName,
+		RawQuery: "sslmode=disable&search_path=" + url.QueryEscape(c.Schema),
+	}
+	return u.String()
+}
+
+// SlogLevel parses LOG_LEVEL.
+func (c *Config) SlogLevel() (slog.Level, error) {
+	var l slog.Level
+	err := l.UnmarshalText([]byte(c.LogLevel))
+	return l, err
+}
+
+// CORSOrigins splits CORS_ALLOWED_ORIGINS.
+func (c *Config) CORSOrigins() []string {
+	return splitList(c.CORSAllowedOrigins)
+}
+
+// CORSMethods splits CORS_ALLOWED_METHODS.
+func (c *Config) CORSMethods() []string {
+	return splitList(c.CORSAllowedMethods)
+}
+
+// CORSHeaders splits CORS_ALLOWED_HEADERS.
+func (c *Config) CORSHeaders() []string {
+	return splitList(c.CORSAllowedHeaders)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// checkOrigin accepts "*" or a scheme and host such as https://hiyab.org;
+// the host may start with "*." to allow every subdomain.
+func checkOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("is not an http(s) origin")
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("must not have a path or query")
+	}
+	if strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+		return fmt.Errorf("may only use a wildcard as the first label")
+	}
+	return nil
+}
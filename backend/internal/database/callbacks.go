@@ -0,0 +1,24 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// OnWrite calls fn with the table's name after every create, update and
+// delete db runs without error. Raw SQL goes unnoticed. name keeps the
+// callbacks apart from those registered by others.
+func OnWrite(db *gorm.DB, name string, fn func(table string)) error {
+	after := func(tx *gorm.DB) {
+		if tx.Error == nil && tx.Statement.Table != "" {
+			fn(tx.Statement.Table)
+		}
+	}
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().After("gorm:create").Register(name+":after_create", after),
+		cb.Update().After("gorm:update").Register(name+":after_update", after),
+		cb.Delete().After("gorm:delete").Register(name+":after_delete", after),
+	)
+}
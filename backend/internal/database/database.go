@@ -3,15 +3,14 @@ package database
 import (
 	"context"
 	"fmt"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/logging"
 	"log"
-	"os"
+	"log/slog"
 	"strconv"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
-	_ "github.com/joho/godotenv/autoload"
-	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -32,88 +31,28 @@ type Service interface {
 }
 
 type service struct {
-	db *gorm.DB
+	db   *gorm.DB
+	name string
 }
 
-var (
-	database   = os.Getenv("BLUEPRINT_DB_DATABASE")
-	password   = os.Getenv("BLUEPRINT_DB_PASSWORD")
-	username   = os.Getenv("BLUEPRINT_DB_USERNAME")
-	port       = os.Getenv("BLUEPRINT_DB_PORT")
-	host       = os.Getenv("BLUEPRINT_DB_HOST")
-	schema     = os.Getenv("BLUEPRINT_DB_SCHEMA")
-	dbInstance *service
-)
+var dbInstance *service
 
-func TestDB() *gorm.DB {
-	ctx := context.Background()
-
-	// Set safe defaults for test DB if env vars are empty
-	testDB := "test_db"
-	testUser := "test_user"
-	testPass := "test_password"
-
-	req := testcontainers.ContainerRequest{
-		Image:        "postgres:15.3-alpine",
-		ExposedPorts: []string{"5432/tcp"},
-		Env: map[string]string{
-			"POSTGRES_DB":       testDB,
-			"POSTGRES_USER":     testUser,
-			"POSTGRES_PASSWORD": testPass,
-		},
-		WaitingFor: wait.ForListeningPort("5432/tcp").WithStartupTimeout(30 * time.Second),
-	}
-	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	if err != nil {
-		log.Fatalf("failed to create container: %v", err)
-	}
+// slowQuery is how long a query may take before it is logged as slow.
+const slowQuery = 200 * time.Millisecond
 
-	// Check container state and print logs if not running
-	state, err := container.State(ctx)
-	if err != nil {
-		log.Fatalf("failed to get container state: %v", err)
-	}
-	if !state.Running {
-		logs, _ := container.Logs(ctx)
-		log.Fatalf("container is not running. Logs:\n%s", logs)
-	}
-	host, err := container.Host(ctx)
-	if err != nil {
-		log.Fatalf("failed to get container host: %v", err)
-	}
-	port, err := container.MappedPort(ctx, "5432/tcp")
-	if err != nil {
-		log.Fatalf("failed to get mapped port: %v", err)
-	}
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=allow", host, port.Port(), testUser, testPass, testDB)
-	var db *gorm.DB
-	for i := 0; i < 5; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-		if err == nil {
-			break
-		}
-		time.Sleep(1 * time.Second) // Retry after a short delay
-	}
-	if err != nil {
-		log.Fatal(err)
-	}
-	return db
-}
-func New() Service {
+// New connects to the database described by c.
+func New(c *config.Config) Service {
 	// Reuse Connection
 	if dbInstance != nil {
 		return dbInstance
 	}
-	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable&search_path=%s", username, password, host, port, database, schema)
-	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(c.DSN()), &gorm.Config{Logger: logging.NewGorm(slowQuery)})
 	if err != nil {
 		log.Fatal(err)
 	}
 	dbInstance = &service{
-		db: db,
+		db:   db,
+		name: c.DBName,
 	}
 	return dbInstance
 }
@@ -134,14 +73,14 @@ func (s *service) Health() map[string]string {
 	if err != nil {
 		stats["status"] = "down"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
+		slog.Warn("database health check failed", "error", err)
 		return stats
 	}
 	err = db.PingContext(ctx)
 	if err != nil {
 		stats["status"] = "down"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf("db down: %v", err) // Log the error and terminate the program
+		slog.Warn("database health check failed", "error", err)
 		return stats
 	}
 
@@ -179,13 +118,22 @@ func (s *service) Health() map[string]string {
 	return stats
 }
 
-// Close closes the database connection.
+// Close closes the connection pool, waiting for the queries in flight.
 // It logs a message indicating the disconnection from the specific database.
 // If the connection is successfully closed, it returns nil.
 // If an error occurs while closing the connection, it returns the error.
 func (s *service) Close() error {
-	log.Printf("Disconnected from database: %s", database)
-	return s.db.Connection(func(tx *gorm.DB) error {
-		return tx.Error
-	})
+	db, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+	// The next New connects again rather than handing out a closed pool
+	if dbInstance == s {
+		dbInstance = nil
+	}
+	slog.Info("disconnected from database", "database", s.name)
+	return nil
 }
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"hiyab-tutor/internal/config"
 	"log"
 	"testing"
 	"time"
@@ -11,6 +12,9 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// testConfig points at the container started by TestMain
+var testConfig = config.Defaults()
+
 func mustStartPostgresContainer() (func(context.Context, ...testcontainers.TerminateOption) error, error) {
 	var (
 		dbName = "database"
@@ -33,9 +37,9 @@ func mustStartPostgresContainer() (func(context.Context, ...testcontainers.Termi
 		return nil, err
 	}
 
-	database = dbName
-	password = dbPwd
-	username = dbUser
+	testConfig.DBName = dbName
+	testConfig.DBPassword = dbPwd
+	testConfig.DBUsername = dbUser
 
 	dbHost, err := dbContainer.Host(context.Background())
 	if err != nil {
@@ -47,8 +51,8 @@ func mustStartPostgresContainer() (func(context.Context, ...testcontainers.Termi
 		return dbContainer.Terminate, err
 	}
 
-	host = dbHost
-	port = dbPort.Port()
+	testConfig.DBHost = dbHost
+	testConfig.DBPort = dbPort.Port()
 
 	return dbContainer.Terminate, err
 }
@@ -67,14 +71,14 @@ func TestMain(m *testing.M) {
 }
 
 func TestNew(t *testing.T) {
-	srv := New()
+	srv := New(testConfig)
 	if srv == nil {
 		t.Fatal("New() returned nil")
 	}
 }
 
 func TestHealth(t *testing.T) {
-	srv := New()
+	srv := New(testConfig)
 
 	stats := srv.Health()
 
@@ -91,8 +95,39 @@ func TestHealth(t *testing.T) {
 	}
 }
 
+func TestMigrateSearch(t *testing.T) {
+	db := New(testConfig).Gorm()
+	if err := db.Exec("CREATE TABLE search_people (id serial PRIMARY KEY, name text)").Error; err != nil {
+		t.Fatal(err)
+	}
+	columns := []SearchColumn{{Name: "name", Weight: 'A'}}
+
+	early := NewSearch(db, "search_people", columns...)
+	early.Match("ab")
+	if early.ready {
+		t.Fatal("expected search to be off before migrating")
+	}
+	// Built before the migration, as the repositories are, it still sees it
+	s := NewSearch(db, "search_people", columns...)
+	// Running twice, as every restart does, leaves things as they are
+	for range 2 {
+		MigrateSearch(context.Background(), db, "search_people", columns...)
+	}
+	s.Match("ab")
+	if !s.ready || !s.trigram {
+		t.Fatalf("expected search and similarity to be on, got ready=%v trigram=%v", s.ready, s.trigram)
+	}
+	var valid []bool
+	if err := db.Raw("SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass('idx_search_people_search_vector')").Scan(&valid).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(valid) != 1 || !valid[0] {
+		t.Fatalf("expected a valid search index, got %v", valid)
+	}
+}
+
 func TestClose(t *testing.T) {
-	srv := New()
+	srv := New(testConfig)
 
 	if srv.Close() != nil {
 		t.Fatalf("expected Close() to return nil")
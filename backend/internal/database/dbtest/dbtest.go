@@ -0,0 +1,120 @@
+// Package dbtest opens throwaway databases for tests.
+//
+// By default tests run against an in-memory SQLite database, so they need
+// neither Docker nor a running server. Set TEST_DB=postgres to run the same
+// tests against a Postgres container instead, as `make test-postgres` does.
+//
+// The SQLite driver wraps the C library, so tests need cgo: a C compiler
+// and CGO_ENABLED=1, which is the default wherever one is installed.
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Drivers accepted in TEST_DB.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Open returns an empty database of the kind selected by TEST_DB.
+func Open() *gorm.DB {
+	switch driver := os.Getenv("TEST_DB"); driver {
+	case "", DriverSQLite:
+		return SQLite()
+	case DriverPostgres:
+		return Postgres()
+	default:
+		log.Fatalf("TEST_DB: unknown driver %q, want %q or %q", driver, DriverSQLite, DriverPostgres)
+		return nil
+	}
+}
+
+var sqliteDBs atomic.Int64
+
+// SQLite returns a new in-memory database. Every call gets its own
+// database, which lives until the last connection to it is closed.
+func SQLite() *gorm.DB {
+	name := fmt.Sprintf("file:test%d?mode=memory&cache=shared&_fk=1&_busy_timeout=5000", sqliteDBs.Add(1))
+	db, err := gorm.Open(sqlite.Open(name), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open sqlite: %v", err)
+	}
+	// A shared in-memory database allows one writer at a time
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return db
+}
+
+// Postgres starts a Postgres container and connects to it.
+func Postgres() *gorm.DB {
+	ctx := context.Background()
+
+	testDB := "test_db"
+	testUser := "test_user"
+	testPass := "test_password"
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:15.3-alpine",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_DB":       testDB,
+			"POSTGRES_USER":     testUser,
+			"POSTGRES_PASSWORD": testPass,
+		},
+		WaitingFor: wait.ForListeningPort("5432/tcp").WithStartupTimeout(30 * time.Second),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		log.Fatalf("failed to create container: %v", err)
+	}
+
+	// Check container state and print logs if not running
+	state, err := container.State(ctx)
+	if err != nil {
+		log.Fatalf("failed to get container state: %v", err)
+	}
+	if !state.Running {
+		logs, _ := container.Logs(ctx)
+		log.Fatalf("container is not running. Logs:\n%s", logs)
+	}
+	host, err := container.Host(ctx)
+	if err != nil {
+		log.Fatalf("failed to get container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		log.Fatalf("failed to get mapped port: %v", err)
+	}
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=allow", host, port.Port(), testUser, testPass, testDB)
+	var db *gorm.DB
+	for i := 0; i < 5; i++ {
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		if err == nil {
+			break
+		}
+		time.Sleep(1 * time.Second) // Retry after a short delay
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Postgres-only SQL lives behind these helpers so queries also run on the
+// SQLite database the tests use.
+
+// IsPostgres reports whether db talks to Postgres.
+func IsPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// likeEscaper makes LIKE treat the search term literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ContainsAny matches rows where any of columns contains s, ignoring case.
+// Postgres needs ILIKE for that; SQLite's LIKE already ignores case.
+func ContainsAny(db *gorm.DB, s string, columns ...string) clause.Expression {
+	return containsAny(IsPostgres(db), s, columns...)
+}
+
+func containsAny(postgres bool, s string, columns ...string) clause.Expression {
+	op := "LIKE"
+	if postgres {
+		op = "ILIKE"
+	}
+	pattern := "%" + likeEscaper.Replace(s) + "%"
+	conds := make([]string, len(columns))
+	vars := make([]any, len(columns))
+	for i, column := range columns {
+		conds[i] = column + " " + op + ` ? ESCAPE '\'`
+		vars[i] = pattern
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conds, " OR ") + ")", Vars: vars}
+}
+
+// PeriodStart is SQL for the first day, as YYYY-MM-DD in UTC, of the day,
+// week or month column falls in. Weeks start on Monday. Any other
+// granularity is taken as day.
+func PeriodStart(db *gorm.DB, granularity, column string) string {
+	if IsPostgres(db) {
+		unit := "day"
+		if granularity == "week" || granularity == "month" {
+			unit = granularity
+		}
+		return fmt.Sprintf("to_char(date_trunc('%s', %s AT TIME ZONE 'UTC'), 'YYYY-MM-DD')", unit, column)
+	}
+	switch granularity {
+	case "week":
+		// Forward to Sunday, unless it already is, then back to Monday
+		return fmt.Sprintf("date(%s, 'weekday 0', '-6 days')", column)
+	case "month":
+		return fmt.Sprintf("strftime('%%Y-%%m-01', %s)", column)
+	}
+	return fmt.Sprintf("date(%s)", column)
+}
+
+// HoursBetween is SQL for the hours from the start column to the end one.
+func HoursBetween(db *gorm.DB, start, end string) string {
+	if IsPostgres(db) {
+		return fmt.Sprintf("(EXTRACT(EPOCH FROM (%s - %s)) / 3600.0)", end, start)
+	}
+	return fmt.Sprintf("((julianday(%s) - julianday(%s)) * 24.0)", end, start)
+}
+
+// FirstPart is SQL for column up to its first sep, trimmed and in lower
+// case.
+func FirstPart(db *gorm.DB, column, sep string) string {
+	if IsPostgres(db) {
+		return fmt.Sprintf("lower(trim(split_part(%s, '%s', 1)))", column, sep)
+	}
+	return fmt.Sprintf("lower(trim(substr(%s, 1, instr(%s || '%s', '%s') - 1)))", column, column, sep, sep)
+}
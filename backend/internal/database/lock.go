@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// AdvisoryLock is a Postgres session advisory lock, held on a connection
+// of its own so it lasts until Release or until that connection dies.
+// Processes use it to agree on one of them doing something. On SQLite,
+// where there is only ever one process, it is always acquired.
+type AdvisoryLock struct {
+	db       *gorm.DB
+	key      int64
+	postgres bool
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(db *gorm.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key, postgres: IsPostgres(db)}
+}
+
+// TryAcquire takes the lock without waiting and reports whether this
+// process holds it. Calling it again while holding the lock checks that
+// the connection holding it is still alive.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	if !l.postgres {
+		return true, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// The lock went with the connection
+		l.conn.Close()
+		l.conn = nil
+	}
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil || !acquired {
+		conn.Close()
+		return false, err
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Acquire waits for the lock until ctx is done. Calling it while holding
+// the lock returns at once.
+func (l *AdvisoryLock) Acquire(ctx context.Context) error {
+	if !l.postgres {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return nil
+	}
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", l.key); err != nil {
+		conn.Close()
+		return err
+	}
+	l.conn = conn
+	return nil
+}
+
+// Release gives the lock up if this process holds it.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	closeErr := l.conn.Close()
+	l.conn = nil
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Full-text search. On Postgres every searchable table gets two generated
+// columns: search_vector, a weighted tsvector of the searched columns with
+// a GIN index, and search_text, the same columns as lower-case text with a
+// trigram index. Words match by prefix against search_vector, so results
+// show up as the user types, with trigram similarity on search_text to
+// forgive misspellings and substring matching for fragments of phone
+// numbers and emails. Names, addresses and Amharic text gain nothing from
+// English stemming, so the 'simple' configuration is used, and Amharic
+// letters that sound the same are folded together on both sides. SQLite,
+// which the tests use, matches every word by substring instead.
+//
+// MigrateSearch adds the columns and indexes once, in the startup
+// migration; a Search only looks at what is there, the first time it is
+// used.
+
+// SearchColumn is a column to search and its weight in the ranking, from
+// 'A', the highest, to 'D'.
+type SearchColumn struct {
+	Name   string
+	Weight byte
+}
+
+type Search struct {
+	db       *gorm.DB
+	table    string
+	columns  []SearchColumn
+	postgres bool
+	detected sync.Once
+	// ready is set once the generated columns exist, trigram once pg_trgm
+	// is installed too
+	ready   bool
+	trigram bool
+}
+
+// NewSearch sets up searching the columns of table. On Postgres it uses
+// the generated columns when MigrateSearch has added them, falling back to
+// substring matching otherwise, and similarity when pg_trgm is installed.
+// It looks at the schema on the first search rather than here, so it can
+// be built before the startup migration has run.
+func NewSearch(db *gorm.DB, table string, columns ...SearchColumn) *Search {
+	return &Search{db: db, table: table, columns: columns, postgres: IsPostgres(db)}
+}
+
+// detect finds out once which of the generated columns and pg_trgm are
+// there.
+func (s *Search) detect() {
+	if s.postgres {
+		s.detected.Do(func() { s.check(s.db) })
+	}
+}
+
+func (s *Search) check(db *gorm.DB) {
+	var columns, extensions int64
+	err := db.Raw("SELECT count(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name IN ('search_vector', 'search_text')",
+		s.table).Scan(&columns).Error
+	if err == nil {
+		err = db.Raw("SELECT count(*) FROM pg_extension WHERE extname = 'pg_trgm'").Scan(&extensions).Error
+	}
+	if err != nil {
+		slog.Warn("full-text search check failed, searching by substring", "table", s.table, "error", err)
+		return
+	}
+	s.ready = columns == 2
+	s.trigram = s.ready && extensions > 0
+	if !s.ready {
+		slog.Warn("full-text search columns missing, searching by substring", "table", s.table)
+	}
+}
+
+// MigrateSearch adds the generated columns and their indexes to table on
+// Postgres, leaving what already exists. Indexes are built without
+// blocking writes. Run it from one process at a time: a failure leaves
+// search matching by substring, or without similarity when only pg_trgm
+// is missing, and is only logged.
+func MigrateSearch(ctx context.Context, db *gorm.DB, table string, columns ...SearchColumn) {
+	if !IsPostgres(db) {
+		return
+	}
+	db = db.WithContext(ctx)
+	vector := make([]string, len(columns))
+	text := make([]string, len(columns))
+	for i, c := range columns {
+		folded := fmt.Sprintf("translate(coalesce(%s, ''), '%s', '%s')", c.Name, foldFrom, foldTo)
+		vector[i] = fmt.Sprintf("setweight(to_tsvector('simple', %s), '%c')", folded, c.Weight)
+		text[i] = folded
+	}
+	for _, stmt := range []string{
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED",
+			table, strings.Join(vector, " || ")),
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_text text GENERATED ALWAYS AS (lower(%s)) STORED",
+			table, strings.Join(text, " || ' ' || ")),
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			slog.Warn("full-text search setup failed, searching by substring", "table", table, "error", err)
+			return
+		}
+	}
+	if err := createIndex(db, "idx_"+table+"_search_vector", table, "GIN (search_vector)"); err != nil {
+		slog.Warn("full-text search index setup failed", "table", table, "error", err)
+	}
+	err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	if err == nil {
+		err = createIndex(db, "idx_"+table+"_search_text", table, "GIN (search_text gin_trgm_ops)")
+	}
+	if err != nil {
+		slog.Warn("trigram index setup failed, searching without similarity", "table", table, "error", err)
+	}
+}
+
+// createIndex builds an index concurrently unless a valid one exists. A
+// concurrent build that failed leaves an invalid index behind, which is
+// dropped and built again.
+func createIndex(db *gorm.DB, name, table, using string) error {
+	var valid []bool
+	if err := db.Raw("SELECT indisvalid FROM pg_index WHERE indexrelid = to_regclass(?)", name).Scan(&valid).Error; err != nil {
+		return err
+	}
+	if len(valid) > 0 {
+		if valid[0] {
+			return nil
+		}
+		if err := db.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", name)).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec(fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s USING %s", name, table, using)).Error
+}
+
+// Match matches the rows containing every word of q: by prefix, by
+// similarity or as a substring of the searched columns.
+func (s *Search) Match(q string) clause.Expression {
+	s.detect()
+	if !s.ready {
+		words := strings.Fields(q)
+		if len(words) == 0 {
+			words = []string{q}
+		}
+		names := make([]string, len(s.columns))
+		for i, c := range s.columns {
+			names[i] = c.Name
+		}
+		conds := make([]clause.Expression, len(words))
+		for i, word := range words {
+			conds[i] = containsAny(s.postgres, word, names...)
+		}
+		return clause.And(conds...)
+	}
+	q = fold(strings.ToLower(q))
+	conds := []string{`search_text LIKE ? ESCAPE '\'`}
+	vars := []any{"%" + likeEscaper.Replace(q) + "%"}
+	if tsquery := prefixQuery(q); tsquery != "" {
+		conds = append(conds, "search_vector @@ to_tsquery('simple', ?)")
+		vars = append(vars, tsquery)
+	}
+	if s.trigram {
+		conds = append(conds, "? <% search_text")
+		vars = append(vars, q)
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conds, " OR ") + ")", Vars: vars}
+}
+
+// Rank orders query by how well rows match q, best first: by the weighted
+// rank of the words, then by similarity. Without the generated columns, as
+// on SQLite, query is left as it is. Use it instead of another order, not
+// after one: gorm keeps only the last ORDER BY with parameters.
+func (s *Search) Rank(query *gorm.DB, q string) *gorm.DB {
+	s.detect()
+	if !s.ready {
+		return query
+	}
+	q = fold(strings.ToLower(q))
+	var orders []string
+	var vars []any
+	if tsquery := prefixQuery(q); tsquery != "" {
+		orders = append(orders, "ts_rank(search_vector, to_tsquery('simple', ?)) DESC")
+		vars = append(vars, tsquery)
+	}
+	if s.trigram {
+		orders = append(orders, "word_similarity(?, search_text) DESC")
+		vars = append(vars, q)
+	}
+	if len(orders) == 0 {
+		return query
+	}
+	return query.Order(clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(orders, ", "), Vars: vars}})
+}
+
+// prefixQuery is a tsquery matching every word of q by prefix, "" when q
+// has no words. Anything but letters and digits separates words, which
+// also keeps tsquery operators out.
+func prefixQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// Amharic writes some sounds with more than one letter, e.g. ሀ, ሐ and ኀ
+// for h, and people spell names with whichever they are used to. Each
+// family is folded into its most common letter, in all seven vowel orders.
+// The fourth order of ሀ and አ sounds like the first and is folded too.
+var foldFrom, foldTo string
+
+var folds = map[rune]rune{}
+
+func init() {
+	families := []struct {
+		to   rune
+		from []rune
+	}{
+		{'ሀ', []rune{'ሐ', 'ኀ'}},
+		{'ሰ', []rune{'ሠ'}},
+		{'አ', []rune{'ዐ'}},
+		{'ጸ', []rune{'ፀ'}},
+	}
+	for _, f := range families {
+		for order := rune(0); order < 7; order++ {
+			for _, from := range f.from {
+				folds[from+order] = f.to + order
+			}
+		}
+	}
+	for _, first := range []rune{'ሀ', 'አ'} {
+		folds[first+3] = first
+	}
+	// Letters folded into a fourth order have to end up in the first
+	for from, to := range folds {
+		if next, ok := folds[to]; ok {
+			folds[from] = next
+		}
+	}
+	var from, to strings.Builder
+	for _, f := range slices.Sorted(maps.Keys(folds)) {
+		from.WriteRune(f)
+		to.WriteRune(folds[f])
+	}
+	foldFrom, foldTo = from.String(), to.String()
+}
+
+// fold rewrites the letters of s the way the generated columns do.
+func fold(s string) string {
+	return strings.Map(func(r rune) rune {
+		if to, ok := folds[r]; ok {
+			return to
+		}
+		return r
+	}, s)
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -19,6 +21,46 @@ type Admin struct {
 	Password string `json:"-" binding:"required"`
 	Role     string `json:"role" binding:"required" validate:"oneof=admin superadmin"`
 	Name     string `json:"name" binding:"required"`
+	// Email and PhoneNumber are where password reset links are delivered.
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	// MustChangePassword is set on seeded and reset accounts. Until it is
+	// cleared the admin can only reach the change-password endpoint.
+	MustChangePassword bool `json:"must_change_password"`
+}
+
+// PasswordHistory keeps previous password hashes so they can't be reused.
+type PasswordHistory struct {
+	Model
+	AdminID uint   `json:"admin_id" gorm:"index"`
+	Hash    string `json:"-"`
+}
+
+// JobSendPasswordReset sends a forgot-password link in the background.
+const JobSendPasswordReset = "admins.send_password_reset"
+
+type SendPasswordResetPayload struct {
+	Username string `json:"username"`
+}
+
+// PasswordResetToken is a one-time token for the forgot-password flow. Only
+// the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	Model
+	AdminID   uint       `json:"admin_id" gorm:"index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	GetByHash(ctx context.Context, hash string) (*PasswordResetToken, error)
+	// Claim marks the token as used if it still is unused and unexpired at
+	// now, reporting whether it was.
+	Claim(ctx context.Context, id uint, now time.Time) (bool, error)
+	// InvalidateForAdmin marks every outstanding token of the admin as used.
+	InvalidateForAdmin(ctx context.Context, adminID uint) error
 }
 
 func (a *Admin) BeforeCreate(tx *gorm.DB) (err error) {
@@ -27,10 +69,18 @@ func (a *Admin) BeforeCreate(tx *gorm.DB) (err error) {
 		return err
 	}
 	a.Password = string(hashedPassword)
-	log.Println(a)
 	return nil
 }
 
+// LogValue keeps the password hash and contact details out of logs.
+func (a Admin) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Uint64("id", uint64(a.ID)),
+		slog.String("username", a.Username),
+		slog.String("role", a.Role),
+	)
+}
+
 type AdminFilter struct {
 	Page      int    `json:"page"`
 	Limit     int    `json:"limit"`
@@ -47,22 +97,29 @@ type MultipleAdmins struct {
 	Pagination Pagination `json:"meta"`
 }
 type AdminRepository interface {
-	Create(admin *Admin) (*Admin, error)
-	GetByID(id uint) (*Admin, error)
-	GetByUsername(username string) (*Admin, error)
-	GetAll(f *AdminFilter) (*MultipleAdmins, error)
-	Update(admin *Admin) (*Admin, error)
-	Delete(id uint) error
+	Create(ctx context.Context, admin *Admin) (*Admin, error)
+	GetByID(ctx context.Context, id uint) (*Admin, error)
+	GetByUsername(ctx context.Context, username string) (*Admin, error)
+	GetAll(ctx context.Context, f *AdminFilter) (*MultipleAdmins, error)
+	Update(ctx context.Context, admin *Admin) (*Admin, error)
+	Delete(ctx context.Context, id uint) error
+	AddPasswordHistory(ctx context.Context, history *PasswordHistory) error
+	GetPasswordHistory(ctx context.Context, adminID uint, limit int) ([]PasswordHistory, error)
 }
 
 type AdminUsecase interface {
-	Create(admin *Admin) (*Admin, error)
-	GetByID(id uint) (*Admin, error)
-	GetByUsername(username string) (*Admin, error)
-	GetAll(f *AdminFilter) (*MultipleAdmins, error)
-	Update(admin *Admin) (*Admin, error)
-	Delete(id uint) error
-	ResetPassword(id uint, newPassword string) error
-	ChangePassword(id uint, oldPassword, newPassword string) error
-	Login(username, password string) (*Admin, error)
+	Create(ctx context.Context, admin *Admin) (*Admin, error)
+	GetByID(ctx context.Context, id uint) (*Admin, error)
+	GetByUsername(ctx context.Context, username string) (*Admin, error)
+	GetAll(ctx context.Context, f *AdminFilter) (*MultipleAdmins, error)
+	Update(ctx context.Context, id uint, req *UpdateAdminRequest) (*Admin, error)
+	Delete(ctx context.Context, id uint) error
+	ResetPassword(ctx context.Context, id uint, newPassword string) error
+	ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error
+	Login(ctx context.Context, username, password string) (*Admin, error)
+	// RequestPasswordReset queues SendPasswordReset, so the caller can't
+	// tell from the answer or its timing whether the username exists.
+	RequestPasswordReset(ctx context.Context, username string) error
+	SendPasswordReset(ctx context.Context, username string) error
+	ResetPasswordWithToken(ctx context.Context, token, newPassword string) error
 }
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Bucket sizes of an analytics time series. Weeks start on Monday.
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// What bookings can be broken down by
+const (
+	DimensionGrade  = "grade"
+	DimensionGender = "gender"
+	// DimensionArea is the first part of the address, before any comma
+	DimensionArea = "area"
+)
+
+// AnalyticsQuery selects the days a report covers and how they are
+// grouped. From and To are whole days in UTC, both included.
+type AnalyticsQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity string
+}
+
+// AnalyticsRange echoes the query a report answers.
+type AnalyticsRange struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Granularity string `json:"granularity"`
+}
+
+// swagger:model AnalyticsTotals
+type AnalyticsTotals struct {
+	Bookings      int64 `json:"bookings"`
+	Tutors        int64 `json:"tutors"`
+	Partners      int64 `json:"partners"`
+	Testimonials  int64 `json:"testimonials"`
+	OtherServices int64 `json:"other_services"`
+}
+
+// swagger:model AnalyticsSnapshot
+// AnalyticsSnapshot is the totals as they were at ComputedAt. Version
+// only changes when the totals do, so it serves as their ETag.
+type AnalyticsSnapshot struct {
+	Totals     AnalyticsTotals `json:"totals"`
+	ComputedAt time.Time       `json:"computed_at"`
+	Version    string          `json:"-"`
+}
+
+// BookingPoint counts the bookings made in one period. Period is the
+// period's first day, as YYYY-MM-DD.
+type BookingPoint struct {
+	Period   string `json:"period"`
+	Bookings int64  `json:"bookings"`
+	Assigned int64  `json:"assigned"`
+}
+
+// swagger:model BookingTrend
+type BookingTrend struct {
+	AnalyticsRange
+	Points []BookingPoint `json:"points"`
+}
+
+// TutorPoint counts the tutors registered and the tutors verified in one
+// period.
+type TutorPoint struct {
+	Period     string `json:"period"`
+	Registered int64  `json:"registered"`
+	Verified   int64  `json:"verified"`
+}
+
+// swagger:model TutorTrend
+type TutorTrend struct {
+	AnalyticsRange
+	Points []TutorPoint `json:"points"`
+}
+
+// HistogramBucket counts the waits of at least MinHours and less than
+// MaxHours; the last bucket has no MaxHours.
+type HistogramBucket struct {
+	Label    string   `json:"label"`
+	MinHours float64  `json:"min_hours"`
+	MaxHours *float64 `json:"max_hours,omitempty"`
+	Count    int64    `json:"count"`
+}
+
+// DurationPoint is the average wait of what finished waiting in one period.
+type DurationPoint struct {
+	Period   string  `json:"period"`
+	Count    int64   `json:"count"`
+	AvgHours float64 `json:"avg_hours"`
+}
+
+// swagger:model DurationReport
+// DurationReport describes how long bookings waited for a tutor, or tutors
+// for verification, counting from when they were submitted.
+type DurationReport struct {
+	AnalyticsRange
+	Count     int64             `json:"count"`
+	AvgHours  float64           `json:"avg_hours"`
+	MinHours  float64           `json:"min_hours"`
+	MaxHours  float64           `json:"max_hours"`
+	Histogram []HistogramBucket `json:"histogram"`
+	Points    []DurationPoint   `json:"points"`
+}
+
+// GroupCount is how many bookings share a value of the dimension.
+type GroupCount struct {
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// GroupPoint is GroupCount within one period.
+type GroupPoint struct {
+	Period string `json:"period"`
+	Key    string `json:"key"`
+	Count  int64  `json:"count"`
+}
+
+// swagger:model BookingBreakdown
+type BookingBreakdown struct {
+	AnalyticsRange
+	Dimension string       `json:"dimension"`
+	Totals    []GroupCount `json:"totals"`
+	Points    []GroupPoint `json:"points"`
+}
+
+// AnalyticsRepository computes the reports with SQL aggregates. Periods
+// nothing happened in are left out of the points.
+type AnalyticsRepository interface {
+	Totals(ctx context.Context) (*AnalyticsTotals, error)
+	Bookings(ctx context.Context, q *AnalyticsQuery) ([]BookingPoint, error)
+	Tutors(ctx context.Context, q *AnalyticsQuery) ([]TutorPoint, error)
+	TimeToAssignment(ctx context.Context, q *AnalyticsQuery) (*DurationReport, error)
+	TimeToVerification(ctx context.Context, q *AnalyticsQuery) (*DurationReport, error)
+	BookingBreakdown(ctx context.Context, q *AnalyticsQuery, dimension string) ([]GroupPoint, error)
+}
+
+// AnalyticsUsecase fills in the query's defaults and every period of the
+// range. Quarantined and deleted records are left out.
+type AnalyticsUsecase interface {
+	// Totals returns a cached snapshot, computed again once it is older
+	// than its TTL or has been invalidated
+	Totals(ctx context.Context) (*AnalyticsSnapshot, error)
+	// Invalidate marks the snapshot stale after a write
+	Invalidate()
+	Bookings(ctx context.Context, q *AnalyticsQuery) (*BookingTrend, error)
+	Tutors(ctx context.Context, q *AnalyticsQuery) (*TutorTrend, error)
+	TimeToAssignment(ctx context.Context, q *AnalyticsQuery) (*DurationReport, error)
+	TimeToVerification(ctx context.Context, q *AnalyticsQuery) (*DurationReport, error)
+	BookingBreakdown(ctx context.Context, q *AnalyticsQuery, dimension string) (*BookingBreakdown, error)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Entity types recorded in the audit trail
+const (
+	AuditEntityAdmin        = "admin"
+	AuditEntityBooking      = "booking"
+	AuditEntityTutor        = "tutor"
+	AuditEntityPartner      = "partner"
+	AuditEntityTestimonial  = "testimonial"
+	AuditEntityOtherService = "other_service"
+)
+
+// ErrAuditLogImmutable is returned when something tries to change or remove
+// an audit entry.
+var ErrAuditLogImmutable = errors.New("audit log entries are append-only")
+
+// FieldChange is the before and after value of a single field.
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// swagger:model AuditLog
+// AuditLog records one administrative mutation. Entries are never updated
+// or deleted, so it doesn't embed Model.
+type AuditLog struct {
+	ID            uint                   `json:"id" gorm:"primaryKey"`
+	CreatedAt     time.Time              `json:"created_at" gorm:"autoCreateTime;index"`
+	ActorID       uint                   `json:"actor_id" gorm:"index"`
+	ActorUsername string                 `json:"actor_username"`
+	ActorRole     string                 `json:"actor_role"`
+	Action        string                 `json:"action" gorm:"index"`
+	EntityType    string                 `json:"entity_type" gorm:"index:idx_audit_entity"`
+	EntityID      uint                   `json:"entity_id" gorm:"index:idx_audit_entity"`
+	Changes       map[string]FieldChange `json:"changes" gorm:"serializer:json;type:text"`
+	IPAddress     string                 `json:"ip_address"`
+}
+
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error { return ErrAuditLogImmutable }
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error { return ErrAuditLogImmutable }
+
+type AuditFilter struct {
+	ActorID    uint
+	Action     string
+	EntityType string
+	EntityID   uint
+	From       time.Time
+	To         time.Time
+	// Pagination
+	Page  int
+	Limit int
+}
+
+// swagger:model MultipleAuditLogs
+type MultipleAuditLogs struct {
+	Data       []AuditLog `json:"data"`
+	Pagination Pagination `json:"meta"`
+}
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditLog) error
+	GetAll(ctx context.Context, f *AuditFilter) (*MultipleAuditLogs, error)
+	// Each streams every matching entry, oldest first, without loading the
+	// whole result set into memory.
+	Each(ctx context.Context, f *AuditFilter, fn func(*AuditLog) error) error
+}
+
+type AuditUsecase interface {
+	// Record stores entry with the field level difference between before
+	// and after. Either snapshot may be nil for creates and deletes.
+	Record(ctx context.Context, entry *AuditLog, before, after any) error
+	GetAll(ctx context.Context, f *AuditFilter) (*MultipleAuditLogs, error)
+	Export(ctx context.Context, f *AuditFilter, fn func(*AuditLog) error) error
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type Booking struct {
 	Model
 	FirstName   string `json:"first_name"`
@@ -7,11 +12,22 @@ type Booking struct {
 	Gender      string `json:"gender"`
 	Grade       int    `json:"grade"`
 	Address     string `json:"address"`
-	PhoneNumber string `json:"phone_number"`
+	PhoneNumber string `json:"phone_number" gorm:"index"`
 	DayPerWeek  int    `json:"day_per_week"`
 	HrPerDay    int    `json:"hr_per_day"`
 	Assigned    bool   `json:"assigned"`
 	Age         int    `json:"age"`
+	TutorID     *uint  `json:"tutor_id,omitempty" gorm:"index"`
+	// Language is the parent's language for notifications, e.g. "en" or
+	// "am"; the default language is used when it is empty
+	Language string `json:"language,omitempty"`
+	// AssignedAt is when the booking was last marked assigned
+	AssignedAt *time.Time `json:"assigned_at,omitempty" gorm:"index"`
+
+	// Suspicious public submissions are held for review and left out of
+	// the default list until an admin releases them
+	Quarantined      bool   `json:"quarantined" gorm:"index"`
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
 }
 
 type BookingFilter struct {
@@ -25,6 +41,9 @@ type BookingFilter struct {
 	MaxDayPerWeek int
 	MinHrPerDay   int
 	MaxHrPerDay   int
+	Quarantined   bool
+	// Deleted lists the trash instead
+	Deleted bool
 	// Pagination & sorting
 	Page      int
 	Limit     int
@@ -32,19 +51,39 @@ type BookingFilter struct {
 	SortOrder string
 }
 type BookingRepository interface {
-	Create(*Booking) (*Booking, error)
-	GetAll(*BookingFilter) (MultipleBookingResponse, error)
-	GetByID(uint) (*Booking, error)
-	Update(uint, *Booking) (*Booking, error)
-	Delete(uint) error
+	Create(context.Context, *Booking) (*Booking, error)
+	GetAll(context.Context, *BookingFilter) (MultipleBookingResponse, error)
+	// CreateMany adds all the bookings or, on error, none of them. Unless
+	// it is nil, check is called with each booking first, inside the
+	// transaction and with the table locked against other writers; repo
+	// reads within the transaction. An error from check rolls back.
+	CreateMany(ctx context.Context, bookings []*Booking, check func(repo BookingRepository, b *Booking) error) error
+	// Each streams every matching booking, ignoring paging and sorting.
+	Each(ctx context.Context, filter *BookingFilter, fn func(*Booking) error) error
+	GetByID(context.Context, uint) (*Booking, error)
+	Update(context.Context, uint, *Booking) (*Booking, error)
+	Delete(context.Context, uint) error
+	// GetByPhoneNumber returns the bookings with the given normalized number.
+	GetByPhoneNumber(ctx context.Context, phone string) ([]Booking, error)
+	// Release clears the quarantine flag.
+	Release(ctx context.Context, id uint) error
+	// Bulk applies fn to the bookings with the given IDs, or else those
+	// matching filter, and saves the changed ones in one transaction.
+	Bulk(ctx context.Context, ids []uint, filter *BookingFilter, fn BulkFunc[Booking]) (*BulkResult, error)
 }
 type BookingUsecase interface {
-	Create(*Booking) (*Booking, error)
-	GetAll(*BookingFilter) (MultipleBookingResponse, error)
-	GetByID(uint) (*Booking, error)
-	Update(uint, *Booking) (*Booking, error)
-	Delete(uint) error
-	Assign(uint) error
+	Create(context.Context, *Booking) (*Booking, error)
+	GetAll(context.Context, *BookingFilter) (MultipleBookingResponse, error)
+	GetByID(context.Context, uint) (*Booking, error)
+	Update(context.Context, uint, *Booking) (*Booking, error)
+	Delete(context.Context, uint) error
+	Assign(ctx context.Context, id uint, tutorID uint) error
+	// Release lets a quarantined booking through as if it had just been
+	// submitted.
+	Release(ctx context.Context, id uint) error
+	// Bulk applies one action to many bookings at once. Bookings it fails
+	// for are reported in the result and left as they were.
+	Bulk(ctx context.Context, req *BookingBulkRequest) (*BulkResult, error)
 }
 
 type MultipleBookingResponse struct {
@@ -0,0 +1,110 @@
+package domain
+
+// Bulk actions. Verify and unverify apply to tutors, assign and status to
+// bookings, delete and restore to both.
+const (
+	BulkVerify   = "verify"
+	BulkUnverify = "unverify"
+	BulkAssign   = "assign"
+	BulkStatus   = "status"
+	BulkDelete   = "delete"
+	BulkRestore  = "restore"
+)
+
+// Booking statuses the status bulk action can set
+const (
+	BookingStatusPending  = "pending"
+	BookingStatusAssigned = "assigned"
+)
+
+// MaxBulkItems caps how many records one bulk action may touch, whether
+// picked by ID or by filter.
+const MaxBulkItems = 1000
+
+// Outcome of a bulk action on one record
+const (
+	BulkItemUpdated   = "updated"
+	BulkItemUnchanged = "unchanged"
+	BulkItemFailed    = "failed"
+)
+
+// swagger:model BulkActionRequest
+// BulkActionRequest picks records either by ID or with a filter written
+// like the query string of the matching list endpoint, e.g.
+// "verified=true&education_level=BSc". Add "deleted=true" to pick from
+// the trash.
+type BulkActionRequest struct {
+	Action string `json:"action" binding:"required"`
+	IDs    []uint `json:"ids,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	// TutorID is the tutor to record on bookings being assigned
+	TutorID uint `json:"tutor_id,omitempty"`
+	// Status is pending or assigned, for the status action
+	Status string `json:"status,omitempty"`
+}
+
+type BookingBulkRequest struct {
+	Action  string
+	IDs     []uint
+	Filter  *BookingFilter
+	TutorID uint
+	Status  string
+}
+
+type TutorBulkRequest struct {
+	Action string
+	IDs    []uint
+	Filter *TutorFilter
+}
+
+// swagger:model BulkItem
+type BulkItem struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	// Error says why the action failed for this record
+	Error string `json:"error,omitempty"`
+	// Before and After are the record either side of the change, kept for
+	// the audit trail on updated items only
+	Before any `json:"-"`
+	After  any `json:"-"`
+}
+
+// swagger:model BulkResult
+// BulkResult reports a bulk action record by record. The records it could
+// be applied to are saved together; the failed ones are left as they were.
+type BulkResult struct {
+	Action    string     `json:"action"`
+	Matched   int        `json:"matched"`
+	Updated   int        `json:"updated"`
+	Unchanged int        `json:"unchanged"`
+	Failed    int        `json:"failed"`
+	Items     []BulkItem `json:"items"`
+}
+
+// Add records the outcome for one record: err if fn failed for it, else
+// whether it changed.
+func (r *BulkResult) Add(id uint, changed bool, err error) {
+	r.AddChange(id, changed, err, nil, nil)
+}
+
+// AddChange is Add keeping the record before and after an update.
+func (r *BulkResult) AddChange(id uint, changed bool, err error, before, after any) {
+	item := BulkItem{ID: id, Status: BulkItemUnchanged}
+	switch {
+	case err != nil:
+		item.Status = BulkItemFailed
+		item.Error = err.Error()
+		r.Failed++
+	case changed:
+		item.Status = BulkItemUpdated
+		item.Before, item.After = before, after
+		r.Updated++
+	default:
+		r.Unchanged++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// BulkFunc applies a bulk action to one record in memory, reporting
+// whether it changed anything. An error fails just that record.
+type BulkFunc[T any] func(*T) (changed bool, err error)
@@ -33,3 +33,58 @@ var (
 	ErrInvalidSortOrder    = errors.New("invalid sort order")
 	ErrInvalidID           = errors.New("invalid ID")
 )
+
+// Password policy errors
+var (
+	ErrPasswordTooShort       = errors.New("password is too short")
+	ErrPasswordTooWeak        = errors.New("password is missing required character types")
+	ErrPasswordCommon         = errors.New("password is too common or known to be breached")
+	ErrPasswordReused         = errors.New("password was used recently")
+	ErrPasswordChangeRequired = errors.New("password change required")
+	ErrResetTokenInvalid      = errors.New("password reset token is invalid or expired")
+)
+
+// Phone number errors
+var (
+	ErrInvalidPhoneNumber = errors.New("invalid phone number")
+	ErrDuplicateBooking   = errors.New("an open booking with this phone number already exists")
+	ErrDuplicateTutor     = errors.New("a tutor with this phone number is already registered")
+)
+
+// Job queue errors
+var (
+	ErrUnknownJobType  = errors.New("no handler is registered for this job type")
+	ErrJobNotRetryable = errors.New("only dead or pending jobs can be retried")
+)
+
+// Analytics errors
+var (
+	ErrInvalidGranularity = errors.New("granularity must be day, week or month")
+	ErrInvalidDimension   = errors.New("dimension must be grade, gender or area")
+	ErrRangeTooLarge      = errors.New("date range has too many periods for this granularity")
+)
+
+// Export errors
+var (
+	ErrUnknownExport  = errors.New("entity must be bookings or tutors")
+	ErrInvalidFormat  = errors.New("format must be csv or xlsx")
+	ErrUnknownColumn  = errors.New("unknown column")
+	ErrExportNotReady = errors.New("export is not ready yet")
+)
+
+// Import errors
+var (
+	ErrMissingColumn  = errors.New("missing required column")
+	ErrEmptyImport    = errors.New("the sheet has no rows to import")
+	ErrTooManyRows    = errors.New("the sheet has too many rows")
+	ErrImportRejected = errors.New("some rows are invalid, nothing was imported")
+)
+
+// Bulk action errors
+var (
+	ErrUnknownAction = errors.New("unknown bulk action")
+	ErrInvalidStatus = errors.New("status must be pending or assigned")
+	ErrNoSelection   = errors.New("either ids or a filter is required")
+	ErrTooManyItems  = errors.New("too many records selected")
+	ErrInTrash       = errors.New("record is in the trash")
+)
@@ -0,0 +1,41 @@
+package domain
+
+// Event types published by the usecases
+const (
+	EventBookingCreated  = "booking.created"
+	EventBookingAssigned = "booking.assigned"
+	EventTutorRegistered = "tutor.registered"
+	EventTutorVerified   = "tutor.verified"
+
+	// Digests sent by the scheduled tasks
+	EventBookingsUnassigned     = "bookings.unassigned"
+	EventTutorDocumentsExpiring = "tutors.documents_expiring"
+)
+
+// Contact is who an event is about, when that person should hear about it
+// directly (e.g. the tutor that was verified).
+type Contact struct {
+	Name     string
+	Email    string
+	Phone    string
+	Language string
+}
+
+// Event is something that happened which people may need to be told about.
+// Data is made available to the notification templates.
+type Event struct {
+	Type    string
+	Subject *Contact
+	Data    map[string]any
+}
+
+// EventPublisher hands events to whoever is interested. Publish must not
+// block the caller on delivery.
+type EventPublisher interface {
+	Publish(event Event)
+}
+
+// NopPublisher discards every event.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(Event) {}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// What can be exported
+const (
+	ExportBookings = "bookings"
+	ExportTutors   = "tutors"
+)
+
+// Spreadsheet formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Stages of an asynchronous export
+const (
+	ExportStatusPending = "pending"
+	ExportStatusRunning = "running"
+	ExportStatusReady   = "ready"
+	ExportStatusFailed  = "failed"
+)
+
+// JobGenerateExport writes an asynchronous export's file.
+const JobGenerateExport = "exports.generate"
+
+type GenerateExportPayload struct {
+	ExportID uint `json:"export_id"`
+}
+
+// ExportRequest says what to export and how. Only the filter of Entity is
+// used; its paging and sorting are ignored and rows come in ID order.
+type ExportRequest struct {
+	Entity string
+	Format string
+	// Columns to include, in order; empty means all of them
+	Columns  []string
+	Bookings *BookingFilter
+	Tutors   *TutorFilter
+	// RequestedBy is the admin asking, the only one besides superadmins
+	// who may download an asynchronous export
+	RequestedBy uint
+}
+
+// swagger:model Export
+// Export is an export produced in the background, kept until ExpiresAt.
+type Export struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Entity      string     `json:"entity"`
+	Format      string     `json:"format"`
+	Columns     []string   `json:"columns" gorm:"serializer:json;type:text"`
+	Filter      string     `json:"-" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"index"`
+	Rows        int        `json:"rows"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	File        string     `json:"-"`
+	RequestedBy uint       `json:"requested_by" gorm:"index"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+}
+
+type ExportRepository interface {
+	Create(ctx context.Context, e *Export) error
+	Update(ctx context.Context, e *Export) error
+	// GetByID returns ErrNotFound for unknown exports.
+	GetByID(ctx context.Context, id uint) (*Export, error)
+}
+
+type ExportUsecase interface {
+	// Large reports whether req matches too many rows to stream right
+	// away, after checking its entity, format and columns
+	Large(ctx context.Context, req *ExportRequest) (bool, error)
+	// Write streams the matching rows to w
+	Write(ctx context.Context, req *ExportRequest, w io.Writer) error
+	// Start queues an asynchronous export
+	Start(ctx context.Context, req *ExportRequest) (*Export, error)
+	// Generate writes the file of a queued export; the job queue calls it
+	Generate(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*Export, error)
+	// Open returns a ready export's file, ErrExportNotReady before that
+	// and ErrNotFound once it has expired
+	Open(ctx context.Context, id uint) (*Export, io.ReadCloser, error)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// Bookings are for school grades MinGrade to MaxGrade
+const (
+	MinGrade = 1
+	MaxGrade = 12
+)
+
+// ImportRequest is a spreadsheet of bookings or tutors to add. Nothing is
+// saved on a dry run.
+type ImportRequest struct {
+	Entity string
+	Format string
+	File   io.Reader
+	DryRun bool
+}
+
+// swagger:model ImportRowError
+// ImportRowError lists what is wrong with one row of an import.
+type ImportRowError struct {
+	// Row is the spreadsheet row, the header being row 1
+	Row    int                `json:"row"`
+	Errors []ImportFieldError `json:"errors"`
+}
+
+type ImportFieldError struct {
+	// Column is empty for problems with the row as a whole
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// swagger:model ImportReport
+// ImportReport says how an import went. An import with errors saves
+// nothing, so it can be fixed and uploaded again.
+type ImportReport struct {
+	Entity   string           `json:"entity"`
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+type ImportUsecase interface {
+	// Import checks every row of the sheet and, unless it is a dry run,
+	// saves them all in one transaction. With invalid rows the report
+	// comes back along with ErrImportRejected.
+	Import(ctx context.Context, req *ImportRequest) (*ImportReport, error)
+}
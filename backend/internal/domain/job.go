@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// States of a background job. A job that fails is put back as pending
+// with a later RunAt until it runs out of attempts and is dead.
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusDead    = "dead"
+)
+
+// Job types handled by the workers
+const (
+	JobRemoveFile = "files.remove"
+)
+
+// RemoveFilePayload names an upload to delete, as returned by FileStorage.Save.
+type RemoveFilePayload struct {
+	Path string `json:"path"`
+}
+
+// swagger:model Job
+// Job is one unit of background work. Payload is the JSON the handler for
+// Type decodes.
+type Job struct {
+	Model
+	Type        string     `json:"type" gorm:"index"`
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Status      string     `json:"status" gorm:"index:idx_jobs_due,priority:1"`
+	RunAt       time.Time  `json:"run_at" gorm:"index:idx_jobs_due,priority:2"`
+	Attempts    int        `json:"attempts"`
+	MaxAttempts int        `json:"max_attempts"`
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+	LockedBy    string     `json:"locked_by,omitempty"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+type JobFilter struct {
+	Status string
+	Type   string
+	// Pagination
+	Page  int
+	Limit int
+}
+
+// swagger:model MultipleJobs
+type MultipleJobs struct {
+	Data       []Job      `json:"data"`
+	Pagination Pagination `json:"meta"`
+}
+
+type JobRepository interface {
+	Create(ctx context.Context, job *Job) error
+	Update(ctx context.Context, job *Job) error
+	GetByID(ctx context.Context, id uint) (*Job, error)
+	GetAll(ctx context.Context, f *JobFilter) (*MultipleJobs, error)
+	// Claim marks up to limit pending jobs whose RunAt has come as running
+	// by worker and returns them. Jobs another worker is claiming at the
+	// same moment are skipped rather than waited for.
+	Claim(ctx context.Context, worker string, now time.Time, limit int) ([]Job, error)
+	// Requeue makes a pending job due at now, or puts a dead one back as
+	// pending with fresh attempts, in a single statement so it can't race
+	// a worker claiming the job. It reports false for jobs in any other
+	// state.
+	Requeue(ctx context.Context, id uint, now time.Time) (bool, error)
+	// Finish records the outcome of a job, its status, error, RunAt and
+	// FinishedAt, and unlocks it, but only while worker still holds it. It
+	// reports false when the job was released as stale in the meantime and
+	// may belong to another worker now.
+	Finish(ctx context.Context, job *Job, worker string) (bool, error)
+	// Release puts running jobs locked before staleBefore back as pending,
+	// for workers that died halfway, and returns how many it found.
+	Release(ctx context.Context, staleBefore time.Time) (int64, error)
+}
+
+// JobEnqueuer hands work to the background workers.
+type JobEnqueuer interface {
+	// Enqueue stores a job of jobType that runs at runAt with payload
+	// encoded as JSON. A zero runAt means right away.
+	Enqueue(ctx context.Context, jobType string, payload any, runAt time.Time) (*Job, error)
+}
+
+type JobUsecase interface {
+	GetAll(ctx context.Context, f *JobFilter) (*MultipleJobs, error)
+	GetByID(ctx context.Context, id uint) (*Job, error)
+	// Retry queues a dead job again with fresh attempts.
+	Retry(ctx context.Context, id uint) (*Job, error)
+}
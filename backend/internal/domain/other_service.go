@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 //go:generate moq -out other_service_mock.go . OtherServiceUsecase OtherServiceRepository
 type OtherService struct {
 	Model
@@ -32,18 +34,18 @@ type MultipleOtherServices struct {
 }
 
 type OtherServiceRepository interface {
-	Create(service *OtherService) (*OtherService, error)
-	GetByID(id uint, languageCodes []string) (*OtherService, error)
-	GetAll(filter *ServiceFilter) (*MultipleOtherServices, error)
-	Update(service *OtherService) (*OtherService, error)
-	Delete(id uint) error
-	AddTranslation(translation *OtherServiceTranslation) error
+	Create(ctx context.Context, service *OtherService) (*OtherService, error)
+	GetByID(ctx context.Context, id uint, languageCodes []string) (*OtherService, error)
+	GetAll(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error)
+	Update(ctx context.Context, service *OtherService) (*OtherService, error)
+	Delete(ctx context.Context, id uint) error
+	AddTranslation(ctx context.Context, translation *OtherServiceTranslation) error
 }
 type OtherServiceUsecase interface {
-	CreateService(service *OtherService) (*OtherService, error)
-	GetAllServices(filter *ServiceFilter) (*MultipleOtherServices, error)
-	GetServiceByID(id uint, languageCodes []string) (*OtherService, error)
-	DeleteService(id uint) error
-	UpdateService(service *OtherService) (*OtherService, error)
-	AddTranslation(serviceID uint, translation *OtherServiceTranslation) (*OtherService, error)
+	CreateService(ctx context.Context, service *OtherService) (*OtherService, error)
+	GetAllServices(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error)
+	GetServiceByID(ctx context.Context, id uint, languageCodes []string) (*OtherService, error)
+	DeleteService(ctx context.Context, id uint) error
+	UpdateService(ctx context.Context, service *OtherService) (*OtherService, error)
+	AddTranslation(ctx context.Context, serviceID uint, translation *OtherServiceTranslation) (*OtherService, error)
 }
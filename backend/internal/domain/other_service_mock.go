@@ -4,6 +4,7 @@
 package domain
 
 import (
+	"context"
 	"sync"
 )
 
@@ -17,22 +18,22 @@ var _ OtherServiceUsecase = &OtherServiceUsecaseMock{}
 //
 //		// make and configure a mocked OtherServiceUsecase
 //		mockedOtherServiceUsecase := &OtherServiceUsecaseMock{
-//			AddTranslationFunc: func(serviceID uint, translation *OtherServiceTranslation) (*OtherService, error) {
+//			AddTranslationFunc: func(ctx context.Context, serviceID uint, translation *OtherServiceTranslation) (*OtherService, error) {
 //				panic("mock out the AddTranslation method")
 //			},
-//			CreateServiceFunc: func(service *OtherService) (*OtherService, error) {
+//			CreateServiceFunc: func(ctx context.Context, service *OtherService) (*OtherService, error) {
 //				panic("mock out the CreateService method")
 //			},
-//			DeleteServiceFunc: func(id uint) error {
+//			DeleteServiceFunc: func(ctx context.Context, id uint) error {
 //				panic("mock out the DeleteService method")
 //			},
-//			GetAllServicesFunc: func(filter *ServiceFilter) (*MultipleOtherServices, error) {
+//			GetAllServicesFunc: func(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error) {
 //				panic("mock out the GetAllServices method")
 //			},
-//			GetServiceByIDFunc: func(id uint, languageCodes []string) (*OtherService, error) {
+//			GetServiceByIDFunc: func(ctx context.Context, id uint, languageCodes []string) (*OtherService, error) {
 //				panic("mock out the GetServiceByID method")
 //			},
-//			UpdateServiceFunc: func(service *OtherService) (*OtherService, error) {
+//			UpdateServiceFunc: func(ctx context.Context, service *OtherService) (*OtherService, error) {
 //				panic("mock out the UpdateService method")
 //			},
 //		}
@@ -43,27 +44,29 @@ var _ OtherServiceUsecase = &OtherServiceUsecaseMock{}
 //	}
 type OtherServiceUsecaseMock struct {
 	// AddTranslationFunc mocks the AddTranslation method.
-	AddTranslationFunc func(serviceID uint, translation *OtherServiceTranslation) (*OtherService, error)
+	AddTranslationFunc func(ctx context.Context, serviceID uint, translation *OtherServiceTranslation) (*OtherService, error)
 
 	// CreateServiceFunc mocks the CreateService method.
-	CreateServiceFunc func(service *OtherService) (*OtherService, error)
+	CreateServiceFunc func(ctx context.Context, service *OtherService) (*OtherService, error)
 
 	// DeleteServiceFunc mocks the DeleteService method.
-	DeleteServiceFunc func(id uint) error
+	DeleteServiceFunc func(ctx context.Context, id uint) error
 
 	// GetAllServicesFunc mocks the GetAllServices method.
-	GetAllServicesFunc func(filter *ServiceFilter) (*MultipleOtherServices, error)
+	GetAllServicesFunc func(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error)
 
 	// GetServiceByIDFunc mocks the GetServiceByID method.
-	GetServiceByIDFunc func(id uint, languageCodes []string) (*OtherService, error)
+	GetServiceByIDFunc func(ctx context.Context, id uint, languageCodes []string) (*OtherService, error)
 
 	// UpdateServiceFunc mocks the UpdateService method.
-	UpdateServiceFunc func(service *OtherService) (*OtherService, error)
+	UpdateServiceFunc func(ctx context.Context, service *OtherService) (*OtherService, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// AddTranslation holds details about calls to the AddTranslation method.
 		AddTranslation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ServiceID is the serviceID argument value.
 			ServiceID uint
 			// Translation is the translation argument value.
@@ -71,21 +74,29 @@ type OtherServiceUsecaseMock struct {
 		}
 		// CreateService holds details about calls to the CreateService method.
 		CreateService []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Service is the service argument value.
 			Service *OtherService
 		}
 		// DeleteService holds details about calls to the DeleteService method.
 		DeleteService []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 		}
 		// GetAllServices holds details about calls to the GetAllServices method.
 		GetAllServices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Filter is the filter argument value.
 			Filter *ServiceFilter
 		}
 		// GetServiceByID holds details about calls to the GetServiceByID method.
 		GetServiceByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 			// LanguageCodes is the languageCodes argument value.
@@ -93,6 +104,8 @@ type OtherServiceUsecaseMock struct {
 		}
 		// UpdateService holds details about calls to the UpdateService method.
 		UpdateService []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Service is the service argument value.
 			Service *OtherService
 		}
@@ -106,21 +119,23 @@ type OtherServiceUsecaseMock struct {
 }
 
 // AddTranslation calls AddTranslationFunc.
-func (mock *OtherServiceUsecaseMock) AddTranslation(serviceID uint, translation *OtherServiceTranslation) (*OtherService, error) {
+func (mock *OtherServiceUsecaseMock) AddTranslation(ctx context.Context, serviceID uint, translation *OtherServiceTranslation) (*OtherService, error) {
 	if mock.AddTranslationFunc == nil {
 		panic("OtherServiceUsecaseMock.AddTranslationFunc: method is nil but OtherServiceUsecase.AddTranslation was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		ServiceID   uint
 		Translation *OtherServiceTranslation
 	}{
+		Ctx:         ctx,
 		ServiceID:   serviceID,
 		Translation: translation,
 	}
 	mock.lockAddTranslation.Lock()
 	mock.calls.AddTranslation = append(mock.calls.AddTranslation, callInfo)
 	mock.lockAddTranslation.Unlock()
-	return mock.AddTranslationFunc(serviceID, translation)
+	return mock.AddTranslationFunc(ctx, serviceID, translation)
 }
 
 // AddTranslationCalls gets all the calls that were made to AddTranslation.
@@ -128,10 +143,12 @@ func (mock *OtherServiceUsecaseMock) AddTranslation(serviceID uint, translation
 //
 //	len(mockedOtherServiceUsecase.AddTranslationCalls())
 func (mock *OtherServiceUsecaseMock) AddTranslationCalls() []struct {
+	Ctx         context.Context
 	ServiceID   uint
 	Translation *OtherServiceTranslation
 } {
 	var calls []struct {
+		Ctx         context.Context
 		ServiceID   uint
 		Translation *OtherServiceTranslation
 	}
@@ -142,19 +159,21 @@ func (mock *OtherServiceUsecaseMock) AddTranslationCalls() []struct {
 }
 
 // CreateService calls CreateServiceFunc.
-func (mock *OtherServiceUsecaseMock) CreateService(service *OtherService) (*OtherService, error) {
+func (mock *OtherServiceUsecaseMock) CreateService(ctx context.Context, service *OtherService) (*OtherService, error) {
 	if mock.CreateServiceFunc == nil {
 		panic("OtherServiceUsecaseMock.CreateServiceFunc: method is nil but OtherServiceUsecase.CreateService was just called")
 	}
 	callInfo := struct {
+		Ctx     context.Context
 		Service *OtherService
 	}{
+		Ctx:     ctx,
 		Service: service,
 	}
 	mock.lockCreateService.Lock()
 	mock.calls.CreateService = append(mock.calls.CreateService, callInfo)
 	mock.lockCreateService.Unlock()
-	return mock.CreateServiceFunc(service)
+	return mock.CreateServiceFunc(ctx, service)
 }
 
 // CreateServiceCalls gets all the calls that were made to CreateService.
@@ -162,9 +181,11 @@ func (mock *OtherServiceUsecaseMock) CreateService(service *OtherService) (*Othe
 //
 //	len(mockedOtherServiceUsecase.CreateServiceCalls())
 func (mock *OtherServiceUsecaseMock) CreateServiceCalls() []struct {
+	Ctx     context.Context
 	Service *OtherService
 } {
 	var calls []struct {
+		Ctx     context.Context
 		Service *OtherService
 	}
 	mock.lockCreateService.RLock()
@@ -174,19 +195,21 @@ func (mock *OtherServiceUsecaseMock) CreateServiceCalls() []struct {
 }
 
 // DeleteService calls DeleteServiceFunc.
-func (mock *OtherServiceUsecaseMock) DeleteService(id uint) error {
+func (mock *OtherServiceUsecaseMock) DeleteService(ctx context.Context, id uint) error {
 	if mock.DeleteServiceFunc == nil {
 		panic("OtherServiceUsecaseMock.DeleteServiceFunc: method is nil but OtherServiceUsecase.DeleteService was just called")
 	}
 	callInfo := struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}{
-		ID: id,
+		Ctx: ctx,
+		ID:  id,
 	}
 	mock.lockDeleteService.Lock()
 	mock.calls.DeleteService = append(mock.calls.DeleteService, callInfo)
 	mock.lockDeleteService.Unlock()
-	return mock.DeleteServiceFunc(id)
+	return mock.DeleteServiceFunc(ctx, id)
 }
 
 // DeleteServiceCalls gets all the calls that were made to DeleteService.
@@ -194,10 +217,12 @@ func (mock *OtherServiceUsecaseMock) DeleteService(id uint) error {
 //
 //	len(mockedOtherServiceUsecase.DeleteServiceCalls())
 func (mock *OtherServiceUsecaseMock) DeleteServiceCalls() []struct {
-	ID uint
+	Ctx context.Context
+	ID  uint
 } {
 	var calls []struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}
 	mock.lockDeleteService.RLock()
 	calls = mock.calls.DeleteService
@@ -206,19 +231,21 @@ func (mock *OtherServiceUsecaseMock) DeleteServiceCalls() []struct {
 }
 
 // GetAllServices calls GetAllServicesFunc.
-func (mock *OtherServiceUsecaseMock) GetAllServices(filter *ServiceFilter) (*MultipleOtherServices, error) {
+func (mock *OtherServiceUsecaseMock) GetAllServices(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error) {
 	if mock.GetAllServicesFunc == nil {
 		panic("OtherServiceUsecaseMock.GetAllServicesFunc: method is nil but OtherServiceUsecase.GetAllServices was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Filter *ServiceFilter
 	}{
+		Ctx:    ctx,
 		Filter: filter,
 	}
 	mock.lockGetAllServices.Lock()
 	mock.calls.GetAllServices = append(mock.calls.GetAllServices, callInfo)
 	mock.lockGetAllServices.Unlock()
-	return mock.GetAllServicesFunc(filter)
+	return mock.GetAllServicesFunc(ctx, filter)
 }
 
 // GetAllServicesCalls gets all the calls that were made to GetAllServices.
@@ -226,9 +253,11 @@ func (mock *OtherServiceUsecaseMock) GetAllServices(filter *ServiceFilter) (*Mul
 //
 //	len(mockedOtherServiceUsecase.GetAllServicesCalls())
 func (mock *OtherServiceUsecaseMock) GetAllServicesCalls() []struct {
+	Ctx    context.Context
 	Filter *ServiceFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Filter *ServiceFilter
 	}
 	mock.lockGetAllServices.RLock()
@@ -238,21 +267,23 @@ func (mock *OtherServiceUsecaseMock) GetAllServicesCalls() []struct {
 }
 
 // GetServiceByID calls GetServiceByIDFunc.
-func (mock *OtherServiceUsecaseMock) GetServiceByID(id uint, languageCodes []string) (*OtherService, error) {
+func (mock *OtherServiceUsecaseMock) GetServiceByID(ctx context.Context, id uint, languageCodes []string) (*OtherService, error) {
 	if mock.GetServiceByIDFunc == nil {
 		panic("OtherServiceUsecaseMock.GetServiceByIDFunc: method is nil but OtherServiceUsecase.GetServiceByID was just called")
 	}
 	callInfo := struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}{
+		Ctx:           ctx,
 		ID:            id,
 		LanguageCodes: languageCodes,
 	}
 	mock.lockGetServiceByID.Lock()
 	mock.calls.GetServiceByID = append(mock.calls.GetServiceByID, callInfo)
 	mock.lockGetServiceByID.Unlock()
-	return mock.GetServiceByIDFunc(id, languageCodes)
+	return mock.GetServiceByIDFunc(ctx, id, languageCodes)
 }
 
 // GetServiceByIDCalls gets all the calls that were made to GetServiceByID.
@@ -260,10 +291,12 @@ func (mock *OtherServiceUsecaseMock) GetServiceByID(id uint, languageCodes []str
 //
 //	len(mockedOtherServiceUsecase.GetServiceByIDCalls())
 func (mock *OtherServiceUsecaseMock) GetServiceByIDCalls() []struct {
+	Ctx           context.Context
 	ID            uint
 	LanguageCodes []string
 } {
 	var calls []struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}
@@ -274,19 +307,21 @@ func (mock *OtherServiceUsecaseMock) GetServiceByIDCalls() []struct {
 }
 
 // UpdateService calls UpdateServiceFunc.
-func (mock *OtherServiceUsecaseMock) UpdateService(service *OtherService) (*OtherService, error) {
+func (mock *OtherServiceUsecaseMock) UpdateService(ctx context.Context, service *OtherService) (*OtherService, error) {
 	if mock.UpdateServiceFunc == nil {
 		panic("OtherServiceUsecaseMock.UpdateServiceFunc: method is nil but OtherServiceUsecase.UpdateService was just called")
 	}
 	callInfo := struct {
+		Ctx     context.Context
 		Service *OtherService
 	}{
+		Ctx:     ctx,
 		Service: service,
 	}
 	mock.lockUpdateService.Lock()
 	mock.calls.UpdateService = append(mock.calls.UpdateService, callInfo)
 	mock.lockUpdateService.Unlock()
-	return mock.UpdateServiceFunc(service)
+	return mock.UpdateServiceFunc(ctx, service)
 }
 
 // UpdateServiceCalls gets all the calls that were made to UpdateService.
@@ -294,9 +329,11 @@ func (mock *OtherServiceUsecaseMock) UpdateService(service *OtherService) (*Othe
 //
 //	len(mockedOtherServiceUsecase.UpdateServiceCalls())
 func (mock *OtherServiceUsecaseMock) UpdateServiceCalls() []struct {
+	Ctx     context.Context
 	Service *OtherService
 } {
 	var calls []struct {
+		Ctx     context.Context
 		Service *OtherService
 	}
 	mock.lockUpdateService.RLock()
@@ -315,22 +352,22 @@ var _ OtherServiceRepository = &OtherServiceRepositoryMock{}
 //
 //		// make and configure a mocked OtherServiceRepository
 //		mockedOtherServiceRepository := &OtherServiceRepositoryMock{
-//			AddTranslationFunc: func(translation *OtherServiceTranslation) error {
+//			AddTranslationFunc: func(ctx context.Context, translation *OtherServiceTranslation) error {
 //				panic("mock out the AddTranslation method")
 //			},
-//			CreateFunc: func(service *OtherService) (*OtherService, error) {
+//			CreateFunc: func(ctx context.Context, service *OtherService) (*OtherService, error) {
 //				panic("mock out the Create method")
 //			},
-//			DeleteFunc: func(id uint) error {
+//			DeleteFunc: func(ctx context.Context, id uint) error {
 //				panic("mock out the Delete method")
 //			},
-//			GetAllFunc: func(filter *ServiceFilter) (*MultipleOtherServices, error) {
+//			GetAllFunc: func(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error) {
 //				panic("mock out the GetAll method")
 //			},
-//			GetByIDFunc: func(id uint, languageCodes []string) (*OtherService, error) {
+//			GetByIDFunc: func(ctx context.Context, id uint, languageCodes []string) (*OtherService, error) {
 //				panic("mock out the GetByID method")
 //			},
-//			UpdateFunc: func(service *OtherService) (*OtherService, error) {
+//			UpdateFunc: func(ctx context.Context, service *OtherService) (*OtherService, error) {
 //				panic("mock out the Update method")
 //			},
 //		}
@@ -341,47 +378,57 @@ var _ OtherServiceRepository = &OtherServiceRepositoryMock{}
 //	}
 type OtherServiceRepositoryMock struct {
 	// AddTranslationFunc mocks the AddTranslation method.
-	AddTranslationFunc func(translation *OtherServiceTranslation) error
+	AddTranslationFunc func(ctx context.Context, translation *OtherServiceTranslation) error
 
 	// CreateFunc mocks the Create method.
-	CreateFunc func(service *OtherService) (*OtherService, error)
+	CreateFunc func(ctx context.Context, service *OtherService) (*OtherService, error)
 
 	// DeleteFunc mocks the Delete method.
-	DeleteFunc func(id uint) error
+	DeleteFunc func(ctx context.Context, id uint) error
 
 	// GetAllFunc mocks the GetAll method.
-	GetAllFunc func(filter *ServiceFilter) (*MultipleOtherServices, error)
+	GetAllFunc func(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error)
 
 	// GetByIDFunc mocks the GetByID method.
-	GetByIDFunc func(id uint, languageCodes []string) (*OtherService, error)
+	GetByIDFunc func(ctx context.Context, id uint, languageCodes []string) (*OtherService, error)
 
 	// UpdateFunc mocks the Update method.
-	UpdateFunc func(service *OtherService) (*OtherService, error)
+	UpdateFunc func(ctx context.Context, service *OtherService) (*OtherService, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// AddTranslation holds details about calls to the AddTranslation method.
 		AddTranslation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Translation is the translation argument value.
 			Translation *OtherServiceTranslation
 		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Service is the service argument value.
 			Service *OtherService
 		}
 		// Delete holds details about calls to the Delete method.
 		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 		}
 		// GetAll holds details about calls to the GetAll method.
 		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Filter is the filter argument value.
 			Filter *ServiceFilter
 		}
 		// GetByID holds details about calls to the GetByID method.
 		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 			// LanguageCodes is the languageCodes argument value.
@@ -389,6 +436,8 @@ type OtherServiceRepositoryMock struct {
 		}
 		// Update holds details about calls to the Update method.
 		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Service is the service argument value.
 			Service *OtherService
 		}
@@ -402,19 +451,21 @@ type OtherServiceRepositoryMock struct {
 }
 
 // AddTranslation calls AddTranslationFunc.
-func (mock *OtherServiceRepositoryMock) AddTranslation(translation *OtherServiceTranslation) error {
+func (mock *OtherServiceRepositoryMock) AddTranslation(ctx context.Context, translation *OtherServiceTranslation) error {
 	if mock.AddTranslationFunc == nil {
 		panic("OtherServiceRepositoryMock.AddTranslationFunc: method is nil but OtherServiceRepository.AddTranslation was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Translation *OtherServiceTranslation
 	}{
+		Ctx:         ctx,
 		Translation: translation,
 	}
 	mock.lockAddTranslation.Lock()
 	mock.calls.AddTranslation = append(mock.calls.AddTranslation, callInfo)
 	mock.lockAddTranslation.Unlock()
-	return mock.AddTranslationFunc(translation)
+	return mock.AddTranslationFunc(ctx, translation)
 }
 
 // AddTranslationCalls gets all the calls that were made to AddTranslation.
@@ -422,9 +473,11 @@ func (mock *OtherServiceRepositoryMock) AddTranslation(translation *OtherService
 //
 //	len(mockedOtherServiceRepository.AddTranslationCalls())
 func (mock *OtherServiceRepositoryMock) AddTranslationCalls() []struct {
+	Ctx         context.Context
 	Translation *OtherServiceTranslation
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Translation *OtherServiceTranslation
 	}
 	mock.lockAddTranslation.RLock()
@@ -434,19 +487,21 @@ func (mock *OtherServiceRepositoryMock) AddTranslationCalls() []struct {
 }
 
 // Create calls CreateFunc.
-func (mock *OtherServiceRepositoryMock) Create(service *OtherService) (*OtherService, error) {
+func (mock *OtherServiceRepositoryMock) Create(ctx context.Context, service *OtherService) (*OtherService, error) {
 	if mock.CreateFunc == nil {
 		panic("OtherServiceRepositoryMock.CreateFunc: method is nil but OtherServiceRepository.Create was just called")
 	}
 	callInfo := struct {
+		Ctx     context.Context
 		Service *OtherService
 	}{
+		Ctx:     ctx,
 		Service: service,
 	}
 	mock.lockCreate.Lock()
 	mock.calls.Create = append(mock.calls.Create, callInfo)
 	mock.lockCreate.Unlock()
-	return mock.CreateFunc(service)
+	return mock.CreateFunc(ctx, service)
 }
 
 // CreateCalls gets all the calls that were made to Create.
@@ -454,9 +509,11 @@ func (mock *OtherServiceRepositoryMock) Create(service *OtherService) (*OtherSer
 //
 //	len(mockedOtherServiceRepository.CreateCalls())
 func (mock *OtherServiceRepositoryMock) CreateCalls() []struct {
+	Ctx     context.Context
 	Service *OtherService
 } {
 	var calls []struct {
+		Ctx     context.Context
 		Service *OtherService
 	}
 	mock.lockCreate.RLock()
@@ -466,19 +523,21 @@ func (mock *OtherServiceRepositoryMock) CreateCalls() []struct {
 }
 
 // Delete calls DeleteFunc.
-func (mock *OtherServiceRepositoryMock) Delete(id uint) error {
+func (mock *OtherServiceRepositoryMock) Delete(ctx context.Context, id uint) error {
 	if mock.DeleteFunc == nil {
 		panic("OtherServiceRepositoryMock.DeleteFunc: method is nil but OtherServiceRepository.Delete was just called")
 	}
 	callInfo := struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}{
-		ID: id,
+		Ctx: ctx,
+		ID:  id,
 	}
 	mock.lockDelete.Lock()
 	mock.calls.Delete = append(mock.calls.Delete, callInfo)
 	mock.lockDelete.Unlock()
-	return mock.DeleteFunc(id)
+	return mock.DeleteFunc(ctx, id)
 }
 
 // DeleteCalls gets all the calls that were made to Delete.
@@ -486,10 +545,12 @@ func (mock *OtherServiceRepositoryMock) Delete(id uint) error {
 //
 //	len(mockedOtherServiceRepository.DeleteCalls())
 func (mock *OtherServiceRepositoryMock) DeleteCalls() []struct {
-	ID uint
+	Ctx context.Context
+	ID  uint
 } {
 	var calls []struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}
 	mock.lockDelete.RLock()
 	calls = mock.calls.Delete
@@ -498,19 +559,21 @@ func (mock *OtherServiceRepositoryMock) DeleteCalls() []struct {
 }
 
 // GetAll calls GetAllFunc.
-func (mock *OtherServiceRepositoryMock) GetAll(filter *ServiceFilter) (*MultipleOtherServices, error) {
+func (mock *OtherServiceRepositoryMock) GetAll(ctx context.Context, filter *ServiceFilter) (*MultipleOtherServices, error) {
 	if mock.GetAllFunc == nil {
 		panic("OtherServiceRepositoryMock.GetAllFunc: method is nil but OtherServiceRepository.GetAll was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Filter *ServiceFilter
 	}{
+		Ctx:    ctx,
 		Filter: filter,
 	}
 	mock.lockGetAll.Lock()
 	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
 	mock.lockGetAll.Unlock()
-	return mock.GetAllFunc(filter)
+	return mock.GetAllFunc(ctx, filter)
 }
 
 // GetAllCalls gets all the calls that were made to GetAll.
@@ -518,9 +581,11 @@ func (mock *OtherServiceRepositoryMock) GetAll(filter *ServiceFilter) (*Multiple
 //
 //	len(mockedOtherServiceRepository.GetAllCalls())
 func (mock *OtherServiceRepositoryMock) GetAllCalls() []struct {
+	Ctx    context.Context
 	Filter *ServiceFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Filter *ServiceFilter
 	}
 	mock.lockGetAll.RLock()
@@ -530,21 +595,23 @@ func (mock *OtherServiceRepositoryMock) GetAllCalls() []struct {
 }
 
 // GetByID calls GetByIDFunc.
-func (mock *OtherServiceRepositoryMock) GetByID(id uint, languageCodes []string) (*OtherService, error) {
+func (mock *OtherServiceRepositoryMock) GetByID(ctx context.Context, id uint, languageCodes []string) (*OtherService, error) {
 	if mock.GetByIDFunc == nil {
 		panic("OtherServiceRepositoryMock.GetByIDFunc: method is nil but OtherServiceRepository.GetByID was just called")
 	}
 	callInfo := struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}{
+		Ctx:           ctx,
 		ID:            id,
 		LanguageCodes: languageCodes,
 	}
 	mock.lockGetByID.Lock()
 	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
 	mock.lockGetByID.Unlock()
-	return mock.GetByIDFunc(id, languageCodes)
+	return mock.GetByIDFunc(ctx, id, languageCodes)
 }
 
 // GetByIDCalls gets all the calls that were made to GetByID.
@@ -552,10 +619,12 @@ func (mock *OtherServiceRepositoryMock) GetByID(id uint, languageCodes []string)
 //
 //	len(mockedOtherServiceRepository.GetByIDCalls())
 func (mock *OtherServiceRepositoryMock) GetByIDCalls() []struct {
+	Ctx           context.Context
 	ID            uint
 	LanguageCodes []string
 } {
 	var calls []struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}
@@ -566,19 +635,21 @@ func (mock *OtherServiceRepositoryMock) GetByIDCalls() []struct {
 }
 
 // Update calls UpdateFunc.
-func (mock *OtherServiceRepositoryMock) Update(service *OtherService) (*OtherService, error) {
+func (mock *OtherServiceRepositoryMock) Update(ctx context.Context, service *OtherService) (*OtherService, error) {
 	if mock.UpdateFunc == nil {
 		panic("OtherServiceRepositoryMock.UpdateFunc: method is nil but OtherServiceRepository.Update was just called")
 	}
 	callInfo := struct {
+		Ctx     context.Context
 		Service *OtherService
 	}{
+		Ctx:     ctx,
 		Service: service,
 	}
 	mock.lockUpdate.Lock()
 	mock.calls.Update = append(mock.calls.Update, callInfo)
 	mock.lockUpdate.Unlock()
-	return mock.UpdateFunc(service)
+	return mock.UpdateFunc(ctx, service)
 }
 
 // UpdateCalls gets all the calls that were made to Update.
@@ -586,9 +657,11 @@ func (mock *OtherServiceRepositoryMock) Update(service *OtherService) (*OtherSer
 //
 //	len(mockedOtherServiceRepository.UpdateCalls())
 func (mock *OtherServiceRepositoryMock) UpdateCalls() []struct {
+	Ctx     context.Context
 	Service *OtherService
 } {
 	var calls []struct {
+		Ctx     context.Context
 		Service *OtherService
 	}
 	mock.lockUpdate.RLock()
@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 type Partner struct {
 	Model
 	Name       string `json:"name"`
@@ -20,16 +22,16 @@ type MultiplePartners struct {
 	Pagination Pagination `json:"meta"`
 }
 type PartnerRepository interface {
-	Create(partner *Partner) (*Partner, error)
-	GetByID(id uint) (*Partner, error)
-	GetAll(filter *PartnerFilter) (*MultiplePartners, error)
-	Update(partner *Partner) (*Partner, error)
-	Delete(id uint) error
+	Create(ctx context.Context, partner *Partner) (*Partner, error)
+	GetByID(ctx context.Context, id uint) (*Partner, error)
+	GetAll(ctx context.Context, filter *PartnerFilter) (*MultiplePartners, error)
+	Update(ctx context.Context, partner *Partner) (*Partner, error)
+	Delete(ctx context.Context, id uint) error
 }
 type PartnerUsecase interface {
-	CreatePartner(partner *Partner) (*Partner, error)
-	GetAllPartners(filter *PartnerFilter) (*MultiplePartners, error)
-	GetPartnerByID(id uint) (*Partner, error)
-	DeletePartner(id uint) error
-	UpdatePartner(partner *Partner) (*Partner, error)
+	CreatePartner(ctx context.Context, partner *Partner) (*Partner, error)
+	GetAllPartners(ctx context.Context, filter *PartnerFilter) (*MultiplePartners, error)
+	GetPartnerByID(ctx context.Context, id uint) (*Partner, error)
+	DeletePartner(ctx context.Context, id uint) error
+	UpdatePartner(ctx context.Context, partner *Partner) (*Partner, error)
 }
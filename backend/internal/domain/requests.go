@@ -1,6 +1,9 @@
 package domain
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 // swagger:model CreateAdminRequest
 type CreateAdminRequest struct {
@@ -8,12 +11,19 @@ type CreateAdminRequest struct {
 	Password string `json:"password" binding:"required"`
 	Role     string `json:"role" binding:"required,oneof=admin superadmin"`
 	Name     string `json:"name" binding:"required"`
+	// Email or PhoneNumber is needed for self-service password resets
+	Email       string `json:"email" binding:"omitempty,email"`
+	PhoneNumber string `json:"phone_number"`
 }
 
 // swagger:model UpdateAdminRequest
+// UpdateAdminRequest keeps the e-mail and phone number as they are when
+// they are left out; sending them empty removes them.
 type UpdateAdminRequest struct {
-	Name string `json:"name" binding:"required"`
-	Role string `json:"role" binding:"required,oneof=admin superadmin"`
+	Name        string  `json:"name" binding:"required"`
+	Role        string  `json:"role" binding:"required,oneof=admin superadmin"`
+	Email       *string `json:"email" binding:"omitempty,len=0|email"`
+	PhoneNumber *string `json:"phone_number"`
 }
 
 // swagger:model ChangePasswordRequest
@@ -27,6 +37,17 @@ type ResetPasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+// swagger:model ForgotPasswordRequest
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// swagger:model ConfirmPasswordResetRequest
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
 // swagger:model LoginRequest
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -75,6 +96,11 @@ type CreateOtherService struct {
 	Image      *multipart.FileHeader `form:"image"`
 }
 
+// swagger:model AssignBookingRequest
+type AssignBookingRequest struct {
+	TutorID uint `json:"tutor_id"`
+}
+
 type UpdateTutorRequest struct {
 	FullName       string `form:"full_name" json:"full_name,omitempty"`
 	PhoneNumber    string `form:"phone_number" json:"phone_number,omitempty"`
@@ -83,4 +109,6 @@ type UpdateTutorRequest struct {
 	HrPerDay       int    `form:"hr_per_day" json:"hr_per_day,omitempty"`
 	Verified       bool   `form:"verified" json:"verified,omitempty"`
 	Email          string `form:"email" json:"email,omitempty"`
+	// DocumentExpiresAt is when the tutor's document stops being valid
+	DocumentExpiresAt *time.Time `form:"document_expires_at" json:"document_expires_at,omitempty"`
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Outcomes of a scheduled task's last run
+const (
+	TaskStatusRunning = "running"
+	TaskStatusOK      = "ok"
+	TaskStatusFailed  = "failed"
+)
+
+// swagger:model ScheduledTask
+// ScheduledTask is the state of one recurring maintenance task. Keeping it
+// in the database lets whichever instance is leader pick up where the
+// previous one stopped.
+type ScheduledTask struct {
+	Name         string     `json:"name" gorm:"primaryKey"`
+	Schedule     string     `json:"schedule"`
+	NextRunAt    time.Time  `json:"next_run_at"`
+	LastStatus   string     `json:"last_status,omitempty"`
+	LastResult   string     `json:"last_result,omitempty" gorm:"type:text"`
+	LastError    string     `json:"last_error,omitempty" gorm:"type:text"`
+	LastRunBy    string     `json:"last_run_by,omitempty"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	Runs         int        `json:"runs"`
+	Failures     int        `json:"failures"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type ScheduledTaskRepository interface {
+	GetAll(ctx context.Context) ([]ScheduledTask, error)
+	// GetByName returns ErrNotFound for a task that never ran.
+	GetByName(ctx context.Context, name string) (*ScheduledTask, error)
+	Save(ctx context.Context, task *ScheduledTask) error
+}
+
+type SchedulerUsecase interface {
+	// GetAll lists the configured tasks with the outcome of their last run.
+	GetAll(ctx context.Context) ([]ScheduledTask, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Delivery states of an outgoing text message
+const (
+	SMSStatusPending = "pending"
+	SMSStatusSent    = "sent"
+	SMSStatusFailed  = "failed"
+)
+
+// swagger:model SMSMessage
+// SMSMessage is one outgoing text message and its delivery state. Messages
+// that fail are retried until MaxAttempts is reached and then marked failed.
+type SMSMessage struct {
+	Model
+	To                string     `json:"to" gorm:"index"`
+	Body              string     `json:"body" gorm:"type:text"`
+	Status            string     `json:"status" gorm:"index"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"last_error,omitempty"`
+	ProviderMessageID string     `json:"provider_message_id,omitempty"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at,omitempty" gorm:"index"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+}
+
+type SMSFilter struct {
+	Status string
+	To     string
+	// Pagination
+	Page  int
+	Limit int
+}
+
+// swagger:model MultipleSMSMessages
+type MultipleSMSMessages struct {
+	Data       []SMSMessage `json:"data"`
+	Pagination Pagination   `json:"meta"`
+}
+
+type SMSRepository interface {
+	Create(ctx context.Context, msg *SMSMessage) error
+	Update(ctx context.Context, msg *SMSMessage) error
+	GetByID(ctx context.Context, id uint) (*SMSMessage, error)
+	GetAll(ctx context.Context, f *SMSFilter) (*MultipleSMSMessages, error)
+	// ClaimDue returns pending messages whose next attempt is at or before
+	// now, moving their next attempt to until so that nobody else picks
+	// them up meanwhile.
+	ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]SMSMessage, error)
+}
+
+type SMSUsecase interface {
+	GetAll(ctx context.Context, f *SMSFilter) (*MultipleSMSMessages, error)
+	// Retry sends a pending or failed message again right away.
+	Retry(ctx context.Context, id uint) (*SMSMessage, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "mime/multipart"
+
+// FileStorage keeps uploaded files. Save returns the path clients fetch the
+// file from, which is also what gets stored on the record.
+type FileStorage interface {
+	Save(file *multipart.FileHeader, kind, name string) (string, error)
+	Remove(path string) error
+}
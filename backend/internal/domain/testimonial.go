@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 type Testimonial struct {
 	Model
 	Name         string                   `form:"name" json:"name"`
@@ -30,19 +32,19 @@ type MultipleTestimonialResponse struct {
 
 //go:generate moq -out testimonial_service_mock.go . TestimonialRepository TestimonialUsecase
 type TestimonialRepository interface {
-	Create(testimonial *Testimonial) (*Testimonial, error)
-	GetAll(filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
-	GetByID(id uint, languageCodes []string) (*Testimonial, error)
-	Delete(id uint) error
-	Update(testimonial *Testimonial) (*Testimonial, error)
-	AddTranslation(translation *TestimonialTranslation) error
+	Create(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
+	GetAll(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
+	GetByID(ctx context.Context, id uint, languageCodes []string) (*Testimonial, error)
+	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
+	AddTranslation(ctx context.Context, translation *TestimonialTranslation) error
 }
 
 type TestimonialUsecase interface {
-	CreateTestimonial(testimonial *Testimonial) (*Testimonial, error)
-	GetAllTestimonials(filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
-	GetTestimonialByID(id uint) (*Testimonial, error)
-	DeleteTestimonial(id uint) error
-	UpdateTestimonial(testimonial *Testimonial) (*Testimonial, error)
-	AddTranslation(testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error)
+	CreateTestimonial(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
+	GetAllTestimonials(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
+	GetTestimonialByID(ctx context.Context, id uint) (*Testimonial, error)
+	DeleteTestimonial(ctx context.Context, id uint) error
+	UpdateTestimonial(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
+	AddTranslation(ctx context.Context, testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error)
 }
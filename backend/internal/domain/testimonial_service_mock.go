@@ -4,6 +4,7 @@
 package domain
 
 import (
+	"context"
 	"sync"
 )
 
@@ -17,22 +18,22 @@ var _ TestimonialRepository = &TestimonialRepositoryMock{}
 //
 //		// make and configure a mocked TestimonialRepository
 //		mockedTestimonialRepository := &TestimonialRepositoryMock{
-//			AddTranslationFunc: func(translation *TestimonialTranslation) error {
+//			AddTranslationFunc: func(ctx context.Context, translation *TestimonialTranslation) error {
 //				panic("mock out the AddTranslation method")
 //			},
-//			CreateFunc: func(testimonial *Testimonial) (*Testimonial, error) {
+//			CreateFunc: func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 //				panic("mock out the Create method")
 //			},
-//			DeleteFunc: func(id uint) error {
+//			DeleteFunc: func(ctx context.Context, id uint) error {
 //				panic("mock out the Delete method")
 //			},
-//			GetAllFunc: func(filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
+//			GetAllFunc: func(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
 //				panic("mock out the GetAll method")
 //			},
-//			GetByIDFunc: func(id uint, languageCodes []string) (*Testimonial, error) {
+//			GetByIDFunc: func(ctx context.Context, id uint, languageCodes []string) (*Testimonial, error) {
 //				panic("mock out the GetByID method")
 //			},
-//			UpdateFunc: func(testimonial *Testimonial) (*Testimonial, error) {
+//			UpdateFunc: func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 //				panic("mock out the Update method")
 //			},
 //		}
@@ -43,47 +44,57 @@ var _ TestimonialRepository = &TestimonialRepositoryMock{}
 //	}
 type TestimonialRepositoryMock struct {
 	// AddTranslationFunc mocks the AddTranslation method.
-	AddTranslationFunc func(translation *TestimonialTranslation) error
+	AddTranslationFunc func(ctx context.Context, translation *TestimonialTranslation) error
 
 	// CreateFunc mocks the Create method.
-	CreateFunc func(testimonial *Testimonial) (*Testimonial, error)
+	CreateFunc func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
 
 	// DeleteFunc mocks the Delete method.
-	DeleteFunc func(id uint) error
+	DeleteFunc func(ctx context.Context, id uint) error
 
 	// GetAllFunc mocks the GetAll method.
-	GetAllFunc func(filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
+	GetAllFunc func(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
 
 	// GetByIDFunc mocks the GetByID method.
-	GetByIDFunc func(id uint, languageCodes []string) (*Testimonial, error)
+	GetByIDFunc func(ctx context.Context, id uint, languageCodes []string) (*Testimonial, error)
 
 	// UpdateFunc mocks the Update method.
-	UpdateFunc func(testimonial *Testimonial) (*Testimonial, error)
+	UpdateFunc func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// AddTranslation holds details about calls to the AddTranslation method.
 		AddTranslation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Translation is the translation argument value.
 			Translation *TestimonialTranslation
 		}
 		// Create holds details about calls to the Create method.
 		Create []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Testimonial is the testimonial argument value.
 			Testimonial *Testimonial
 		}
 		// Delete holds details about calls to the Delete method.
 		Delete []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 		}
 		// GetAll holds details about calls to the GetAll method.
 		GetAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Filter is the filter argument value.
 			Filter *TestimonialFilter
 		}
 		// GetByID holds details about calls to the GetByID method.
 		GetByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 			// LanguageCodes is the languageCodes argument value.
@@ -91,6 +102,8 @@ type TestimonialRepositoryMock struct {
 		}
 		// Update holds details about calls to the Update method.
 		Update []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Testimonial is the testimonial argument value.
 			Testimonial *Testimonial
 		}
@@ -104,19 +117,21 @@ type TestimonialRepositoryMock struct {
 }
 
 // AddTranslation calls AddTranslationFunc.
-func (mock *TestimonialRepositoryMock) AddTranslation(translation *TestimonialTranslation) error {
+func (mock *TestimonialRepositoryMock) AddTranslation(ctx context.Context, translation *TestimonialTranslation) error {
 	if mock.AddTranslationFunc == nil {
 		panic("TestimonialRepositoryMock.AddTranslationFunc: method is nil but TestimonialRepository.AddTranslation was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Translation *TestimonialTranslation
 	}{
+		Ctx:         ctx,
 		Translation: translation,
 	}
 	mock.lockAddTranslation.Lock()
 	mock.calls.AddTranslation = append(mock.calls.AddTranslation, callInfo)
 	mock.lockAddTranslation.Unlock()
-	return mock.AddTranslationFunc(translation)
+	return mock.AddTranslationFunc(ctx, translation)
 }
 
 // AddTranslationCalls gets all the calls that were made to AddTranslation.
@@ -124,9 +139,11 @@ func (mock *TestimonialRepositoryMock) AddTranslation(translation *TestimonialTr
 //
 //	len(mockedTestimonialRepository.AddTranslationCalls())
 func (mock *TestimonialRepositoryMock) AddTranslationCalls() []struct {
+	Ctx         context.Context
 	Translation *TestimonialTranslation
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Translation *TestimonialTranslation
 	}
 	mock.lockAddTranslation.RLock()
@@ -136,19 +153,21 @@ func (mock *TestimonialRepositoryMock) AddTranslationCalls() []struct {
 }
 
 // Create calls CreateFunc.
-func (mock *TestimonialRepositoryMock) Create(testimonial *Testimonial) (*Testimonial, error) {
+func (mock *TestimonialRepositoryMock) Create(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 	if mock.CreateFunc == nil {
 		panic("TestimonialRepositoryMock.CreateFunc: method is nil but TestimonialRepository.Create was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}{
+		Ctx:         ctx,
 		Testimonial: testimonial,
 	}
 	mock.lockCreate.Lock()
 	mock.calls.Create = append(mock.calls.Create, callInfo)
 	mock.lockCreate.Unlock()
-	return mock.CreateFunc(testimonial)
+	return mock.CreateFunc(ctx, testimonial)
 }
 
 // CreateCalls gets all the calls that were made to Create.
@@ -156,9 +175,11 @@ func (mock *TestimonialRepositoryMock) Create(testimonial *Testimonial) (*Testim
 //
 //	len(mockedTestimonialRepository.CreateCalls())
 func (mock *TestimonialRepositoryMock) CreateCalls() []struct {
+	Ctx         context.Context
 	Testimonial *Testimonial
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}
 	mock.lockCreate.RLock()
@@ -168,19 +189,21 @@ func (mock *TestimonialRepositoryMock) CreateCalls() []struct {
 }
 
 // Delete calls DeleteFunc.
-func (mock *TestimonialRepositoryMock) Delete(id uint) error {
+func (mock *TestimonialRepositoryMock) Delete(ctx context.Context, id uint) error {
 	if mock.DeleteFunc == nil {
 		panic("TestimonialRepositoryMock.DeleteFunc: method is nil but TestimonialRepository.Delete was just called")
 	}
 	callInfo := struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}{
-		ID: id,
+		Ctx: ctx,
+		ID:  id,
 	}
 	mock.lockDelete.Lock()
 	mock.calls.Delete = append(mock.calls.Delete, callInfo)
 	mock.lockDelete.Unlock()
-	return mock.DeleteFunc(id)
+	return mock.DeleteFunc(ctx, id)
 }
 
 // DeleteCalls gets all the calls that were made to Delete.
@@ -188,10 +211,12 @@ func (mock *TestimonialRepositoryMock) Delete(id uint) error {
 //
 //	len(mockedTestimonialRepository.DeleteCalls())
 func (mock *TestimonialRepositoryMock) DeleteCalls() []struct {
-	ID uint
+	Ctx context.Context
+	ID  uint
 } {
 	var calls []struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}
 	mock.lockDelete.RLock()
 	calls = mock.calls.Delete
@@ -200,19 +225,21 @@ func (mock *TestimonialRepositoryMock) DeleteCalls() []struct {
 }
 
 // GetAll calls GetAllFunc.
-func (mock *TestimonialRepositoryMock) GetAll(filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
+func (mock *TestimonialRepositoryMock) GetAll(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
 	if mock.GetAllFunc == nil {
 		panic("TestimonialRepositoryMock.GetAllFunc: method is nil but TestimonialRepository.GetAll was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Filter *TestimonialFilter
 	}{
+		Ctx:    ctx,
 		Filter: filter,
 	}
 	mock.lockGetAll.Lock()
 	mock.calls.GetAll = append(mock.calls.GetAll, callInfo)
 	mock.lockGetAll.Unlock()
-	return mock.GetAllFunc(filter)
+	return mock.GetAllFunc(ctx, filter)
 }
 
 // GetAllCalls gets all the calls that were made to GetAll.
@@ -220,9 +247,11 @@ func (mock *TestimonialRepositoryMock) GetAll(filter *TestimonialFilter) (*Multi
 //
 //	len(mockedTestimonialRepository.GetAllCalls())
 func (mock *TestimonialRepositoryMock) GetAllCalls() []struct {
+	Ctx    context.Context
 	Filter *TestimonialFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Filter *TestimonialFilter
 	}
 	mock.lockGetAll.RLock()
@@ -232,21 +261,23 @@ func (mock *TestimonialRepositoryMock) GetAllCalls() []struct {
 }
 
 // GetByID calls GetByIDFunc.
-func (mock *TestimonialRepositoryMock) GetByID(id uint, languageCodes []string) (*Testimonial, error) {
+func (mock *TestimonialRepositoryMock) GetByID(ctx context.Context, id uint, languageCodes []string) (*Testimonial, error) {
 	if mock.GetByIDFunc == nil {
 		panic("TestimonialRepositoryMock.GetByIDFunc: method is nil but TestimonialRepository.GetByID was just called")
 	}
 	callInfo := struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}{
+		Ctx:           ctx,
 		ID:            id,
 		LanguageCodes: languageCodes,
 	}
 	mock.lockGetByID.Lock()
 	mock.calls.GetByID = append(mock.calls.GetByID, callInfo)
 	mock.lockGetByID.Unlock()
-	return mock.GetByIDFunc(id, languageCodes)
+	return mock.GetByIDFunc(ctx, id, languageCodes)
 }
 
 // GetByIDCalls gets all the calls that were made to GetByID.
@@ -254,10 +285,12 @@ func (mock *TestimonialRepositoryMock) GetByID(id uint, languageCodes []string)
 //
 //	len(mockedTestimonialRepository.GetByIDCalls())
 func (mock *TestimonialRepositoryMock) GetByIDCalls() []struct {
+	Ctx           context.Context
 	ID            uint
 	LanguageCodes []string
 } {
 	var calls []struct {
+		Ctx           context.Context
 		ID            uint
 		LanguageCodes []string
 	}
@@ -268,19 +301,21 @@ func (mock *TestimonialRepositoryMock) GetByIDCalls() []struct {
 }
 
 // Update calls UpdateFunc.
-func (mock *TestimonialRepositoryMock) Update(testimonial *Testimonial) (*Testimonial, error) {
+func (mock *TestimonialRepositoryMock) Update(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 	if mock.UpdateFunc == nil {
 		panic("TestimonialRepositoryMock.UpdateFunc: method is nil but TestimonialRepository.Update was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}{
+		Ctx:         ctx,
 		Testimonial: testimonial,
 	}
 	mock.lockUpdate.Lock()
 	mock.calls.Update = append(mock.calls.Update, callInfo)
 	mock.lockUpdate.Unlock()
-	return mock.UpdateFunc(testimonial)
+	return mock.UpdateFunc(ctx, testimonial)
 }
 
 // UpdateCalls gets all the calls that were made to Update.
@@ -288,9 +323,11 @@ func (mock *TestimonialRepositoryMock) Update(testimonial *Testimonial) (*Testim
 //
 //	len(mockedTestimonialRepository.UpdateCalls())
 func (mock *TestimonialRepositoryMock) UpdateCalls() []struct {
+	Ctx         context.Context
 	Testimonial *Testimonial
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}
 	mock.lockUpdate.RLock()
@@ -309,22 +346,22 @@ var _ TestimonialUsecase = &TestimonialUsecaseMock{}
 //
 //		// make and configure a mocked TestimonialUsecase
 //		mockedTestimonialUsecase := &TestimonialUsecaseMock{
-//			AddTranslationFunc: func(testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error) {
+//			AddTranslationFunc: func(ctx context.Context, testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error) {
 //				panic("mock out the AddTranslation method")
 //			},
-//			CreateTestimonialFunc: func(testimonial *Testimonial) (*Testimonial, error) {
+//			CreateTestimonialFunc: func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 //				panic("mock out the CreateTestimonial method")
 //			},
-//			DeleteTestimonialFunc: func(id uint) error {
+//			DeleteTestimonialFunc: func(ctx context.Context, id uint) error {
 //				panic("mock out the DeleteTestimonial method")
 //			},
-//			GetAllTestimonialsFunc: func(filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
+//			GetAllTestimonialsFunc: func(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
 //				panic("mock out the GetAllTestimonials method")
 //			},
-//			GetTestimonialByIDFunc: func(id uint) (*Testimonial, error) {
+//			GetTestimonialByIDFunc: func(ctx context.Context, id uint) (*Testimonial, error) {
 //				panic("mock out the GetTestimonialByID method")
 //			},
-//			UpdateTestimonialFunc: func(testimonial *Testimonial) (*Testimonial, error) {
+//			UpdateTestimonialFunc: func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 //				panic("mock out the UpdateTestimonial method")
 //			},
 //		}
@@ -335,27 +372,29 @@ var _ TestimonialUsecase = &TestimonialUsecaseMock{}
 //	}
 type TestimonialUsecaseMock struct {
 	// AddTranslationFunc mocks the AddTranslation method.
-	AddTranslationFunc func(testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error)
+	AddTranslationFunc func(ctx context.Context, testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error)
 
 	// CreateTestimonialFunc mocks the CreateTestimonial method.
-	CreateTestimonialFunc func(testimonial *Testimonial) (*Testimonial, error)
+	CreateTestimonialFunc func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
 
 	// DeleteTestimonialFunc mocks the DeleteTestimonial method.
-	DeleteTestimonialFunc func(id uint) error
+	DeleteTestimonialFunc func(ctx context.Context, id uint) error
 
 	// GetAllTestimonialsFunc mocks the GetAllTestimonials method.
-	GetAllTestimonialsFunc func(filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
+	GetAllTestimonialsFunc func(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error)
 
 	// GetTestimonialByIDFunc mocks the GetTestimonialByID method.
-	GetTestimonialByIDFunc func(id uint) (*Testimonial, error)
+	GetTestimonialByIDFunc func(ctx context.Context, id uint) (*Testimonial, error)
 
 	// UpdateTestimonialFunc mocks the UpdateTestimonial method.
-	UpdateTestimonialFunc func(testimonial *Testimonial) (*Testimonial, error)
+	UpdateTestimonialFunc func(ctx context.Context, testimonial *Testimonial) (*Testimonial, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
 		// AddTranslation holds details about calls to the AddTranslation method.
 		AddTranslation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// TestimonialID is the testimonialID argument value.
 			TestimonialID uint
 			// Translation is the translation argument value.
@@ -363,26 +402,36 @@ type TestimonialUsecaseMock struct {
 		}
 		// CreateTestimonial holds details about calls to the CreateTestimonial method.
 		CreateTestimonial []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Testimonial is the testimonial argument value.
 			Testimonial *Testimonial
 		}
 		// DeleteTestimonial holds details about calls to the DeleteTestimonial method.
 		DeleteTestimonial []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 		}
 		// GetAllTestimonials holds details about calls to the GetAllTestimonials method.
 		GetAllTestimonials []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Filter is the filter argument value.
 			Filter *TestimonialFilter
 		}
 		// GetTestimonialByID holds details about calls to the GetTestimonialByID method.
 		GetTestimonialByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// ID is the id argument value.
 			ID uint
 		}
 		// UpdateTestimonial holds details about calls to the UpdateTestimonial method.
 		UpdateTestimonial []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Testimonial is the testimonial argument value.
 			Testimonial *Testimonial
 		}
@@ -396,21 +445,23 @@ type TestimonialUsecaseMock struct {
 }
 
 // AddTranslation calls AddTranslationFunc.
-func (mock *TestimonialUsecaseMock) AddTranslation(testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error) {
+func (mock *TestimonialUsecaseMock) AddTranslation(ctx context.Context, testimonialID uint, translation *TestimonialTranslation) (*Testimonial, error) {
 	if mock.AddTranslationFunc == nil {
 		panic("TestimonialUsecaseMock.AddTranslationFunc: method is nil but TestimonialUsecase.AddTranslation was just called")
 	}
 	callInfo := struct {
+		Ctx           context.Context
 		TestimonialID uint
 		Translation   *TestimonialTranslation
 	}{
+		Ctx:           ctx,
 		TestimonialID: testimonialID,
 		Translation:   translation,
 	}
 	mock.lockAddTranslation.Lock()
 	mock.calls.AddTranslation = append(mock.calls.AddTranslation, callInfo)
 	mock.lockAddTranslation.Unlock()
-	return mock.AddTranslationFunc(testimonialID, translation)
+	return mock.AddTranslationFunc(ctx, testimonialID, translation)
 }
 
 // AddTranslationCalls gets all the calls that were made to AddTranslation.
@@ -418,10 +469,12 @@ func (mock *TestimonialUsecaseMock) AddTranslation(testimonialID uint, translati
 //
 //	len(mockedTestimonialUsecase.AddTranslationCalls())
 func (mock *TestimonialUsecaseMock) AddTranslationCalls() []struct {
+	Ctx           context.Context
 	TestimonialID uint
 	Translation   *TestimonialTranslation
 } {
 	var calls []struct {
+		Ctx           context.Context
 		TestimonialID uint
 		Translation   *TestimonialTranslation
 	}
@@ -432,19 +485,21 @@ func (mock *TestimonialUsecaseMock) AddTranslationCalls() []struct {
 }
 
 // CreateTestimonial calls CreateTestimonialFunc.
-func (mock *TestimonialUsecaseMock) CreateTestimonial(testimonial *Testimonial) (*Testimonial, error) {
+func (mock *TestimonialUsecaseMock) CreateTestimonial(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 	if mock.CreateTestimonialFunc == nil {
 		panic("TestimonialUsecaseMock.CreateTestimonialFunc: method is nil but TestimonialUsecase.CreateTestimonial was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}{
+		Ctx:         ctx,
 		Testimonial: testimonial,
 	}
 	mock.lockCreateTestimonial.Lock()
 	mock.calls.CreateTestimonial = append(mock.calls.CreateTestimonial, callInfo)
 	mock.lockCreateTestimonial.Unlock()
-	return mock.CreateTestimonialFunc(testimonial)
+	return mock.CreateTestimonialFunc(ctx, testimonial)
 }
 
 // CreateTestimonialCalls gets all the calls that were made to CreateTestimonial.
@@ -452,9 +507,11 @@ func (mock *TestimonialUsecaseMock) CreateTestimonial(testimonial *Testimonial)
 //
 //	len(mockedTestimonialUsecase.CreateTestimonialCalls())
 func (mock *TestimonialUsecaseMock) CreateTestimonialCalls() []struct {
+	Ctx         context.Context
 	Testimonial *Testimonial
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}
 	mock.lockCreateTestimonial.RLock()
@@ -464,19 +521,21 @@ func (mock *TestimonialUsecaseMock) CreateTestimonialCalls() []struct {
 }
 
 // DeleteTestimonial calls DeleteTestimonialFunc.
-func (mock *TestimonialUsecaseMock) DeleteTestimonial(id uint) error {
+func (mock *TestimonialUsecaseMock) DeleteTestimonial(ctx context.Context, id uint) error {
 	if mock.DeleteTestimonialFunc == nil {
 		panic("TestimonialUsecaseMock.DeleteTestimonialFunc: method is nil but TestimonialUsecase.DeleteTestimonial was just called")
 	}
 	callInfo := struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}{
-		ID: id,
+		Ctx: ctx,
+		ID:  id,
 	}
 	mock.lockDeleteTestimonial.Lock()
 	mock.calls.DeleteTestimonial = append(mock.calls.DeleteTestimonial, callInfo)
 	mock.lockDeleteTestimonial.Unlock()
-	return mock.DeleteTestimonialFunc(id)
+	return mock.DeleteTestimonialFunc(ctx, id)
 }
 
 // DeleteTestimonialCalls gets all the calls that were made to DeleteTestimonial.
@@ -484,10 +543,12 @@ func (mock *TestimonialUsecaseMock) DeleteTestimonial(id uint) error {
 //
 //	len(mockedTestimonialUsecase.DeleteTestimonialCalls())
 func (mock *TestimonialUsecaseMock) DeleteTestimonialCalls() []struct {
-	ID uint
+	Ctx context.Context
+	ID  uint
 } {
 	var calls []struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}
 	mock.lockDeleteTestimonial.RLock()
 	calls = mock.calls.DeleteTestimonial
@@ -496,19 +557,21 @@ func (mock *TestimonialUsecaseMock) DeleteTestimonialCalls() []struct {
 }
 
 // GetAllTestimonials calls GetAllTestimonialsFunc.
-func (mock *TestimonialUsecaseMock) GetAllTestimonials(filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
+func (mock *TestimonialUsecaseMock) GetAllTestimonials(ctx context.Context, filter *TestimonialFilter) (*MultipleTestimonialResponse, error) {
 	if mock.GetAllTestimonialsFunc == nil {
 		panic("TestimonialUsecaseMock.GetAllTestimonialsFunc: method is nil but TestimonialUsecase.GetAllTestimonials was just called")
 	}
 	callInfo := struct {
+		Ctx    context.Context
 		Filter *TestimonialFilter
 	}{
+		Ctx:    ctx,
 		Filter: filter,
 	}
 	mock.lockGetAllTestimonials.Lock()
 	mock.calls.GetAllTestimonials = append(mock.calls.GetAllTestimonials, callInfo)
 	mock.lockGetAllTestimonials.Unlock()
-	return mock.GetAllTestimonialsFunc(filter)
+	return mock.GetAllTestimonialsFunc(ctx, filter)
 }
 
 // GetAllTestimonialsCalls gets all the calls that were made to GetAllTestimonials.
@@ -516,9 +579,11 @@ func (mock *TestimonialUsecaseMock) GetAllTestimonials(filter *TestimonialFilter
 //
 //	len(mockedTestimonialUsecase.GetAllTestimonialsCalls())
 func (mock *TestimonialUsecaseMock) GetAllTestimonialsCalls() []struct {
+	Ctx    context.Context
 	Filter *TestimonialFilter
 } {
 	var calls []struct {
+		Ctx    context.Context
 		Filter *TestimonialFilter
 	}
 	mock.lockGetAllTestimonials.RLock()
@@ -528,19 +593,21 @@ func (mock *TestimonialUsecaseMock) GetAllTestimonialsCalls() []struct {
 }
 
 // GetTestimonialByID calls GetTestimonialByIDFunc.
-func (mock *TestimonialUsecaseMock) GetTestimonialByID(id uint) (*Testimonial, error) {
+func (mock *TestimonialUsecaseMock) GetTestimonialByID(ctx context.Context, id uint) (*Testimonial, error) {
 	if mock.GetTestimonialByIDFunc == nil {
 		panic("TestimonialUsecaseMock.GetTestimonialByIDFunc: method is nil but TestimonialUsecase.GetTestimonialByID was just called")
 	}
 	callInfo := struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}{
-		ID: id,
+		Ctx: ctx,
+		ID:  id,
 	}
 	mock.lockGetTestimonialByID.Lock()
 	mock.calls.GetTestimonialByID = append(mock.calls.GetTestimonialByID, callInfo)
 	mock.lockGetTestimonialByID.Unlock()
-	return mock.GetTestimonialByIDFunc(id)
+	return mock.GetTestimonialByIDFunc(ctx, id)
 }
 
 // GetTestimonialByIDCalls gets all the calls that were made to GetTestimonialByID.
@@ -548,10 +615,12 @@ func (mock *TestimonialUsecaseMock) GetTestimonialByID(id uint) (*Testimonial, e
 //
 //	len(mockedTestimonialUsecase.GetTestimonialByIDCalls())
 func (mock *TestimonialUsecaseMock) GetTestimonialByIDCalls() []struct {
-	ID uint
+	Ctx context.Context
+	ID  uint
 } {
 	var calls []struct {
-		ID uint
+		Ctx context.Context
+		ID  uint
 	}
 	mock.lockGetTestimonialByID.RLock()
 	calls = mock.calls.GetTestimonialByID
@@ -560,19 +629,21 @@ func (mock *TestimonialUsecaseMock) GetTestimonialByIDCalls() []struct {
 }
 
 // UpdateTestimonial calls UpdateTestimonialFunc.
-func (mock *TestimonialUsecaseMock) UpdateTestimonial(testimonial *Testimonial) (*Testimonial, error) {
+func (mock *TestimonialUsecaseMock) UpdateTestimonial(ctx context.Context, testimonial *Testimonial) (*Testimonial, error) {
 	if mock.UpdateTestimonialFunc == nil {
 		panic("TestimonialUsecaseMock.UpdateTestimonialFunc: method is nil but TestimonialUsecase.UpdateTestimonial was just called")
 	}
 	callInfo := struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}{
+		Ctx:         ctx,
 		Testimonial: testimonial,
 	}
 	mock.lockUpdateTestimonial.Lock()
 	mock.calls.UpdateTestimonial = append(mock.calls.UpdateTestimonial, callInfo)
 	mock.lockUpdateTestimonial.Unlock()
-	return mock.UpdateTestimonialFunc(testimonial)
+	return mock.UpdateTestimonialFunc(ctx, testimonial)
 }
 
 // UpdateTestimonialCalls gets all the calls that were made to UpdateTestimonial.
@@ -580,9 +651,11 @@ func (mock *TestimonialUsecaseMock) UpdateTestimonial(testimonial *Testimonial)
 //
 //	len(mockedTestimonialUsecase.UpdateTestimonialCalls())
 func (mock *TestimonialUsecaseMock) UpdateTestimonialCalls() []struct {
+	Ctx         context.Context
 	Testimonial *Testimonial
 } {
 	var calls []struct {
+		Ctx         context.Context
 		Testimonial *Testimonial
 	}
 	mock.lockUpdateTestimonial.RLock()
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type Tutor struct {
 	Model
 	FirstName      string `form:"first_name" json:"first_name,omitempty"`
@@ -7,12 +12,25 @@ type Tutor struct {
 	EducationLevel string `form:"education_level" json:"education_level,omitempty"`
 	Document       string `json:"document,omitempty"`
 	Image          string `json:"image,omitempty"`
-	PhoneNumber    string `form:"phone_number" json:"phone_number,omitempty"`
+	PhoneNumber    string `form:"phone_number" json:"phone_number,omitempty" gorm:"index"`
 	DayPerWeek     int    `form:"day_per_week" json:"day_per_week,omitempty"`
 	HrPerDay       int    `form:"hr_per_day" json:"hr_per_day,omitempty"`
 	Verified       bool   `form:"verified" json:"verified,omitempty"`
 	Email          string `form:"email" json:"email,omitempty"`
 	Address        string `form:"address" json:"address"`
+	// Language is the tutor's language for notifications, e.g. "en" or
+	// "am"; the default language is used when it is empty
+	Language string `form:"language" json:"language,omitempty"`
+	// DocumentExpiresAt is when the submitted document stops being valid;
+	// admins are reminded ahead of it
+	DocumentExpiresAt *time.Time `form:"document_expires_at" time_format:"2006-01-02" json:"document_expires_at,omitempty" gorm:"index"`
+	// VerifiedAt is when the tutor was last marked verified
+	VerifiedAt *time.Time `form:"-" json:"verified_at,omitempty" gorm:"index"`
+
+	// Suspicious public registrations are held for review and left out of
+	// the default list until an admin releases them
+	Quarantined      bool   `form:"-" json:"quarantined" gorm:"index"`
+	QuarantineReason string `form:"-" json:"quarantine_reason,omitempty"`
 }
 
 type TutorFilter struct {
@@ -23,6 +41,9 @@ type TutorFilter struct {
 	Query          string
 	MinHrPerDay    int
 	MaxHrPerDay    int
+	Quarantined    bool
+	// Deleted lists the trash instead
+	Deleted bool
 	// Pagination & sorting
 	Page      int
 	Limit     int
@@ -36,17 +57,37 @@ type MultipleTutorResponse struct {
 }
 
 type TutorRepository interface {
-	Create(*Tutor) (*Tutor, error)
-	GetAll(*TutorFilter) (MultipleTutorResponse, error)
-	GetByID(uint) (*Tutor, error)
-	Update(uint, *Tutor) (*Tutor, error)
-	Delete(uint) error
+	Create(context.Context, *Tutor) (*Tutor, error)
+	GetAll(context.Context, *TutorFilter) (MultipleTutorResponse, error)
+	// CreateMany adds all the tutors or, on error, none of them. Unless it
+	// is nil, check is called with each tutor first, inside the
+	// transaction and with the table locked against other writers; repo
+	// reads within the transaction. An error from check rolls back.
+	CreateMany(ctx context.Context, tutors []*Tutor, check func(repo TutorRepository, t *Tutor) error) error
+	// Each streams every matching tutor, ignoring paging and sorting.
+	Each(ctx context.Context, filter *TutorFilter, fn func(*Tutor) error) error
+	GetByID(context.Context, uint) (*Tutor, error)
+	Update(context.Context, uint, *Tutor) (*Tutor, error)
+	Delete(context.Context, uint) error
+	// GetByPhoneNumber returns the tutors with the given normalized number.
+	GetByPhoneNumber(ctx context.Context, phone string) ([]Tutor, error)
+	// Release clears the quarantine flag.
+	Release(ctx context.Context, id uint) error
+	// Bulk applies fn to the tutors with the given IDs, or else those
+	// matching filter, and saves the changed ones in one transaction.
+	Bulk(ctx context.Context, ids []uint, filter *TutorFilter, fn BulkFunc[Tutor]) (*BulkResult, error)
 }
 type TutorUsecase interface {
-	Create(*Tutor) (*Tutor, error)
-	GetAll(*TutorFilter) (MultipleTutorResponse, error)
-	GetByID(uint) (*Tutor, error)
-	Update(uint, *Tutor) (*Tutor, error)
-	Delete(uint) error
-	Verify(uint) error
+	Create(context.Context, *Tutor) (*Tutor, error)
+	GetAll(context.Context, *TutorFilter) (MultipleTutorResponse, error)
+	GetByID(context.Context, uint) (*Tutor, error)
+	Update(context.Context, uint, *Tutor) (*Tutor, error)
+	Delete(context.Context, uint) error
+	Verify(context.Context, uint) error
+	// Release lets a quarantined registration through as if it had just
+	// been submitted.
+	Release(ctx context.Context, id uint) error
+	// Bulk applies one action to many tutors at once. Tutors it fails for
+	// are reported in the result and left as they were.
+	Bulk(ctx context.Context, req *TutorBulkRequest) (*BulkResult, error)
 }
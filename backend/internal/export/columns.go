@@ -0,0 +1,94 @@
+package export
+
+import (
+	"fmt"
+	"hiyab-tutor/internal/domain"
+)
+
+// Column is one column of a sheet of T.
+type Column[T any] struct {
+	Name  string
+	Value func(*T) any
+}
+
+// BookingColumns are the columns a bookings export can have, in their
+// default order.
+var BookingColumns = []Column[domain.Booking]{
+	{"id", func(b *domain.Booking) any { return b.ID }},
+	{"created_at", func(b *domain.Booking) any { return b.CreatedAt }},
+	{"first_name", func(b *domain.Booking) any { return b.FirstName }},
+	{"last_name", func(b *domain.Booking) any { return b.LastName }},
+	{"gender", func(b *domain.Booking) any { return b.Gender }},
+	{"age", func(b *domain.Booking) any { return b.Age }},
+	{"grade", func(b *domain.Booking) any { return b.Grade }},
+	{"phone_number", func(b *domain.Booking) any { return b.PhoneNumber }},
+	{"address", func(b *domain.Booking) any { return b.Address }},
+	{"day_per_week", func(b *domain.Booking) any { return b.DayPerWeek }},
+	{"hr_per_day", func(b *domain.Booking) any { return b.HrPerDay }},
+	{"assigned", func(b *domain.Booking) any { return b.Assigned }},
+	{"assigned_at", func(b *domain.Booking) any { return b.AssignedAt }},
+	{"tutor_id", func(b *domain.Booking) any { return b.TutorID }},
+	{"quarantined", func(b *domain.Booking) any { return b.Quarantined }},
+}
+
+// TutorColumns are the columns a tutors export can have, in their default
+// order. Uploaded files are left out.
+var TutorColumns = []Column[domain.Tutor]{
+	{"id", func(t *domain.Tutor) any { return t.ID }},
+	{"created_at", func(t *domain.Tutor) any { return t.CreatedAt }},
+	{"first_name", func(t *domain.Tutor) any { return t.FirstName }},
+	{"last_name", func(t *domain.Tutor) any { return t.LastName }},
+	{"phone_number", func(t *domain.Tutor) any { return t.PhoneNumber }},
+	{"email", func(t *domain.Tutor) any { return t.Email }},
+	{"address", func(t *domain.Tutor) any { return t.Address }},
+	{"education_level", func(t *domain.Tutor) any { return t.EducationLevel }},
+	{"day_per_week", func(t *domain.Tutor) any { return t.DayPerWeek }},
+	{"hr_per_day", func(t *domain.Tutor) any { return t.HrPerDay }},
+	{"verified", func(t *domain.Tutor) any { return t.Verified }},
+	{"verified_at", func(t *domain.Tutor) any { return t.VerifiedAt }},
+	{"document_expires_at", func(t *domain.Tutor) any { return t.DocumentExpiresAt }},
+	{"quarantined", func(t *domain.Tutor) any { return t.Quarantined }},
+}
+
+// Select picks the named columns of all, in the order given. No names
+// means all columns.
+func Select[T any](all []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	byName := make(map[string]Column[T], len(all))
+	for _, c := range all {
+		byName[c.Name] = c
+	}
+	selected := make([]Column[T], 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", domain.ErrUnknownColumn, name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// Names lists the names of columns.
+func Names[T any](columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Row is the values of columns for v.
+func Row[T any](columns []Column[T], v *T) []any {
+	row := make([]any, len(columns))
+	for i, c := range columns {
+		row[i] = c.Value(v)
+	}
+	return row
+}
@@ -0,0 +1,153 @@
+// Package export writes bookings and tutors as CSV or XLSX sheets, one
+// row at a time so large exports don't have to fit in memory.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer writes a sheet row by row. Nothing is complete until Close.
+type Writer interface {
+	Write(row []any) error
+	Close() error
+}
+
+// NewWriter writes a sheet in format to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case domain.FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case domain.FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, domain.ErrInvalidFormat
+}
+
+// ContentType is the MIME type of format.
+func ContentType(format string) string {
+	if format == domain.FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		record[i] = escapeFormula(text(v))
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// text formats a cell value for CSV; empty pointers are empty cells.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case *uint:
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*v), 10)
+	}
+	return fmt.Sprint(v)
+}
+
+// escapeFormula keeps spreadsheet programs from running submitted text
+// as a formula. Phone numbers such as +251... are left alone.
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + s
+	case '+', '-':
+		if _, err := strconv.ParseFloat(strings.ReplaceAll(s, " ", ""), 64); err != nil {
+			return "'" + s
+		}
+	}
+	return s
+}
+
+// xlsxWriter streams rows into a single sheet. excelize keeps them in a
+// temporary file once they outgrow its buffer.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: file, stream: stream}, nil
+}
+
+func (x *xlsxWriter) Write(row []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(row))
+	for i, v := range row {
+		values[i] = cellValue(v)
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// cellValue keeps numbers and booleans typed, and writes text as text so
+// nothing is taken for a formula.
+func cellValue(v any) any {
+	switch v := v.(type) {
+	case int, uint, bool:
+		return v
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return text(v)
+}
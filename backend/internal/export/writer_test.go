@@ -0,0 +1,78 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestSelect(t *testing.T) {
+	all, err := Select(BookingColumns, nil)
+	require.NoError(t, err)
+	require.Len(t, all, len(BookingColumns))
+
+	columns, err := Select(TutorColumns, []string{"phone_number", "id", "phone_number"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"phone_number", "id"}, Names(columns))
+
+	_, err = Select(TutorColumns, []string{"id", "document"})
+	require.ErrorIs(t, err, domain.ErrUnknownColumn)
+	require.ErrorContains(t, err, `"document"`)
+}
+
+func TestCSV(t *testing.T) {
+	columns, err := Select(BookingColumns, []string{"id", "first_name", "phone_number", "assigned_at", "tutor_id", "created_at"})
+	require.NoError(t, err)
+	tutorID := uint(4)
+	created := time.Date(2025, 3, 19, 9, 30, 0, 0, time.FixedZone("EAT", 3*60*60))
+
+	var buf bytes.Buffer
+	w, err := NewWriter(domain.FormatCSV, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write([]any{"id", "first_name", "phone_number", "assigned_at", "tutor_id", "created_at"}))
+	require.NoError(t, w.Write(Row(columns, &domain.Booking{Model: domain.Model{ID: 1, CreatedAt: created}, FirstName: "=HYPERLINK(\"x\")", PhoneNumber: "+251911234567", TutorID: &tutorID})))
+	require.NoError(t, w.Write(Row(columns, &domain.Booking{Model: domain.Model{ID: 2}, FirstName: "-sum", PhoneNumber: "@x"})))
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", `'=HYPERLINK("x")`, "+251911234567", "", "4", "2025-03-19T06:30:00Z"}, records[1])
+	require.Equal(t, []string{"'-sum", "'@x", ""}, records[2][1:4])
+}
+
+func TestXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(domain.FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write([]any{"id", "first_name", "verified", "verified_at"}))
+	require.NoError(t, w.Write(Row(TutorColumns[:1], &domain.Tutor{Model: domain.Model{ID: 9}})))
+	columns, err := Select(TutorColumns, []string{"id", "first_name", "verified", "verified_at"})
+	require.NoError(t, err)
+	require.NoError(t, w.Write(Row(columns, &domain.Tutor{Model: domain.Model{ID: 10}, FirstName: "=1+1", Verified: true})))
+	require.NoError(t, w.Close())
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"id", "first_name", "verified", "verified_at"},
+		{"9"},
+		{"10", "=1+1", "TRUE"},
+	}, rows)
+	// Text stays text
+	formula, err := f.GetCellFormula("Sheet1", "B3")
+	require.NoError(t, err)
+	require.Empty(t, formula)
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{})
+	require.ErrorIs(t, err, domain.ErrInvalidFormat)
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Database pings the pool. The pool statistics go in the details.
+func Database(db *gorm.DB) Check {
+	return Check{Name: "database", Critical: true, Run: func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		details := map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+			"wait_count":       stats.WaitCount,
+			"wait_duration":    stats.WaitDuration.String(),
+		}
+		return details, sqlDB.PingContext(ctx)
+	}}
+}
+
+// Migrations checks that every model's table and columns exist, which
+// catches an instance started against a schema it doesn't know. Schemas
+// only move forward, so once everything is there the catalog isn't asked
+// again.
+func Migrations(db *gorm.DB, models ...any) Check {
+	var current atomic.Bool
+	return Check{Name: "migrations", Critical: true, Run: func(ctx context.Context) (map[string]any, error) {
+		details := map[string]any{"tables": len(models)}
+		if current.Load() {
+			return details, nil
+		}
+		tx := db.WithContext(ctx)
+		var missing []string
+		for _, model := range models {
+			stmt := &gorm.Statement{DB: tx}
+			if err := stmt.Parse(model); err != nil {
+				return nil, err
+			}
+			table := stmt.Schema.Table
+			if !tx.Migrator().HasTable(table) {
+				missing = append(missing, table)
+				continue
+			}
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName != "" && !tx.Migrator().HasColumn(model, field.DBName) {
+					missing = append(missing, table+"."+field.DBName)
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			details["missing"] = missing
+			return details, fmt.Errorf("%d tables or columns are missing", len(missing))
+		}
+		current.Store(true)
+		return details, nil
+	}}
+}
+
+// Writable checks that files can be created in dir, where uploads go.
+func Writable(name, dir string) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (map[string]any, error) {
+		details := map[string]any{"dir": dir}
+		f, err := os.CreateTemp(dir, ".health-*")
+		if err != nil {
+			return details, err
+		}
+		_, err = f.WriteString("ok")
+		return details, errors.Join(err, f.Close(), os.Remove(f.Name()))
+	}}
+}
+
+// errUnsupported is returned by diskUsage where there is no statfs
+var errUnsupported = errors.New("disk usage not supported on this platform")
+
+// DiskSpace reports the free space where dir lives and fails when less
+// than minFree bytes are left.
+func DiskSpace(dir string, minFree uint64) Check {
+	return Check{Name: "disk", Run: func(ctx context.Context) (map[string]any, error) {
+		free, total, err := diskUsage(dir)
+		if errors.Is(err, errUnsupported) {
+			return map[string]any{"dir": dir, "supported": false}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		details := map[string]any{"dir": dir, "free_bytes": free, "total_bytes": total, "min_free_bytes": minFree}
+		if free < minFree {
+			return details, fmt.Errorf("%d MB free, want at least %d MB", free>>20, minFree>>20)
+		}
+		return details, nil
+	}}
+}
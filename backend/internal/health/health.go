@@ -0,0 +1,181 @@
+// Package health runs the checks behind the liveness and readiness probes.
+// A failing check is reported, never fatal: the orchestrator decides what
+// to do with an instance that isn't ready.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Overall and per-check states
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// DefaultTimeout bounds a single check unless the Checker says otherwise.
+const DefaultTimeout = 2 * time.Second
+
+// Check is one named probe.
+type Check struct {
+	Name string
+	// Critical checks take the instance out of rotation when they fail;
+	// the others only mark it degraded
+	Critical bool
+	// Run returns details worth showing to an operator and an error when
+	// the dependency is unhealthy
+	Run func(ctx context.Context) (map[string]any, error)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status   string         `json:"status"`
+	Critical bool           `json:"critical"`
+	Error    string         `json:"error,omitempty"`
+	Details  map[string]any `json:"details,omitempty"`
+	Duration string         `json:"duration"`
+}
+
+// Report is the outcome of every check.
+type Report struct {
+	Status    string            `json:"status"`
+	Checks    map[string]Result `json:"checks,omitempty"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// Checker holds a set of checks. It is safe to register checks while
+// probes are being served.
+type Checker struct {
+	// Timeout bounds every check; zero means DefaultTimeout
+	Timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []Check
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Register adds checks, replacing any with the same name.
+func (c *Checker) Register(checks ...Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, check := range checks {
+		replaced := false
+		for i := range c.checks {
+			if c.checks[i].Name == check.Name {
+				c.checks[i], replaced = check, true
+			}
+		}
+		if !replaced {
+			c.checks = append(c.checks, check)
+		}
+	}
+}
+
+// Names lists the registered checks in alphabetical order.
+func (c *Checker) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, len(c.checks))
+	for i, check := range c.checks {
+		names[i] = check.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs every check at once and sums them up: down when a critical
+// check fails, degraded when any other does and up otherwise. A nil
+// Checker is always up.
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{Status: StatusUp, CheckedAt: time.Now().UTC()}
+	if c == nil {
+		return report
+	}
+	c.mu.RLock()
+	checks := append([]Check(nil), c.checks...)
+	c.mu.RUnlock()
+	if len(checks) == 0 {
+		return report
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check, timeout)
+		}()
+	}
+	wg.Wait()
+
+	report.Checks = make(map[string]Result, len(checks))
+	for i, check := range checks {
+		res := results[i]
+		report.Checks[check.Name] = res
+		switch {
+		case res.Status == StatusUp:
+		case check.Critical:
+			report.Status = StatusDown
+		case report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run runs one check, turning a timeout or a panic into a failure.
+func run(ctx context.Context, check Check, timeout time.Duration) (res Result) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	res.Critical = check.Critical
+	defer func() { res.Duration = time.Since(start).Round(time.Microsecond).String() }()
+
+	type outcome struct {
+		details map[string]any
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("check panicked: %v", r)}
+			}
+		}()
+		details, err := check.Run(ctx)
+		done <- outcome{details, err}
+	}()
+
+	select {
+	case out := <-done:
+		res.Details = out.details
+		if out.err != nil {
+			res.Status, res.Error = failed(check), out.err.Error()
+			return res
+		}
+		res.Status = StatusUp
+	case <-ctx.Done():
+		res.Status, res.Error = failed(check), fmt.Sprintf("timed out after %s", timeout)
+	}
+	return res
+}
+
+func failed(check Check) string {
+	if check.Critical {
+		return StatusDown
+	}
+	return StatusDegraded
+}
@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"hiyab-tutor/internal/database/dbtest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ok(context.Context) (map[string]any, error) { return nil, nil }
+
+func fail(context.Context) (map[string]any, error) { return nil, errors.New("boom") }
+
+func TestChecker_Run(t *testing.T) {
+	var nilChecker *Checker
+	require.Equal(t, StatusUp, nilChecker.Run(context.Background()).Status)
+	require.Equal(t, StatusUp, NewChecker().Run(context.Background()).Status)
+
+	c := NewChecker(Check{Name: "db", Critical: true, Run: ok}, Check{Name: "disk", Run: ok})
+	require.Equal(t, StatusUp, c.Run(context.Background()).Status)
+
+	// A non-critical failure degrades
+	c.Register(Check{Name: "disk", Run: fail})
+	report := c.Run(context.Background())
+	require.Equal(t, StatusDegraded, report.Status)
+	require.Equal(t, StatusUp, report.Checks["db"].Status)
+	require.Equal(t, StatusDegraded, report.Checks["disk"].Status)
+	require.Equal(t, "boom", report.Checks["disk"].Error)
+	require.Equal(t, []string{"db", "disk"}, c.Names())
+
+	// A critical one takes the instance down
+	c.Register(Check{Name: "db", Critical: true, Run: func(context.Context) (map[string]any, error) {
+		panic("driver bug")
+	}})
+	report = c.Run(context.Background())
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, "check panicked: driver bug", report.Checks["db"].Error)
+	require.True(t, report.Checks["db"].Critical)
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker(Check{Name: "slow", Critical: true, Run: func(ctx context.Context) (map[string]any, error) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		return nil, nil
+	}})
+	c.Timeout = 10 * time.Millisecond
+	report := c.Run(context.Background())
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, "timed out after 10ms", report.Checks["slow"].Error)
+}
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+type widgetV2 struct {
+	ID    uint
+	Name  string
+	Color string
+}
+
+func (widgetV2) TableName() string { return "widgets" }
+
+func TestDatabaseAndMigrations(t *testing.T) {
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&widget{}))
+
+	c := NewChecker(Database(db), Migrations(db, &widget{}))
+	report := c.Run(context.Background())
+	require.Equal(t, StatusUp, report.Status, report)
+	require.Contains(t, report.Checks["database"].Details, "open_connections")
+
+	c.Register(Migrations(db, &widgetV2{}))
+	report = c.Run(context.Background())
+	require.Equal(t, StatusDown, report.Status)
+	require.Equal(t, []string{"widgets.color"}, report.Checks["migrations"].Details["missing"])
+}
+
+func TestWritableAndDiskSpace(t *testing.T) {
+	dir := t.TempDir()
+	c := NewChecker(Writable("storage", dir), DiskSpace(dir, 1))
+	report := c.Run(context.Background())
+	require.Equal(t, StatusUp, report.Status, report)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	c = NewChecker(Writable("storage", filepath.Join(dir, "missing")), DiskSpace(dir, 1<<62))
+	report = c.Run(context.Background())
+	require.Equal(t, StatusDegraded, report.Status)
+	require.Equal(t, StatusDegraded, report.Checks["storage"].Status)
+	require.Equal(t, StatusDegraded, report.Checks["disk"].Status)
+}
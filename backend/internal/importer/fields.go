@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field is a column that can be imported into a T. Set gets the trimmed
+// cell, which is never empty.
+type Field[T any] struct {
+	Name     string
+	Required bool
+	Set      func(v *T, cell string) error
+}
+
+// BookingFields are the columns a bookings sheet may have.
+var BookingFields = []Field[domain.Booking]{
+	{"first_name", true, func(b *domain.Booking, s string) error { b.FirstName = s; return nil }},
+	{"last_name", false, func(b *domain.Booking, s string) error { b.LastName = s; return nil }},
+	{"gender", false, func(b *domain.Booking, s string) error { b.Gender = s; return nil }},
+	{"age", false, func(b *domain.Booking, s string) error { return integer(&b.Age, s) }},
+	{"grade", true, func(b *domain.Booking, s string) error { return integer(&b.Grade, s) }},
+	{"phone_number", true, func(b *domain.Booking, s string) error { b.PhoneNumber = s; return nil }},
+	{"address", false, func(b *domain.Booking, s string) error { b.Address = s; return nil }},
+	{"day_per_week", false, func(b *domain.Booking, s string) error { return integer(&b.DayPerWeek, s) }},
+	{"hr_per_day", false, func(b *domain.Booking, s string) error { return integer(&b.HrPerDay, s) }},
+}
+
+// TutorFields are the columns a tutors sheet may have. Documents and
+// photos can't be imported.
+var TutorFields = []Field[domain.Tutor]{
+	{"first_name", true, func(t *domain.Tutor, s string) error { t.FirstName = s; return nil }},
+	{"last_name", false, func(t *domain.Tutor, s string) error { t.LastName = s; return nil }},
+	{"phone_number", true, func(t *domain.Tutor, s string) error { t.PhoneNumber = s; return nil }},
+	{"email", true, func(t *domain.Tutor, s string) error { t.Email = s; return nil }},
+	{"address", false, func(t *domain.Tutor, s string) error { t.Address = s; return nil }},
+	{"education_level", true, func(t *domain.Tutor, s string) error { t.EducationLevel = s; return nil }},
+	{"day_per_week", false, func(t *domain.Tutor, s string) error { return integer(&t.DayPerWeek, s) }},
+	{"hr_per_day", false, func(t *domain.Tutor, s string) error { return integer(&t.HrPerDay, s) }},
+	{"document_expires_at", false, func(t *domain.Tutor, s string) error { return date(&t.DocumentExpiresAt, s) }},
+}
+
+// Header matches the header row to fields: columns[i] is the field of
+// cell i, or nil for blank and ignored columns, such as the id column of
+// an export. Other unknown columns and missing required ones are errors.
+func Header[T any](fields []Field[T], row []string, ignored []string) ([]*Field[T], error) {
+	byName := make(map[string]*Field[T], len(fields))
+	for i := range fields {
+		byName[fields[i].Name] = &fields[i]
+	}
+	skip := make(map[string]bool, len(ignored))
+	for _, name := range ignored {
+		skip[name] = true
+	}
+	columns := make([]*Field[T], len(row))
+	seen := map[string]bool{}
+	for i, cell := range row {
+		name := ColumnName(cell)
+		f, ok := byName[name]
+		if !ok {
+			if name == "" || skip[name] {
+				continue
+			}
+			return nil, fmt.Errorf("%w %q", domain.ErrUnknownColumn, cell)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column %q appears twice", cell)
+		}
+		seen[name] = true
+		columns[i] = f
+	}
+	var missing []string
+	for _, f := range fields {
+		if f.Required && !seen[f.Name] {
+			missing = append(missing, f.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", domain.ErrMissingColumn, strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+// ColumnName is a header cell as a column name: "Phone Number" is
+// phone_number.
+func ColumnName(cell string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(cell)), " ", "_")
+}
+
+func integer(dst *int, s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		// Spreadsheets may turn whole numbers into 5.0
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int(f)) {
+			return errors.New("must be a whole number")
+		}
+		n = int(f)
+	}
+	*dst = n
+	return nil
+}
+
+func date(dst **time.Time, s string) error {
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			*dst = &t
+			return nil
+		}
+	}
+	return errors.New("must be a date (YYYY-MM-DD)")
+}
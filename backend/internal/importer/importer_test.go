@@ -0,0 +1,83 @@
+package importer
+
+import (
+	"bytes"
+	"hiyab-tutor/internal/domain"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func readAll(t *testing.T, r Reader) [][]string {
+	t.Helper()
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+	require.NoError(t, r.Close())
+	return rows
+}
+
+func TestCSVReader(t *testing.T) {
+	r, err := NewReader(domain.FormatCSV, strings.NewReader("\ufefffirst_name,grade\nAbebe,5\nSara\n"))
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"first_name", "grade"}, {"Abebe", "5"}, {"Sara"}}, readAll(t, r))
+}
+
+func TestXLSXReader(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]any{"first_name", "grade"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]any{"Abebe", 5}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	r, err := NewReader(domain.FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"first_name", "grade"}, {"Abebe", "5"}}, readAll(t, r))
+
+	_, err = NewReader(domain.FormatXLSX, strings.NewReader("not a workbook"))
+	require.Error(t, err)
+	_, err = NewReader("ods", strings.NewReader(""))
+	require.ErrorIs(t, err, domain.ErrInvalidFormat)
+}
+
+func TestHeader(t *testing.T) {
+	columns, err := Header(BookingFields, []string{"ID", "First Name", "grade", "", "Phone_Number"}, []string{"id"})
+	require.NoError(t, err)
+	require.Nil(t, columns[0])
+	require.Equal(t, "first_name", columns[1].Name)
+	require.Nil(t, columns[3])
+	require.Equal(t, "phone_number", columns[4].Name)
+
+	_, err = Header(BookingFields, []string{"first_name", "grade", "phone_number", "school"}, nil)
+	require.ErrorIs(t, err, domain.ErrUnknownColumn)
+	_, err = Header(TutorFields, []string{"first_name", "phone_number"}, nil)
+	require.ErrorIs(t, err, domain.ErrMissingColumn)
+	require.ErrorContains(t, err, "email, education_level")
+	_, err = Header(BookingFields, []string{"first_name", "grade", "grade", "phone_number"}, nil)
+	require.Error(t, err)
+}
+
+func TestFields(t *testing.T) {
+	var b domain.Booking
+	require.Equal(t, "grade", BookingFields[4].Name)
+	require.NoError(t, BookingFields[4].Set(&b, "7.0"))
+	require.Equal(t, 7, b.Grade)
+	require.EqualError(t, BookingFields[4].Set(&b, "seven"), "must be a whole number")
+	require.Error(t, BookingFields[4].Set(&b, "7.5"))
+
+	var tutor domain.Tutor
+	expires := TutorFields[len(TutorFields)-1]
+	require.Equal(t, "document_expires_at", expires.Name)
+	require.NoError(t, expires.Set(&tutor, "2026-01-31"))
+	require.Equal(t, "2026-01-31", tutor.DocumentExpiresAt.Format("2006-01-02"))
+	require.Error(t, expires.Set(&tutor, "31/01/2026"))
+}
@@ -0,0 +1,93 @@
+// Package importer reads bookings and tutors from CSV or XLSX sheets, the
+// formats internal/export writes.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Reader returns a sheet's rows one by one, then io.EOF.
+type Reader interface {
+	Read() ([]string, error)
+	Close() error
+}
+
+// NewReader reads a sheet in format from r. Only the first sheet of a
+// workbook is read.
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case domain.FormatCSV:
+		c := csv.NewReader(r)
+		// Rows may be short; missing cells are empty
+		c.FieldsPerRecord = -1
+		return &csvReader{r: c}, nil
+	case domain.FormatXLSX:
+		return newXLSXReader(r)
+	}
+	return nil, domain.ErrInvalidFormat
+}
+
+type csvReader struct {
+	r    *csv.Reader
+	read bool
+}
+
+func (c *csvReader) Read() ([]string, error) {
+	row, err := c.r.Read()
+	if err == nil && !c.read {
+		// Spreadsheet programs start UTF-8 CSV files with a byte order mark
+		c.read = true
+		if len(row) > 0 {
+			row[0] = strings.TrimPrefix(row[0], "\ufeff")
+		}
+	}
+	return row, err
+}
+
+func (c *csvReader) Close() error { return nil }
+
+// xlsxReader walks the rows of the first sheet. The workbook itself has
+// to be read whole, since it is a zip archive.
+type xlsxReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func newXLSXReader(r io.Reader) (*xlsxReader, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sheet := file.GetSheetName(0)
+	if sheet == "" {
+		file.Close()
+		return nil, errors.New("workbook has no sheets")
+	}
+	rows, err := file.Rows(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxReader{file: file, rows: rows}, nil
+}
+
+func (x *xlsxReader) Read() ([]string, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return x.rows.Columns()
+}
+
+func (x *xlsxReader) Close() error {
+	x.rows.Close()
+	return x.file.Close()
+}
@@ -0,0 +1,307 @@
+// Package jobs runs background work stored in the database, so requests
+// can hand off slow work and nothing is lost when the process restarts.
+// Any number of processes may run workers against the same table; each
+// job is claimed by exactly one of them.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// PollInterval is how long an idle worker waits before looking again
+	PollInterval = 2 * time.Second
+	// LockTimeout bounds one run of a handler. Jobs locked for longer
+	// belong to a worker that died and are handed out again.
+	LockTimeout   = 15 * time.Minute
+	maxRetryDelay = time.Hour
+)
+
+// permanentError marks a failure that retrying won't fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job goes straight to dead instead of being
+// retried, e.g. when the record it refers to is gone.
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+type handler func(ctx context.Context, payload []byte) error
+
+// Queue stores jobs and runs them on a pool of workers. It is also the
+// usecase behind the admin endpoints.
+type Queue struct {
+	repo domain.JobRepository
+	// MaxAttempts is how often a job is tried before it is dead
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry; it doubles every time
+	RetryDelay time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+
+	now     func() time.Time
+	name    string
+	stop    context.CancelFunc
+	abort   context.CancelFunc
+	running sync.WaitGroup
+}
+
+var (
+	_ domain.JobUsecase  = (*Queue)(nil)
+	_ domain.JobEnqueuer = (*Queue)(nil)
+)
+
+func NewQueue(repo domain.JobRepository, maxAttempts int, retryDelay time.Duration) *Queue {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	host, _ := os.Hostname()
+	return &Queue{
+		repo:        repo,
+		MaxAttempts: maxAttempts,
+		RetryDelay:  retryDelay,
+		handlers:    map[string]handler{},
+		now:         time.Now,
+		name:        fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+func NewQueueFromConfig(c *config.Config, repo domain.JobRepository) *Queue {
+	return NewQueue(repo, c.JobMaxAttempts, time.Duration(c.JobRetryDelaySeconds)*time.Second)
+}
+
+// Handle registers fn for jobs of jobType. Their payload is decoded into a
+// T first; one that doesn't decode kills the job without calling fn.
+func Handle[T any](q *Queue, jobType string, fn func(ctx context.Context, payload T) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = func(ctx context.Context, raw []byte) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("decoding payload: %w", err))
+		}
+		return fn(ctx, payload)
+	}
+}
+
+func (q *Queue) handler(jobType string) handler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.handlers[jobType]
+}
+
+// Enqueue stores a job for a registered type. It runs at runAt, or as soon
+// as a worker is free when runAt is zero or past.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, runAt time.Time) (*domain.Job, error) {
+	if q.handler(jobType) == nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownJobType, jobType)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if runAt.IsZero() {
+		runAt = q.now()
+	}
+	job := &domain.Job{
+		Type:        jobType,
+		Payload:     string(raw),
+		Status:      domain.JobStatusPending,
+		RunAt:       runAt,
+		MaxAttempts: q.MaxAttempts,
+	}
+	if err := q.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (q *Queue) GetAll(ctx context.Context, f *domain.JobFilter) (*domain.MultipleJobs, error) {
+	return q.repo.GetAll(ctx, f)
+}
+
+func (q *Queue) GetByID(ctx context.Context, id uint) (*domain.Job, error) {
+	return q.repo.GetByID(ctx, id)
+}
+
+// Retry moves a dead job back to pending with fresh attempts, or makes a
+// pending one due right away.
+func (q *Queue) Retry(ctx context.Context, id uint) (*domain.Job, error) {
+	requeued, err := q.repo.Requeue(ctx, id, q.now())
+	if err != nil {
+		return nil, err
+	}
+	job, err := q.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !requeued {
+		return nil, domain.ErrJobNotRetryable
+	}
+	return job, nil
+}
+
+// RunDue claims up to limit due jobs for worker, runs them one after the
+// other and returns how many it ran.
+func (q *Queue) RunDue(ctx context.Context, worker string, limit int) (int, error) {
+	jobs, err := q.repo.Claim(ctx, worker, q.now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	for i := range jobs {
+		q.process(ctx, &jobs[i])
+	}
+	return len(jobs), nil
+}
+
+// Start runs workers goroutines that poll for due jobs until Stop.
+func (q *Queue) Start(workers int) {
+	ctx, stop := context.WithCancel(context.Background())
+	// Jobs keep running after Stop until they are done or Stop gives up
+	jobCtx, abort := context.WithCancel(context.Background())
+	q.stop, q.abort = stop, abort
+	for i := range workers {
+		q.running.Add(1)
+		go q.work(ctx, jobCtx, fmt.Sprintf("%s/%d", q.name, i))
+	}
+	q.running.Add(1)
+	go q.releaseStale(ctx)
+}
+
+// Stop stops claiming jobs and waits for the ones running to finish. When
+// ctx is done first their contexts are cancelled; whatever didn't finish
+// is handed out again once LockTimeout has passed.
+func (q *Queue) Stop(ctx context.Context) error {
+	if q.stop == nil {
+		return nil
+	}
+	q.stop()
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.abort()
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work(ctx, jobCtx context.Context, worker string) {
+	defer q.running.Done()
+	logger := logging.FromContext(ctx).With("worker", worker)
+	for {
+		n, err := q.RunDue(logging.WithLogger(jobCtx, logger), worker, 1)
+		if err != nil {
+			logger.Error("claiming jobs failed", "error", err)
+		}
+		if n > 0 && ctx.Err() == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+func (q *Queue) releaseStale(ctx context.Context) {
+	defer q.running.Done()
+	ticker := time.NewTicker(LockTimeout / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := q.repo.Release(ctx, q.now().Add(-LockTimeout))
+			if err != nil {
+				logging.FromContext(ctx).Error("releasing stale jobs failed", "error", err)
+			} else if n > 0 {
+				logging.FromContext(ctx).Warn("released stale jobs", "count", n)
+			}
+		}
+	}
+}
+
+// process runs one claimed job and records the outcome.
+func (q *Queue) process(ctx context.Context, job *domain.Job) {
+	logger := logging.FromContext(ctx).With("job_id", job.ID, "job_type", job.Type, "attempt", job.Attempts)
+	ctx = logging.WithLogger(ctx, logger)
+
+	var err error
+	if h := q.handler(job.Type); h == nil {
+		err = Permanent(fmt.Errorf("%w: %s", domain.ErrUnknownJobType, job.Type))
+	} else if job.Attempts > job.MaxAttempts {
+		// Released after its last attempt was cut short
+		err = Permanent(errors.New("out of attempts"))
+	} else {
+		err = run(ctx, h, job)
+	}
+
+	now := q.now()
+	worker := job.LockedBy
+	job.LockedBy, job.LockedAt = "", nil
+	var permanent permanentError
+	switch {
+	case err == nil:
+		job.Status = domain.JobStatusDone
+		job.LastError = ""
+		job.FinishedAt = &now
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		logger.Error("job dead", "error", err)
+		job.Status = domain.JobStatusDead
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+	default:
+		job.Status = domain.JobStatusPending
+		job.LastError = err.Error()
+		job.RunAt = now.Add(q.backoff(job.Attempts))
+		logger.Warn("job failed, retrying", "retry_at", job.RunAt, "error", err)
+	}
+	// Record the outcome even when the job was cut short by shutdown
+	saved, err := q.repo.Finish(context.WithoutCancel(ctx), job, worker)
+	switch {
+	case err != nil:
+		logger.Error("saving job outcome failed", "error", err)
+	case !saved:
+		logger.Warn("job outcome dropped, the job was released while running")
+	}
+}
+
+// run calls h with a deadline, turning a panic into an error.
+func run(ctx context.Context, h handler, job *domain.Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, LockTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return h(ctx, []byte(job.Payload))
+}
+
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.RetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,229 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/repository"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+// newJobRepository returns a repository on a new database.
+func newJobRepository(t *testing.T) domain.JobRepository {
+	t.Helper()
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&domain.Job{}))
+	return repository.NewJobRepository(db)
+}
+
+func newTestQueue(t *testing.T) (*Queue, domain.JobRepository, *time.Time) {
+	t.Helper()
+	repo := newJobRepository(t)
+	q := NewQueue(repo, 3, time.Minute)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	q.now = func() time.Time { return now }
+	return q, repo, &now
+}
+
+func TestQueue_RunsTypedHandlers(t *testing.T) {
+	q, repo, _ := newTestQueue(t)
+	ctx := context.Background()
+	var got []string
+	Handle(q, "greet", func(ctx context.Context, p greeting) error {
+		got = append(got, p.Name)
+		return nil
+	})
+
+	job, err := q.Enqueue(ctx, "greet", greeting{Name: "Abebe"}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"Abebe"}`, job.Payload)
+
+	n, err := q.RunDue(ctx, "test", 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"Abebe"}, got)
+
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusDone, stored.Status)
+	require.NotNil(t, stored.FinishedAt)
+	require.Empty(t, stored.LockedBy)
+
+	_, err = q.Enqueue(ctx, "greet.typo", greeting{}, time.Time{})
+	require.ErrorIs(t, err, domain.ErrUnknownJobType)
+}
+
+func TestQueue_DelayedJobs(t *testing.T) {
+	q, _, now := newTestQueue(t)
+	ctx := context.Background()
+	var runs int
+	Handle(q, "greet", func(context.Context, greeting) error {
+		runs++
+		return nil
+	})
+	_, err := q.Enqueue(ctx, "greet", greeting{}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	n, err := q.RunDue(ctx, "test", 10)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	*now = now.Add(time.Hour)
+	n, err = q.RunDue(ctx, "test", 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, 1, runs)
+}
+
+func TestQueue_RetriesWithBackoffThenDies(t *testing.T) {
+	q, repo, now := newTestQueue(t)
+	ctx := context.Background()
+	Handle(q, "flaky", func(context.Context, greeting) error { return errors.New("gateway down") })
+	job, err := q.Enqueue(ctx, "flaky", greeting{}, time.Time{})
+	require.NoError(t, err)
+
+	for i, delay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		_, err = q.RunDue(ctx, "test", 1)
+		require.NoError(t, err)
+		stored, err := repo.GetByID(ctx, job.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.JobStatusPending, stored.Status)
+		require.Equal(t, i+1, stored.Attempts)
+		require.Equal(t, "gateway down", stored.LastError)
+		require.WithinDuration(t, now.Add(delay), stored.RunAt, time.Second)
+		*now = stored.RunAt
+	}
+
+	_, err = q.RunDue(ctx, "test", 1)
+	require.NoError(t, err)
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusDead, stored.Status)
+	require.Equal(t, 3, stored.Attempts)
+
+	// An admin queues it again with fresh attempts
+	retried, err := q.Retry(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusPending, retried.Status)
+	require.Zero(t, retried.Attempts)
+	require.Nil(t, retried.FinishedAt)
+}
+
+func TestQueue_StaleWorkerKeepsOffReclaimedJob(t *testing.T) {
+	q, repo, now := newTestQueue(t)
+	ctx := context.Background()
+	Handle(q, "slow", func(ctx context.Context, _ greeting) error {
+		// Taken for dead and handed to another worker while still running
+		_, err := repo.Release(ctx, now.Add(LockTimeout))
+		require.NoError(t, err)
+		claimed, err := repo.Claim(ctx, "other", *now, 1)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		return nil
+	})
+	job, err := q.Enqueue(ctx, "slow", greeting{}, time.Time{})
+	require.NoError(t, err)
+
+	_, err = q.RunDue(ctx, "test", 1)
+	require.NoError(t, err)
+	stored, err := repo.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusRunning, stored.Status)
+	require.Equal(t, "other", stored.LockedBy)
+}
+
+func TestQueue_DeadLetters(t *testing.T) {
+	q, repo, _ := newTestQueue(t)
+	ctx := context.Background()
+	Handle(q, "gone", func(context.Context, greeting) error {
+		return Permanent(domain.ErrNotFound)
+	})
+	Handle(q, "broken", func(context.Context, greeting) error { panic("nil map") })
+	Handle(q, "strict", func(context.Context, greeting) error { return nil })
+
+	gone, err := q.Enqueue(ctx, "gone", greeting{}, time.Time{})
+	require.NoError(t, err)
+	broken, err := q.Enqueue(ctx, "broken", greeting{}, time.Time{})
+	require.NoError(t, err)
+	garbled, err := q.Enqueue(ctx, "strict", greeting{}, time.Time{})
+	require.NoError(t, err)
+	garbled.Payload = "not json"
+	require.NoError(t, repo.Update(ctx, garbled))
+
+	_, err = q.RunDue(ctx, "test", 10)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		id     uint
+		status string
+		err    string
+	}{
+		{gone.ID, domain.JobStatusDead, "resource not found"},
+		{broken.ID, domain.JobStatusPending, "job panicked: nil map"},
+		{garbled.ID, domain.JobStatusDead, "decoding payload: invalid character 'o' in literal null (expecting 'u')"},
+	} {
+		stored, err := repo.GetByID(ctx, tc.id)
+		require.NoError(t, err)
+		require.Equal(t, tc.status, stored.Status, stored.Type)
+		require.Equal(t, tc.err, stored.LastError, stored.Type)
+	}
+
+	done, err := q.Enqueue(ctx, "strict", greeting{}, time.Time{})
+	require.NoError(t, err)
+	_, err = q.RunDue(ctx, "test", 10)
+	require.NoError(t, err)
+	_, err = q.Retry(ctx, done.ID)
+	require.ErrorIs(t, err, domain.ErrJobNotRetryable)
+
+	// A job a worker has claimed is left to it
+	running, err := q.Enqueue(ctx, "strict", greeting{}, time.Time{})
+	require.NoError(t, err)
+	_, err = repo.Claim(ctx, "other", q.now(), 10)
+	require.NoError(t, err)
+	_, err = q.Retry(ctx, running.ID)
+	require.ErrorIs(t, err, domain.ErrJobNotRetryable)
+	stored, err := repo.GetByID(ctx, running.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusRunning, stored.Status)
+}
+
+func TestQueue_StartAndStop(t *testing.T) {
+	repo := newJobRepository(t)
+	q := NewQueue(repo, 3, time.Minute)
+	release := make(chan struct{})
+	var finished atomic.Int32
+	Handle(q, "slow", func(context.Context, greeting) error {
+		<-release
+		finished.Add(1)
+		return nil
+	})
+	job, err := q.Enqueue(context.Background(), "slow", greeting{}, time.Time{})
+	require.NoError(t, err)
+
+	q.Start(2)
+	require.Eventually(t, func() bool {
+		stored, err := repo.GetByID(context.Background(), job.ID)
+		return err == nil && stored.Status == domain.JobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Stop waits for the running job
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, q.Stop(ctx), context.DeadlineExceeded)
+	close(release)
+	require.Eventually(t, func() bool { return finished.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, q.Stop(context.Background()))
+
+	stored, err := repo.GetByID(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.JobStatusDone, stored.Status)
+}
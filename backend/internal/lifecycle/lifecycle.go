@@ -0,0 +1,127 @@
+// Package lifecycle shuts the process down in a fixed order: traffic stops
+// first, work in flight is drained, queues are flushed and only then are
+// the resources they need closed.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Phase orders the shutdown hooks. Phases run one after the other; the
+// hooks of one phase run in the order they were registered.
+type Phase int
+
+const (
+	// PhaseStopTraffic takes the instance out of rotation and stops
+	// accepting requests
+	PhaseStopTraffic Phase = iota
+	// PhaseDrain waits for work started by requests, such as uploads
+	PhaseDrain
+	// PhaseFlush delivers what is still queued
+	PhaseFlush
+	// PhaseClose releases connections and exporters
+	PhaseClose
+)
+
+var phaseNames = [...]string{"stop traffic", "drain", "flush", "close"}
+
+func (p Phase) String() string {
+	if p < 0 || int(p) >= len(phaseNames) {
+		return fmt.Sprintf("phase %d", int(p))
+	}
+	return phaseNames[p]
+}
+
+type hook struct {
+	phase Phase
+	name  string
+	run   func(ctx context.Context) error
+}
+
+// Manager runs the shutdown hooks once.
+type Manager struct {
+	// Timeout bounds each phase; zero leaves only the caller's deadline
+	Timeout time.Duration
+	Logger  *slog.Logger
+
+	mu    sync.Mutex
+	hooks []hook
+	once  sync.Once
+	err   error
+}
+
+func New(timeout time.Duration, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Manager{Timeout: timeout, Logger: logger}
+}
+
+// OnShutdown registers run under name in phase.
+func (m *Manager) OnShutdown(phase Phase, name string, run func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{phase: phase, name: name, run: run})
+}
+
+// Shutdown runs the hooks phase by phase and logs each one. A failing or
+// timed out hook is logged and doesn't stop the ones after it, so the
+// database is closed even when a queue couldn't be flushed. Later calls
+// return the first call's result without running anything.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.once.Do(func() { m.err = m.shutdown(ctx) })
+	return m.err
+}
+
+func (m *Manager) shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	start := time.Now()
+	var errs []error
+	for phase := PhaseStopTraffic; phase <= PhaseClose; phase++ {
+		var inPhase []hook
+		for _, h := range hooks {
+			if h.phase == phase {
+				inPhase = append(inPhase, h)
+			}
+		}
+		if len(inPhase) == 0 {
+			continue
+		}
+		errs = append(errs, m.runPhase(ctx, phase, inPhase))
+	}
+	err := errors.Join(errs...)
+	m.Logger.Info("shutdown complete", "duration", time.Since(start).String(), "clean", err == nil)
+	return err
+}
+
+func (m *Manager) runPhase(ctx context.Context, phase Phase, hooks []hook) error {
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+	m.Logger.Info("shutdown phase started", "phase", phase.String(), "hooks", len(hooks))
+	start := time.Now()
+	var errs []error
+	for _, h := range hooks {
+		hookStart := time.Now()
+		if err := h.run(ctx); err != nil {
+			m.Logger.Warn("shutdown hook failed", "phase", phase.String(), "hook", h.name,
+				"duration", time.Since(hookStart).String(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		m.Logger.Info("shutdown hook done", "phase", phase.String(), "hook", h.name,
+			"duration", time.Since(hookStart).String())
+	}
+	m.Logger.Info("shutdown phase finished", "phase", phase.String(), "duration", time.Since(start).String())
+	return errors.Join(errs...)
+}
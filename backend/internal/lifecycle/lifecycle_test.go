@@ -0,0 +1,66 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown_RunsPhasesInOrder(t *testing.T) {
+	var buf bytes.Buffer
+	m := New(time.Second, slog.New(slog.NewTextHandler(&buf, nil)))
+	var order []string
+	record := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	// Registered out of order on purpose
+	m.OnShutdown(PhaseClose, "database", record("database", nil))
+	m.OnShutdown(PhaseFlush, "notifications", record("notifications", errors.New("smtp down")))
+	m.OnShutdown(PhaseStopTraffic, "http server", record("http server", nil))
+	m.OnShutdown(PhaseDrain, "uploads", record("uploads", nil))
+	m.OnShutdown(PhaseFlush, "jobs", record("jobs", nil))
+
+	err := m.Shutdown(context.Background())
+	require.EqualError(t, err, "notifications: smtp down")
+	// A failed flush doesn't keep the database open
+	require.Equal(t, []string{"http server", "uploads", "notifications", "jobs", "database"}, order)
+
+	logs := buf.String()
+	for _, phase := range []string{`"stop traffic"`, "drain", "flush", "close"} {
+		require.Contains(t, logs, `msg="shutdown phase started" phase=`+phase)
+	}
+	require.Contains(t, logs, `msg="shutdown hook failed" phase=flush hook=notifications`)
+	require.Contains(t, logs, "clean=false")
+
+	// Only the first call does anything
+	require.Equal(t, err, m.Shutdown(context.Background()))
+	require.Len(t, order, 5)
+}
+
+func TestShutdown_TimeoutIsPerPhase(t *testing.T) {
+	m := New(20*time.Millisecond, slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	m.OnShutdown(PhaseDrain, "stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	var closeErr error
+	m.OnShutdown(PhaseClose, "database", func(ctx context.Context) error {
+		closeErr = ctx.Err()
+		return nil
+	})
+
+	err := m.Shutdown(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, strings.HasPrefix(err.Error(), "stuck: "))
+	// The stuck drain didn't eat the close phase's time
+	require.NoError(t, closeErr)
+}
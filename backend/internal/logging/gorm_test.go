@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGorm_TraceUsesRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := WithLogger(context.Background(), l)
+	g := NewGorm(time.Second)
+	fc := func() (string, int64) { return "SELECT * FROM tutors", 1 }
+
+	g.Trace(ctx, time.Now(), fc, nil)
+	require.Contains(t, buf.String(), "level=DEBUG msg=query")
+	buf.Reset()
+
+	g.Trace(ctx, time.Now().Add(-2*time.Second), fc, nil)
+	require.Contains(t, buf.String(), "level=WARN msg=\"slow query\"")
+	buf.Reset()
+
+	g.Trace(ctx, time.Now(), fc, errors.New("boom"))
+	require.Contains(t, buf.String(), "level=ERROR msg=\"query failed\"")
+	require.Contains(t, buf.String(), "error=boom")
+	buf.Reset()
+
+	g.Trace(ctx, time.Now(), fc, gorm.ErrRecordNotFound)
+	require.Contains(t, buf.String(), "level=DEBUG")
+}
+
+func TestGorm_ParamsFilter(t *testing.T) {
+	sql, params := NewGorm(0).ParamsFilter(context.Background(), "SELECT ?", "secret")
+	require.Equal(t, "SELECT ?", sql)
+	require.Nil(t, params)
+}
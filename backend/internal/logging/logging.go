@@ -0,0 +1,98 @@
+// Package logging sets up the structured logger and carries per request
+// loggers through contexts.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"hiyab-tutor/internal/config"
+)
+
+// Redacted replaces the value of secret attributes.
+const Redacted = "[REDACTED]"
+
+// New builds the logger described by LOG_LEVEL and LOG_FORMAT. Secrets and
+// phone numbers are redacted from every record.
+func New(c *config.Config, w io.Writer) *slog.Logger {
+	level, err := c.SlogLevel()
+	if err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: Redact}
+	if c.LogFormat == config.LogFormatText {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Keys containing one of these are never logged
+var secretKeys = []string{"password", "token", "secret", "authorization", "cookie", "api_key", "apikey"}
+
+// Redact is a slog ReplaceAttr function. It hides attributes whose key
+// names a secret and masks phone numbers.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+	if strings.Contains(key, "phone") {
+		return slog.String(a.Key, MaskPhone(a.Value.String()))
+	}
+	return a
+}
+
+// MaskPhone keeps the country code and the last two digits of a phone
+// number, which is enough to tell numbers apart in logs.
+func MaskPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+	keepStart := 4
+	if !strings.HasPrefix(phone, "+") {
+		keepStart = 0
+	}
+	if len(phone) <= keepStart+4 {
+		return strings.Repeat("*", len(phone))
+	}
+	return phone[:keepStart] + strings.Repeat("*", len(phone)-keepStart-2) + phone[len(phone)-2:]
+}
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying l.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx, e.g. the request logger
+// with the request ID attached, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+			return l
+		}
+	}
+	return slog.Default()
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, if any, so it can be
+// passed on to other services.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"hiyab-tutor/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_RedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	c := config.Defaults()
+	New(c, &buf).Info("login",
+		"username", "abebe",
+		"password", "hunter2",
+		slog.Group("request", "Authorization", "Bearer abc", "refresh_token", "xyz"),
+		"phone_number", "+251911234567",
+	)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Equal(t, "abebe", got["username"])
+	require.Equal(t, Redacted, got["password"])
+	require.Equal(t, map[string]any{"Authorization": Redacted, "refresh_token": Redacted}, got["request"])
+	require.Equal(t, "+251*******67", got["phone_number"])
+	require.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	c := config.Defaults()
+	c.LogLevel = "warn"
+	c.LogFormat = config.LogFormatText
+	l := New(c, &buf)
+	l.Info("hidden")
+	l.Warn("shown")
+	require.NotContains(t, buf.String(), "hidden")
+	require.Contains(t, buf.String(), "level=WARN msg=shown")
+}
+
+func TestMaskPhone(t *testing.T) {
+	require.Equal(t, "", MaskPhone(""))
+	require.Equal(t, "+251*******67", MaskPhone("+251911234567"))
+	require.Equal(t, "********67", MaskPhone("0911234567"))
+	require.Equal(t, "****", MaskPhone("1234"))
+}
+
+func TestFromContext(t *testing.T) {
+	require.Same(t, slog.Default(), FromContext(context.Background()))
+
+	l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	ctx := WithRequestID(WithLogger(context.Background(), l), "abc")
+	require.Same(t, l, FromContext(ctx))
+	require.Equal(t, "abc", RequestID(ctx))
+	require.Empty(t, RequestID(context.Background()))
+}
@@ -0,0 +1,200 @@
+// Package maintenance holds the recurring clean-up and reminder tasks the
+// scheduler runs. Each returns a short summary of what it did.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/storage"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// digestLimit caps how many records a reminder lists
+const digestLimit = 50
+
+// fileColumns are the columns that hold upload paths, per table
+var fileColumns = []struct {
+	model   any
+	columns []string
+}{
+	{&domain.Tutor{}, []string{"document", "image"}},
+	{&domain.Partner{}, []string{"image_url"}},
+	{&domain.Testimonial{}, []string{"video", "thumbnail"}},
+	{&domain.OtherService{}, []string{"image"}},
+}
+
+// OrphanFiles queues the removal of uploads no record refers to. Files
+// younger than grace are left alone, since an upload is saved before the
+// record pointing at it.
+func OrphanFiles(db *gorm.DB, dir string, jobs domain.JobEnqueuer, grace time.Duration) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		referenced := map[string]bool{}
+		for _, fc := range fileColumns {
+			for _, column := range fc.columns {
+				var values []string
+				if err := db.WithContext(ctx).Model(fc.model).Where(column+" <> ''").Pluck(column, &values).Error; err != nil {
+					return "", err
+				}
+				for _, v := range values {
+					if stored := storedPath(v); stored != "" {
+						referenced[stored] = true
+					}
+				}
+			}
+		}
+
+		cutoff := time.Now().Add(-grace)
+		checked, queued := 0, 0
+		err := filepath.WalkDir(dir, func(file string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return err
+			}
+			checked++
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return err
+			}
+			stored := path.Join(storage.URLPrefix, filepath.ToSlash(rel))
+			if referenced[stored] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				return err
+			}
+			if _, err := jobs.Enqueue(ctx, domain.JobRemoveFile, domain.RemoveFilePayload{Path: stored}, time.Time{}); err != nil {
+				return err
+			}
+			queued++
+			return nil
+		})
+		return fmt.Sprintf("queued %d of %d files for removal", queued, checked), err
+	}
+}
+
+// storedPath cuts a stored reference down to "uploads/<kind>/<name>",
+// whether it was saved with a leading slash or a full URL.
+func storedPath(v string) string {
+	i := strings.Index(v, storage.URLPrefix+"/")
+	if i < 0 {
+		return ""
+	}
+	return path.Clean(v[i:])
+}
+
+// UnassignedBookings tells the admins about bookings that have waited
+// longer than olderThan for a tutor.
+func UnassignedBookings(db *gorm.DB, events domain.EventPublisher, olderThan time.Duration) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		query := db.WithContext(ctx).Model(&domain.Booking{}).
+			Where("assigned = ? AND quarantined = ? AND deleted_at IS NULL AND created_at < ?", false, false, time.Now().Add(-olderThan))
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return "no bookings waiting", nil
+		}
+		var bookings []domain.Booking
+		if err := query.Order("created_at").Limit(digestLimit).Find(&bookings).Error; err != nil {
+			return "", err
+		}
+		events.Publish(domain.Event{
+			Type: domain.EventBookingsUnassigned,
+			Data: map[string]any{"Count": count, "Hours": int(olderThan.Hours()), "Bookings": bookings},
+		})
+		return fmt.Sprintf("reminded admins of %d bookings", count), nil
+	}
+}
+
+// ExpiringDocuments tells the admins about tutors whose documents have
+// expired or expire within the next within.
+func ExpiringDocuments(db *gorm.DB, events domain.EventPublisher, within time.Duration) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		query := db.WithContext(ctx).Model(&domain.Tutor{}).
+			Where("document_expires_at IS NOT NULL AND document_expires_at < ? AND deleted_at IS NULL", time.Now().Add(within))
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return "no documents expiring", nil
+		}
+		var tutors []domain.Tutor
+		if err := query.Order("document_expires_at").Limit(digestLimit).Find(&tutors).Error; err != nil {
+			return "", err
+		}
+		events.Publish(domain.Event{
+			Type: domain.EventTutorDocumentsExpiring,
+			Data: map[string]any{"Count": count, "Days": int(within.Hours() / 24), "Tutors": tutors},
+		})
+		return fmt.Sprintf("reminded admins of %d expiring documents", count), nil
+	}
+}
+
+// PurgeTrash deletes for good the rows of models that have been in the
+// trash, i.e. had deleted_at set, for longer than retention.
+func PurgeTrash(db *gorm.DB, retention time.Duration, models ...any) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		cutoff := time.Now().Add(-retention)
+		var purged []string
+		var total int64
+		for _, model := range models {
+			res := db.WithContext(ctx).Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Delete(model)
+			if res.Error != nil {
+				return "", res.Error
+			}
+			if res.RowsAffected > 0 {
+				stmt := &gorm.Statement{DB: db}
+				if err := stmt.Parse(model); err != nil {
+					return "", err
+				}
+				purged = append(purged, fmt.Sprintf("%s %d", stmt.Schema.Table, res.RowsAffected))
+				total += res.RowsAffected
+			}
+		}
+		if total == 0 {
+			return "nothing to purge", nil
+		}
+		return fmt.Sprintf("purged %d rows (%s)", total, strings.Join(purged, ", ")), nil
+	}
+}
+
+// ExpiredExports removes the generated exports whose download has expired,
+// and those that failed more than retention ago, files and all.
+func ExpiredExports(db *gorm.DB, dir string, retention time.Duration) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		now := time.Now()
+		var exports []domain.Export
+		if err := db.WithContext(ctx).
+			Where("expires_at < ? OR (status = ? AND updated_at < ?)", now, domain.ExportStatusFailed, now.Add(-retention)).
+			Find(&exports).Error; err != nil {
+			return "", err
+		}
+		if len(exports) == 0 {
+			return "no exports expired", nil
+		}
+		ids := make([]uint, 0, len(exports))
+		for _, e := range exports {
+			if e.File != "" {
+				if err := os.Remove(filepath.Join(dir, e.File)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					return "", err
+				}
+			}
+			ids = append(ids, e.ID)
+		}
+		if err := db.WithContext(ctx).Delete(&domain.Export{}, ids).Error; err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("removed %d exports", len(ids)), nil
+	}
+}
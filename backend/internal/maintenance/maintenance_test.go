@@ -0,0 +1,183 @@
+package maintenance
+
+import (
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type fakeEnqueuer struct {
+	paths []string
+}
+
+func (f *fakeEnqueuer) Enqueue(ctx context.Context, jobType string, payload any, runAt time.Time) (*domain.Job, error) {
+	f.paths = append(f.paths, payload.(domain.RemoveFilePayload).Path)
+	return &domain.Job{Type: jobType}, nil
+}
+
+type fakePublisher struct {
+	events []domain.Event
+}
+
+func (f *fakePublisher) Publish(event domain.Event) {
+	f.events = append(f.events, event)
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&domain.Booking{}, &domain.Tutor{}, &domain.Partner{}, &domain.Testimonial{}, &domain.OtherService{}))
+	return db
+}
+
+func TestOrphanFiles(t *testing.T) {
+	db := openDB(t)
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	write := func(name string, modTime time.Time) {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte("x"), 0o644))
+		require.NoError(t, os.Chtimes(file, modTime, modTime))
+	}
+	write("documents/kept.pdf", old)
+	write("images/logo.png", old)
+	write("images/orphan.png", old)
+	write("images/fresh.png", time.Now())
+	write(".keep", old)
+
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Abebe", Document: "/uploads/documents/kept.pdf"}).Error)
+	require.NoError(t, db.Create(&domain.Partner{Name: "Acme", ImageURL: "https://api.example.com/uploads/images/logo.png"}).Error)
+
+	jobs := &fakeEnqueuer{}
+	result, err := OrphanFiles(db, dir, jobs, 24*time.Hour)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"uploads/images/orphan.png"}, jobs.paths)
+	require.Equal(t, "queued 1 of 4 files for removal", result)
+}
+
+func TestUnassignedBookings(t *testing.T) {
+	db := openDB(t)
+	events := &fakePublisher{}
+	run := UnassignedBookings(db, events, 24*time.Hour)
+
+	result, err := run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "no bookings waiting", result)
+	require.Empty(t, events.events)
+
+	old := time.Now().Add(-48 * time.Hour)
+	waiting := &domain.Booking{FirstName: "Hana", Model: domain.Model{CreatedAt: old}}
+	require.NoError(t, db.Create(waiting).Error)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "Assigned", Assigned: true, Model: domain.Model{CreatedAt: old}}).Error)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "Held", Quarantined: true, Model: domain.Model{CreatedAt: old}}).Error)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "New"}).Error)
+
+	result, err = run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "reminded admins of 1 bookings", result)
+	require.Len(t, events.events, 1)
+	event := events.events[0]
+	require.Equal(t, domain.EventBookingsUnassigned, event.Type)
+	data := event.Data
+	require.EqualValues(t, 1, data["Count"])
+	require.Equal(t, 24, data["Hours"])
+	bookings := data["Bookings"].([]domain.Booking)
+	require.Len(t, bookings, 1)
+	require.Equal(t, waiting.ID, bookings[0].ID)
+}
+
+func TestExpiringDocuments(t *testing.T) {
+	db := openDB(t)
+	events := &fakePublisher{}
+	soon := time.Now().Add(10 * 24 * time.Hour)
+	later := time.Now().Add(90 * 24 * time.Hour)
+	expired := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Soon", DocumentExpiresAt: &soon}).Error)
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Expired", DocumentExpiresAt: &expired}).Error)
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Later", DocumentExpiresAt: &later}).Error)
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Unknown"}).Error)
+
+	result, err := ExpiringDocuments(db, events, 30*24*time.Hour)(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "reminded admins of 2 expiring documents", result)
+	require.Len(t, events.events, 1)
+	data := events.events[0].Data
+	require.Equal(t, 30, data["Days"])
+	tutors := data["Tutors"].([]domain.Tutor)
+	require.Equal(t, "Expired", tutors[0].FirstName)
+	require.Equal(t, "Soon", tutors[1].FirstName)
+}
+
+func TestPurgeTrash(t *testing.T) {
+	db := openDB(t)
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	recently := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "Purged", Model: domain.Model{DeletedAt: &longAgo}}).Error)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "Trashed", Model: domain.Model{DeletedAt: &recently}}).Error)
+	require.NoError(t, db.Create(&domain.Booking{FirstName: "Live"}).Error)
+	require.NoError(t, db.Create(&domain.Tutor{FirstName: "Purged", Model: domain.Model{DeletedAt: &longAgo}}).Error)
+
+	run := PurgeTrash(db, 30*24*time.Hour, &domain.Booking{}, &domain.Tutor{}, &domain.Partner{})
+	result, err := run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "purged 2 rows (bookings 1, tutors 1)", result)
+
+	var names []string
+	require.NoError(t, db.Model(&domain.Booking{}).Order("id").Pluck("first_name", &names).Error)
+	require.Equal(t, []string{"Trashed", "Live"}, names)
+
+	result, err = run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "nothing to purge", result)
+}
+
+func TestExpiredExports(t *testing.T) {
+	db := openDB(t)
+	require.NoError(t, db.AutoMigrate(&domain.Export{}))
+	dir := t.TempDir()
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	for _, e := range []domain.Export{
+		{Status: domain.ExportStatusReady, File: "expired.csv", ExpiresAt: &past},
+		{Status: domain.ExportStatusReady, File: "kept.csv", ExpiresAt: &future},
+		{Status: domain.ExportStatusFailed, UpdatedAt: time.Now().Add(-48 * time.Hour)},
+		{Status: domain.ExportStatusPending},
+	} {
+		require.NoError(t, db.Create(&e).Error)
+	}
+	for _, name := range []string{"expired.csv", "kept.csv"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("id\n"), 0o600))
+	}
+
+	run := ExpiredExports(db, dir, 24*time.Hour)
+	result, err := run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "removed 2 exports", result)
+	require.NoFileExists(t, filepath.Join(dir, "expired.csv"))
+	require.FileExists(t, filepath.Join(dir, "kept.csv"))
+	var left []string
+	require.NoError(t, db.Model(&domain.Export{}).Order("id").Pluck("status", &left).Error)
+	require.Equal(t, []string{domain.ExportStatusReady, domain.ExportStatusPending}, left)
+
+	result, err = run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "no exports expired", result)
+}
+
+func TestStoredPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"uploads/images/a.png":                         "uploads/images/a.png",
+		"/uploads/images/a.png":                        "uploads/images/a.png",
+		"https://cdn.example.com/uploads/images/a.png": "uploads/images/a.png",
+		"https://example.com/elsewhere.png":            "",
+	} {
+		require.Equal(t, want, storedPath(in), in)
+	}
+}
@@ -0,0 +1,131 @@
+// Package metrics exposes Prometheus metrics for the HTTP layer, the
+// database pool and business events.
+package metrics
+
+import (
+	"database/sql"
+	"hiyab-tutor/internal/domain"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "hiyab"
+
+// Metrics owns a registry with every collector the API reports.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	uploads     *prometheus.CounterVec
+	uploadBytes *prometheus.CounterVec
+	events      map[string]prometheus.Counter
+}
+
+// New registers the collectors on a fresh registry, along with the Go
+// runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "uploads_total",
+			Help:      "Files uploaded by kind (images, documents, videos, ...).",
+		}, []string{"kind"}),
+		uploadBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upload_bytes_total",
+			Help:      "Bytes uploaded by kind.",
+		}, []string{"kind"}),
+		events: map[string]prometheus.Counter{
+			domain.EventBookingCreated:  counter("bookings_created_total", "Bookings accepted, including ones released from quarantine."),
+			domain.EventBookingAssigned: counter("booking_assignments_total", "Tutors assigned to bookings."),
+			domain.EventTutorRegistered: counter("tutors_registered_total", "Tutors registered, including ones released from quarantine."),
+			domain.EventTutorVerified:   counter("tutors_verified_total", "Tutors verified by an admin."),
+		},
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.uploads, m.uploadBytes,
+	)
+	for _, c := range m.events {
+		m.registry.MustRegister(c)
+	}
+	return m
+}
+
+func counter(name, help string) prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help})
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB reports the connection pool statistics of db.
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records one handled HTTP request. Route should be the
+// route pattern rather than the path, to keep the number of series bounded.
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(method, route, code).Inc()
+	m.duration.WithLabelValues(method, route, code).Observe(elapsed.Seconds())
+}
+
+// CountEvents wraps next so published business events are counted.
+func (m *Metrics) CountEvents(next domain.EventPublisher) domain.EventPublisher {
+	return &eventCounter{next: next, events: m.events}
+}
+
+type eventCounter struct {
+	next   domain.EventPublisher
+	events map[string]prometheus.Counter
+}
+
+func (p *eventCounter) Publish(event domain.Event) {
+	if c, ok := p.events[event.Type]; ok {
+		c.Inc()
+	}
+	p.next.Publish(event)
+}
+
+// CountUploads wraps next so saved files and their sizes are counted.
+func (m *Metrics) CountUploads(next domain.FileStorage) domain.FileStorage {
+	return &uploadCounter{FileStorage: next, m: m}
+}
+
+type uploadCounter struct {
+	domain.FileStorage
+	m *Metrics
+}
+
+func (s *uploadCounter) Save(file *multipart.FileHeader, kind, name string) (string, error) {
+	stored, err := s.FileStorage.Save(file, kind, name)
+	if err == nil {
+		s.m.uploads.WithLabelValues(kind).Inc()
+		s.m.uploadBytes.WithLabelValues(kind).Add(float64(file.Size))
+	}
+	return stored, err
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"bytes"
+	"hiyab-tutor/internal/domain"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct{ events []domain.Event }
+
+func (p *recordingPublisher) Publish(e domain.Event) { p.events = append(p.events, e) }
+
+type memStorage struct{}
+
+func (memStorage) Save(_ *multipart.FileHeader, kind, name string) (string, error) {
+	return "uploads/" + kind + "/" + name, nil
+}
+func (memStorage) Remove(string) error { return nil }
+
+func TestCountEvents(t *testing.T) {
+	m := New()
+	next := &recordingPublisher{}
+	events := m.CountEvents(next)
+	events.Publish(domain.Event{Type: domain.EventBookingCreated})
+	events.Publish(domain.Event{Type: domain.EventBookingCreated})
+	events.Publish(domain.Event{Type: domain.EventBookingAssigned})
+	events.Publish(domain.Event{Type: "something.else"})
+
+	require.Len(t, next.events, 4)
+	require.Equal(t, 2.0, testutil.ToFloat64(m.events[domain.EventBookingCreated]))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.events[domain.EventBookingAssigned]))
+	require.Equal(t, 0.0, testutil.ToFloat64(m.events[domain.EventTutorRegistered]))
+}
+
+func TestCountUploads(t *testing.T) {
+	m := New()
+	files := m.CountUploads(memStorage{})
+	_, err := files.Save(&multipart.FileHeader{Size: 1500}, "images", "a.png")
+	require.NoError(t, err)
+	_, err = files.Save(&multipart.FileHeader{Size: 500}, "images", "b.png")
+	require.NoError(t, err)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(m.uploads.WithLabelValues("images")))
+	require.Equal(t, 2000.0, testutil.ToFloat64(m.uploadBytes.WithLabelValues("images")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ObserveRequest(http.MethodGet, "/api/v1/tutors/:id", http.StatusOK, 20*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	body, _ := io.ReadAll(w.Body)
+	require.Contains(t, string(body), `hiyab_http_requests_total{method="GET",route="/api/v1/tutors/:id",status="200"} 1`)
+	require.Contains(t, string(body), `hiyab_http_request_duration_seconds_bucket{method="GET",route="/api/v1/tutors/:id",status="200",le="0.025"} 1`)
+	require.Contains(t, string(body), "hiyab_bookings_created_total 0")
+	require.True(t, bytes.Contains(body, []byte("go_goroutines")))
+	require.False(t, strings.Contains(string(body), "hiyab_upload_bytes_total{"), "no uploads yet")
+}
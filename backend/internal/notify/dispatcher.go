@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/domain"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQueueSize = 256
+	sendTimeout      = 30 * time.Second
+)
+
+// Rule sends an event type over a set of channels. Admin facing events go to
+// the configured admins; when ToSubject is set the event goes to the person
+// it is about instead. Template defaults to the event type.
+type Rule struct {
+	Event     string
+	Template  string
+	Channels  []Channel
+	ToSubject bool
+}
+
+// Dispatcher turns published events into messages. Delivery happens on a
+// background goroutine so usecases never wait for an SMTP server or an SMS
+// gateway.
+type Dispatcher struct {
+	templates *Templates
+	admins    []Recipient
+	rules     map[string][]Rule
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan domain.Event
+	done   chan struct{}
+}
+
+var _ domain.EventPublisher = (*Dispatcher)(nil)
+
+func NewDispatcher(templates *Templates, admins []Recipient, rules []Rule) *Dispatcher {
+	d := &Dispatcher{
+		templates: templates,
+		admins:    admins,
+		rules:     map[string][]Rule{},
+		queue:     make(chan domain.Event, defaultQueueSize),
+		done:      make(chan struct{}),
+	}
+	for _, r := range rules {
+		d.rules[r.Event] = append(d.rules[r.Event], r)
+	}
+	go d.run()
+	return d
+}
+
+// NewDispatcherFromConfig wires the channels listed in NOTIFY_CHANNELS to
+// the default rules: admins hear about new bookings, tutor applications and
+// assignments, and get the digests of bookings still without a tutor and
+// of tutor documents about to expire; parents get a confirmation of their booking and the details
+// of the assigned tutor; tutors hear when they are verified. When outbox is
+// given, text messages go through it so they are stored and retried.
+func NewDispatcherFromConfig(c *config.Config, outbox *SMSOutbox) (*Dispatcher, error) {
+	templates, err := LoadTemplates(c.NotifyTemplateDir, c.NotifyDefaultLanguage)
+	if err != nil {
+		return nil, err
+	}
+	var channels []Channel
+	for _, kind := range splitList(c.NotifyChannels) {
+		if kind == ChannelSMS && outbox != nil {
+			channels = append(channels, NewSMSChannel(outbox))
+			continue
+		}
+		ch, err := NewChannel(kind, c)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, ch)
+	}
+	var admins []Recipient
+	for _, email := range splitList(c.NotifyAdminEmails) {
+		admins = append(admins, Recipient{Name: "Admin", Email: email})
+	}
+	for _, phone := range splitList(c.NotifyAdminPhones) {
+		admins = append(admins, Recipient{Name: "Admin", Phone: phone})
+	}
+	if c.TelegramChatID != "" {
+		admins = append(admins, Recipient{Name: "Admin", TelegramChatID: c.TelegramChatID})
+	}
+	rules := []Rule{
+		{Event: domain.EventBookingCreated, Channels: channels},
+		{Event: domain.EventTutorRegistered, Channels: channels},
+		{Event: domain.EventBookingAssigned, Channels: channels},
+		{Event: domain.EventBookingsUnassigned, Channels: channels},
+		{Event: domain.EventTutorDocumentsExpiring, Channels: channels},
+		{Event: domain.EventBookingCreated, Template: "booking.created.parent", Channels: channels, ToSubject: true},
+		{Event: domain.EventBookingAssigned, Template: "booking.assigned.parent", Channels: channels, ToSubject: true},
+		{Event: domain.EventTutorVerified, Channels: channels, ToSubject: true},
+	}
+	return NewDispatcher(templates, admins, rules), nil
+}
+
+// Publish queues the event. If the queue is full the event is dropped and
+// logged rather than blocking the request.
+func (d *Dispatcher) Publish(event domain.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		slog.Warn("notification dropped: dispatcher closed", "event", event.Type)
+		return
+	}
+	select {
+	case d.queue <- event:
+	default:
+		slog.Warn("notification dropped: queue full", "event", event.Type)
+	}
+}
+
+// Close stops accepting events and waits until the queued ones have been
+// delivered or ctx is done.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for event := range d.queue {
+		d.deliver(event)
+	}
+}
+
+func (d *Dispatcher) deliver(event domain.Event) {
+	for _, rule := range d.rules[event.Type] {
+		recipients := d.admins
+		if rule.ToSubject {
+			if event.Subject == nil {
+				continue
+			}
+			recipients = []Recipient{{
+				Name:     event.Subject.Name,
+				Email:    event.Subject.Email,
+				Phone:    event.Subject.Phone,
+				Language: event.Subject.Language,
+			}}
+		}
+		name := rule.Template
+		if name == "" {
+			name = event.Type
+		}
+		for _, to := range recipients {
+			subject, body, err := d.templates.Render(name, to.Language, event.Data)
+			if err != nil {
+				slog.Error("notification not rendered", "template", name, "error", err)
+				continue
+			}
+			for _, ch := range rule.Channels {
+				ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+				err := ch.Send(ctx, Message{To: to, Subject: subject, Body: body})
+				cancel()
+				// Recipients usually only have some of the addresses
+				if err != nil && !errors.Is(err, ErrNoAddress) {
+					slog.Error("notification failed", "event", event.Type, "channel", ch.Name(), "error", err)
+				}
+			}
+		}
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := LoadTemplates("", "en")
+	require.NoError(t, err)
+	data := map[string]any{"Tutor": &domain.Tutor{FirstName: "Abebe"}}
+
+	subject, body, err := templates.Render(domain.EventTutorVerified, "am", data)
+	require.NoError(t, err)
+	require.Contains(t, subject, "ተረጋግጧል")
+	require.Contains(t, body, "Abebe")
+
+	// Unknown languages fall back to the default
+	subject, _, err = templates.Render(domain.EventTutorVerified, "fr", data)
+	require.NoError(t, err)
+	require.Contains(t, subject, "verified")
+
+	_, _, err = templates.Render("tutor.unknown", "en", data)
+	require.ErrorIs(t, err, ErrNoTemplate)
+}
+
+func TestTemplates_Override(t *testing.T) {
+	dir := t.TempDir()
+	custom := `{{define "subject"}}Custom{{end}}{{define "body"}}Hello {{.Tutor.FirstName}}{{end}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tutor.verified.en.tmpl"), []byte(custom), 0o644))
+
+	templates, err := LoadTemplates(dir, "en")
+	require.NoError(t, err)
+	subject, body, err := templates.Render(domain.EventTutorVerified, "en", map[string]any{"Tutor": &domain.Tutor{FirstName: "Abebe"}})
+	require.NoError(t, err)
+	require.Equal(t, "Custom", subject)
+	require.Equal(t, "Hello Abebe", body)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.en.tmpl"), []byte(`{{define "subject"}}x{{end}}`), 0o644))
+	_, err = LoadTemplates(dir, "en")
+	require.Error(t, err)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	templates, err := LoadTemplates("", "en")
+	require.NoError(t, err)
+	email := &FakeChannel{ChannelName: "email"}
+	failing := &FakeChannel{Err: errors.New("gateway down")}
+	admins := []Recipient{{Name: "Admin", Email: "admin@hiyab.org"}}
+	d := NewDispatcher(templates, admins, []Rule{
+		{Event: domain.EventBookingCreated, Channels: []Channel{email, failing}},
+		{Event: domain.EventTutorVerified, Channels: []Channel{email}, ToSubject: true},
+	})
+
+	d.Publish(domain.Event{
+		Type: domain.EventBookingCreated,
+		Data: map[string]any{"Booking": &domain.Booking{FirstName: "Sara", LastName: "Kebede"}},
+	})
+	d.Publish(domain.Event{
+		Type:    domain.EventTutorVerified,
+		Subject: &domain.Contact{Name: "Abebe", Email: "abebe@example.com", Language: "am"},
+		Data:    map[string]any{"Tutor": &domain.Tutor{FirstName: "Abebe"}},
+	})
+	// Events without a rule are ignored
+	d.Publish(domain.Event{Type: domain.EventBookingAssigned})
+	require.NoError(t, d.Close(context.Background()))
+
+	sent := email.Sent()
+	require.Len(t, sent, 2)
+	require.Equal(t, "admin@hiyab.org", sent[0].To.Email)
+	require.Contains(t, sent[0].Subject, "Sara Kebede")
+	require.Equal(t, "abebe@example.com", sent[1].To.Email)
+	require.Contains(t, sent[1].Body, "ሰላም Abebe")
+
+	// Publishing after Close must not panic
+	d.Publish(domain.Event{Type: domain.EventBookingCreated})
+}
@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeChannel keeps every message in memory. It is meant for tests.
+type FakeChannel struct {
+	// ChannelName is reported by Name; it defaults to "fake"
+	ChannelName string
+	// Err, when set, is returned from Send instead of recording the message
+	Err error
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (c *FakeChannel) Name() string {
+	if c.ChannelName == "" {
+		return "fake"
+	}
+	return c.ChannelName
+}
+
+func (c *FakeChannel) Send(_ context.Context, msg Message) error {
+	if c.Err != nil {
+		return c.Err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+// Sent returns a copy of the messages sent so far.
+func (c *FakeChannel) Sent() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.sent...)
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"hiyab-tutor/internal/logging"
+)
+
+// LogChannel writes messages to the logger instead of sending them. It is
+// meant for local development only: the body may carry a password reset
+// link, which is why config refuses it for resets elsewhere.
+type LogChannel struct{}
+
+func NewLogChannel() *LogChannel { return &LogChannel{} }
+
+func (c *LogChannel) Name() string { return ChannelLog }
+
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).Info("notification",
+		"name", msg.To.Name, "email", msg.To.Email, "phone", msg.To.Phone,
+		"subject", msg.Subject, "body", msg.Body)
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package notify delivers short messages to people over pluggable channels
+// such as e-mail, SMS or a plain log for development.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/config"
+)
+
+const (
+	ChannelSMTP     = "smtp"
+	ChannelSMS      = "sms"
+	ChannelTelegram = "telegram"
+	ChannelLog      = "log"
+)
+
+var (
+	// ErrNoAddress is returned when the recipient has no address the channel
+	// can deliver to (e.g. no e-mail for the SMTP channel).
+	ErrNoAddress = errors.New("recipient has no address for this channel")
+	// ErrUnknownChannel is returned by NewChannel for unsupported kinds.
+	ErrUnknownChannel = errors.New("unknown notification channel")
+)
+
+// Recipient holds every address we may know for a person. Each channel picks
+// the one it needs.
+type Recipient struct {
+	Name           string
+	Email          string
+	Phone          string
+	TelegramChatID string
+	// Language selects the message template, e.g. "en" or "am"
+	Language string
+}
+
+// Message is a channel independent notification.
+type Message struct {
+	To      Recipient
+	Subject string
+	Body    string
+}
+
+// Channel sends a message over one transport.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewChannel builds the channel named kind from the configuration.
+func NewChannel(kind string, c *config.Config) (Channel, error) {
+	switch kind {
+	case ChannelSMTP:
+		return NewSMTPChannel(c.SMTPHost, c.SMTPPort, c.SMTPUsername, c.SMTPPassword, c.SMTPFrom), nil
+	case ChannelSMS:
+		return NewSMSChannel(NewHTTPSMSProvider(c.SMSGatewayURL, c.SMSAPIKey, c.SMSSenderID)), nil
+	case ChannelTelegram:
+		return NewTelegramChannel(c.TelegramBotToken), nil
+	case ChannelLog, "":
+		return NewLogChannel(), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownChannel, kind)
+}
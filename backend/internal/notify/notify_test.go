@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"hiyab-tutor/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChannel(t *testing.T) {
+	c := &config.Config{SMTPHost: "localhost", SMTPPort: 25}
+	for kind, want := range map[string]string{"smtp": ChannelSMTP, "sms": ChannelSMS, "telegram": ChannelTelegram, "log": ChannelLog, "": ChannelLog} {
+		ch, err := NewChannel(kind, c)
+		require.NoError(t, err)
+		require.Equal(t, want, ch.Name())
+	}
+	_, err := NewChannel("pigeon", c)
+	require.ErrorIs(t, err, ErrUnknownChannel)
+}
+
+func TestSMSChannel_Send(t *testing.T) {
+	var got smsPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	ch := NewSMSChannel(NewHTTPSMSProvider(srv.URL, "key", "HIYAB"))
+	err := ch.Send(context.Background(), Message{To: Recipient{Phone: "+251911000000"}, Body: "hello"})
+	require.NoError(t, err)
+	require.Equal(t, smsPayload{To: "+251911000000", From: "HIYAB", Message: "hello"}, got)
+
+	err = ch.Send(context.Background(), Message{To: Recipient{Email: "a@b.c"}, Body: "hello"})
+	require.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestSMSChannel_GatewayError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := NewSMSChannel(NewHTTPSMSProvider(srv.URL, "", "")).Send(context.Background(), Message{To: Recipient{Phone: "+251911000000"}})
+	require.Error(t, err)
+}
+
+func TestSMTPChannel_Send(t *testing.T) {
+	ch := NewSMTPChannel("mail.example.com", 587, "user", "pass", "noreply@hiyab.org")
+	var addr string
+	var to []string
+	var body string
+	ch.sendMail = func(a string, _ smtp.Auth, _ string, rcpt []string, msg []byte) error {
+		addr, to, body = a, rcpt, string(msg)
+		return nil
+	}
+
+	err := ch.Send(context.Background(), Message{To: Recipient{Email: "admin@hiyab.org"}, Subject: "Hi", Body: "there"})
+	require.NoError(t, err)
+	require.Equal(t, "mail.example.com:587", addr)
+	require.Equal(t, []string{"admin@hiyab.org"}, to)
+	require.True(t, strings.HasPrefix(body, "From: noreply@hiyab.org\r\n"))
+	require.Contains(t, body, "Subject: Hi\r\n")
+	require.True(t, strings.HasSuffix(body, "\r\n\r\nthere"))
+
+	err = ch.Send(context.Background(), Message{To: Recipient{Phone: "+251911000000"}})
+	require.ErrorIs(t, err, ErrNoAddress)
+}
+
+func TestTelegramChannel_Send(t *testing.T) {
+	var path string
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	ch := NewTelegramChannel("123:abc")
+	ch.BaseURL = srv.URL
+	err := ch.Send(context.Background(), Message{To: Recipient{TelegramChatID: "42"}, Subject: "Hi", Body: "there"})
+	require.NoError(t, err)
+	require.Equal(t, "/bot123:abc/sendMessage", path)
+	require.Equal(t, map[string]string{"chat_id": "42", "text": "Hi\n\nthere"}, got)
+
+	err = ch.Send(context.Background(), Message{To: Recipient{Phone: "+251911000000"}})
+	require.ErrorIs(t, err, ErrNoAddress)
+}
@@ -0,0 +1,159 @@
+package notify
+
+import (
+	"context"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"time"
+)
+
+const (
+	maxRetryDelay = time.Hour
+	retryBatch    = 100
+	// claimTimeout is how long messages RetryDue picked up stay out of
+	// other instances' reach; should it die half way, they are retried
+	// after this
+	claimTimeout = 30 * time.Minute
+)
+
+// SMSOutbox records every text message before handing it to the gateway, so
+// the delivery status can be looked up later, and retries failed sends with
+// exponential backoff. It is itself an SMSProvider and sits in front of the
+// real one.
+type SMSOutbox struct {
+	repo     domain.SMSRepository
+	provider SMSProvider
+	// MaxAttempts is how often a message is tried before it is marked failed
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry; it doubles every time
+	RetryDelay time.Duration
+
+	now func() time.Time
+}
+
+var _ domain.SMSUsecase = (*SMSOutbox)(nil)
+
+func NewSMSOutbox(repo domain.SMSRepository, provider SMSProvider, maxAttempts int, retryDelay time.Duration) *SMSOutbox {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &SMSOutbox{
+		repo:        repo,
+		provider:    provider,
+		MaxAttempts: maxAttempts,
+		RetryDelay:  retryDelay,
+		now:         time.Now,
+	}
+}
+
+func NewSMSOutboxFromConfig(c *config.Config, repo domain.SMSRepository) *SMSOutbox {
+	provider := NewHTTPSMSProvider(c.SMSGatewayURL, c.SMSAPIKey, c.SMSSenderID)
+	return NewSMSOutbox(repo, provider, c.SMSMaxAttempts, time.Duration(c.SMSRetryDelaySeconds)*time.Second)
+}
+
+// SendSMS stores the message and tries to deliver it once. A failed attempt
+// is not reported as an error because the message will be retried; only a
+// failure to store it is. The message is stored as claimed, so should the
+// process die while sending, RetryDue picks it up once the claim expires.
+func (o *SMSOutbox) SendSMS(ctx context.Context, to, body string) (string, error) {
+	lease := o.now().Add(claimTimeout)
+	msg := &domain.SMSMessage{To: to, Body: body, Status: domain.SMSStatusPending, NextAttemptAt: &lease}
+	if err := o.repo.Create(ctx, msg); err != nil {
+		return "", err
+	}
+	if err := o.attempt(ctx, msg); err != nil {
+		return "", err
+	}
+	return msg.ProviderMessageID, nil
+}
+
+func (o *SMSOutbox) GetAll(ctx context.Context, f *domain.SMSFilter) (*domain.MultipleSMSMessages, error) {
+	return o.repo.GetAll(ctx, f)
+}
+
+// Retry sends a message again right away, whatever its state. A message
+// that already failed gets one more attempt.
+func (o *SMSOutbox) Retry(ctx context.Context, id uint) (*domain.SMSMessage, error) {
+	msg, err := o.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Status == domain.SMSStatusSent {
+		return msg, nil
+	}
+	if msg.Attempts >= o.MaxAttempts {
+		msg.Attempts = o.MaxAttempts - 1
+	}
+	if err := o.attempt(ctx, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RetryDue attempts the pending messages whose retry time has come and
+// returns how many it tried. The messages are claimed first, so instances
+// running side by side never send the same one twice.
+func (o *SMSOutbox) RetryDue(ctx context.Context) (int, error) {
+	now := o.now()
+	due, err := o.repo.ClaimDue(ctx, now, now.Add(claimTimeout), retryBatch)
+	if err != nil {
+		return 0, err
+	}
+	for i := range due {
+		if err := o.attempt(ctx, &due[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+// Run calls RetryDue every interval until ctx is done.
+func (o *SMSOutbox) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := o.RetryDue(ctx); err != nil {
+				logging.FromContext(ctx).Error("sms retry failed", "error", err)
+			}
+		}
+	}
+}
+
+func (o *SMSOutbox) attempt(ctx context.Context, msg *domain.SMSMessage) error {
+	msg.Attempts++
+	id, err := o.provider.SendSMS(ctx, msg.To, msg.Body)
+	now := o.now()
+	switch {
+	case err == nil:
+		msg.Status = domain.SMSStatusSent
+		msg.ProviderMessageID = id
+		msg.LastError = ""
+		msg.SentAt = &now
+		msg.NextAttemptAt = nil
+	case msg.Attempts >= o.MaxAttempts:
+		logging.FromContext(ctx).Error("sms failed for good", "sms_id", msg.ID, "phone", msg.To, "attempts", msg.Attempts, "error", err)
+		msg.Status = domain.SMSStatusFailed
+		msg.LastError = err.Error()
+		msg.NextAttemptAt = nil
+	default:
+		next := now.Add(o.backoff(msg.Attempts))
+		logging.FromContext(ctx).Warn("sms failed, retrying", "sms_id", msg.ID, "phone", msg.To, "retry_at", next, "error", err)
+		msg.Status = domain.SMSStatusPending
+		msg.LastError = err.Error()
+		msg.NextAttemptAt = &next
+	}
+	return o.repo.Update(ctx, msg)
+}
+
+func (o *SMSOutbox) backoff(attempts int) time.Duration {
+	delay := o.RetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
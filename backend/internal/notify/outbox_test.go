@@ -0,0 +1,186 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memorySMSRepository struct {
+	messages map[uint]*domain.SMSMessage
+}
+
+func newMemorySMSRepository() *memorySMSRepository {
+	return &memorySMSRepository{messages: map[uint]*domain.SMSMessage{}}
+}
+
+func (r *memorySMSRepository) Create(_ context.Context, msg *domain.SMSMessage) error {
+	msg.ID = uint(len(r.messages) + 1)
+	copy := *msg
+	r.messages[msg.ID] = &copy
+	return nil
+}
+
+func (r *memorySMSRepository) Update(_ context.Context, msg *domain.SMSMessage) error {
+	copy := *msg
+	r.messages[msg.ID] = &copy
+	return nil
+}
+
+func (r *memorySMSRepository) GetByID(_ context.Context, id uint) (*domain.SMSMessage, error) {
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copy := *msg
+	return &copy, nil
+}
+
+func (r *memorySMSRepository) GetAll(_ context.Context, f *domain.SMSFilter) (*domain.MultipleSMSMessages, error) {
+	out := &domain.MultipleSMSMessages{}
+	for _, msg := range r.messages {
+		out.Data = append(out.Data, *msg)
+	}
+	return out, nil
+}
+
+func (r *memorySMSRepository) ClaimDue(_ context.Context, now, until time.Time, limit int) ([]domain.SMSMessage, error) {
+	var due []domain.SMSMessage
+	for id := uint(1); id <= uint(len(r.messages)); id++ {
+		msg := r.messages[id]
+		if msg.Status == domain.SMSStatusPending && msg.NextAttemptAt != nil && !msg.NextAttemptAt.After(now) {
+			msg.NextAttemptAt = &until
+			due = append(due, *msg)
+		}
+	}
+	return due, nil
+}
+
+// flakyProvider fails the first failures sends.
+type flakyProvider struct {
+	failures int
+	calls    int
+}
+
+func (p *flakyProvider) SendSMS(_ context.Context, to, body string) (string, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return "", errors.New("gateway down")
+	}
+	return "gw-1", nil
+}
+
+func TestSMSOutbox_RetriesWithBackoff(t *testing.T) {
+	repo := newMemorySMSRepository()
+	provider := &flakyProvider{failures: 2}
+	outbox := NewSMSOutbox(repo, provider, 5, time.Minute)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time { return now }
+
+	_, err := outbox.SendSMS(context.Background(), "+251911000000", "hello")
+	require.NoError(t, err)
+	msg := repo.messages[1]
+	require.Equal(t, domain.SMSStatusPending, msg.Status)
+	require.Equal(t, 1, msg.Attempts)
+	require.Equal(t, "gateway down", msg.LastError)
+	require.Equal(t, now.Add(time.Minute), *msg.NextAttemptAt)
+
+	// Not due yet
+	n, err := outbox.RetryDue(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	now = now.Add(time.Minute)
+	n, err = outbox.RetryDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	msg = repo.messages[1]
+	require.Equal(t, 2, msg.Attempts)
+	require.Equal(t, now.Add(2*time.Minute), *msg.NextAttemptAt)
+
+	now = now.Add(2 * time.Minute)
+	_, err = outbox.RetryDue(context.Background())
+	require.NoError(t, err)
+	msg = repo.messages[1]
+	require.Equal(t, domain.SMSStatusSent, msg.Status)
+	require.Equal(t, "gw-1", msg.ProviderMessageID)
+	require.Empty(t, msg.LastError)
+	require.Nil(t, msg.NextAttemptAt)
+	require.Equal(t, now, *msg.SentAt)
+}
+
+func TestSMSOutbox_GivesUpAndManualRetry(t *testing.T) {
+	repo := newMemorySMSRepository()
+	provider := &flakyProvider{failures: 2}
+	outbox := NewSMSOutbox(repo, provider, 2, time.Second)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time { return now }
+
+	_, err := outbox.SendSMS(context.Background(), "+251911000000", "hello")
+	require.NoError(t, err)
+	now = now.Add(time.Second)
+	_, err = outbox.RetryDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.SMSStatusFailed, repo.messages[1].Status)
+	require.Nil(t, repo.messages[1].NextAttemptAt)
+
+	msg, err := outbox.Retry(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, domain.SMSStatusSent, msg.Status)
+
+	_, err = outbox.Retry(context.Background(), 42)
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// crashingProvider stands in for a process that dies while sending.
+type crashingProvider struct{}
+
+func (crashingProvider) SendSMS(context.Context, string, string) (string, error) {
+	panic("killed")
+}
+
+func TestSMSOutbox_RetriesAfterCrash(t *testing.T) {
+	repo := newMemorySMSRepository()
+	outbox := NewSMSOutbox(repo, crashingProvider{}, 5, time.Minute)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	outbox.now = func() time.Time { return now }
+
+	require.Panics(t, func() { outbox.SendSMS(context.Background(), "+251911000000", "hello") })
+	require.Equal(t, now.Add(claimTimeout), *repo.messages[1].NextAttemptAt)
+
+	outbox.provider = &flakyProvider{}
+	now = now.Add(claimTimeout)
+	n, err := outbox.RetryDue(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, domain.SMSStatusSent, repo.messages[1].Status)
+}
+
+func TestSMSOutbox_MockGateway(t *testing.T) {
+	gateway := NewMockSMSGateway("key", 0)
+	srv := httptest.NewServer(gateway)
+	defer srv.Close()
+
+	repo := newMemorySMSRepository()
+	outbox := NewSMSOutbox(repo, NewHTTPSMSProvider(srv.URL+"/sms", "key", "HIYAB"), 3, time.Minute)
+	ch := NewSMSChannel(outbox)
+	require.NoError(t, ch.Send(context.Background(), Message{To: Recipient{Phone: "+251911000000"}, Body: "ሰላም"}))
+
+	received := gateway.Messages()
+	require.Len(t, received, 1)
+	require.Equal(t, "ሰላም", received[0].Message)
+	require.Equal(t, "HIYAB", received[0].From)
+	require.Equal(t, received[0].ID, repo.messages[1].ProviderMessageID)
+
+	// A wrong key is rejected and left for a retry
+	outbox = NewSMSOutbox(repo, NewHTTPSMSProvider(srv.URL+"/sms", "wrong", ""), 3, time.Minute)
+	_, err := outbox.SendSMS(context.Background(), "+251911000001", "hello")
+	require.NoError(t, err)
+	require.Equal(t, domain.SMSStatusPending, repo.messages[2].Status)
+	require.Contains(t, repo.messages[2].LastError, "401")
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MockSMS is a message received by MockSMSGateway.
+type MockSMS struct {
+	ID         string    `json:"id"`
+	To         string    `json:"to"`
+	From       string    `json:"from,omitempty"`
+	Message    string    `json:"message"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// MockSMSGateway speaks the same JSON as HTTPSMSProvider so the SMS flow can
+// be tried locally without a real gateway. Messages are accepted on
+// POST /sms and listed on GET /messages. FailRate rejects that share of the
+// messages with 503 to exercise retries.
+type MockSMSGateway struct {
+	APIKey   string
+	FailRate float64
+
+	mu       sync.Mutex
+	messages []MockSMS
+	mux      *http.ServeMux
+}
+
+func NewMockSMSGateway(apiKey string, failRate float64) *MockSMSGateway {
+	g := &MockSMSGateway{APIKey: apiKey, FailRate: failRate, mux: http.NewServeMux()}
+	g.mux.HandleFunc("POST /sms", g.receive)
+	g.mux.HandleFunc("GET /messages", g.list)
+	return g
+}
+
+func (g *MockSMSGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Messages returns a copy of the messages accepted so far.
+func (g *MockSMSGateway) Messages() []MockSMS {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]MockSMS(nil), g.messages...)
+}
+
+func (g *MockSMSGateway) receive(w http.ResponseWriter, r *http.Request) {
+	if g.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+g.APIKey {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+		return
+	}
+	var payload smsPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.To == "" {
+		http.Error(w, "invalid message", http.StatusBadRequest)
+		return
+	}
+	if g.FailRate > 0 && rand.Float64() < g.FailRate {
+		log.Printf("mock sms: rejecting message to %s", payload.To)
+		http.Error(w, "temporarily unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	g.mu.Lock()
+	msg := MockSMS{
+		ID:         fmt.Sprintf("mock-%d", len(g.messages)+1),
+		To:         payload.To,
+		From:       payload.From,
+		Message:    payload.Message,
+		ReceivedAt: time.Now(),
+	}
+	g.messages = append(g.messages, msg)
+	g.mu.Unlock()
+
+	log.Printf("mock sms %s to %s: %s", msg.ID, msg.To, msg.Message)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": msg.ID})
+}
+
+func (g *MockSMSGateway) list(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g.Messages())
+}
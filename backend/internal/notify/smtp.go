@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPChannel sends plain-text e-mail through an SMTP relay.
+type SMTPChannel struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+
+	// sendMail is swapped out in tests
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPChannel(host string, port int, username, password, from string) *SMTPChannel {
+	return &SMTPChannel{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (c *SMTPChannel) Name() string { return ChannelSMTP }
+
+func (c *SMTPChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return ErrNoAddress
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if c.Username != "" {
+		auth = smtp.PlainAuth("", c.Username, c.Password, c.Host)
+	}
+	addr := net.JoinHostPort(c.Host, fmt.Sprint(c.Port))
+	return c.sendMail(addr, auth, c.From, []string{msg.To.Email}, c.compose(msg))
+}
+
+func (c *SMTPChannel) compose(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", c.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
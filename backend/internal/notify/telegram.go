@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// TelegramChannel sends messages through a Telegram bot.
+type TelegramChannel struct {
+	Token string
+	// BaseURL is the Bot API endpoint; it is only changed in tests
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewTelegramChannel(token string) *TelegramChannel {
+	return &TelegramChannel{
+		Token:   token,
+		BaseURL: telegramAPI,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *TelegramChannel) Name() string { return ChannelTelegram }
+
+func (c *TelegramChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To.TelegramChatID == "" {
+		return ErrNoAddress
+	}
+	text := msg.Body
+	if msg.Subject != "" {
+		text = msg.Subject + "\n\n" + msg.Body
+	}
+	payload, err := json.Marshal(map[string]string{"chat_id": msg.To.TelegramChatID, "text": text})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", c.BaseURL, c.Token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("telegram returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// Templates are named "<event>.<language>.tmpl" and define a "subject" and
+// a "body" template, e.g.
+//
+//	{{define "subject"}}New booking{{end}}
+//	{{define "body"}}{{.Booking.FirstName}} asked for a tutor{{end}}
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// ErrNoTemplate is returned when neither the requested nor the fallback
+// language has a template for an event.
+var ErrNoTemplate = errors.New("no notification template")
+
+// Templates renders notification messages per event and language.
+type Templates struct {
+	defaultLanguage string
+	set             map[string]*template.Template
+}
+
+// LoadTemplates parses the bundled templates and then the ones in dir, if
+// given. Files in dir replace bundled templates with the same name, so a
+// deployment can reword a single message without rebuilding.
+func LoadTemplates(dir, defaultLanguage string) (*Templates, error) {
+	if defaultLanguage == "" {
+		defaultLanguage = "en"
+	}
+	t := &Templates{defaultLanguage: defaultLanguage, set: map[string]*template.Template{}}
+	if err := t.parseFS(defaultTemplates, "templates"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := t.parseFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+func (t *Templates) parseFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		key := strings.TrimSuffix(path.Base(file), ".tmpl")
+		tmpl, err := template.New(key).Option("missingkey=zero").ParseFS(fsys, file)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", file, err)
+		}
+		if tmpl.Lookup("subject") == nil || tmpl.Lookup("body") == nil {
+			return fmt.Errorf("%s must define both \"subject\" and \"body\"", file)
+		}
+		t.set[key] = tmpl
+	}
+	return nil
+}
+
+// Render executes the template for event in language, falling back to the
+// default language and then to English.
+func (t *Templates) Render(event, language string, data any) (subject, body string, err error) {
+	var tmpl *template.Template
+	for _, lang := range []string{language, t.defaultLanguage, "en"} {
+		if found, ok := t.set[event+"."+lang]; lang != "" && ok {
+			tmpl = found
+			break
+		}
+	}
+	if tmpl == nil {
+		return "", "", fmt.Errorf("%w for %s", ErrNoTemplate, event)
+	}
+	var s, b strings.Builder
+	if err := tmpl.ExecuteTemplate(&s, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&b, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(s.String()), strings.TrimSpace(b.String()), nil
+}
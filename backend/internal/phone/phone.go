@@ -0,0 +1,91 @@
+// Package phone normalizes phone numbers to E.164. Numbers written without a
+// country code are taken to be Ethiopian, which is how parents and tutors
+// usually type them (0911..., 911...).
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultCountryCode is assumed for numbers in national format.
+const DefaultCountryCode = "251"
+
+// Ethiopian subscriber numbers are nine digits after the country code or
+// the trunk prefix 0. Mobile numbers start with 9 (Ethio telecom) or 7
+// (Safaricom); landlines with their area code (11, 22, 25, 33, ...).
+const ethiopianNationalLength = 9
+
+var ErrInvalid = errors.New("invalid phone number")
+
+// Normalize returns number in E.164 form, e.g. "+251911234567". Spaces,
+// dashes, dots and parentheses are ignored, and "00" is accepted in place
+// of "+". Numbers with a foreign country code are only checked for length
+// because we don't know their numbering plans.
+func Normalize(number string) (string, error) {
+	digits, international, err := clean(number)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case international:
+		// Already has a country code
+	case strings.HasPrefix(digits, DefaultCountryCode) && len(digits) == len(DefaultCountryCode)+ethiopianNationalLength:
+		// 251911234567 typed without the plus
+	case strings.HasPrefix(digits, "0") && len(digits) == ethiopianNationalLength+1:
+		digits = DefaultCountryCode + digits[1:]
+	case len(digits) == ethiopianNationalLength:
+		digits = DefaultCountryCode + digits
+	default:
+		return "", ErrInvalid
+	}
+
+	if national, ok := strings.CutPrefix(digits, DefaultCountryCode); ok {
+		if !validEthiopian(national) {
+			return "", ErrInvalid
+		}
+	} else if digits[0] == '0' || len(digits) < 8 || len(digits) > 15 {
+		return "", ErrInvalid
+	}
+	return "+" + digits, nil
+}
+
+// clean strips formatting characters and reports whether the number was
+// written with an international prefix.
+func clean(number string) (digits string, international bool, err error) {
+	number = strings.TrimSpace(number)
+	if rest, ok := strings.CutPrefix(number, "+"); ok {
+		number, international = rest, true
+	}
+	var b strings.Builder
+	for _, r := range number {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", false, ErrInvalid
+		}
+	}
+	digits = b.String()
+	if !international {
+		if rest, ok := strings.CutPrefix(digits, "00"); ok {
+			digits, international = rest, true
+		}
+	}
+	if digits == "" {
+		return "", false, ErrInvalid
+	}
+	return digits, international, nil
+}
+
+func validEthiopian(national string) bool {
+	if len(national) != ethiopianNationalLength {
+		return false
+	}
+	switch national[0] {
+	case '1', '2', '3', '4', '5', '7', '9':
+		return true
+	}
+	return false
+}
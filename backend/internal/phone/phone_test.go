@@ -0,0 +1,45 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	valid := map[string]string{
+		"0911234567":        "+251911234567",
+		"911234567":         "+251911234567",
+		"+251911234567":     "+251911234567",
+		"251911234567":      "+251911234567",
+		"251 911 23 45 67":  "+251911234567",
+		"00251-911-234-567": "+251911234567",
+		"(011) 551-2345":    "+251115512345",
+		" 0712345678 ":      "+251712345678",
+		"+1 202 555 0143":   "+12025550143",
+		"+44 20 7946 0958":  "+442079460958",
+	}
+	for in, want := range valid {
+		got, err := Normalize(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"abc",
+		"0911 23 45",  // too short
+		"09112345678", // too long
+		"0611234567",  // no such Ethiopian prefix
+		"+251 611 234 567",
+		"+2519112345678",
+		"+0123456789",
+		"+1234",
+		"0911234567 ext 2",
+	}
+	for _, in := range invalid {
+		_, err := Normalize(in)
+		require.ErrorIs(t, err, ErrInvalid, in)
+	}
+}
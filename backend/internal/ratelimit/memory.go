@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps buckets in process. Each instance counts on its own, so
+// use the Redis store when running more than one.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket will have refilled completely
+	full time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, p Policy, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key = p.Name + ":" + key
+	b, ok := s.buckets[key]
+	if !ok {
+		s.sweep(now)
+		b = &bucket{tokens: float64(p.Limit), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(p.Limit), b.tokens+p.tokensFor(now.Sub(b.last)))
+	b.last = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	r := result(p, b.tokens, allowed)
+	b.full = now.Add(r.Reset)
+	return r, nil
+}
+
+// sweep forgets buckets that have refilled, so the map doesn't grow without
+// bound. It runs at most once a minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package ratelimit implements token bucket rate limiting. Buckets live in a
+// Store so that several API instances can share them through Redis.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/config"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+)
+
+// ErrUnknownStore is returned by NewStore for unsupported store kinds.
+var ErrUnknownStore = errors.New("unknown rate limit store")
+
+// Policy lets a client burst up to Limit requests; the bucket refills
+// evenly so that it is full again after Period. Name keeps the buckets of
+// different policies apart.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// Enabled reports whether the policy limits anything.
+func (p Policy) Enabled() bool {
+	return p.Limit > 0 && p.Period > 0
+}
+
+// String formats the policy for the RateLimit-Policy header, e.g. "120;w=60".
+func (p Policy) String() string {
+	return fmt.Sprintf("%d;w=%d", p.Limit, int(p.Period.Seconds()))
+}
+
+// tokensFor is how many tokens are added over d.
+func (p Policy) tokensFor(d time.Duration) float64 {
+	return d.Seconds() * float64(p.Limit) / p.Period.Seconds()
+}
+
+// timeFor is how long it takes to add n tokens.
+func (p Policy) timeFor(n float64) time.Duration {
+	return time.Duration(n * float64(p.Period) / float64(p.Limit))
+}
+
+// ParsePolicy reads a "<limit>/<period>" spec such as "120/1m" or "20/1h".
+// An empty spec or a zero limit gives a disabled policy.
+func ParsePolicy(name, spec string) (Policy, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "0" {
+		return Policy{Name: name}, nil
+	}
+	limit, period, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("rate limit %s: %q is not <limit>/<period>", name, spec)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n < 0 {
+		return Policy{}, fmt.Errorf("rate limit %s: invalid limit %q", name, limit)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %s: invalid period %q", name, period)
+	}
+	return Policy{Name: name, Limit: n, Period: d}, nil
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next token, when not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps one bucket per policy and key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
+
+// NewStore builds the store selected by RATE_LIMIT_STORE.
+func NewStore(c *config.Config) (Store, error) {
+	switch c.RateLimitStore {
+	case StoreMemory, "":
+		return NewMemoryStore(), nil
+	case StoreRedis:
+		opts, err := redis.ParseURL(c.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("REDIS_URL: %w", err)
+		}
+		return NewRedisStore(redis.NewClient(opts)), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownStore, c.RateLimitStore)
+}
+
+// result describes a bucket left with tokens after a take.
+func result(p Policy, tokens float64, allowed bool) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     p.timeFor(float64(p.Limit) - tokens),
+	}
+	if !allowed {
+		r.RetryAfter = p.timeFor(1 - tokens)
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("read", "120/1m")
+	require.NoError(t, err)
+	require.Equal(t, Policy{Name: "read", Limit: 120, Period: time.Minute}, p)
+	require.Equal(t, "120;w=60", p.String())
+
+	p, err = ParsePolicy("read", "")
+	require.NoError(t, err)
+	require.False(t, p.Enabled())
+
+	for _, spec := range []string{"120", "x/1m", "10/soon", "10/0s"} {
+		_, err := ParsePolicy("read", spec)
+		require.Error(t, err, spec)
+	}
+}
+
+// testBucket checks the token bucket behaviour every store must share.
+func testBucket(t *testing.T, store Store) {
+	ctx := context.Background()
+	p := Policy{Name: "test", Limit: 2, Period: time.Hour}
+	now := time.Now()
+
+	res, err := store.Take(ctx, "a", p, now)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 2, res.Limit)
+	require.Equal(t, 1, res.Remaining)
+	require.Equal(t, 30*time.Minute, res.Reset)
+
+	res, err = store.Take(ctx, "a", p, now)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+
+	res, err = store.Take(ctx, "a", p, now)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 30*time.Minute, res.RetryAfter)
+	require.Equal(t, time.Hour, res.Reset)
+
+	// Other keys and policies have their own buckets
+	res, err = store.Take(ctx, "b", p, now)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	res, err = store.Take(ctx, "a", Policy{Name: "other", Limit: 1, Period: time.Hour}, now)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	// Half the period refills half the bucket
+	res, err = store.Take(ctx, "a", p, now.Add(30*time.Minute))
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testBucket(t, NewMemoryStore())
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	s := NewMemoryStore()
+	p := Policy{Name: "test", Limit: 2, Period: time.Hour}
+	now := time.Now()
+	_, _ = s.Take(context.Background(), "a", p, now)
+
+	// Refilled buckets are forgotten
+	_, _ = s.Take(context.Background(), "b", p, now.Add(3*time.Hour))
+	require.NotContains(t, s.buckets, "test:a")
+	require.Contains(t, s.buckets, "test:b")
+}
+
+func TestRedisStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	testBucket(t, NewRedisStore(client))
+
+	// Buckets expire once they would be full again
+	ttl := mr.TTL("ratelimit:test:b")
+	require.Greater(t, ttl, 30*time.Minute)
+	require.LessOrEqual(t, ttl, 31*time.Minute)
+}
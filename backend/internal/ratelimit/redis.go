@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills and takes from the bucket in one round trip, so
+// concurrent instances can't both spend the last token. Buckets expire once
+// they would be full again.
+//
+// KEYS[1] bucket; ARGV limit, period and now in milliseconds.
+// Returns whether a token was taken and the tokens left, as a string so
+// the fraction survives the conversion to a Redis reply.
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = limit
+	ts = now
+end
+tokens = math.min(limit, tokens + math.max(0, now - ts) * limit / period)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((limit - tokens) * period / limit) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis (or anything speaking its protocol and
+// Lua scripting) so every instance shares them.
+type RedisStore struct {
+	client redis.Scripter
+	// Prefix namespaces the bucket keys
+	Prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client, Prefix: "ratelimit:"}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error) {
+	out, err := takeScript.Run(ctx, s.client, []string{s.Prefix + p.Name + ":" + key},
+		p.Limit,
+		p.Period.Milliseconds(),
+		now.UnixMilli(),
+	).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, _ := out[0].(int64)
+	left, _ := out[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(p, tokens, allowed == 1), nil
+}
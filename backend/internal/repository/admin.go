@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"hiyab-tutor/internal/database"
 	"hiyab-tutor/internal/domain"
 
 	"gorm.io/gorm"
@@ -13,29 +15,29 @@ type adminRepository struct {
 func NewAdminRepository(db *gorm.DB) domain.AdminRepository {
 	return &adminRepository{db: db}
 }
-func (r *adminRepository) Create(admin *domain.Admin) (*domain.Admin, error) {
-	if err := r.db.Create(admin).Error; err != nil {
+func (r *adminRepository) Create(ctx context.Context, admin *domain.Admin) (*domain.Admin, error) {
+	if err := r.db.WithContext(ctx).Create(admin).Error; err != nil {
 		return nil, err
 	}
 	return admin, nil
 }
-func (r *adminRepository) GetByID(id uint) (*domain.Admin, error) {
+func (r *adminRepository) GetByID(ctx context.Context, id uint) (*domain.Admin, error) {
 	var admin domain.Admin
-	if err := r.db.First(&admin, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&admin, id).Error; err != nil {
 		return nil, err
 	}
 	return &admin, nil
 }
-func (r *adminRepository) GetByUsername(username string) (*domain.Admin, error) {
+func (r *adminRepository) GetByUsername(ctx context.Context, username string) (*domain.Admin, error) {
 	var admin domain.Admin
-	if err := r.db.Where("username = ?", username).First(&admin).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&admin).Error; err != nil {
 		return nil, err
 	}
 	return &admin, nil
 }
-func (r *adminRepository) GetAll(f *domain.AdminFilter) (*domain.MultipleAdmins, error) {
+func (r *adminRepository) GetAll(ctx context.Context, f *domain.AdminFilter) (*domain.MultipleAdmins, error) {
 	var admins []domain.Admin
-	query := r.db.Model(&domain.Admin{})
+	query := r.db.WithContext(ctx).Model(&domain.Admin{})
 	if f == nil {
 		f = &domain.AdminFilter{
 			Page:   1,
@@ -49,8 +51,7 @@ func (r *adminRepository) GetAll(f *domain.AdminFilter) (*domain.MultipleAdmins,
 		}
 		f.Offset = (f.Page - 1) * f.Limit
 		if f.Search != "" {
-			q := "%" + f.Search + "%"
-			query = query.Where("username LIKE ? OR email LIKE ? OR name LIKE ?", q, q, q)
+			query = query.Where(database.ContainsAny(r.db, f.Search, "username", "email", "name"))
 		}
 	}
 	var total int64
@@ -87,15 +88,28 @@ func (r *adminRepository) GetAll(f *domain.AdminFilter) (*domain.MultipleAdmins,
 		},
 	}, nil
 }
-func (r *adminRepository) Update(admin *domain.Admin) (*domain.Admin, error) {
-	if err := r.db.Save(admin).Error; err != nil {
+func (r *adminRepository) Update(ctx context.Context, admin *domain.Admin) (*domain.Admin, error) {
+	if err := r.db.WithContext(ctx).Save(admin).Error; err != nil {
 		return nil, err
 	}
 	return admin, nil
 }
-func (r *adminRepository) Delete(id uint) error {
-	if err := r.db.Delete(&domain.Admin{}, id).Error; err != nil {
+func (r *adminRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&domain.Admin{}, id).Error; err != nil {
 		return err
 	}
 	return nil
 }
+func (r *adminRepository) AddPasswordHistory(ctx context.Context, history *domain.PasswordHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+func (r *adminRepository) GetPasswordHistory(ctx context.Context, adminID uint, limit int) ([]domain.PasswordHistory, error) {
+	var history []domain.PasswordHistory
+	if limit <= 0 {
+		return history, nil
+	}
+	if err := r.db.WithContext(ctx).Where("admin_id = ?", adminID).Order("created_at DESC").Limit(limit).Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"hiyab-tutor/internal/database"
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -21,7 +22,7 @@ func TestAdminRepository(t *testing.T) {
 }
 func (suite *AdminRepositoryTestSuite) SetupSuite() {
 	// Create DB connection once for the entire suite
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		suite.T().Fatal("Failed to initialize database connection")
 	}
@@ -48,7 +49,7 @@ func (suite *AdminRepositoryTestSuite) TestCreateAdmin() {
 		Role:     "admin",
 		Name:     "Test Admin",
 	}
-	createdAdmin, err := suite.adminRepo.Create(admin)
+	createdAdmin, err := suite.adminRepo.Create(context.Background(), admin)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin: %v", err)
 	}
@@ -68,11 +69,11 @@ func (suite *AdminRepositoryTestSuite) TestGetByID() {
 		Role:     "admin",
 		Name:     "Test Admin",
 	}
-	createdAdmin, err := suite.adminRepo.Create(admin)
+	createdAdmin, err := suite.adminRepo.Create(context.Background(), admin)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin: %v", err)
 	}
-	retrievedAdmin, err := suite.adminRepo.GetByID(createdAdmin.ID)
+	retrievedAdmin, err := suite.adminRepo.GetByID(context.Background(), createdAdmin.ID)
 	suite.NoError(err)
 	suite.NotNil(retrievedAdmin)
 	suite.Equal(createdAdmin.ID, retrievedAdmin.ID)
@@ -91,11 +92,11 @@ func (suite *AdminRepositoryTestSuite) TestGetByUsername() {
 		Role:     "admin",
 		Name:     "Test Admin",
 	}
-	createdAdmin, err := suite.adminRepo.Create(admin)
+	createdAdmin, err := suite.adminRepo.Create(context.Background(), admin)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin: %v", err)
 	}
-	retrievedAdmin, err := suite.adminRepo.GetByUsername(createdAdmin.Username)
+	retrievedAdmin, err := suite.adminRepo.GetByUsername(context.Background(), createdAdmin.Username)
 	suite.NoError(err)
 	suite.NotNil(retrievedAdmin)
 	suite.Equal(createdAdmin.ID, retrievedAdmin.ID)
@@ -114,7 +115,7 @@ func (suite *AdminRepositoryTestSuite) TestUpdate() {
 		Role:     "admin",
 		Name:     "Test Admin",
 	}
-	createdAdmin, err := suite.adminRepo.Create(admin)
+	createdAdmin, err := suite.adminRepo.Create(context.Background(), admin)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin: %v", err)
 	}
@@ -125,11 +126,11 @@ func (suite *AdminRepositoryTestSuite) TestUpdate() {
 		Role:     "admin",
 		Name:     "Test Admin Updated",
 	}
-	_, err = suite.adminRepo.Update(updatedAdmin)
+	_, err = suite.adminRepo.Update(context.Background(), updatedAdmin)
 	if err != nil {
 		suite.T().Fatalf("Failed to update admin: %v", err)
 	}
-	retrievedAdmin, err := suite.adminRepo.GetByID(createdAdmin.ID)
+	retrievedAdmin, err := suite.adminRepo.GetByID(context.Background(), createdAdmin.ID)
 	suite.NoError(err)
 	suite.NotNil(retrievedAdmin)
 	suite.Equal(updatedAdmin.ID, retrievedAdmin.ID)
@@ -146,15 +147,15 @@ func (suite *AdminRepositoryTestSuite) TestDelete() {
 		Role:     "admin",
 		Name:     "Test Admin",
 	}
-	createdAdmin, err := suite.adminRepo.Create(admin)
+	createdAdmin, err := suite.adminRepo.Create(context.Background(), admin)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin: %v", err)
 	}
-	err = suite.adminRepo.Delete(createdAdmin.ID)
+	err = suite.adminRepo.Delete(context.Background(), createdAdmin.ID)
 	if err != nil {
 		suite.T().Fatalf("Failed to delete admin: %v", err)
 	}
-	retrievedAdmin, err := suite.adminRepo.GetByID(createdAdmin.ID)
+	retrievedAdmin, err := suite.adminRepo.GetByID(context.Background(), createdAdmin.ID)
 	suite.Error(err)
 	suite.Nil(retrievedAdmin)
 }
@@ -171,15 +172,15 @@ func (suite *AdminRepositoryTestSuite) TestGetAll() {
 		Role:     "admin",
 		Name:     "Admin Two",
 	}
-	_, err := suite.adminRepo.Create(admin1)
+	_, err := suite.adminRepo.Create(context.Background(), admin1)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin1: %v", err)
 	}
-	_, err = suite.adminRepo.Create(admin2)
+	_, err = suite.adminRepo.Create(context.Background(), admin2)
 	if err != nil {
 		suite.T().Fatalf("Failed to create admin2: %v", err)
 	}
-	admins, err := suite.adminRepo.GetAll(&domain.AdminFilter{SortBy: "created_at", SortOrder: "asc"})
+	admins, err := suite.adminRepo.GetAll(context.Background(), &domain.AdminFilter{SortBy: "created_at", SortOrder: "asc"})
 	suite.NoError(err)
 	suite.Len(admins.Admins, 2)
 	suite.Equal(admin1.Username, admins.Admins[0].Username)
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/domain"
+	"math"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// waitBuckets are the histogram buckets of a DurationReport, in hours
+var waitBuckets = []struct {
+	label    string
+	min, max float64
+}{
+	{"under 1 hour", 0, 1},
+	{"1-6 hours", 1, 6},
+	{"6-24 hours", 6, 24},
+	{"1-3 days", 24, 72},
+	{"3-7 days", 72, 168},
+	{"over 7 days", 168, math.Inf(1)},
+}
+
+type analyticsRepo struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) domain.AnalyticsRepository {
+	return &analyticsRepo{db: db}
+}
+
+func (r *analyticsRepo) Totals(ctx context.Context) (*domain.AnalyticsTotals, error) {
+	var totals domain.AnalyticsTotals
+	counts := []struct {
+		model any
+		n     *int64
+		// spam marks models whose rows can be quarantined
+		spam bool
+	}{
+		{&domain.Booking{}, &totals.Bookings, true},
+		{&domain.Tutor{}, &totals.Tutors, true},
+		{&domain.Partner{}, &totals.Partners, false},
+		{&domain.Testimonial{}, &totals.Testimonials, false},
+		{&domain.OtherService{}, &totals.OtherServices, false},
+	}
+	for _, c := range counts {
+		query := r.db.WithContext(ctx).Model(c.model)
+		if c.spam {
+			query = live(query)
+		} else {
+			query = query.Where("deleted_at IS NULL")
+		}
+		if err := query.Count(c.n).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &totals, nil
+}
+
+// live leaves out the bookings or tutors in the trash or quarantine
+func live(query *gorm.DB) *gorm.DB {
+	return query.Where("deleted_at IS NULL AND quarantined = ?", false)
+}
+
+// between limits column to the query's days
+func between(column string, q *domain.AnalyticsQuery) (string, any, any) {
+	return column + " >= ? AND " + column + " < ?", q.From, q.To.AddDate(0, 0, 1)
+}
+
+// bookings are the live bookings made in the query's range
+func (r *analyticsRepo) bookings(ctx context.Context, q *domain.AnalyticsQuery) *gorm.DB {
+	return live(r.db.WithContext(ctx).Model(&domain.Booking{})).
+		Where(between("created_at", q))
+}
+
+func (r *analyticsRepo) Bookings(ctx context.Context, q *domain.AnalyticsQuery) ([]domain.BookingPoint, error) {
+	points := []domain.BookingPoint{}
+	err := r.bookings(ctx, q).
+		Select(database.PeriodStart(r.db, q.Granularity, "created_at") + " AS period, COUNT(*) AS bookings, " +
+			"SUM(CASE WHEN assigned THEN 1 ELSE 0 END) AS assigned").
+		Group("period").Order("period").
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *analyticsRepo) Tutors(ctx context.Context, q *domain.AnalyticsQuery) ([]domain.TutorPoint, error) {
+	type count struct {
+		Period string
+		N      int64
+	}
+	tutors := func(column string) ([]count, error) {
+		counts := []count{}
+		err := live(r.db.WithContext(ctx).Model(&domain.Tutor{})).
+			Where(between(column, q)).
+			Select(database.PeriodStart(r.db, q.Granularity, column) + " AS period, COUNT(*) AS n").
+			Group("period").Order("period").
+			Scan(&counts).Error
+		return counts, err
+	}
+	registered, err := tutors("created_at")
+	if err != nil {
+		return nil, err
+	}
+	verified, err := tutors("verified_at")
+	if err != nil {
+		return nil, err
+	}
+
+	// Both lists are ordered by period, so merge them
+	points := make([]domain.TutorPoint, 0, len(registered)+len(verified))
+	for len(registered) > 0 || len(verified) > 0 {
+		var p domain.TutorPoint
+		switch {
+		case len(verified) == 0 || len(registered) > 0 && registered[0].Period < verified[0].Period:
+			p.Period, p.Registered = registered[0].Period, registered[0].N
+			registered = registered[1:]
+		case len(registered) == 0 || verified[0].Period < registered[0].Period:
+			p.Period, p.Verified = verified[0].Period, verified[0].N
+			verified = verified[1:]
+		default:
+			p.Period, p.Registered, p.Verified = registered[0].Period, registered[0].N, verified[0].N
+			registered, verified = registered[1:], verified[1:]
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func (r *analyticsRepo) TimeToAssignment(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	waits := live(r.db.WithContext(ctx).Model(&domain.Booking{})).
+		Select(database.HoursBetween(r.db, "created_at", "assigned_at")+" AS hours, assigned_at AS ended_at").
+		Where("assigned = ? AND assigned_at IS NOT NULL", true).
+		Where(between("assigned_at", q))
+	return r.durations(ctx, q, waits)
+}
+
+func (r *analyticsRepo) TimeToVerification(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	waits := live(r.db.WithContext(ctx).Model(&domain.Tutor{})).
+		Select(database.HoursBetween(r.db, "created_at", "verified_at")+" AS hours, verified_at AS ended_at").
+		Where("verified = ? AND verified_at IS NOT NULL", true).
+		Where(between("verified_at", q))
+	return r.durations(ctx, q, waits)
+}
+
+// durations sums up waits, a query selecting hours and ended_at.
+func (r *analyticsRepo) durations(ctx context.Context, q *domain.AnalyticsQuery, waits *gorm.DB) (*domain.DurationReport, error) {
+	from := func() *gorm.DB { return r.db.WithContext(ctx).Table("(?) AS waits", waits) }
+
+	var summary struct {
+		Count                        int64
+		AvgHours, MinHours, MaxHours *float64
+	}
+	if err := from().Select("COUNT(*) AS count, AVG(hours) AS avg_hours, MIN(hours) AS min_hours, MAX(hours) AS max_hours").
+		Scan(&summary).Error; err != nil {
+		return nil, err
+	}
+	report := &domain.DurationReport{Count: summary.Count, Histogram: []domain.HistogramBucket{}}
+	if summary.Count > 0 {
+		report.AvgHours, report.MinHours, report.MaxHours = *summary.AvgHours, *summary.MinHours, *summary.MaxHours
+	}
+
+	var cases strings.Builder
+	cases.WriteString("CASE")
+	for i, b := range waitBuckets[:len(waitBuckets)-1] {
+		fmt.Fprintf(&cases, " WHEN hours < %g THEN %d", b.max, i)
+	}
+	fmt.Fprintf(&cases, " ELSE %d END", len(waitBuckets)-1)
+	var buckets []struct {
+		Bucket int
+		N      int64
+	}
+	if err := from().Select(cases.String() + " AS bucket, COUNT(*) AS n").Group("bucket").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(waitBuckets))
+	for _, b := range buckets {
+		counts[b.Bucket] = b.N
+	}
+	for i, b := range waitBuckets {
+		bucket := domain.HistogramBucket{Label: b.label, MinHours: b.min, Count: counts[i]}
+		if !math.IsInf(b.max, 1) {
+			bucket.MaxHours = &b.max
+		}
+		report.Histogram = append(report.Histogram, bucket)
+	}
+
+	report.Points = []domain.DurationPoint{}
+	err := from().
+		Select(database.PeriodStart(r.db, q.Granularity, "ended_at") + " AS period, COUNT(*) AS count, AVG(hours) AS avg_hours").
+		Group("period").Order("period").
+		Scan(&report.Points).Error
+	return report, err
+}
+
+func (r *analyticsRepo) BookingBreakdown(ctx context.Context, q *domain.AnalyticsQuery, dimension string) ([]domain.GroupPoint, error) {
+	var key string
+	switch dimension {
+	case domain.DimensionGrade:
+		key = "CAST(grade AS TEXT)"
+	case domain.DimensionGender:
+		key = "lower(trim(gender))"
+	case domain.DimensionArea:
+		key = database.FirstPart(r.db, "address", ",")
+	default:
+		return nil, domain.ErrInvalidDimension
+	}
+	points := []domain.GroupPoint{}
+	err := r.bookings(ctx, q).
+		Select(database.PeriodStart(r.db, q.Granularity, "created_at") + " AS period, " + key + " AS key, COUNT(*) AS count").
+		Group("period, key").Order("period, key").
+		Scan(&points).Error
+	return points, err
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AnalyticsTestSuite struct {
+	suite.Suite
+	analyticsRepo domain.AnalyticsRepository
+	db            *gorm.DB
+}
+
+func TestAnalyticsRepository(t *testing.T) {
+	suite.Run(t, new(AnalyticsTestSuite))
+}
+
+func (suite *AnalyticsTestSuite) SetupTest() {
+	suite.db = dbtest.Open()
+	suite.NoError(suite.db.AutoMigrate(&domain.Booking{}, &domain.Tutor{}, &domain.Partner{}, &domain.Testimonial{}, &domain.OtherService{}))
+	suite.analyticsRepo = NewAnalyticsRepository(suite.db)
+}
+
+// at is a time on a day of March 2025, in UTC
+func at(day, hour int) time.Time {
+	return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func ptr[T any](v T) *T { return &v }
+
+// march covers March 2025
+func march(granularity string) *domain.AnalyticsQuery {
+	return &domain.AnalyticsQuery{From: at(1, 0), To: at(31, 0), Granularity: granularity}
+}
+
+func (suite *AnalyticsTestSuite) booking(b domain.Booking) {
+	suite.NoError(suite.db.Create(&b).Error)
+}
+
+func (suite *AnalyticsTestSuite) tutor(t domain.Tutor) {
+	suite.NoError(suite.db.Create(&t).Error)
+}
+
+func (suite *AnalyticsTestSuite) TestTotals() {
+	suite.booking(domain.Booking{FirstName: "A"})
+	suite.booking(domain.Booking{FirstName: "Gone", Model: domain.Model{DeletedAt: ptr(at(1, 0))}})
+	suite.booking(domain.Booking{FirstName: "Spam", Quarantined: true})
+	suite.tutor(domain.Tutor{FirstName: "T"})
+	suite.tutor(domain.Tutor{FirstName: "Spam", Quarantined: true})
+	suite.NoError(suite.db.Create(&domain.Partner{Name: "P"}).Error)
+
+	totals, err := suite.analyticsRepo.Totals(context.Background())
+	suite.NoError(err)
+	suite.Equal(domain.AnalyticsTotals{Bookings: 1, Tutors: 1, Partners: 1}, *totals)
+}
+
+func (suite *AnalyticsTestSuite) TestBookings() {
+	// Monday the 3rd, Sunday the 9th and Monday the 10th
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Assigned: true})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 18)}})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(9, 23)}})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(10, 0)}})
+	// Left out: quarantined, deleted and outside the range
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Quarantined: true})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9), DeletedAt: ptr(at(4, 0))}})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)}})
+
+	points, err := suite.analyticsRepo.Bookings(context.Background(), march(domain.GranularityDay))
+	suite.NoError(err)
+	suite.Equal([]domain.BookingPoint{
+		{Period: "2025-03-03", Bookings: 2, Assigned: 1},
+		{Period: "2025-03-09", Bookings: 1},
+		{Period: "2025-03-10", Bookings: 1},
+	}, points)
+
+	points, err = suite.analyticsRepo.Bookings(context.Background(), march(domain.GranularityWeek))
+	suite.NoError(err)
+	suite.Equal([]domain.BookingPoint{
+		{Period: "2025-03-03", Bookings: 3, Assigned: 1},
+		{Period: "2025-03-10", Bookings: 1},
+	}, points)
+
+	points, err = suite.analyticsRepo.Bookings(context.Background(), march(domain.GranularityMonth))
+	suite.NoError(err)
+	suite.Equal([]domain.BookingPoint{{Period: "2025-03-01", Bookings: 4, Assigned: 1}}, points)
+}
+
+func (suite *AnalyticsTestSuite) TestTutors() {
+	suite.tutor(domain.Tutor{Model: domain.Model{CreatedAt: at(2, 9)}, Verified: true, VerifiedAt: ptr(at(5, 9))})
+	suite.tutor(domain.Tutor{Model: domain.Model{CreatedAt: at(5, 9)}})
+	suite.tutor(domain.Tutor{Model: domain.Model{CreatedAt: at(7, 9)}})
+
+	points, err := suite.analyticsRepo.Tutors(context.Background(), march(domain.GranularityDay))
+	suite.NoError(err)
+	suite.Equal([]domain.TutorPoint{
+		{Period: "2025-03-02", Registered: 1},
+		{Period: "2025-03-05", Registered: 1, Verified: 1},
+		{Period: "2025-03-07", Registered: 1},
+	}, points)
+}
+
+func (suite *AnalyticsTestSuite) TestTimeToAssignment() {
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Assigned: true, AssignedAt: ptr(at(3, 9).Add(30 * time.Minute))})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Assigned: true, AssignedAt: ptr(at(4, 21))})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(1, 0)}, Assigned: true, AssignedAt: ptr(at(10, 0))})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}})
+	// Spam never counts, whatever happened to it
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Assigned: true, AssignedAt: ptr(at(3, 10)), Quarantined: true})
+
+	report, err := suite.analyticsRepo.TimeToAssignment(context.Background(), march(domain.GranularityMonth))
+	suite.NoError(err)
+	suite.EqualValues(3, report.Count)
+	suite.InDelta(0.5, report.MinHours, 0.01)
+	suite.InDelta(216, report.MaxHours, 0.01)
+	suite.InDelta((0.5+36+216)/3, report.AvgHours, 0.01)
+
+	counts := map[string]int64{}
+	for _, b := range report.Histogram {
+		counts[b.Label] = b.Count
+	}
+	suite.Len(report.Histogram, len(waitBuckets))
+	suite.Equal(map[string]int64{
+		"under 1 hour": 1, "1-6 hours": 0, "6-24 hours": 0,
+		"1-3 days": 1, "3-7 days": 0, "over 7 days": 1,
+	}, counts)
+	suite.Nil(report.Histogram[len(report.Histogram)-1].MaxHours)
+
+	suite.Len(report.Points, 1)
+	suite.Equal("2025-03-01", report.Points[0].Period)
+	suite.EqualValues(3, report.Points[0].Count)
+}
+
+func (suite *AnalyticsTestSuite) TestTimeToVerification_Empty() {
+	suite.tutor(domain.Tutor{Model: domain.Model{CreatedAt: at(3, 9)}, Verified: true, VerifiedAt: ptr(at(3, 10)), Quarantined: true})
+	report, err := suite.analyticsRepo.TimeToVerification(context.Background(), march(domain.GranularityDay))
+	suite.NoError(err)
+	suite.Zero(report.Count)
+	suite.Len(report.Histogram, len(waitBuckets))
+	suite.Empty(report.Points)
+}
+
+func (suite *AnalyticsTestSuite) TestBookingBreakdown() {
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 9)}, Grade: 5, Gender: "Female", Address: "Bole, Addis Ababa"})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(3, 10)}, Grade: 5, Gender: "female", Address: " bole"})
+	suite.booking(domain.Booking{Model: domain.Model{CreatedAt: at(4, 9)}, Grade: 8, Gender: "male", Address: "Piassa, Addis Ababa"})
+
+	points, err := suite.analyticsRepo.BookingBreakdown(context.Background(), march(domain.GranularityMonth), domain.DimensionArea)
+	suite.NoError(err)
+	suite.Equal([]domain.GroupPoint{
+		{Period: "2025-03-01", Key: "bole", Count: 2},
+		{Period: "2025-03-01", Key: "piassa", Count: 1},
+	}, points)
+
+	points, err = suite.analyticsRepo.BookingBreakdown(context.Background(), march(domain.GranularityDay), domain.DimensionGender)
+	suite.NoError(err)
+	suite.Equal([]domain.GroupPoint{
+		{Period: "2025-03-03", Key: "female", Count: 2},
+		{Period: "2025-03-04", Key: "male", Count: 1},
+	}, points)
+
+	points, err = suite.analyticsRepo.BookingBreakdown(context.Background(), march(domain.GranularityMonth), domain.DimensionGrade)
+	suite.NoError(err)
+	suite.Equal([]domain.GroupPoint{
+		{Period: "2025-03-01", Key: "5", Count: 2},
+		{Period: "2025-03-01", Key: "8", Count: 1},
+	}, points)
+
+	_, err = suite.analyticsRepo.BookingBreakdown(context.Background(), march(domain.GranularityMonth), "age")
+	suite.ErrorIs(err, domain.ErrInvalidDimension)
+}
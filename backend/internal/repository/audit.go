@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const auditExportBatchSize = 500
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *auditRepository) filter(ctx context.Context, f *domain.AuditFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+	if f == nil {
+		return query
+	}
+	if f.ActorID > 0 {
+		query = query.Where("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.EntityType != "" {
+		query = query.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID > 0 {
+		query = query.Where("entity_id = ?", f.EntityID)
+	}
+	if !f.From.IsZero() {
+		query = query.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		query = query.Where("created_at <= ?", f.To)
+	}
+	return query
+}
+
+func (r *auditRepository) GetAll(ctx context.Context, f *domain.AuditFilter) (*domain.MultipleAuditLogs, error) {
+	var total int64
+	if err := r.filter(ctx, f).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	limit := 20
+	page := 1
+	if f != nil {
+		if f.Limit > 0 {
+			limit = f.Limit
+		}
+		if f.Page > 0 {
+			page = f.Page
+		}
+	}
+	offset := (page - 1) * limit
+	entries := []domain.AuditLog{}
+	if err := r.filter(ctx, f).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return &domain.MultipleAuditLogs{
+		Data: entries,
+		Pagination: domain.Pagination{
+			Page:         page,
+			Limit:        limit,
+			Offset:       offset,
+			Total:        int(total),
+			PreviousPage: page - 1,
+			NextPage:     page + 1,
+		},
+	}, nil
+}
+
+func (r *auditRepository) Each(ctx context.Context, f *domain.AuditFilter, fn func(*domain.AuditLog) error) error {
+	var batch []domain.AuditLog
+	return r.filter(ctx, f).FindInBatches(&batch, auditExportBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"hiyab-tutor/internal/database"
 	"hiyab-tutor/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,32 +12,64 @@ import (
 type BookingRepository interface {
 }
 
+// eachBatchSize is how many rows Each loads at a time, createBatchSize
+// how many CreateMany inserts per statement
+const (
+	eachBatchSize   = 500
+	createBatchSize = 100
+)
+
+// bookingSearchColumns are what GetAll's query searches, by weight
+var bookingSearchColumns = []database.SearchColumn{
+	{Name: "first_name", Weight: 'A'},
+	{Name: "last_name", Weight: 'A'},
+	{Name: "phone_number", Weight: 'B'},
+	{Name: "address", Weight: 'C'},
+}
+
 type bookingRepo struct {
-	db *gorm.DB
+	db     *gorm.DB
+	search *database.Search
 }
 
 func NewBookingRepository(db *gorm.DB) domain.BookingRepository {
-	db.AutoMigrate(&domain.Booking{})
-	return &bookingRepo{db: db}
+	return &bookingRepo{db: db, search: database.NewSearch(db, "bookings", bookingSearchColumns...)}
 }
 
-func (r *bookingRepo) Create(b *domain.Booking) (*domain.Booking, error) {
-	if err := r.db.Model(&domain.Booking{}).Create(b).Error; err != nil {
+func (r *bookingRepo) Create(ctx context.Context, b *domain.Booking) (*domain.Booking, error) {
+	if err := r.db.WithContext(ctx).Model(&domain.Booking{}).Create(b).Error; err != nil {
 		return nil, err
 	}
 	return b, nil
 }
-func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
-	var bookings []*domain.Booking
-	var total int64
-	query := r.db.Model(&domain.Booking{})
-	// Filtering
+
+// CreateMany inserts bookings in batches inside one transaction.
+func (r *bookingRepo) CreateMany(ctx context.Context, bookings []*domain.Booking, check func(domain.BookingRepository, *domain.Booking) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if check != nil {
+			if err := lockTable(tx, "bookings"); err != nil {
+				return err
+			}
+			repo := &bookingRepo{db: tx, search: r.search}
+			for _, b := range bookings {
+				if err := check(repo, b); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.CreateInBatches(bookings, createBatchSize).Error
+	})
+}
+
+// filter selects the bookings matching filter, without paging or sorting.
+func (r *bookingRepo) filter(ctx context.Context, filter *domain.BookingFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.Booking{})
 	if filter != nil {
 		if filter.Gender != "" {
 			query = query.Where("gender = ?", filter.Gender)
 		}
 		if filter.Address != "" {
-			query = query.Where("address LIKE ?", "%"+filter.Address+"%")
+			query = query.Where(database.ContainsAny(r.db, filter.Address, "address"))
 		}
 		if filter.MinGrade > 0 {
 			query = query.Where("grade >= ?", filter.MinGrade)
@@ -43,8 +78,7 @@ func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBooki
 			query = query.Where("grade <= ?", filter.MaxGrade)
 		}
 		if filter.Query != "" {
-			q := "%" + filter.Query + "%"
-			query = query.Where("first_name LIKE ? OR last_name LIKE ? OR phone_number LIKE ? OR address LIKE ?", q, q, q, q)
+			query = query.Where(r.search.Match(filter.Query))
 		}
 		if filter.MinDayPerWeek > 0 {
 			query = query.Where("day_per_week >= ?", filter.MinDayPerWeek)
@@ -62,7 +96,16 @@ func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBooki
 		if filter.Assigned {
 			query = query.Where("assigned = ?", filter.Assigned)
 		}
+		// Quarantined bookings are only listed when asked for
+		query = query.Where("quarantined = ?", filter.Quarantined)
 	}
+	return trashed(query, filter != nil && filter.Deleted)
+}
+
+func (r *bookingRepo) GetAll(ctx context.Context, filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
+	var bookings []*domain.Booking
+	var total int64
+	query := r.filter(ctx, filter)
 	// Count total matching
 	if err := query.Count(&total).Error; err != nil {
 		return domain.MultipleBookingResponse{}, err
@@ -82,6 +125,7 @@ func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBooki
 	offset := (page - 1) * limit
 
 	// Apply safe ordering if provided
+	sorted := false
 	if filter != nil && filter.SortBy != "" {
 		allowed := map[string]bool{
 			"first_name":   true,
@@ -97,8 +141,13 @@ func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBooki
 				order = "desc"
 			}
 			query = query.Order(filter.SortBy + " " + order)
+			sorted = true
 		}
 	}
+	// Unless sorted otherwise, search results come best match first
+	if filter != nil && filter.Query != "" && !sorted {
+		query = r.search.Rank(query, filter.Query)
+	}
 
 	query = query.Limit(limit).Offset(offset)
 	if err := query.Find(&bookings).Error; err != nil {
@@ -122,17 +171,34 @@ func (r *bookingRepo) GetAll(filter *domain.BookingFilter) (domain.MultipleBooki
 	}
 	return resp, nil
 }
-func (r *bookingRepo) GetByID(id uint) (*domain.Booking, error) {
+
+// Each calls fn with every booking matching filter, in ID order, loading
+// them in batches. Paging and sorting are ignored.
+func (r *bookingRepo) Each(ctx context.Context, filter *domain.BookingFilter, fn func(*domain.Booking) error) error {
+	var batch []domain.Booking
+	return r.filter(ctx, filter).FindInBatches(&batch, eachBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// GetByID, Update and Release don't see bookings in the trash; only Bulk
+// can restore them.
+func (r *bookingRepo) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
 	var b domain.Booking
-	if err := r.db.First(&b, id).Error; err != nil {
+	if err := trashed(r.db.WithContext(ctx), false).First(&b, id).Error; err != nil {
 		return nil, err
 	}
 	return &b, nil
 }
-func (r *bookingRepo) Update(id uint, b *domain.Booking) (*domain.Booking, error) {
+func (r *bookingRepo) Update(ctx context.Context, id uint, b *domain.Booking) (*domain.Booking, error) {
 	// Find the booking by ID
 	var booking domain.Booking
-	if err := r.db.First(&booking, id).Error; err != nil {
+	if err := trashed(r.db.WithContext(ctx), false).First(&booking, id).Error; err != nil {
 		return nil, err
 	}
 	// Update all fields
@@ -144,14 +210,55 @@ func (r *bookingRepo) Update(id uint, b *domain.Booking) (*domain.Booking, error
 	booking.PhoneNumber = b.PhoneNumber
 	booking.DayPerWeek = b.DayPerWeek
 	booking.HrPerDay = b.HrPerDay
+	if b.Language != "" {
+		booking.Language = b.Language
+	}
+	// AssignedAt follows Assigned, whichever way the flag was changed
+	if b.Assigned && !booking.Assigned {
+		now := time.Now()
+		booking.AssignedAt = &now
+	} else if !b.Assigned {
+		booking.AssignedAt = nil
+	}
 	booking.Assigned = b.Assigned
-	if err := r.db.Save(&booking).Error; err != nil {
+	booking.TutorID = b.TutorID
+	if err := r.db.WithContext(ctx).Save(&booking).Error; err != nil {
 		return nil, err
 	}
 	return &booking, nil
 }
-func (r *bookingRepo) Delete(id uint) error {
-	if err := r.db.Delete(&domain.Booking{}, id).Error; err != nil {
+func (r *bookingRepo) GetByPhoneNumber(ctx context.Context, phone string) ([]domain.Booking, error) {
+	var bookings []domain.Booking
+	if err := trashed(r.db.WithContext(ctx), false).Where("phone_number = ?", phone).Order("id").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+func (r *bookingRepo) Release(ctx context.Context, id uint) error {
+	return trashed(r.db.WithContext(ctx).Model(&domain.Booking{}), false).Where("id = ?", id).
+		Updates(map[string]any{"quarantined": false, "quarantine_reason": ""}).Error
+}
+
+// Bulk runs in a transaction, with the bookings it acts on locked until it
+// ends.
+func (r *bookingRepo) Bulk(ctx context.Context, ids []uint, filter *domain.BookingFilter, fn domain.BulkFunc[domain.Booking]) (*domain.BulkResult, error) {
+	var result *domain.BulkResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Booking{})
+		if len(ids) == 0 {
+			query = (&bookingRepo{db: tx, search: r.search}).filter(ctx, filter)
+		}
+		var err error
+		result, err = bulk(tx, query, ids, fn, func(b *domain.Booking) uint { return b.ID })
+		return err
+	})
+	return result, err
+}
+func (r *bookingRepo) Delete(ctx context.Context, id uint) error {
+	// Moved to the trash, like a bulk delete; the purge task removes it
+	err := trashed(r.db.WithContext(ctx).Model(&domain.Booking{}), false).Where("id = ?", id).
+		Update("deleted_at", time.Now()).Error
+	if err != nil {
 		return err
 	}
 	return nil
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -20,7 +23,7 @@ func TestBookingRepository(t *testing.T) {
 }
 
 func (s *BookingRepoTestSuite) SetupSuite() {
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		s.T().Fatal("creating test database issue")
 	}
@@ -53,7 +56,7 @@ func (s *BookingRepoTestSuite) TestCreate() {
 		HrPerDay:    2,
 		Assigned:    false,
 	}
-	createdBooking, err := s.bookingRepo.Create(b)
+	createdBooking, err := s.bookingRepo.Create(context.Background(), b)
 	s.NoError(err)
 	s.NotNil(createdBooking)
 	s.Equal(createdBooking.ID, uint(1))
@@ -77,9 +80,9 @@ func (s *BookingRepoTestSuite) TestGetByID() {
 		HrPerDay:    2,
 		Assigned:    false,
 	}
-	created, err := s.bookingRepo.Create(b)
+	created, err := s.bookingRepo.Create(context.Background(), b)
 	s.NoError(err)
-	booking, err := s.bookingRepo.GetByID(created.ID)
+	booking, err := s.bookingRepo.GetByID(context.Background(), created.ID)
 	s.NoError(err)
 	s.NotNil(booking)
 	s.Equal(booking.FirstName, b.FirstName)
@@ -105,11 +108,11 @@ func (s *BookingRepoTestSuite) TestGetAll_NoFilter() {
 			HrPerDay:    i % 5,
 			Assigned:    i%2 == 0,
 		}
-		_, err := s.bookingRepo.Create(b)
+		_, err := s.bookingRepo.Create(context.Background(), b)
 		s.NoError(err)
 	}
 	// Should return only 10 due to pagination
-	resp, err := s.bookingRepo.GetAll(&domain.BookingFilter{})
+	resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{})
 	s.NoError(err)
 	s.Len(resp.Data, 10)
 }
@@ -117,11 +120,11 @@ func (s *BookingRepoTestSuite) TestGetAll_NoFilter() {
 func (s *BookingRepoTestSuite) TestGetAll_FilterByGender() {
 	b1 := &domain.Booking{FirstName: "A", Gender: "Male"}
 	b2 := &domain.Booking{FirstName: "B", Gender: "Female"}
-	_, err := s.bookingRepo.Create(b1)
+	_, err := s.bookingRepo.Create(context.Background(), b1)
 	s.NoError(err)
-	_, err = s.bookingRepo.Create(b2)
+	_, err = s.bookingRepo.Create(context.Background(), b2)
 	s.NoError(err)
-	resp, err := s.bookingRepo.GetAll(&domain.BookingFilter{Gender: "Female"})
+	resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Gender: "Female"})
 	s.NoError(err)
 	s.Len(resp.Data, 1)
 	s.Equal("Female", resp.Data[0].Gender)
@@ -130,10 +133,10 @@ func (s *BookingRepoTestSuite) TestGetAll_FilterByGender() {
 func (s *BookingRepoTestSuite) TestGetAll_FilterByGradeRange() {
 	for i := 1; i <= 5; i++ {
 		b := &domain.Booking{FirstName: "User", Grade: i}
-		_, err := s.bookingRepo.Create(b)
+		_, err := s.bookingRepo.Create(context.Background(), b)
 		s.NoError(err)
 	}
-	resp, err := s.bookingRepo.GetAll(&domain.BookingFilter{MinGrade: 2, MaxGrade: 4})
+	resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{MinGrade: 2, MaxGrade: 4})
 	s.NoError(err)
 	for _, b := range resp.Data {
 		s.True(b.Grade >= 2 && b.Grade <= 4)
@@ -143,16 +146,52 @@ func (s *BookingRepoTestSuite) TestGetAll_FilterByGradeRange() {
 func (s *BookingRepoTestSuite) TestGetAll_QuerySearch() {
 	b1 := &domain.Booking{FirstName: "Alice", Address: "Wonderland", PhoneNumber: "111"}
 	b2 := &domain.Booking{FirstName: "Bob", Address: "Builder", PhoneNumber: "222"}
-	_, err := s.bookingRepo.Create(b1)
+	_, err := s.bookingRepo.Create(context.Background(), b1)
 	s.NoError(err)
-	_, err = s.bookingRepo.Create(b2)
+	_, err = s.bookingRepo.Create(context.Background(), b2)
 	s.NoError(err)
-	resp, err := s.bookingRepo.GetAll(&domain.BookingFilter{Query: "Alice"})
+	resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Query: "Alice"})
 	s.NoError(err)
 	s.Len(resp.Data, 1)
 	s.Equal("Alice", resp.Data[0].FirstName)
 }
 
+func (s *BookingRepoTestSuite) TestGetAll_SearchWords() {
+	for _, b := range []*domain.Booking{
+		{FirstName: "Abebe", LastName: "Kebede", Address: "Bole"},
+		{FirstName: "Kebede", LastName: "Abera", Address: "Abebech street"},
+		{FirstName: "Sara", LastName: "Tesfaye", Address: "Piassa"},
+		{FirstName: "ሐይሌ", LastName: "ገብሬ", Address: "አዲስ አበባ"},
+	} {
+		_, err := s.bookingRepo.Create(context.Background(), b)
+		s.NoError(err)
+	}
+	search := func(q string) []string {
+		resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Query: q})
+		s.NoError(err)
+		var names []string
+		for _, b := range resp.Data {
+			names = append(names, b.FirstName)
+		}
+		return names
+	}
+	// Every word has to match, in whichever column, in any case
+	s.ElementsMatch([]string{"Abebe"}, search("kebede bole"))
+	s.ElementsMatch([]string{"Abebe", "Kebede"}, search("ABEB"))
+	s.Empty(search("abebe piassa"))
+	s.ElementsMatch([]string{"ሐይሌ"}, search("ገብ"))
+	s.ElementsMatch([]string{"Sara"}, search("  sara  "))
+	if !database.IsPostgres(s.db) {
+		return
+	}
+	// A match on the name ranks above one on the address
+	s.Equal([]string{"Abebe", "Kebede"}, search("abeb"))
+	// Letters that sound the same match each other
+	s.ElementsMatch([]string{"ሐይሌ"}, search("ሀይሌ"))
+	// and so do misspellings
+	s.ElementsMatch([]string{"Sara"}, search("sarah tesfaye"))
+}
+
 func (s *BookingRepoTestSuite) TestUpdate() {
 	b := &domain.Booking{
 		FirstName:   "Hundera Awoke",
@@ -164,7 +203,7 @@ func (s *BookingRepoTestSuite) TestUpdate() {
 		HrPerDay:    2,
 		Assigned:    false,
 	}
-	created, err := s.bookingRepo.Create(b)
+	created, err := s.bookingRepo.Create(context.Background(), b)
 	s.NoError(err)
 	updated := &domain.Booking{
 		FirstName:   "Updated Name",
@@ -176,7 +215,7 @@ func (s *BookingRepoTestSuite) TestUpdate() {
 		HrPerDay:    4,
 		Assigned:    true,
 	}
-	result, err := s.bookingRepo.Update(created.ID, updated)
+	result, err := s.bookingRepo.Update(context.Background(), created.ID, updated)
 	s.NoError(err)
 	s.NotNil(result)
 	s.Equal(result.FirstName, updated.FirstName)
@@ -187,6 +226,13 @@ func (s *BookingRepoTestSuite) TestUpdate() {
 	s.Equal(result.DayPerWeek, updated.DayPerWeek)
 	s.Equal(result.HrPerDay, updated.HrPerDay)
 	s.Equal(result.Assigned, updated.Assigned)
+	s.NotNil(result.AssignedAt)
+
+	// Unassigning clears the time
+	updated.Assigned = false
+	result, err = s.bookingRepo.Update(context.Background(), created.ID, updated)
+	s.NoError(err)
+	s.Nil(result.AssignedAt)
 }
 
 func (s *BookingRepoTestSuite) TestDelete() {
@@ -200,41 +246,144 @@ func (s *BookingRepoTestSuite) TestDelete() {
 		HrPerDay:    2,
 		Assigned:    false,
 	}
-	_, err := s.bookingRepo.Create(b)
+	created, err := s.bookingRepo.Create(context.Background(), b)
 	s.NoError(err)
-	err = s.bookingRepo.Delete(1)
+	err = s.bookingRepo.Delete(context.Background(), created.ID)
 	s.NoError(err)
-	deleted, err := s.bookingRepo.GetByID(1)
+	deleted, err := s.bookingRepo.GetByID(context.Background(), created.ID)
 	s.Error(err)
 	s.Nil(deleted)
+
+	// It went to the trash, where it no longer holds its phone number
+	trash, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Deleted: true})
+	s.NoError(err)
+	s.Len(trash.Data, 1)
+	same, err := s.bookingRepo.GetByPhoneNumber(context.Background(), "+251987654321")
+	s.NoError(err)
+	s.Empty(same)
 }
 func (s *BookingRepoTestSuite) TestGetAll_EdgeCases() {
 	// No bookings
-	resp, err := s.bookingRepo.GetAll(&domain.BookingFilter{Gender: "Nonexistent"})
+	resp, err := s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Gender: "Nonexistent"})
 	s.NoError(err)
 	s.Len(resp.Data, 0)
 
 	// Large grade range
 	for i := 1; i <= 20; i++ {
 		b := &domain.Booking{FirstName: "User", Grade: i}
-		_, err := s.bookingRepo.Create(b)
+		_, err := s.bookingRepo.Create(context.Background(), b)
 		s.NoError(err)
 	}
-	resp, err = s.bookingRepo.GetAll(&domain.BookingFilter{MinGrade: 100, MaxGrade: 200})
+	resp, err = s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{MinGrade: 100, MaxGrade: 200})
 	s.NoError(err)
 	s.Len(resp.Data, 0)
 
 	// Assigned true/false
 	b1 := &domain.Booking{FirstName: "AssignedTrue", Assigned: true}
 	b2 := &domain.Booking{FirstName: "AssignedFalse", Assigned: false}
-	_, err = s.bookingRepo.Create(b1)
+	_, err = s.bookingRepo.Create(context.Background(), b1)
 	s.NoError(err)
-	_, err = s.bookingRepo.Create(b2)
+	_, err = s.bookingRepo.Create(context.Background(), b2)
 	s.NoError(err)
-	resp, err = s.bookingRepo.GetAll(&domain.BookingFilter{Assigned: true})
+	resp, err = s.bookingRepo.GetAll(context.Background(), &domain.BookingFilter{Assigned: true})
 	s.NoError(err)
 	s.True(len(resp.Data) > 0)
 	for _, b := range resp.Data {
 		s.True(b.Assigned)
 	}
 }
+
+func (s *BookingRepoTestSuite) TestGetByPhoneNumber() {
+	s.bookingRepo.Create(context.Background(), &domain.Booking{FirstName: "A", PhoneNumber: "+251911234567"})
+	s.bookingRepo.Create(context.Background(), &domain.Booking{FirstName: "B", PhoneNumber: "+251911234567", Assigned: true})
+	s.bookingRepo.Create(context.Background(), &domain.Booking{FirstName: "C", PhoneNumber: "+251922222222"})
+	found, err := s.bookingRepo.GetByPhoneNumber(context.Background(), "+251911234567")
+	s.NoError(err)
+	s.Len(found, 2)
+	s.Equal("A", found[0].FirstName)
+}
+
+func (s *BookingRepoTestSuite) TestEach() {
+	for i := 0; i < eachBatchSize+2; i++ {
+		s.NoError(s.db.Create(&domain.Booking{FirstName: "A", Grade: i % 12}).Error)
+	}
+	s.NoError(s.db.Create(&domain.Booking{FirstName: "Held", Grade: 5, Quarantined: true}).Error)
+
+	var ids []uint
+	err := s.bookingRepo.Each(context.Background(), &domain.BookingFilter{MinGrade: 5, MaxGrade: 5, Limit: 1}, func(b *domain.Booking) error {
+		s.Equal(5, b.Grade)
+		s.False(b.Quarantined)
+		ids = append(ids, b.ID)
+		return nil
+	})
+	s.NoError(err)
+	// Paging is ignored and rows come in ID order, across batches
+	s.Len(ids, 42)
+	s.IsIncreasing(ids)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.bookingRepo.Each(context.Background(), nil, func(*domain.Booking) error {
+		calls++
+		return stop
+	})
+	s.ErrorIs(err, stop)
+	s.Equal(1, calls)
+}
+
+func (s *BookingRepoTestSuite) TestUpdateInBulk() {
+	ctx := context.Background()
+	a := &domain.Booking{FirstName: "A", Grade: 9}
+	b := &domain.Booking{FirstName: "B", Grade: 9}
+	c := &domain.Booking{FirstName: "C", Grade: 3}
+	for _, booking := range []*domain.Booking{a, b, c} {
+		s.Require().NoError(s.db.Create(booking).Error)
+	}
+	assign := func(b *domain.Booking) (bool, error) {
+		if b.FirstName == "B" {
+			return false, errors.New("not B")
+		}
+		b.Assigned = true
+		return true, nil
+	}
+
+	// Results follow the IDs asked for, missing and failed ones included
+	result, err := s.bookingRepo.Bulk(ctx, []uint{c.ID, 999, b.ID, c.ID}, nil, assign)
+	s.Require().NoError(err)
+	s.Equal(2, result.Matched)
+	// The updated booking is kept as it was and as it is for the audit trail
+	s.False(result.Items[0].Before.(*domain.Booking).Assigned)
+	s.True(result.Items[0].After.(*domain.Booking).Assigned)
+	result.Items[0].Before, result.Items[0].After = nil, nil
+	s.Equal([]domain.BulkItem{
+		{ID: c.ID, Status: domain.BulkItemUpdated},
+		{ID: 999, Status: domain.BulkItemFailed, Error: domain.ErrNotFound.Error()},
+		{ID: b.ID, Status: domain.BulkItemFailed, Error: "not B"},
+	}, result.Items)
+	got, _ := s.bookingRepo.GetByID(ctx, c.ID)
+	s.True(got.Assigned)
+	got, _ = s.bookingRepo.GetByID(ctx, b.ID)
+	s.False(got.Assigned)
+
+	// Deleted bookings drop out of the list and into the trash
+	result, err = s.bookingRepo.Bulk(ctx, nil, &domain.BookingFilter{MinGrade: 9}, func(b *domain.Booking) (bool, error) {
+		b.DeletedAt = &b.CreatedAt
+		return true, nil
+	})
+	s.Require().NoError(err)
+	s.Equal(2, result.Updated)
+	list, err := s.bookingRepo.GetAll(ctx, &domain.BookingFilter{})
+	s.NoError(err)
+	s.Equal(1, list.Pagination.Total)
+	trash, err := s.bookingRepo.GetAll(ctx, &domain.BookingFilter{Deleted: true})
+	s.NoError(err)
+	s.Equal(2, trash.Pagination.Total)
+
+	more := make([]*domain.Booking, domain.MaxBulkItems)
+	for i := range more {
+		more[i] = &domain.Booking{FirstName: "More"}
+	}
+	s.Require().NoError(s.bookingRepo.CreateMany(ctx, more, nil))
+	_, err = s.bookingRepo.Bulk(ctx, nil, &domain.BookingFilter{}, assign)
+	s.ErrorIs(err, domain.ErrTooManyItems)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bulk locks the rows query selects, narrowed to ids when there are any,
+// calls fn with each and saves the ones it changed, all inside the
+// transaction tx. Results follow the order of ids, or else of the rows,
+// and IDs matching no row are reported as not found.
+func bulk[T any](tx, query *gorm.DB, ids []uint, fn domain.BulkFunc[T], id func(*T) uint) (*domain.BulkResult, error) {
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	var rows []T
+	err := query.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Order("id").Limit(domain.MaxBulkItems + 1).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > domain.MaxBulkItems {
+		return nil, domain.ErrTooManyItems
+	}
+	found := make(map[uint]*T, len(rows))
+	for i := range rows {
+		found[id(&rows[i])] = &rows[i]
+	}
+	if len(ids) == 0 {
+		for i := range rows {
+			ids = append(ids, id(&rows[i]))
+		}
+	}
+	result := &domain.BulkResult{Matched: len(rows)}
+	seen := make(map[uint]bool, len(ids))
+	for _, n := range ids {
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		row, ok := found[n]
+		if !ok {
+			result.Add(n, false, domain.ErrNotFound)
+			continue
+		}
+		before := *row
+		changed, err := fn(row)
+		if err == nil && changed {
+			if err := tx.Save(row).Error; err != nil {
+				return nil, err
+			}
+		}
+		result.AddChange(n, changed, err, &before, row)
+	}
+	return result, nil
+}
+
+// trashed keeps only the rows in the trash when deleted is set, and leaves
+// them out otherwise.
+func trashed(query *gorm.DB, deleted bool) *gorm.DB {
+	if deleted {
+		return query.Where("deleted_at IS NOT NULL")
+	}
+	return query.Where("deleted_at IS NULL")
+}
+
+// lockTable keeps other transactions from writing to table until tx ends,
+// so what tx checked before inserting still holds. SQLite allows only one
+// writer anyway.
+func lockTable(tx *gorm.DB, table string) error {
+	if !database.IsPostgres(tx) {
+		return nil
+	}
+	return tx.Exec("LOCK TABLE " + table + " IN SHARE ROW EXCLUSIVE MODE").Error
+}
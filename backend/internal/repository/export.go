@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) domain.ExportRepository {
+	return &exportRepository{db: db}
+}
+
+func (r *exportRepository) Create(ctx context.Context, e *domain.Export) error {
+	return r.db.WithContext(ctx).Create(e).Error
+}
+
+func (r *exportRepository) Update(ctx context.Context, e *domain.Export) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+func (r *exportRepository) GetByID(ctx context.Context, id uint) (*domain.Export, error) {
+	var e domain.Export
+	if err := r.db.WithContext(ctx).First(&e, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
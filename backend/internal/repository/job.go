@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) domain.JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *domain.Job) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *jobRepository) Update(ctx context.Context, job *domain.Job) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id uint) (*domain.Job, error) {
+	var job domain.Job
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) filter(ctx context.Context, f *domain.JobFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.Job{})
+	if f == nil {
+		return query
+	}
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.Type != "" {
+		query = query.Where("type = ?", f.Type)
+	}
+	return query
+}
+
+func (r *jobRepository) GetAll(ctx context.Context, f *domain.JobFilter) (*domain.MultipleJobs, error) {
+	var total int64
+	if err := r.filter(ctx, f).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	limit := 20
+	page := 1
+	if f != nil {
+		if f.Limit > 0 {
+			limit = f.Limit
+		}
+		if f.Page > 0 {
+			page = f.Page
+		}
+	}
+	offset := (page - 1) * limit
+	jobs := []domain.Job{}
+	if err := r.filter(ctx, f).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return &domain.MultipleJobs{
+		Data: jobs,
+		Pagination: domain.Pagination{
+			Page:         page,
+			Limit:        limit,
+			Offset:       offset,
+			Total:        int(total),
+			PreviousPage: page - 1,
+			NextPage:     page + 1,
+		},
+	}, nil
+}
+
+// Claim selects the due rows with FOR UPDATE SKIP LOCKED, so concurrent
+// workers each get different jobs without blocking on one another. SQLite
+// has no row locks and serializes the transaction instead.
+func (r *jobRepository) Claim(ctx context.Context, worker string, now time.Time, limit int) ([]domain.Job, error) {
+	var jobs []domain.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND run_at <= ?", domain.JobStatusPending, now).
+			Order("run_at, id").Limit(limit).Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+		ids := make([]uint, len(jobs))
+		for i := range jobs {
+			ids[i] = jobs[i].ID
+			jobs[i].Status = domain.JobStatusRunning
+			jobs[i].Attempts++
+			jobs[i].LockedBy = worker
+			jobs[i].LockedAt = &now
+		}
+		return tx.Model(&domain.Job{}).Where("id IN ?", ids).Updates(map[string]any{
+			"status":    domain.JobStatusRunning,
+			"attempts":  gorm.Expr("attempts + 1"),
+			"locked_by": worker,
+			"locked_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Requeue(ctx context.Context, id uint, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Job{}).
+		Where("id = ? AND status IN ?", id, []string{domain.JobStatusPending, domain.JobStatusDead}).
+		Updates(map[string]any{
+			"attempts":    gorm.Expr("CASE WHEN status = ? THEN 0 ELSE attempts END", domain.JobStatusDead),
+			"finished_at": nil,
+			"status":      domain.JobStatusPending,
+			"run_at":      now,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *jobRepository) Finish(ctx context.Context, job *domain.Job, worker string) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.ID, domain.JobStatusRunning, worker).
+		Updates(map[string]any{
+			"status":      job.Status,
+			"last_error":  job.LastError,
+			"run_at":      job.RunAt,
+			"finished_at": job.FinishedAt,
+			"locked_by":   "",
+			"locked_at":   nil,
+		})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *jobRepository) Release(ctx context.Context, staleBefore time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Model(&domain.Job{}).
+		Where("status = ? AND locked_at < ?", domain.JobStatusRunning, staleBefore).
+		Updates(map[string]any{
+			"status":    domain.JobStatusPending,
+			"locked_by": "",
+			"locked_at": nil,
+		})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type JobTestSuite struct {
+	suite.Suite
+	jobRepo domain.JobRepository
+	db      *gorm.DB
+}
+
+func TestJobRepository(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
+
+func (suite *JobTestSuite) SetupSuite() {
+	db := dbtest.Open()
+	if db == nil {
+		suite.T().Fatal("Failed to initialize database connection")
+	}
+	suite.db = db
+	suite.Require().NoError(db.AutoMigrate(&domain.Job{}))
+}
+
+func (suite *JobTestSuite) SetupTest() {
+	suite.jobRepo = NewJobRepository(suite.db)
+	suite.db.Exec("DELETE FROM jobs")
+}
+
+func (suite *JobTestSuite) create(jobType string, runAt time.Time) *domain.Job {
+	job := &domain.Job{Type: jobType, Payload: "{}", Status: domain.JobStatusPending, RunAt: runAt, MaxAttempts: 3}
+	suite.NoError(suite.jobRepo.Create(context.Background(), job))
+	return job
+}
+
+func (suite *JobTestSuite) TestClaim() {
+	ctx := context.Background()
+	now := time.Now()
+	first := suite.create("a", now.Add(-2*time.Minute))
+	second := suite.create("b", now.Add(-time.Minute))
+	suite.create("c", now.Add(time.Hour))
+
+	claimed, err := suite.jobRepo.Claim(ctx, "w1", now, 1)
+	suite.NoError(err)
+	suite.Len(claimed, 1)
+	suite.Equal(first.ID, claimed[0].ID)
+	suite.Equal(domain.JobStatusRunning, claimed[0].Status)
+	suite.Equal(1, claimed[0].Attempts)
+
+	// Running and future jobs are left alone
+	claimed, err = suite.jobRepo.Claim(ctx, "w2", now, 10)
+	suite.NoError(err)
+	suite.Len(claimed, 1)
+	suite.Equal(second.ID, claimed[0].ID)
+	claimed, err = suite.jobRepo.Claim(ctx, "w3", now, 10)
+	suite.NoError(err)
+	suite.Empty(claimed)
+
+	stored, err := suite.jobRepo.GetByID(ctx, second.ID)
+	suite.NoError(err)
+	suite.Equal("w2", stored.LockedBy)
+	suite.Equal(1, stored.Attempts)
+}
+
+func (suite *JobTestSuite) TestRelease() {
+	ctx := context.Background()
+	now := time.Now()
+	job := suite.create("a", now.Add(-time.Hour))
+	_, err := suite.jobRepo.Claim(ctx, "w1", now.Add(-30*time.Minute), 1)
+	suite.NoError(err)
+	fresh := suite.create("b", now.Add(-time.Minute))
+	_, err = suite.jobRepo.Claim(ctx, "w2", now, 1)
+	suite.NoError(err)
+
+	released, err := suite.jobRepo.Release(ctx, now.Add(-15*time.Minute))
+	suite.NoError(err)
+	suite.EqualValues(1, released)
+
+	stored, err := suite.jobRepo.GetByID(ctx, job.ID)
+	suite.NoError(err)
+	suite.Equal(domain.JobStatusPending, stored.Status)
+	suite.Empty(stored.LockedBy)
+	stored, err = suite.jobRepo.GetByID(ctx, fresh.ID)
+	suite.NoError(err)
+	suite.Equal(domain.JobStatusRunning, stored.Status)
+}
+
+func (suite *JobTestSuite) TestGetAllAndNotFound() {
+	ctx := context.Background()
+	suite.create("files.remove", time.Now())
+	dead := suite.create("reports.build", time.Now())
+	dead.Status = domain.JobStatusDead
+	suite.NoError(suite.jobRepo.Update(ctx, dead))
+
+	resp, err := suite.jobRepo.GetAll(ctx, &domain.JobFilter{Status: domain.JobStatusDead})
+	suite.NoError(err)
+	suite.Equal(1, resp.Pagination.Total)
+	suite.Equal("reports.build", resp.Data[0].Type)
+
+	resp, err = suite.jobRepo.GetAll(ctx, &domain.JobFilter{Type: "files.remove"})
+	suite.NoError(err)
+	suite.Len(resp.Data, 1)
+
+	_, err = suite.jobRepo.GetByID(ctx, dead.ID+100)
+	suite.ErrorIs(err, domain.ErrNotFound)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
 
 	"gorm.io/gorm"
@@ -14,30 +15,30 @@ func NewServiceRepository(db *gorm.DB) domain.OtherServiceRepository {
 	return &serviceRepository{db: db}
 }
 
-func (r *serviceRepository) Create(service *domain.OtherService) (*domain.OtherService, error) {
-	tx := r.db.Model(&domain.OtherService{}).Create(service)
+func (r *serviceRepository) Create(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.OtherService{}).Create(service)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 	return service, nil
 }
-func (r *serviceRepository) GetByID(id uint, languageCodes []string) (*domain.OtherService, error) {
+func (r *serviceRepository) GetByID(ctx context.Context, id uint, languageCodes []string) (*domain.OtherService, error) {
 	var service *domain.OtherService
 	var tx *gorm.DB
 	if len(languageCodes) > 0 {
-		tx = r.db.Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations", "language_code IN ?", languageCodes).First(&service)
+		tx = r.db.WithContext(ctx).Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations", "language_code IN ?", languageCodes).First(&service)
 	} else {
-		tx = r.db.Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations").First(&service)
+		tx = r.db.WithContext(ctx).Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations").First(&service)
 	}
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 	return service, nil
 }
-func (r *serviceRepository) GetAll(filter *domain.ServiceFilter) (*domain.MultipleOtherServices, error) {
+func (r *serviceRepository) GetAll(ctx context.Context, filter *domain.ServiceFilter) (*domain.MultipleOtherServices, error) {
 	var services []domain.OtherService
 	var total int64
-	query := r.db.Model(&domain.OtherService{}).Preload("Translations")
+	query := r.db.WithContext(ctx).Model(&domain.OtherService{}).Preload("Translations")
 	translationFields := map[string]bool{
 		"name":        true,
 		"description": true,
@@ -89,16 +90,16 @@ func (r *serviceRepository) GetAll(filter *domain.ServiceFilter) (*domain.Multip
 		Pagination:        domain.Pagination{Total: int(total), Page: filter.Page, Limit: filter.Limit},
 	}, nil
 }
-func (r *serviceRepository) Update(service *domain.OtherService) (*domain.OtherService, error) {
-	tx := r.db.Model(&domain.OtherService{}).Where("id = ?", service.ID).Preload("Translations").Updates(service)
+func (r *serviceRepository) Update(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.OtherService{}).Where("id = ?", service.ID).Preload("Translations").Updates(service)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 	return service, nil
 }
-func (r *serviceRepository) Delete(id uint) error {
-	return r.db.Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations").Delete(&domain.OtherService{}).Error
+func (r *serviceRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&domain.OtherService{}).Where("id = ?", id).Preload("Translations").Delete(&domain.OtherService{}).Error
 }
-func (r *serviceRepository) AddTranslation(translation *domain.OtherServiceTranslation) error {
-	return r.db.Model(&domain.OtherServiceTranslation{}).Create(translation).Error
+func (r *serviceRepository) AddTranslation(ctx context.Context, translation *domain.OtherServiceTranslation) error {
+	return r.db.WithContext(ctx).Model(&domain.OtherServiceTranslation{}).Create(translation).Error
 }
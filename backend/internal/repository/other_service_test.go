@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"hiyab-tutor/internal/database"
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestServiceRepository(t *testing.T) {
 }
 func (suite *ServiceRepositoryTestSuite) SetupSuite() {
 	// Create DB connection once for the entire suite
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		suite.T().Fatal("Failed to initialize database connection")
 	}
@@ -51,7 +52,7 @@ func (suite *ServiceRepositoryTestSuite) TestCreateService() {
 		},
 	}
 
-	createdService, err := suite.serviceRepo.Create(service)
+	createdService, err := suite.serviceRepo.Create(context.Background(), service)
 	suite.NoError(err)
 	suite.NotNil(createdService)
 	suite.Equal(service.WebsiteURL, createdService.WebsiteURL)
@@ -73,10 +74,10 @@ func (suite *ServiceRepositoryTestSuite) TestGetByID() {
 		},
 	}
 
-	createdService, err := suite.serviceRepo.Create(service)
+	createdService, err := suite.serviceRepo.Create(context.Background(), service)
 	suite.NoError(err)
 
-	fetchedService, err := suite.serviceRepo.GetByID(createdService.ID, nil)
+	fetchedService, err := suite.serviceRepo.GetByID(context.Background(), createdService.ID, nil)
 	suite.NoError(err)
 	suite.NotNil(fetchedService)
 	suite.Equal(createdService.ID, fetchedService.ID)
@@ -114,7 +115,7 @@ func (suite *ServiceRepositoryTestSuite) TestGetAllServices() {
 		}},
 	}
 	for _, svc := range services {
-		_, err := suite.serviceRepo.Create(svc)
+		_, err := suite.serviceRepo.Create(context.Background(), svc)
 		suite.NoError(err)
 	}
 	filter := &domain.ServiceFilter{
@@ -124,7 +125,7 @@ func (suite *ServiceRepositoryTestSuite) TestGetAllServices() {
 		SortOrder: "asc",
 	}
 
-	response, err := suite.serviceRepo.GetAll(filter)
+	response, err := suite.serviceRepo.GetAll(context.Background(), filter)
 	suite.NoError(err)
 	suite.NotNil(response)
 	suite.Greater(len(response.OtherServicesList), 0)
@@ -153,12 +154,12 @@ func (suite *ServiceRepositoryTestSuite) TestUpdateService() {
 		},
 	}
 
-	createdService, err := suite.serviceRepo.Create(service)
+	createdService, err := suite.serviceRepo.Create(context.Background(), service)
 	suite.NoError(err)
 
 	// Update the service's name
 	createdService.Translations[0].Name = "Updated Service"
-	updatedService, err := suite.serviceRepo.Update(createdService)
+	updatedService, err := suite.serviceRepo.Update(context.Background(), createdService)
 	suite.NoError(err)
 	suite.NotNil(updatedService)
 	suite.Equal("Updated Service", updatedService.Translations[0].Name)
@@ -174,13 +175,13 @@ func (suite *ServiceRepositoryTestSuite) TestDeleteService() {
 		},
 	}
 
-	createdService, err := suite.serviceRepo.Create(service)
+	createdService, err := suite.serviceRepo.Create(context.Background(), service)
 	suite.NoError(err)
 
-	err = suite.serviceRepo.Delete(createdService.ID)
+	err = suite.serviceRepo.Delete(context.Background(), createdService.ID)
 	suite.NoError(err)
 
-	deletedService, err := suite.serviceRepo.GetByID(createdService.ID, nil)
+	deletedService, err := suite.serviceRepo.GetByID(context.Background(), createdService.ID, nil)
 	suite.Error(err)
 	suite.Nil(deletedService)
 }
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"hiyab-tutor/internal/database"
 	"hiyab-tutor/internal/domain"
 
 	"gorm.io/gorm"
@@ -14,16 +16,16 @@ func NewPartnerRepository(db *gorm.DB) domain.PartnerRepository {
 	return &partnerRepository{db: db}
 }
 
-func (r *partnerRepository) Create(partner *domain.Partner) (*domain.Partner, error) {
-	tx := r.db.Model(&domain.Partner{}).Create(partner)
+func (r *partnerRepository) Create(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.Partner{}).Create(partner)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 	return partner, nil
 }
-func (r *partnerRepository) GetByID(id uint) (*domain.Partner, error) {
+func (r *partnerRepository) GetByID(ctx context.Context, id uint) (*domain.Partner, error) {
 	var partner *domain.Partner
-	tx := r.db.Model(&domain.Partner{}).Where("id = ?", id).First(&partner)
+	tx := r.db.WithContext(ctx).Model(&domain.Partner{}).Where("id = ?", id).First(&partner)
 	if tx.Error != nil {
 		if tx.Error == gorm.ErrRecordNotFound {
 			return nil, nil // Return nil if not found
@@ -32,7 +34,7 @@ func (r *partnerRepository) GetByID(id uint) (*domain.Partner, error) {
 	}
 	return partner, nil
 }
-func (r *partnerRepository) GetAll(filter *domain.PartnerFilter) (*domain.MultiplePartners, error) {
+func (r *partnerRepository) GetAll(ctx context.Context, filter *domain.PartnerFilter) (*domain.MultiplePartners, error) {
 	if filter == nil {
 		filter = &domain.PartnerFilter{
 			Page:   1,
@@ -45,9 +47,9 @@ func (r *partnerRepository) GetAll(filter *domain.PartnerFilter) (*domain.Multip
 		}
 		filter.Offset = (filter.Page - 1) * filter.Limit
 	}
-	query := r.db.Model(&domain.Partner{}).Offset(filter.Offset).Limit(filter.Limit)
+	query := r.db.WithContext(ctx).Model(&domain.Partner{}).Offset(filter.Offset).Limit(filter.Limit)
 	if filter.Search != "" {
-		query = query.Where("name LIKE ?", "%"+filter.Search+"%")
+		query = query.Where(database.ContainsAny(r.db, filter.Search, "name"))
 	}
 	if filter.SortBy != "" {
 		// whitelist allowed sort fields to avoid SQL injection
@@ -74,8 +76,8 @@ func (r *partnerRepository) GetAll(filter *domain.PartnerFilter) (*domain.Multip
 
 	return &domain.MultiplePartners{Partners: partners, Pagination: domain.Pagination{Page: filter.Page, Limit: filter.Limit, Total: int(total)}}, nil
 }
-func (r *partnerRepository) Update(partner *domain.Partner) (*domain.Partner, error) {
-	tx := r.db.Model(&domain.Partner{}).Where("id = ?", partner.ID).Updates(partner)
+func (r *partnerRepository) Update(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.Partner{}).Where("id = ?", partner.ID).Updates(partner)
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -84,8 +86,8 @@ func (r *partnerRepository) Update(partner *domain.Partner) (*domain.Partner, er
 	}
 	return partner, nil
 }
-func (r *partnerRepository) Delete(id uint) error {
-	tx := r.db.Model(&domain.Partner{}).Where("id = ?", id).Delete(&domain.Partner{})
+func (r *partnerRepository) Delete(ctx context.Context, id uint) error {
+	tx := r.db.WithContext(ctx).Model(&domain.Partner{}).Where("id = ?", id).Delete(&domain.Partner{})
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"hiyab-tutor/internal/database"
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestPartnersRepository(t *testing.T) {
 }
 func (suite *PartnerTestSuite) SetupSuite() {
 	// Create DB connection once for the entire suite
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		suite.T().Fatal("Failed to initialize database connection")
 	}
@@ -47,7 +48,7 @@ func (suite *PartnerTestSuite) TestCreatePartner() {
 		WebsiteURL: "http://example.com",
 	}
 
-	createdPartner, err := suite.partnerRepo.Create(partner)
+	createdPartner, err := suite.partnerRepo.Create(context.Background(), partner)
 	suite.NoError(err)
 	suite.NotNil(createdPartner)
 	suite.Equal(partner.Name, createdPartner.Name)
@@ -61,10 +62,10 @@ func (suite *PartnerTestSuite) TestGetByID() {
 		WebsiteURL: "http://example.com",
 	}
 
-	createdPartner, err := suite.partnerRepo.Create(partner)
+	createdPartner, err := suite.partnerRepo.Create(context.Background(), partner)
 	suite.NoError(err)
 
-	retrievedPartner, err := suite.partnerRepo.GetByID(createdPartner.ID)
+	retrievedPartner, err := suite.partnerRepo.GetByID(context.Background(), createdPartner.ID)
 	suite.NoError(err)
 	suite.NotNil(retrievedPartner)
 	suite.Equal(createdPartner.ID, retrievedPartner.ID)
@@ -79,12 +80,33 @@ func (suite *PartnerTestSuite) TestGetAll() {
 		Offset: 0,
 	}
 
-	partners, err := suite.partnerRepo.GetAll(filter)
+	partners, err := suite.partnerRepo.GetAll(context.Background(), filter)
 	suite.NoError(err)
 	suite.NotNil(partners)
 	suite.Empty(partners.Partners) // Initially, no partners should be present
 }
 
+func (suite *PartnerTestSuite) TestGetAll_SearchIgnoresCase() {
+	for _, name := range []string{"Addis Tutors", "ADDIS 100% Learning", "Bole Academy"} {
+		_, err := suite.partnerRepo.Create(context.Background(), &domain.Partner{Name: name})
+		suite.NoError(err)
+	}
+	search := func(q string) []string {
+		partners, err := suite.partnerRepo.GetAll(context.Background(), &domain.PartnerFilter{Page: 1, Limit: 10, Search: q})
+		suite.NoError(err)
+		var names []string
+		for _, p := range partners.Partners {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	suite.ElementsMatch([]string{"Addis Tutors", "ADDIS 100% Learning"}, search("addis"))
+	// LIKE wildcards in the search are matched literally
+	suite.Equal([]string{"ADDIS 100% Learning"}, search("0%"))
+	suite.Empty(search("_cademy"))
+}
+
 func (suite *PartnerTestSuite) TestUpdatePartner() {
 	partner := &domain.Partner{
 		Name:       "Test Partner",
@@ -92,12 +114,12 @@ func (suite *PartnerTestSuite) TestUpdatePartner() {
 		WebsiteURL: "http://example.com",
 	}
 
-	createdPartner, err := suite.partnerRepo.Create(partner)
+	createdPartner, err := suite.partnerRepo.Create(context.Background(), partner)
 	suite.NoError(err)
 
 	// Update the partner's name
 	createdPartner.Name = "Updated Partner"
-	updatedPartner, err := suite.partnerRepo.Update(createdPartner)
+	updatedPartner, err := suite.partnerRepo.Update(context.Background(), createdPartner)
 	suite.NoError(err)
 	suite.NotNil(updatedPartner)
 	suite.Equal("Updated Partner", updatedPartner.Name)
@@ -109,14 +131,14 @@ func (suite *PartnerTestSuite) TestDeletePartner() {
 		WebsiteURL: "http://example.com",
 	}
 
-	createdPartner, err := suite.partnerRepo.Create(partner)
+	createdPartner, err := suite.partnerRepo.Create(context.Background(), partner)
 	suite.NoError(err)
 
-	err = suite.partnerRepo.Delete(createdPartner.ID)
+	err = suite.partnerRepo.Delete(context.Background(), createdPartner.ID)
 	suite.NoError(err)
 
 	// Try to retrieve the deleted partner
-	retrievedPartner, err := suite.partnerRepo.GetByID(createdPartner.ID)
+	retrievedPartner, err := suite.partnerRepo.GetByID(context.Background(), createdPartner.ID)
 	suite.NoError(err)
 	suite.Nil(retrievedPartner)
 }
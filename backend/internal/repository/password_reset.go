@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) domain.PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *passwordResetRepository) GetByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *passwordResetRepository) Claim(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *passwordResetRepository) InvalidateForAdmin(ctx context.Context, adminID uint) error {
+	return r.db.WithContext(ctx).Model(&domain.PasswordResetToken{}).
+		Where("admin_id = ? AND used_at IS NULL", adminID).
+		Update("used_at", time.Now()).Error
+}
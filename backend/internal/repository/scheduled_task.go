@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type scheduledTaskRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduledTaskRepository(db *gorm.DB) domain.ScheduledTaskRepository {
+	return &scheduledTaskRepository{db: db}
+}
+
+func (r *scheduledTaskRepository) GetAll(ctx context.Context) ([]domain.ScheduledTask, error) {
+	tasks := []domain.ScheduledTask{}
+	err := r.db.WithContext(ctx).Order("name").Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *scheduledTaskRepository) GetByName(ctx context.Context, name string) (*domain.ScheduledTask, error) {
+	var task domain.ScheduledTask
+	if err := r.db.WithContext(ctx).First(&task, "name = ?", name).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *scheduledTaskRepository) Save(ctx context.Context, task *domain.ScheduledTask) error {
+	return r.db.WithContext(ctx).Save(task).Error
+}
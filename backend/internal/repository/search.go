@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/database"
+
+	"gorm.io/gorm"
+)
+
+// MigrateSearch adds the full-text search columns and indexes to the
+// bookings and tutors tables, which have to exist already. The
+// repositories built afterwards pick them up.
+func MigrateSearch(ctx context.Context, db *gorm.DB) {
+	database.MigrateSearch(ctx, db, "bookings", bookingSearchColumns...)
+	database.MigrateSearch(ctx, db, "tutors", tutorSearchColumns...)
+}
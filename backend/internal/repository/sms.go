@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type smsRepository struct {
+	db *gorm.DB
+}
+
+func NewSMSRepository(db *gorm.DB) domain.SMSRepository {
+	return &smsRepository{db: db}
+}
+
+func (r *smsRepository) Create(ctx context.Context, msg *domain.SMSMessage) error {
+	return r.db.WithContext(ctx).Create(msg).Error
+}
+
+func (r *smsRepository) Update(ctx context.Context, msg *domain.SMSMessage) error {
+	return r.db.WithContext(ctx).Save(msg).Error
+}
+
+func (r *smsRepository) GetByID(ctx context.Context, id uint) (*domain.SMSMessage, error) {
+	var msg domain.SMSMessage
+	if err := r.db.WithContext(ctx).First(&msg, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+func (r *smsRepository) filter(ctx context.Context, f *domain.SMSFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.SMSMessage{})
+	if f == nil {
+		return query
+	}
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.To != "" {
+		query = query.Where("\"to\" = ?", f.To)
+	}
+	return query
+}
+
+func (r *smsRepository) GetAll(ctx context.Context, f *domain.SMSFilter) (*domain.MultipleSMSMessages, error) {
+	var total int64
+	if err := r.filter(ctx, f).Count(&total).Error; err != nil {
+		return nil, err
+	}
+	limit := 20
+	page := 1
+	if f != nil {
+		if f.Limit > 0 {
+			limit = f.Limit
+		}
+		if f.Page > 0 {
+			page = f.Page
+		}
+	}
+	offset := (page - 1) * limit
+	messages := []domain.SMSMessage{}
+	if err := r.filter(ctx, f).Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return &domain.MultipleSMSMessages{
+		Data: messages,
+		Pagination: domain.Pagination{
+			Page:         page,
+			Limit:        limit,
+			Offset:       offset,
+			Total:        int(total),
+			PreviousPage: page - 1,
+			NextPage:     page + 1,
+		},
+	}, nil
+}
+
+func (r *smsRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]domain.SMSMessage, error) {
+	var messages []domain.SMSMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND next_attempt_at <= ?", domain.SMSStatusPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+		ids := make([]uint, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+			messages[i].NextAttemptAt = &until
+		}
+		return tx.Model(&domain.SMSMessage{}).Where("id IN ?", ids).Update("next_attempt_at", until).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SMSTestSuite struct {
+	suite.Suite
+	smsRepo domain.SMSRepository
+	db      *gorm.DB
+}
+
+func TestSMSRepository(t *testing.T) {
+	suite.Run(t, new(SMSTestSuite))
+}
+
+func (suite *SMSTestSuite) SetupSuite() {
+	db := dbtest.Open()
+	if db == nil {
+		suite.T().Fatal("Failed to initialize database connection")
+	}
+	suite.db = db
+	suite.Require().NoError(db.AutoMigrate(&domain.SMSMessage{}))
+}
+
+func (suite *SMSTestSuite) SetupTest() {
+	suite.smsRepo = NewSMSRepository(suite.db)
+	suite.db.Exec("DELETE FROM sms_messages")
+}
+
+func (suite *SMSTestSuite) TestCreateAndUpdate() {
+	msg := &domain.SMSMessage{To: "+251911000000", Body: "hello", Status: domain.SMSStatusPending}
+	suite.NoError(suite.smsRepo.Create(context.Background(), msg))
+	suite.NotZero(msg.ID)
+
+	msg.Status = domain.SMSStatusSent
+	msg.Attempts = 1
+	suite.NoError(suite.smsRepo.Update(context.Background(), msg))
+
+	found, err := suite.smsRepo.GetByID(context.Background(), msg.ID)
+	suite.NoError(err)
+	suite.Equal(domain.SMSStatusSent, found.Status)
+	suite.Equal(1, found.Attempts)
+
+	_, err = suite.smsRepo.GetByID(context.Background(), msg.ID+100)
+	suite.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (suite *SMSTestSuite) TestClaimDueAndFilter() {
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+	suite.NoError(suite.smsRepo.Create(context.Background(), &domain.SMSMessage{To: "+251911000001", Status: domain.SMSStatusPending, NextAttemptAt: &past}))
+	suite.NoError(suite.smsRepo.Create(context.Background(), &domain.SMSMessage{To: "+251911000002", Status: domain.SMSStatusPending, NextAttemptAt: &future}))
+	suite.NoError(suite.smsRepo.Create(context.Background(), &domain.SMSMessage{To: "+251911000003", Status: domain.SMSStatusFailed}))
+
+	due, err := suite.smsRepo.ClaimDue(context.Background(), now, future, 10)
+	suite.NoError(err)
+	suite.Len(due, 1)
+	suite.Equal("+251911000001", due[0].To)
+	// Claimed messages aren't due again until the claim runs out
+	due, err = suite.smsRepo.ClaimDue(context.Background(), now, future, 10)
+	suite.NoError(err)
+	suite.Empty(due)
+
+	resp, err := suite.smsRepo.GetAll(context.Background(), &domain.SMSFilter{Status: domain.SMSStatusPending})
+	suite.NoError(err)
+	suite.Equal(2, resp.Pagination.Total)
+
+	resp, err = suite.smsRepo.GetAll(context.Background(), &domain.SMSFilter{To: "+251911000003"})
+	suite.NoError(err)
+	suite.Len(resp.Data, 1)
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"hiyab-tutor/internal/database"
 	"hiyab-tutor/internal/domain"
 
 	"gorm.io/gorm"
@@ -13,21 +15,20 @@ type testimonialRepository struct {
 }
 
 func NewTestimonialRepository(db *gorm.DB) domain.TestimonialRepository {
-	db.AutoMigrate(&domain.Testimonial{})
 	return &testimonialRepository{db: db}
 }
-func (r *testimonialRepository) Create(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+func (r *testimonialRepository) Create(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
 
-	tx := r.db.Model(&domain.Testimonial{}).Create(testimonial)
+	tx := r.db.WithContext(ctx).Model(&domain.Testimonial{}).Create(testimonial)
 	if tx.Error != nil {
 		return nil, domain.ErrCreateFailed
 	}
 	return testimonial, nil
 }
-func (r *testimonialRepository) GetAll(filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
+func (r *testimonialRepository) GetAll(ctx context.Context, filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
 	var testimonials []*domain.Testimonial
 	var total int64
-	query := r.db.Model(&domain.Testimonial{})
+	query := r.db.WithContext(ctx).Model(&domain.Testimonial{})
 	query = query.Joins("LEFT JOIN testimonial_translations ON testimonial_translations.testimonial_id = testimonials.id")
 	if filter == nil {
 		filter = &domain.TestimonialFilter{
@@ -41,8 +42,7 @@ func (r *testimonialRepository) GetAll(filter *domain.TestimonialFilter) (*domai
 		}
 		filter.Offset = (filter.Page - 1) * filter.Limit
 		if filter.Query != "" {
-			q := "%" + filter.Query + "%"
-			query = query.Where("testimonials.name LIKE ? OR testimonials.role LIKE ? OR testimonial_translations.text LIKE ?", q, q, q)
+			query = query.Where(database.ContainsAny(r.db, filter.Query, "testimonials.name", "testimonials.role", "testimonial_translations.text"))
 		}
 		if filter.SortBy != "" {
 			allowedSortFields := map[string]bool{
@@ -87,31 +87,31 @@ func (r *testimonialRepository) GetAll(filter *domain.TestimonialFilter) (*domai
 		},
 	}, nil
 }
-func (r *testimonialRepository) GetByID(id uint, languageCodes []string) (*domain.Testimonial, error) {
+func (r *testimonialRepository) GetByID(ctx context.Context, id uint, languageCodes []string) (*domain.Testimonial, error) {
 	var t domain.Testimonial
 	var tx *gorm.DB
 	if len(languageCodes) > 0 {
-		tx = r.db.Model(&domain.Testimonial{}).Preload("Translations", "language_code IN ?", languageCodes).First(&t, id)
+		tx = r.db.WithContext(ctx).Model(&domain.Testimonial{}).Preload("Translations", "language_code IN ?", languageCodes).First(&t, id)
 	} else {
-		tx = r.db.Model(&domain.Testimonial{}).Preload("Translations").First(&t, id)
+		tx = r.db.WithContext(ctx).Model(&domain.Testimonial{}).Preload("Translations").First(&t, id)
 	}
 	if tx.Error != nil {
 		return nil, domain.ErrNotFound
 	}
 	return &t, nil
 }
-func (r *testimonialRepository) Delete(id uint) error {
+func (r *testimonialRepository) Delete(ctx context.Context, id uint) error {
 	// delete associated translations first
-	if err := r.db.Where("testimonial_id = ?", id).Delete(&domain.TestimonialTranslation{}).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("testimonial_id = ?", id).Delete(&domain.TestimonialTranslation{}).Error; err != nil {
 		return err
 	}
-	if err := r.db.Delete(&domain.Testimonial{}, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Delete(&domain.Testimonial{}, id).Error; err != nil {
 		return err
 	}
 	return nil
 }
-func (r *testimonialRepository) Update(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
-	tx := r.db.Model(&domain.Testimonial{}).Where("id = ?", testimonial.ID).Updates(testimonial)
+func (r *testimonialRepository) Update(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+	tx := r.db.WithContext(ctx).Model(&domain.Testimonial{}).Where("id = ?", testimonial.ID).Updates(testimonial)
 	if tx.Error != nil {
 		return nil, domain.ErrUpdateFailed
 	}
@@ -124,6 +124,6 @@ func (r *testimonialRepository) Update(testimonial *domain.Testimonial) (*domain
 	return testimonial, nil
 }
 
-func (r *testimonialRepository) AddTranslation(translation *domain.TestimonialTranslation) error {
-	return r.db.Model(&domain.TestimonialTranslation{}).Create(translation).Error
+func (r *testimonialRepository) AddTranslation(ctx context.Context, translation *domain.TestimonialTranslation) error {
+	return r.db.WithContext(ctx).Model(&domain.TestimonialTranslation{}).Create(translation).Error
 }
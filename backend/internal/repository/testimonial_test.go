@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"hiyab-tutor/internal/database"
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestTestimonialsRepository(t *testing.T) {
 }
 
 func (suite *TestimonialTestSuite) SetupSuite() {
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		suite.T().Fatal("Failed to initialize database connection")
 	}
@@ -59,7 +60,7 @@ func (suite *TestimonialTestSuite) TestCreateTestimonial() {
 		},
 	}
 
-	createdTestimonial, err := suite.testimonialRepo.Create(testimonial)
+	createdTestimonial, err := suite.testimonialRepo.Create(context.Background(), testimonial)
 	if err != nil {
 		suite.T().Fatalf("Failed to create testimonial: %v", err)
 	}
@@ -90,7 +91,7 @@ func (suite *TestimonialTestSuite) TestGetAllTestimonials() {
 			{LanguageCode: "am", Text: "ይህ አንደኛ መልእክት ነው።"}}, Thumbnail: "http://example.com/thumbnail.jpg"},
 	}
 	for _, t := range testimonials {
-		_, err := suite.testimonialRepo.Create(t)
+		_, err := suite.testimonialRepo.Create(context.Background(), t)
 		suite.Assert().NoError(err)
 	}
 	suite.Run("Get All without filter", func() {
@@ -101,7 +102,7 @@ func (suite *TestimonialTestSuite) TestGetAllTestimonials() {
 			SortBy:    "created_at",
 			SortOrder: "asc",
 		}
-		found, err := suite.testimonialRepo.GetAll(filter)
+		found, err := suite.testimonialRepo.GetAll(context.Background(), filter)
 		if err != nil {
 			suite.T().Fatalf("Failed to get testimonials: %v", err)
 		}
@@ -125,7 +126,7 @@ func (suite *TestimonialTestSuite) TestGetAllTestimonials() {
 			SortBy:        "created_at",
 			SortOrder:     "asc",
 		}
-		found, err := suite.testimonialRepo.GetAll(filter)
+		found, err := suite.testimonialRepo.GetAll(context.Background(), filter)
 		if err != nil {
 			suite.T().Fatalf("Failed to get testimonials: %v", err)
 		}
@@ -149,7 +150,7 @@ func (suite *TestimonialTestSuite) TestGetAllTestimonials() {
 		filter := &domain.TestimonialFilter{
 			Query: "random",
 		}
-		found, err := suite.testimonialRepo.GetAll(filter)
+		found, err := suite.testimonialRepo.GetAll(context.Background(), filter)
 		if err != nil {
 			suite.T().Fatalf("Failed to get testimonials: %v", err)
 		}
@@ -178,12 +179,12 @@ func (suite *TestimonialTestSuite) TestGetTestimonialByID() {
 		Thumbnail: "http://example.com/thumbnail.jpg",
 	}
 
-	createdTestimonial, err := suite.testimonialRepo.Create(testimonial)
+	createdTestimonial, err := suite.testimonialRepo.Create(context.Background(), testimonial)
 	if err != nil {
 		suite.T().Fatalf("Failed to create testimonial: %v", err)
 	}
 
-	foundTestimonial, err := suite.testimonialRepo.GetByID(createdTestimonial.Model.ID, nil)
+	foundTestimonial, err := suite.testimonialRepo.GetByID(context.Background(), createdTestimonial.Model.ID, nil)
 	if err != nil {
 		suite.T().Fatalf("Failed to get testimonial by ID: %v", err)
 	}
@@ -213,17 +214,17 @@ func (suite *TestimonialTestSuite) TestDeleteTestimonial() {
 		Thumbnail: "http://example.com/thumbnail.jpg",
 	}
 
-	createdTestimonial, err := suite.testimonialRepo.Create(testimonial)
+	createdTestimonial, err := suite.testimonialRepo.Create(context.Background(), testimonial)
 	if err != nil {
 		suite.T().Fatalf("Failed to create testimonial: %v", err)
 	}
 	suite.T().Log("Created testimonial with ID:", createdTestimonial.Model.ID)
-	err = suite.testimonialRepo.Delete(createdTestimonial.Model.ID)
+	err = suite.testimonialRepo.Delete(context.Background(), createdTestimonial.Model.ID)
 	if err != nil {
 		suite.T().Fatalf("Failed to delete testimonial: %v", err)
 	}
 
-	foundTestimonial, err := suite.testimonialRepo.GetByID(createdTestimonial.Model.ID, []string{})
+	foundTestimonial, err := suite.testimonialRepo.GetByID(context.Background(), createdTestimonial.Model.ID, []string{})
 	suite.Assert().Error(err, "Expected error when getting deleted testimonial")
 	suite.Assert().Nil(foundTestimonial, "Expected testimonial to be nil after deletion")
 }
@@ -239,13 +240,13 @@ func (suite *TestimonialTestSuite) TestUpdateTestimonial() {
 		Thumbnail: "http://example.com/thumbnail.jpg",
 	}
 
-	createdTestimonial, err := suite.testimonialRepo.Create(testimonial)
+	createdTestimonial, err := suite.testimonialRepo.Create(context.Background(), testimonial)
 	if err != nil {
 		suite.T().Fatalf("Failed to create testimonial: %v", err)
 	}
 
 	createdTestimonial.Translations[0].Text = "Updated testimonial content"
-	updatedTestimonial, err := suite.testimonialRepo.Update(createdTestimonial)
+	updatedTestimonial, err := suite.testimonialRepo.Update(context.Background(), createdTestimonial)
 	if err != nil {
 		suite.T().Fatalf("Failed to update testimonial: %v", err)
 	}
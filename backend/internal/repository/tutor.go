@@ -1,38 +1,68 @@
 package repository
 
 import (
+	"context"
+	"hiyab-tutor/internal/database"
 	"hiyab-tutor/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// tutorSearchColumns are what GetAll's query searches, by weight
+var tutorSearchColumns = []database.SearchColumn{
+	{Name: "first_name", Weight: 'A'},
+	{Name: "last_name", Weight: 'A'},
+	{Name: "email", Weight: 'B'},
+	{Name: "phone_number", Weight: 'B'},
+}
+
 type tutorRepo struct {
-	db *gorm.DB
+	db     *gorm.DB
+	search *database.Search
 }
 
 func NewTutorRepository(db *gorm.DB) domain.TutorRepository {
-	db.AutoMigrate(&domain.Tutor{})
-	return &tutorRepo{db: db}
+	return &tutorRepo{db: db, search: database.NewSearch(db, "tutors", tutorSearchColumns...)}
 }
 
-func (r *tutorRepo) Create(t *domain.Tutor) (*domain.Tutor, error) {
-	if err := r.db.Create(t).Error; err != nil {
+func (r *tutorRepo) Create(ctx context.Context, t *domain.Tutor) (*domain.Tutor, error) {
+	if err := r.db.WithContext(ctx).Create(t).Error; err != nil {
 		return nil, err
 	}
 	return t, nil
 }
 
-func (r *tutorRepo) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
-	var tutors []domain.Tutor
-	var total int64
-	query := r.db.Model(&domain.Tutor{})
+// CreateMany inserts tutors in batches inside one transaction.
+func (r *tutorRepo) CreateMany(ctx context.Context, tutors []*domain.Tutor, check func(domain.TutorRepository, *domain.Tutor) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if check != nil {
+			if err := lockTable(tx, "tutors"); err != nil {
+				return err
+			}
+			repo := &tutorRepo{db: tx, search: r.search}
+			for _, t := range tutors {
+				if err := check(repo, t); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.CreateInBatches(tutors, createBatchSize).Error
+	})
+}
+
+// filter selects the tutors matching filter, without paging or sorting.
+func (r *tutorRepo) filter(ctx context.Context, filter *domain.TutorFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&domain.Tutor{})
 	if filter != nil {
 		if filter.EducationLevel != "" {
-			query = query.Where("education_level LIKE ?", "%"+filter.EducationLevel+"%")
+			query = query.Where(database.ContainsAny(r.db, filter.EducationLevel, "education_level"))
 		}
 		if filter.Verified {
 			query = query.Where("verified = ?", filter.Verified)
 		}
+		// Quarantined tutors are only listed when asked for
+		query = query.Where("quarantined = ?", filter.Quarantined)
 		if filter.MinDayPerWeek > 0 {
 			query = query.Where("day_per_week >= ?", filter.MinDayPerWeek)
 		}
@@ -46,10 +76,16 @@ func (r *tutorRepo) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResp
 			query = query.Where("hr_per_day <= ?", filter.MaxHrPerDay)
 		}
 		if filter.Query != "" {
-			q := "%" + filter.Query + "%"
-			query = query.Where("first_name LIKE ? OR last_name LIKE ? OR email LIKE ? OR phone_number LIKE ?", q, q, q, q)
+			query = query.Where(r.search.Match(filter.Query))
 		}
 	}
+	return trashed(query, filter != nil && filter.Deleted)
+}
+
+func (r *tutorRepo) GetAll(ctx context.Context, filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
+	var tutors []domain.Tutor
+	var total int64
+	query := r.filter(ctx, filter)
 	query.Count(&total)
 	// Pagination: default limit 10, page 1
 	limit := 10
@@ -65,6 +101,7 @@ func (r *tutorRepo) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResp
 	offset := (page - 1) * limit
 	query = query.Limit(limit).Offset(offset)
 	// Apply safe ordering if provided
+	sorted := false
 	if filter != nil && filter.SortBy != "" {
 		// whitelist sortable columns to avoid SQL injection
 		allowed := map[string]bool{
@@ -81,8 +118,13 @@ func (r *tutorRepo) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResp
 				order = "desc"
 			}
 			query = query.Order(filter.SortBy + " " + order)
+			sorted = true
 		}
 	}
+	// Unless sorted otherwise, search results come best match first
+	if filter != nil && filter.Query != "" && !sorted {
+		query = r.search.Rank(query, filter.Query)
+	}
 	if err := query.Find(&tutors).Error; err != nil {
 		return domain.MultipleTutorResponse{}, err
 	}
@@ -97,9 +139,25 @@ func (r *tutorRepo) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResp
 	}, nil
 }
 
-func (r *tutorRepo) GetByID(id uint) (*domain.Tutor, error) {
+// Each calls fn with every tutor matching filter, in ID order, loading
+// them in batches. Paging and sorting are ignored.
+func (r *tutorRepo) Each(ctx context.Context, filter *domain.TutorFilter, fn func(*domain.Tutor) error) error {
+	var batch []domain.Tutor
+	return r.filter(ctx, filter).FindInBatches(&batch, eachBatchSize, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// GetByID, Update and Release don't see tutors in the trash; only Bulk can
+// restore them.
+func (r *tutorRepo) GetByID(ctx context.Context, id uint) (*domain.Tutor, error) {
 	var t domain.Tutor
-	if err := r.db.First(&t, id).Error; err != nil {
+	if err := trashed(r.db.WithContext(ctx), false).First(&t, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrNotFound
 		}
@@ -108,9 +166,9 @@ func (r *tutorRepo) GetByID(id uint) (*domain.Tutor, error) {
 	return &t, nil
 }
 
-func (r *tutorRepo) Update(id uint, t *domain.Tutor) (*domain.Tutor, error) {
+func (r *tutorRepo) Update(ctx context.Context, id uint, t *domain.Tutor) (*domain.Tutor, error) {
 	var tutor domain.Tutor
-	if err := r.db.First(&tutor, id).Error; err != nil {
+	if err := trashed(r.db.WithContext(ctx), false).First(&tutor, id).Error; err != nil {
 		return nil, err
 	}
 	// Update all fields based on domain.Tutor
@@ -120,16 +178,59 @@ func (r *tutorRepo) Update(id uint, t *domain.Tutor) (*domain.Tutor, error) {
 	tutor.PhoneNumber = t.PhoneNumber
 	tutor.DayPerWeek = t.DayPerWeek
 	tutor.HrPerDay = t.HrPerDay
+	if t.Language != "" {
+		tutor.Language = t.Language
+	}
+	// VerifiedAt follows Verified, whichever way the flag was changed
+	if t.Verified && !tutor.Verified {
+		now := time.Now()
+		tutor.VerifiedAt = &now
+	} else if !t.Verified {
+		tutor.VerifiedAt = nil
+	}
 	tutor.Verified = t.Verified
 	tutor.Email = t.Email
-	if err := r.db.Save(&tutor).Error; err != nil {
+	tutor.DocumentExpiresAt = t.DocumentExpiresAt
+	if err := r.db.WithContext(ctx).Save(&tutor).Error; err != nil {
 		return nil, err
 	}
 	return &tutor, nil
 }
 
-func (r *tutorRepo) Delete(id uint) error {
-	if err := r.db.Delete(&domain.Tutor{}, id).Error; err != nil {
+func (r *tutorRepo) GetByPhoneNumber(ctx context.Context, phone string) ([]domain.Tutor, error) {
+	var tutors []domain.Tutor
+	if err := trashed(r.db.WithContext(ctx), false).Where("phone_number = ?", phone).Order("id").Find(&tutors).Error; err != nil {
+		return nil, err
+	}
+	return tutors, nil
+}
+
+func (r *tutorRepo) Release(ctx context.Context, id uint) error {
+	return trashed(r.db.WithContext(ctx).Model(&domain.Tutor{}), false).Where("id = ?", id).
+		Updates(map[string]any{"quarantined": false, "quarantine_reason": ""}).Error
+}
+
+// Bulk runs in a transaction, with the tutors it acts on locked until it
+// ends.
+func (r *tutorRepo) Bulk(ctx context.Context, ids []uint, filter *domain.TutorFilter, fn domain.BulkFunc[domain.Tutor]) (*domain.BulkResult, error) {
+	var result *domain.BulkResult
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.Tutor{})
+		if len(ids) == 0 {
+			query = (&tutorRepo{db: tx, search: r.search}).filter(ctx, filter)
+		}
+		var err error
+		result, err = bulk(tx, query, ids, fn, func(t *domain.Tutor) uint { return t.ID })
+		return err
+	})
+	return result, err
+}
+
+func (r *tutorRepo) Delete(ctx context.Context, id uint) error {
+	// Moved to the trash, like a bulk delete; the purge task removes it
+	err := trashed(r.db.WithContext(ctx).Model(&domain.Tutor{}), false).Where("id = ?", id).
+		Update("deleted_at", time.Now()).Error
+	if err != nil {
 		return err
 	}
 	return nil
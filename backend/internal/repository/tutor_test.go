@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"hiyab-tutor/internal/database"
+	"context"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestTutorRepository(t *testing.T) {
 }
 
 func (s *TutorRepoTestSuite) SetupSuite() {
-	db := database.TestDB()
+	db := dbtest.Open()
 	if db == nil {
 		s.T().Fatal("creating test database issue")
 	}
@@ -52,10 +53,10 @@ func (s *TutorRepoTestSuite) TestCreateAndGetByID() {
 		Verified:       true,
 		Email:          "test@example.com",
 	}
-	created, err := s.tutorRepo.Create(t)
+	created, err := s.tutorRepo.Create(context.Background(), t)
 	s.NoError(err)
 	s.NotNil(created)
-	fetched, err := s.tutorRepo.GetByID(created.ID)
+	fetched, err := s.tutorRepo.GetByID(context.Background(), created.ID)
 	s.NoError(err)
 	s.Equal(created.FirstName, fetched.FirstName)
 	s.Equal(created.EducationLevel, fetched.EducationLevel)
@@ -71,9 +72,9 @@ func (s *TutorRepoTestSuite) TestGetAllWithFilter() {
 	t1 := &domain.Tutor{FirstName: "Alice", EducationLevel: "Degree", DayPerWeek: 5, HrPerDay: 2, Verified: true, Email: "alice@example.com"}
 	t2 := &domain.Tutor{FirstName: "Bob", EducationLevel: "Diploma", DayPerWeek: 3, HrPerDay: 1, Verified: false, Email: "bob@example.com"}
 	t3 := &domain.Tutor{FirstName: "Charlie", EducationLevel: "Degree", DayPerWeek: 6, HrPerDay: 3, Verified: true, Email: "charlie@example.com"}
-	s.tutorRepo.Create(t1)
-	s.tutorRepo.Create(t2)
-	s.tutorRepo.Create(t3)
+	s.tutorRepo.Create(context.Background(), t1)
+	s.tutorRepo.Create(context.Background(), t2)
+	s.tutorRepo.Create(context.Background(), t3)
 
 	filter := &domain.TutorFilter{
 		EducationLevel: "Degree",
@@ -81,7 +82,7 @@ func (s *TutorRepoTestSuite) TestGetAllWithFilter() {
 		Verified:       true,
 		Query:          "Charlie",
 	}
-	resp, err := s.tutorRepo.GetAll(filter)
+	resp, err := s.tutorRepo.GetAll(context.Background(), filter)
 	s.NoError(err)
 	s.Len(resp.Data, 1)
 	s.Equal("Charlie", resp.Data[0].FirstName)
@@ -93,9 +94,9 @@ func (s *TutorRepoTestSuite) TestGetAllWithFilter() {
 
 func (s *TutorRepoTestSuite) TestUpdate() {
 	t := &domain.Tutor{FirstName: "Old Name", EducationLevel: "Diploma", DayPerWeek: 3, HrPerDay: 1, Verified: false, Email: "old@example.com"}
-	created, _ := s.tutorRepo.Create(t)
+	created, _ := s.tutorRepo.Create(context.Background(), t)
 	updated := &domain.Tutor{FirstName: "New Name", EducationLevel: "Degree", DayPerWeek: 5, HrPerDay: 2, Verified: true, Email: "new@example.com"}
-	result, err := s.tutorRepo.Update(created.ID, updated)
+	result, err := s.tutorRepo.Update(context.Background(), created.ID, updated)
 	s.NoError(err)
 	s.Equal("New Name", result.FirstName)
 	s.Equal("Degree", result.EducationLevel)
@@ -106,11 +107,48 @@ func (s *TutorRepoTestSuite) TestUpdate() {
 }
 
 func (s *TutorRepoTestSuite) TestDelete() {
-	t := &domain.Tutor{FirstName: "ToDelete", EducationLevel: "Degree", Email: "delete@example.com"}
-	created, _ := s.tutorRepo.Create(t)
-	err := s.tutorRepo.Delete(created.ID)
+	t := &domain.Tutor{FirstName: "ToDelete", EducationLevel: "Degree", Email: "delete@example.com", PhoneNumber: "+251911111111"}
+	created, _ := s.tutorRepo.Create(context.Background(), t)
+	err := s.tutorRepo.Delete(context.Background(), created.ID)
 	s.NoError(err)
-	fetched, err := s.tutorRepo.GetByID(created.ID)
+	fetched, err := s.tutorRepo.GetByID(context.Background(), created.ID)
 	s.Error(err)
 	s.Nil(fetched)
+
+	// It went to the trash, where it no longer holds its phone number
+	trash, err := s.tutorRepo.GetAll(context.Background(), &domain.TutorFilter{Deleted: true})
+	s.NoError(err)
+	s.Len(trash.Data, 1)
+	same, err := s.tutorRepo.GetByPhoneNumber(context.Background(), "+251911111111")
+	s.NoError(err)
+	s.Empty(same)
+}
+
+func (s *TutorRepoTestSuite) TestCreateMany() {
+	ctx := context.Background()
+	tutors := []*domain.Tutor{{FirstName: "Abebe"}, {FirstName: "Sara"}}
+	s.NoError(s.tutorRepo.CreateMany(ctx, tutors, nil))
+	s.NotZero(tutors[1].ID)
+
+	// A failing row rolls back the batches before it
+	var clash []*domain.Tutor
+	for i := 0; i < createBatchSize; i++ {
+		clash = append(clash, &domain.Tutor{FirstName: "Hana"})
+	}
+	clash = append(clash, &domain.Tutor{Model: domain.Model{ID: tutors[0].ID}, FirstName: "Clash"})
+	s.Error(s.tutorRepo.CreateMany(ctx, clash, nil))
+	var count int64
+	s.NoError(s.db.Model(&domain.Tutor{}).Count(&count).Error)
+	s.EqualValues(2, count)
+
+	// So does a failing check, which sees the rows already stored
+	err := s.tutorRepo.CreateMany(ctx, []*domain.Tutor{{FirstName: "Hana", PhoneNumber: "+251911111111"}}, func(repo domain.TutorRepository, t *domain.Tutor) error {
+		all, err := repo.GetAll(ctx, &domain.TutorFilter{})
+		s.Require().NoError(err)
+		s.Len(all.Data, 2)
+		return domain.ErrDuplicateTutor
+	})
+	s.ErrorIs(err, domain.ErrDuplicateTutor)
+	s.NoError(s.db.Model(&domain.Tutor{}).Count(&count).Error)
+	s.EqualValues(2, count)
 }
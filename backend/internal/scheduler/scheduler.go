@@ -0,0 +1,242 @@
+// Package scheduler runs recurring maintenance tasks. Every instance runs
+// a scheduler, but only the one holding the leader lock runs tasks, and
+// the task state lives in the database so a new leader carries on where
+// the old one stopped.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// DefaultTick is how often the scheduler checks leadership and due tasks.
+const DefaultTick = 30 * time.Second
+
+// Task is one recurring piece of work.
+type Task struct {
+	Name string
+	// Schedule is a five field cron expression such as "0 3 * * *" or a
+	// descriptor such as "@daily", in the server's time zone
+	Schedule string
+	// Run returns a short summary of what it did, shown to superadmins
+	Run func(ctx context.Context) (string, error)
+}
+
+// Locker elects the leader; see database.AdvisoryLock.
+type Locker interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type entry struct {
+	Task
+	schedule cron.Schedule
+}
+
+// Scheduler runs its tasks when they are due while it is leader. It is
+// also the usecase behind the superadmin task list.
+type Scheduler struct {
+	repo domain.ScheduledTaskRepository
+	lock Locker
+	// Tick is how often due tasks are looked for; zero means DefaultTick
+	Tick time.Duration
+
+	tasks   []entry
+	name    string
+	now     func() time.Time
+	stop    context.CancelFunc
+	running sync.WaitGroup
+}
+
+var _ domain.SchedulerUsecase = (*Scheduler)(nil)
+
+func New(repo domain.ScheduledTaskRepository, lock Locker) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		repo: repo,
+		lock: lock,
+		name: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		now:  time.Now,
+	}
+}
+
+// Add registers t. A task with an empty schedule is left out.
+func (s *Scheduler) Add(t Task) error {
+	if t.Schedule == "" {
+		return nil
+	}
+	schedule, err := cron.ParseStandard(t.Schedule)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", t.Name, err)
+	}
+	s.tasks = append(s.tasks, entry{Task: t, schedule: schedule})
+	return nil
+}
+
+// GetAll lists the registered tasks with their stored state. Tasks that
+// never ran show when they first will.
+func (s *Scheduler) GetAll(ctx context.Context) ([]domain.ScheduledTask, error) {
+	stored, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]domain.ScheduledTask, len(stored))
+	for _, t := range stored {
+		byName[t.Name] = t
+	}
+	tasks := make([]domain.ScheduledTask, 0, len(s.tasks))
+	for _, e := range s.tasks {
+		t, ok := byName[e.Name]
+		if !ok || t.Schedule != e.Schedule {
+			t.Name, t.Schedule, t.NextRunAt = e.Name, e.Schedule, e.schedule.Next(s.now())
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// RunDue runs the tasks that are due, one after the other, if this
+// instance is leader, and returns how many ran.
+func (s *Scheduler) RunDue(ctx context.Context) (int, error) {
+	leader, err := s.lock.TryAcquire(ctx)
+	if err != nil || !leader {
+		return 0, err
+	}
+	ran := 0
+	var errs []error
+	for _, e := range s.tasks {
+		if ctx.Err() != nil {
+			break
+		}
+		due, err := s.due(ctx, e)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if due != nil {
+			errs = append(errs, s.run(ctx, e, due))
+			ran++
+		}
+	}
+	return ran, errors.Join(errs...)
+}
+
+// due returns the task's state when it should run now. A task seen for the
+// first time, or whose schedule changed, is only scheduled.
+func (s *Scheduler) due(ctx context.Context, e entry) (*domain.ScheduledTask, error) {
+	now := s.now()
+	state, err := s.repo.GetByName(ctx, e.Name)
+	if errors.Is(err, domain.ErrNotFound) {
+		state = &domain.ScheduledTask{Name: e.Name}
+	} else if err != nil {
+		return nil, err
+	}
+	if state.Schedule != e.Schedule {
+		state.Schedule = e.Schedule
+		state.NextRunAt = e.schedule.Next(now)
+		return nil, s.repo.Save(ctx, state)
+	}
+	if state.NextRunAt.After(now) {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// run runs one task and records the outcome. Runs missed while no
+// instance was leader are not made up for; the task runs once and then
+// follows its schedule again.
+func (s *Scheduler) run(ctx context.Context, e entry, state *domain.ScheduledTask) error {
+	logger := logging.FromContext(ctx).With("task", e.Name)
+	start := s.now()
+	state.LastStatus = domain.TaskStatusRunning
+	state.LastRunAt = &start
+	state.LastRunBy = s.name
+	if err := s.repo.Save(ctx, state); err != nil {
+		return err
+	}
+	logger.Info("scheduled task started")
+
+	result, err := call(ctx, e.Task)
+	end := s.now()
+	state.Runs++
+	state.LastResult = result
+	state.LastDuration = end.Sub(start).Round(time.Millisecond).String()
+	state.NextRunAt = e.schedule.Next(end)
+	if err != nil {
+		state.LastStatus = domain.TaskStatusFailed
+		state.LastError = err.Error()
+		state.Failures++
+		logger.Error("scheduled task failed", "error", err, "duration", state.LastDuration)
+	} else {
+		state.LastStatus = domain.TaskStatusOK
+		state.LastError = ""
+		logger.Info("scheduled task done", "result", result, "duration", state.LastDuration)
+	}
+	return s.repo.Save(context.WithoutCancel(ctx), state)
+}
+
+func call(ctx context.Context, t Task) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return t.Run(ctx)
+}
+
+// Start looks for due tasks every Tick until Stop.
+func (s *Scheduler) Start() {
+	if len(s.tasks) == 0 {
+		return
+	}
+	tick := s.Tick
+	if tick <= 0 {
+		tick = DefaultTick
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			if _, err := s.RunDue(ctx); err != nil && ctx.Err() == nil {
+				logging.FromContext(ctx).Error("scheduler failed", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the task that is running, waits for it to return, or for
+// ctx, and hands the leadership on.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+	s.stop()
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return s.lock.Release(ctx)
+}
@@ -0,0 +1,180 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLock struct {
+	leader   bool
+	released bool
+}
+
+func (l *fakeLock) TryAcquire(context.Context) (bool, error) { return l.leader, nil }
+
+func (l *fakeLock) Release(context.Context) error {
+	l.released = true
+	return nil
+}
+
+func newTestScheduler(t *testing.T) (*Scheduler, domain.ScheduledTaskRepository, *fakeLock, *time.Time) {
+	t.Helper()
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&domain.ScheduledTask{}))
+	repo := repository.NewScheduledTaskRepository(db)
+	lock := &fakeLock{leader: true}
+	s := New(repo, lock)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, repo, lock, &now
+}
+
+func TestScheduler_RunsDueTasks(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, now := newTestScheduler(t)
+	runs := 0
+	require.NoError(t, s.Add(Task{Name: "cleanup", Schedule: "0 3 * * *", Run: func(context.Context) (string, error) {
+		runs++
+		return "removed 2 files", nil
+	}}))
+	require.NoError(t, s.Add(Task{Name: "disabled", Run: func(context.Context) (string, error) {
+		t.Fatal("a task without a schedule ran")
+		return "", nil
+	}}))
+
+	// The first tick only schedules the task
+	ran, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, ran)
+	state, err := repo.GetByName(ctx, "cleanup")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 3, 2, 3, 0, 0, 0, time.UTC), state.NextRunAt.UTC())
+
+	*now = time.Date(2025, 3, 2, 3, 0, 10, 0, time.UTC)
+	ran, err = s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, ran)
+	require.Equal(t, 1, runs)
+
+	state, err = repo.GetByName(ctx, "cleanup")
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskStatusOK, state.LastStatus)
+	require.Equal(t, "removed 2 files", state.LastResult)
+	require.Equal(t, 1, state.Runs)
+	require.Equal(t, time.Date(2025, 3, 3, 3, 0, 0, 0, time.UTC), state.NextRunAt.UTC())
+
+	// Not due again until tomorrow
+	ran, err = s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, ran)
+
+	tasks, err := s.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, "cleanup", tasks[0].Name)
+	require.Equal(t, 1, tasks[0].Runs)
+}
+
+func TestScheduler_RecordsFailures(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, now := newTestScheduler(t)
+	require.NoError(t, s.Add(Task{Name: "failing", Schedule: "@hourly", Run: func(context.Context) (string, error) {
+		return "", errors.New("boom")
+	}}))
+	require.NoError(t, s.Add(Task{Name: "panicking", Schedule: "@hourly", Run: func(context.Context) (string, error) {
+		panic("oops")
+	}}))
+
+	_, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	*now = now.Add(time.Hour)
+	ran, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, ran)
+
+	state, err := repo.GetByName(ctx, "failing")
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskStatusFailed, state.LastStatus)
+	require.Equal(t, "boom", state.LastError)
+	require.Equal(t, 1, state.Failures)
+
+	state, err = repo.GetByName(ctx, "panicking")
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskStatusFailed, state.LastStatus)
+	require.Contains(t, state.LastError, "oops")
+}
+
+func TestScheduler_OnlyLeaderRuns(t *testing.T) {
+	ctx := context.Background()
+	s, repo, lock, _ := newTestScheduler(t)
+	lock.leader = false
+	require.NoError(t, s.Add(Task{Name: "cleanup", Schedule: "@hourly", Run: func(context.Context) (string, error) {
+		t.Fatal("a follower ran a task")
+		return "", nil
+	}}))
+
+	ran, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, ran)
+	_, err = repo.GetByName(ctx, "cleanup")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestScheduler_ChangedScheduleReschedules(t *testing.T) {
+	ctx := context.Background()
+	s, repo, _, now := newTestScheduler(t)
+	require.NoError(t, s.Add(Task{Name: "cleanup", Schedule: "0 3 * * *", Run: func(context.Context) (string, error) {
+		return "", nil
+	}}))
+	_, err := s.RunDue(ctx)
+	require.NoError(t, err)
+
+	// The next deploy moves the task to the evening
+	s, _, _, _ = newTestScheduler(t)
+	s.repo = repo
+	s.now = func() time.Time { return *now }
+	require.NoError(t, s.Add(Task{Name: "cleanup", Schedule: "0 20 * * *", Run: func(context.Context) (string, error) {
+		t.Fatal("a rescheduled task ran")
+		return "", nil
+	}}))
+	ran, err := s.RunDue(ctx)
+	require.NoError(t, err)
+	require.Zero(t, ran)
+
+	state, err := repo.GetByName(ctx, "cleanup")
+	require.NoError(t, err)
+	require.Equal(t, "0 20 * * *", state.Schedule)
+	require.Equal(t, time.Date(2025, 3, 1, 20, 0, 0, 0, time.UTC), state.NextRunAt.UTC())
+}
+
+func TestScheduler_Add_RejectsBadSchedules(t *testing.T) {
+	s, _, _, _ := newTestScheduler(t)
+	require.Error(t, s.Add(Task{Name: "bad", Schedule: "every night"}))
+}
+
+func TestScheduler_StopReleasesLock(t *testing.T) {
+	s, _, lock, _ := newTestScheduler(t)
+	s.Tick = time.Millisecond
+	started := make(chan struct{})
+	require.NoError(t, s.Add(Task{Name: "slow", Schedule: "@every 1s", Run: func(ctx context.Context) (string, error) {
+		close(started)
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}))
+	// Make the task due straight away
+	require.NoError(t, s.repo.Save(context.Background(), &domain.ScheduledTask{Name: "slow", Schedule: "@every 1s"}))
+
+	s.Start()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.Stop(ctx))
+	require.True(t, lock.released)
+}
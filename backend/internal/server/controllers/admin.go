@@ -1,9 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"hiyab-tutor/internal/auth"
 	"hiyab-tutor/internal/domain"
-	"log"
+	"hiyab-tutor/internal/logging"
 	"net/http"
 	"strconv"
 
@@ -11,12 +12,14 @@ import (
 )
 
 type AdminController struct {
-	u domain.AdminUsecase
+	u      domain.AdminUsecase
+	tokens *auth.Tokens
 }
 
-func NewAdminController(u domain.AdminUsecase) *AdminController {
+func NewAdminController(u domain.AdminUsecase, tokens *auth.Tokens) *AdminController {
 	return &AdminController{
-		u: u,
+		u:      u,
+		tokens: tokens,
 	}
 }
 
@@ -39,14 +42,20 @@ func (c *AdminController) Create(ctx *gin.Context) {
 		return
 	}
 
-	createdAdmin, err := c.u.Create(&domain.Admin{
-		Username: request.Username,
-		Password: request.Password,
-		Role:     request.Role,
-		Name:     request.Name,
+	createdAdmin, err := c.u.Create(ctx.Request.Context(), &domain.Admin{
+		Username:    request.Username,
+		Password:    request.Password,
+		Role:        request.Role,
+		Name:        request.Name,
+		Email:       request.Email,
+		PhoneNumber: request.PhoneNumber,
 	})
 	if err != nil {
-		ctx.JSON(500, domain.ErrorResponse{Message: "Failed to create admin"})
+		if isPasswordPolicyError(err) {
+			ctx.JSON(400, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to create admin", err)
 		return
 	}
 
@@ -71,7 +80,7 @@ func (c *AdminController) GetByID(ctx *gin.Context) {
 		return
 	}
 
-	admin, err := c.u.GetByID(uint(id))
+	admin, err := c.u.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(404, domain.ErrorResponse{Message: "Admin not found"})
 		return
@@ -125,9 +134,9 @@ func (c *AdminController) GetAll(ctx *gin.Context) {
 		SortOrder: sortOrder,
 	}
 
-	admins, err := c.u.GetAll(filter)
+	admins, err := c.u.GetAll(ctx.Request.Context(), filter)
 	if err != nil {
-		ctx.JSON(500, domain.ErrorResponse{Message: "Failed to retrieve admins"})
+		internalError(ctx, "Failed to retrieve admins", err)
 		return
 	}
 
@@ -161,11 +170,7 @@ func (c *AdminController) Update(ctx *gin.Context) {
 		return
 	}
 
-	updatedAdmin, err := c.u.Update(&domain.Admin{
-		Model: domain.Model{ID: uint(id)},
-		Role:  request.Role,
-		Name:  request.Name,
-	})
+	updatedAdmin, err := c.u.Update(ctx.Request.Context(), uint(id), &request)
 	if err != nil {
 		ctx.JSON(404, domain.ErrorResponse{Message: "Admin not found"})
 		return
@@ -191,7 +196,7 @@ func (c *AdminController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	admin, err := c.u.GetByID(uint(id))
+	admin, err := c.u.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Admin not found"})
 		return
@@ -200,7 +205,7 @@ func (c *AdminController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Cannot delete superadmin"})
 		return
 	}
-	if err := c.u.Delete(uint(id)); err != nil {
+	if err := c.u.Delete(ctx.Request.Context(), uint(id)); err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Admin not found"})
 		return
 	}
@@ -226,20 +231,20 @@ func (c *AdminController) Login(ctx *gin.Context) {
 		return
 	}
 
-	admin, err := c.u.Login(request.Username, request.Password)
+	admin, err := c.u.Login(ctx.Request.Context(), request.Username, request.Password)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Info("login failed", "username", request.Username, "error", err)
 		ctx.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Invalid credentials"})
 		return
 	}
-	refreshToken, err := auth.GenerateToken(admin, auth.TokenTypeRefresh)
+	refreshToken, err := c.tokens.Generate(admin, auth.TokenTypeRefresh)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to generate token"})
+		internalError(ctx, "Failed to generate token", err)
 		return
 	}
-	accessToken, err := auth.GenerateToken(admin, auth.TokenTypeAccess)
+	accessToken, err := c.tokens.Generate(admin, auth.TokenTypeAccess)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to generate token"})
+		internalError(ctx, "Failed to generate token", err)
 		return
 	}
 	ctx.SetCookie("refresh_token", refreshToken, 60*60*24*7, "/auth", "localhost", true, true)
@@ -276,7 +281,11 @@ func (c *AdminController) ResetPassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.u.ResetPassword(uint(id), request.NewPassword); err != nil {
+	if err := c.u.ResetPassword(ctx.Request.Context(), uint(id), request.NewPassword); err != nil {
+		if isPasswordPolicyError(err) {
+			ctx.JSON(400, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
 		ctx.JSON(404, domain.ErrorResponse{Message: "Admin not found"})
 		return
 	}
@@ -284,6 +293,57 @@ func (c *AdminController) ResetPassword(ctx *gin.Context) {
 	ctx.JSON(200, domain.MessageResponse{Message: "Password reset successfully"})
 }
 
+// ForgotPassword starts the self-service password reset
+// @Summary Request Password Reset
+// @Description Sends a one-time password reset link to the admin's e-mail or phone. The response is the same whether or not the username exists.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.ForgotPasswordRequest true "Username"
+// @Success 202 {object} domain.MessageResponse
+// @Failure 400 {object} domain.ErrorResponse
+// @Router /admin/forgot-password [post]
+func (c *AdminController) ForgotPassword(ctx *gin.Context) {
+	var request domain.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid input"})
+		return
+	}
+	// The answer never depends on the outcome, not even on a failure
+	if err := c.u.RequestPasswordReset(ctx.Request.Context(), request.Username); err != nil {
+		logging.FromContext(ctx.Request.Context()).Error("password reset request failed", "error", err)
+	}
+	ctx.JSON(http.StatusAccepted, domain.MessageResponse{Message: "If the account exists, a reset link has been sent"})
+}
+
+// ConfirmPasswordReset sets a new password using a reset token
+// @Summary Confirm Password Reset
+// @Description Exchanges a one-time reset token for a new password
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body domain.ConfirmPasswordResetRequest true "Token and new password"
+// @Success 200 {object} domain.MessageResponse
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Router /admin/reset-password [post]
+func (c *AdminController) ConfirmPasswordReset(ctx *gin.Context) {
+	var request domain.ConfirmPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid input"})
+		return
+	}
+	if err := c.u.ResetPasswordWithToken(ctx.Request.Context(), request.Token, request.NewPassword); err != nil {
+		if errors.Is(err, domain.ErrResetTokenInvalid) || isPasswordPolicyError(err) {
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to reset password", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, domain.MessageResponse{Message: "Password reset successfully"})
+}
+
 // ChangePassword changes an admin's password
 // @Summary Change Admin Password
 // @Description Changes an admin's password
@@ -315,7 +375,11 @@ func (c *AdminController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.u.ChangePassword(uint(id), request.OldPassword, request.NewPassword); err != nil {
+	if err := c.u.ChangePassword(ctx.Request.Context(), uint(id), request.OldPassword, request.NewPassword); err != nil {
+		if isPasswordPolicyError(err) {
+			ctx.JSON(400, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
 		ctx.JSON(404, domain.ErrorResponse{Message: "Admin not found or invalid credentials"})
 		return
 	}
@@ -340,9 +404,9 @@ func (c *AdminController) GetCurrentAdmin(ctx *gin.Context) {
 		return
 	}
 
-	admin, err := c.u.GetByID(userID.(uint))
+	admin, err := c.u.GetByID(ctx.Request.Context(), userID.(uint))
 	if err != nil {
-		ctx.JSON(500, domain.ErrorResponse{Message: "Failed to retrieve admin"})
+		internalError(ctx, "Failed to retrieve admin", err)
 		return
 	}
 
@@ -361,31 +425,30 @@ func (c *AdminController) GetCurrentAdmin(ctx *gin.Context) {
 func (c *AdminController) RefreshToken(ctx *gin.Context) {
 	refreshToken, err := ctx.Cookie("refresh_token")
 	if err != nil {
-		log.Println(err)
 		ctx.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Unauthorized to make the request"})
 		return
 	}
-	claims, err := auth.ValidateToken(refreshToken, auth.TokenTypeRefresh)
+	claims, err := c.tokens.Validate(refreshToken, auth.TokenTypeRefresh)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Info("refresh token rejected", "error", err)
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "invalid token"})
 		return
 	}
-	user, err := c.u.GetByID(claims.UserID)
+	user, err := c.u.GetByID(ctx.Request.Context(), claims.UserID)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Info("refresh token for unknown admin", "admin_id", claims.UserID, "error", err)
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "invalid token"})
 		return
 	}
-	accessToken, err := auth.GenerateToken(user, auth.TokenTypeAccess)
+	accessToken, err := c.tokens.Generate(user, auth.TokenTypeAccess)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Error("generating access token", "error", err)
 		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error generating token"})
 		return
 	}
-	refreshToken, err = auth.GenerateToken(user, auth.TokenTypeRefresh)
+	refreshToken, err = c.tokens.Generate(user, auth.TokenTypeRefresh)
 	if err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Error("generating refresh token", "error", err)
 		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error generating token"})
 		return
 	}
@@ -396,3 +459,12 @@ func (c *AdminController) RefreshToken(ctx *gin.Context) {
 		User:        *user,
 	})
 }
+
+// isPasswordPolicyError reports whether err means the password was rejected
+// by the policy, which is the client's fault rather than ours.
+func isPasswordPolicyError(err error) bool {
+	return errors.Is(err, domain.ErrPasswordTooShort) ||
+		errors.Is(err, domain.ErrPasswordTooWeak) ||
+		errors.Is(err, domain.ErrPasswordCommon) ||
+		errors.Is(err, domain.ErrPasswordReused)
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsController struct {
+	u domain.AnalyticsUsecase
+}
+
+func NewAnalyticsController(u domain.AnalyticsUsecase) *AnalyticsController {
+	return &AnalyticsController{u: u}
+}
+
+// Totals counts the records of every kind
+// @Summary Record totals
+// @Description Count bookings, tutors, partners, testimonials and other services. The counts are cached for a few minutes or until a record changes; send the ETag back in If-None-Match to get a 304 while they are unchanged (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param If-None-Match header string false "ETag of the totals the client has"
+// @Success 200 {object} domain.AnalyticsSnapshot
+// @Success 304
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics [get]
+func (c *AnalyticsController) Totals(ctx *gin.Context) {
+	snapshot, err := c.u.Totals(ctx.Request.Context())
+	if err != nil {
+		internalError(ctx, "Failed to count records", err)
+		return
+	}
+	// computed_at changes on every refresh, the totals may not, so the
+	// tag is weak
+	if notModified(ctx, `W/"`+snapshot.Version+`"`) {
+		return
+	}
+	ctx.JSON(http.StatusOK, snapshot)
+}
+
+// Bookings counts bookings over time
+// @Summary Bookings over time
+// @Description Count the bookings made, and how many of them are assigned, per day, week or month (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Param granularity query string false "day, week or month" default(day)
+// @Success 200 {object} domain.BookingTrend
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics/bookings [get]
+func (c *AnalyticsController) Bookings(ctx *gin.Context) {
+	report(ctx, func(q *domain.AnalyticsQuery) (*domain.BookingTrend, error) {
+		return c.u.Bookings(ctx.Request.Context(), q)
+	})
+}
+
+// Tutors counts tutor registrations and verifications over time
+// @Summary Tutors over time
+// @Description Count the tutors registered and the tutors verified per day, week or month (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Param granularity query string false "day, week or month" default(day)
+// @Success 200 {object} domain.TutorTrend
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics/tutors [get]
+func (c *AnalyticsController) Tutors(ctx *gin.Context) {
+	report(ctx, func(q *domain.AnalyticsQuery) (*domain.TutorTrend, error) {
+		return c.u.Tutors(ctx.Request.Context(), q)
+	})
+}
+
+// TimeToAssignment describes how long bookings wait for a tutor
+// @Summary Time to assignment
+// @Description Distribution of the hours between a booking being made and assigned, for the bookings assigned in the range (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Param granularity query string false "day, week or month" default(day)
+// @Success 200 {object} domain.DurationReport
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics/time-to-assignment [get]
+func (c *AnalyticsController) TimeToAssignment(ctx *gin.Context) {
+	report(ctx, func(q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+		return c.u.TimeToAssignment(ctx.Request.Context(), q)
+	})
+}
+
+// TimeToVerification describes how long tutors wait to be verified
+// @Summary Time to verification
+// @Description Distribution of the hours between a tutor registering and being verified, for the tutors verified in the range (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Param granularity query string false "day, week or month" default(day)
+// @Success 200 {object} domain.DurationReport
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics/time-to-verification [get]
+func (c *AnalyticsController) TimeToVerification(ctx *gin.Context) {
+	report(ctx, func(q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+		return c.u.TimeToVerification(ctx.Request.Context(), q)
+	})
+}
+
+// BookingBreakdown groups bookings by grade, gender or area
+// @Summary Bookings by grade, gender or area
+// @Description Count the bookings made in the range per grade, gender or address area, in total and per period (admin only)
+// @Tags Analytics
+// @Produce json
+// @Param dimension path string true "grade, gender or area"
+// @Param from query string false "First day (YYYY-MM-DD), 30 days before to by default"
+// @Param to query string false "Last day (YYYY-MM-DD), today by default"
+// @Param granularity query string false "day, week or month" default(day)
+// @Success 200 {object} domain.BookingBreakdown
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /analytics/bookings/breakdown/{dimension} [get]
+func (c *AnalyticsController) BookingBreakdown(ctx *gin.Context) {
+	report(ctx, func(q *domain.AnalyticsQuery) (*domain.BookingBreakdown, error) {
+		return c.u.BookingBreakdown(ctx.Request.Context(), q, ctx.Param("dimension"))
+	})
+}
+
+// report parses the query, runs get and writes the report or the error.
+func report[T any](ctx *gin.Context, get func(*domain.AnalyticsQuery) (*T, error)) {
+	q, err := parseAnalyticsQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	resp, err := get(q)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDateRange), errors.Is(err, domain.ErrInvalidGranularity),
+			errors.Is(err, domain.ErrInvalidDimension), errors.Is(err, domain.ErrRangeTooLarge):
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		default:
+			internalError(ctx, "Failed to compute report", err)
+		}
+		return
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		internalError(ctx, "Failed to compute report", err)
+		return
+	}
+	// Reports are computed each time, but an unchanged one isn't sent again
+	sum := sha256.Sum256(body)
+	if notModified(ctx, `"`+hex.EncodeToString(sum[:8])+`"`) {
+		return
+	}
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// notModified sets the response's ETag and answers 304 when the request's
+// If-None-Match already has it. Tags are compared weakly, as RFC 9110
+// asks for If-None-Match.
+func notModified(ctx *gin.Context, etag string) bool {
+	ctx.Header("ETag", etag)
+	// Only behind auth, so shared caches must not keep it
+	ctx.Header("Cache-Control", "private, no-cache")
+	match := ctx.GetHeader("If-None-Match")
+	if match == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(match, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			ctx.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func parseAnalyticsQuery(ctx *gin.Context) (*domain.AnalyticsQuery, error) {
+	q := &domain.AnalyticsQuery{Granularity: ctx.Query("granularity")}
+	var err error
+	if v := ctx.Query("from"); v != "" {
+		if q.From, err = time.Parse(time.DateOnly, v); err != nil {
+			return nil, errors.New("invalid from date, want YYYY-MM-DD")
+		}
+	}
+	if v := ctx.Query("to"); v != "" {
+		if q.To, err = time.Parse(time.DateOnly, v); err != nil {
+			return nil, errors.New("invalid to date, want YYYY-MM-DD")
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	u domain.AuditUsecase
+}
+
+func NewAuditController(u domain.AuditUsecase) *AuditController {
+	return &AuditController{u: u}
+}
+
+// GetAll lists audit entries
+// @Summary List audit log
+// @Description List administrative mutations, newest first (superadmin only)
+// @Tags Audit
+// @Produce json
+// @Param actor_id query int false "Actor admin ID"
+// @Param action query string false "Action (create, update, delete, verify, ...)"
+// @Param entity_type query string false "Entity type (tutor, booking, partner, ...)"
+// @Param entity_id query int false "Entity ID"
+// @Param from query string false "Start time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End time (RFC3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} domain.MultipleAuditLogs
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /audit [get]
+func (c *AuditController) GetAll(ctx *gin.Context) {
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	resp, err := c.u.GetAll(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidDateRange) {
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to fetch audit log", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Export streams audit entries as CSV
+// @Summary Export audit log
+// @Description Download the audit log as CSV, oldest first. Accepts the same filters as the list endpoint (superadmin only)
+// @Tags Audit
+// @Produce text/csv
+// @Param actor_id query int false "Actor admin ID"
+// @Param action query string false "Action"
+// @Param entity_type query string false "Entity type"
+// @Param entity_id query int false "Entity ID"
+// @Param from query string false "Start time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "End time (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /audit/export [get]
+func (c *AuditController) Export(ctx *gin.Context) {
+	filter, err := parseAuditFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		return
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: domain.ErrInvalidDateRange.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", "attachment; filename=audit-log.csv")
+	ctx.Status(http.StatusOK)
+	w := csv.NewWriter(ctx.Writer)
+	_ = w.Write([]string{"id", "created_at", "actor_id", "actor_username", "actor_role", "action", "entity_type", "entity_id", "ip_address", "changes"})
+	err = c.u.Export(ctx.Request.Context(), filter, func(entry *domain.AuditLog) error {
+		changes, _ := json.Marshal(entry.Changes)
+		return w.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(entry.ActorID), 10),
+			entry.ActorUsername,
+			entry.ActorRole,
+			entry.Action,
+			entry.EntityType,
+			strconv.FormatUint(uint64(entry.EntityID), 10),
+			entry.IPAddress,
+			string(changes),
+		})
+	})
+	w.Flush()
+	if err != nil {
+		// Headers are already sent, so all we can do is log
+		logging.FromContext(ctx.Request.Context()).Error("audit export failed", "error", err)
+	}
+}
+
+func parseAuditFilter(ctx *gin.Context) (*domain.AuditFilter, error) {
+	filter := &domain.AuditFilter{
+		Action:     ctx.Query("action"),
+		EntityType: ctx.Query("entity_type"),
+	}
+	if v := ctx.Query("actor_id"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid actor_id")
+		}
+		filter.ActorID = uint(n)
+	}
+	if v := ctx.Query("entity_id"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid entity_id")
+		}
+		filter.EntityID = uint(n)
+	}
+	var err error
+	if filter.From, err = parseTimeParam(ctx.Query("from")); err != nil {
+		return nil, errors.New("invalid from date")
+	}
+	if filter.To, err = parseTimeParam(ctx.Query("to")); err != nil {
+		return nil, errors.New("invalid to date")
+	}
+	// A plain date as upper bound means the whole day
+	if v := ctx.Query("to"); len(v) == len(time.DateOnly) {
+		filter.To = filter.To.Add(24*time.Hour - time.Nanosecond)
+	}
+	if v := ctx.Query("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Page = n
+		}
+	}
+	if v := ctx.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Limit = n
+		}
+	}
+	return filter, nil
+}
+
+// parseTimeParam accepts RFC3339 timestamps and plain dates.
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
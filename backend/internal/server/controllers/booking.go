@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"hiyab-tutor/internal/domain"
-	"log"
+	"hiyab-tutor/internal/logging"
+	"hiyab-tutor/internal/server/middlewares"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,18 +30,29 @@ func NewBookingController(u domain.BookingUsecase) *BookingController {
 // @Param booking body domain.Booking true "Booking"
 // @Success 201 {object} domain.Booking
 // @Failure 400 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Router /bookings [post]
 func (c *BookingController) Create(ctx *gin.Context) {
 	var req domain.Booking
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		log.Println(err)
+		logging.FromContext(ctx.Request.Context()).Debug("invalid booking request", "error", err)
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid request"})
 		return
 	}
-	created, err := c.u.Create(&req)
+	if req.Language == "" {
+		req.Language = requestLanguage(ctx)
+	}
+	// Set by the spam guard; never taken from the client
+	req.QuarantineReason = ctx.GetString(middlewares.QuarantineKey)
+	req.Quarantined = req.QuarantineReason != ""
+	created, err := c.u.Create(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to create booking"})
+		if status, ok := phoneErrorStatus(err); ok {
+			ctx.JSON(status, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to create booking", err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, created)
@@ -52,64 +67,49 @@ func (c *BookingController) Create(ctx *gin.Context) {
 // @Param page query int false "Page number"
 // @Param gender query string false "Gender"
 // @Param assigned query bool false "Assigned"
+// @Param query query string false "Words to find in names, phone number or address, matched by prefix and best matches first unless sorted"
 // @Success 200 {array} domain.Booking
 // @Failure 500 {object} domain.ErrorResponse
 // @Security JWT
 // @Router /bookings [get]
 func (c *BookingController) GetAll(ctx *gin.Context) {
-	filter := &domain.BookingFilter{}
-	// standard filters
-	if v := ctx.Query("gender"); v != "" {
-		filter.Gender = v
-	}
-	if v := ctx.Query("assigned"); v != "" {
-		filter.Assigned = v == "true"
-	}
-	if v := ctx.Query("query"); v != "" {
-		filter.Query = v
-	}
-	// numeric filters
-	if v := ctx.Query("min_day_per_week"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.MinDayPerWeek = n
-		}
-	}
-	if v := ctx.Query("max_day_per_week"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.MaxDayPerWeek = n
-		}
-	}
-	if v := ctx.Query("min_hr_per_day"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.MinHrPerDay = n
-		}
-	}
-	if v := ctx.Query("max_hr_per_day"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.MaxHrPerDay = n
-		}
-	}
-	// pagination & sorting
-	if v := ctx.Query("page"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.Page = n
-		}
-	}
-	if v := ctx.Query("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil {
-			filter.Limit = n
-		}
-	}
-	if v := ctx.Query("sort_by"); v != "" {
-		filter.SortBy = v
-	}
-	if v := ctx.Query("sort_order"); v != "" {
-		filter.SortOrder = v
-	}
+	c.list(ctx, bookingFilter(ctx.Request.URL.Query(), false))
+}
 
-	resp, err := c.u.GetAll(filter)
+// GetQuarantined lists the bookings held for review
+// @Summary Get quarantined bookings
+// @Description List bookings held for review by the spam checks (protected). Accepts the same filters as the main list
+// @Tags Bookings
+// @Produce json
+// @Param page query int false "Page number"
+// @Success 200 {object} domain.MultipleBookingResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/quarantined [get]
+func (c *BookingController) GetQuarantined(ctx *gin.Context) {
+	c.list(ctx, bookingFilter(ctx.Request.URL.Query(), true))
+}
+
+// GetTrash lists the bookings deleted in bulk
+// @Summary Get deleted bookings
+// @Description List the bookings moved to the trash by a bulk delete, which can be restored until the trash is purged (protected). Accepts the same filters as the main list
+// @Tags Bookings
+// @Produce json
+// @Param page query int false "Page number"
+// @Success 200 {object} domain.MultipleBookingResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/trash [get]
+func (c *BookingController) GetTrash(ctx *gin.Context) {
+	filter := bookingFilter(ctx.Request.URL.Query(), ctx.Query("quarantined") == "true")
+	filter.Deleted = true
+	c.list(ctx, filter)
+}
+
+func (c *BookingController) list(ctx *gin.Context, filter *domain.BookingFilter) {
+	resp, err := c.u.GetAll(ctx.Request.Context(), filter)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to fetch bookings"})
+		internalError(ctx, "Failed to fetch bookings", err)
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -134,7 +134,7 @@ func (c *BookingController) GetByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	booking, err := c.u.GetByID(uint(id))
+	booking, err := c.u.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Booking not found"})
 		return
@@ -149,7 +149,9 @@ func (c *BookingController) GetByID(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Booking ID"
+// @Param request body domain.AssignBookingRequest false "Tutor to assign"
 // @Success 200 {object} domain.Booking
+// @Failure 400 {object} domain.ErrorResponse
 // @Failure 404 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Security JWT
@@ -161,10 +163,137 @@ func (c *BookingController) Assign(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	if err := c.u.Assign(uint(id)); err != nil {
+	// The body is optional; without it the booking is only marked assigned
+	var req domain.AssignBookingRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid request body"})
+			return
+		}
+	}
+	if err := c.u.Assign(ctx.Request.Context(), uint(id), req.TutorID); err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Booking not found or failed to assign"})
 		return
 	}
-	booking, _ := c.u.GetByID(uint(id))
+	booking, _ := c.u.GetByID(ctx.Request.Context(), uint(id))
+	ctx.JSON(http.StatusOK, booking)
+}
+
+// Release lets a quarantined booking through
+// @Summary Release a quarantined booking
+// @Description Clear the quarantine flag so the booking shows up in the main list and notifications are sent (protected)
+// @Tags Bookings
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Success 200 {object} domain.Booking
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/{id}/release [put]
+func (c *BookingController) Release(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	if err := c.u.Release(ctx.Request.Context(), uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Booking not found or failed to release"})
+		return
+	}
+	booking, _ := c.u.GetByID(ctx.Request.Context(), uint(id))
 	ctx.JSON(http.StatusOK, booking)
 }
+
+// internalError answers 500 with message and logs err with the request's
+// logger, so the cause can be found from the request ID.
+func internalError(ctx *gin.Context, message string, err error) {
+	logging.FromContext(ctx.Request.Context()).Error(message, "error", err)
+	ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: message})
+}
+
+// requestLanguage is the caller's preferred language from the
+// Accept-Language header, kept for records submitted without one.
+func requestLanguage(ctx *gin.Context) string {
+	first, _, _ := strings.Cut(ctx.GetHeader("Accept-Language"), ",")
+	first, _, _ = strings.Cut(first, ";")
+	return strings.TrimSpace(first)
+}
+
+// phoneErrorStatus maps phone number validation errors to a status code.
+func phoneErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPhoneNumber):
+		return http.StatusBadRequest, true
+	case errors.Is(err, domain.ErrDuplicateBooking), errors.Is(err, domain.ErrDuplicateTutor):
+		return http.StatusConflict, true
+	}
+	return 0, false
+}
+
+// bookingFilter reads the list filters shared by the list, export and bulk
+// endpoints from query.
+func bookingFilter(query url.Values, quarantined bool) *domain.BookingFilter {
+	filter := &domain.BookingFilter{Quarantined: quarantined}
+	// standard filters
+	if v := query.Get("gender"); v != "" {
+		filter.Gender = v
+	}
+	if v := query.Get("assigned"); v != "" {
+		filter.Assigned = v == "true"
+	}
+	if v := query.Get("query"); v != "" {
+		filter.Query = v
+	}
+	if v := query.Get("address"); v != "" {
+		filter.Address = v
+	}
+	// numeric filters
+	if v := query.Get("min_grade"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MinGrade = n
+		}
+	}
+	if v := query.Get("max_grade"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MaxGrade = n
+		}
+	}
+	if v := query.Get("min_day_per_week"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MinDayPerWeek = n
+		}
+	}
+	if v := query.Get("max_day_per_week"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MaxDayPerWeek = n
+		}
+	}
+	if v := query.Get("min_hr_per_day"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MinHrPerDay = n
+		}
+	}
+	if v := query.Get("max_hr_per_day"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.MaxHrPerDay = n
+		}
+	}
+	// pagination & sorting
+	if v := query.Get("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Page = n
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Limit = n
+		}
+	}
+	if v := query.Get("sort_by"); v != "" {
+		filter.SortBy = v
+	}
+	if v := query.Get("sort_order"); v != "" {
+		filter.SortOrder = v
+	}
+	return filter
+}
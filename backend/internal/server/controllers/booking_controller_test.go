@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/server/middlewares"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,15 +27,18 @@ type mockBookingUsecase struct {
 	lastID   uint
 }
 
-func (m *mockBookingUsecase) Create(b *domain.Booking) (*domain.Booking, error) {
+func (m *mockBookingUsecase) Create(_ context.Context, b *domain.Booking) (*domain.Booking, error) {
 	m.lastID++
 	b.ID = m.lastID
 	m.bookings[b.ID] = b
 	return b, nil
 }
-func (m *mockBookingUsecase) GetAll(filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
+func (m *mockBookingUsecase) GetAll(_ context.Context, filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
 	var result []domain.Booking
 	for _, b := range m.bookings {
+		if filter != nil && b.Quarantined != filter.Quarantined {
+			continue
+		}
 		result = append(result, *b)
 	}
 	resp := domain.MultipleBookingResponse{
@@ -46,14 +52,14 @@ func (m *mockBookingUsecase) GetAll(filter *domain.BookingFilter) (domain.Multip
 	}
 	return resp, nil
 }
-func (m *mockBookingUsecase) GetByID(id uint) (*domain.Booking, error) {
+func (m *mockBookingUsecase) GetByID(_ context.Context, id uint) (*domain.Booking, error) {
 	b, ok := m.bookings[id]
 	if !ok {
 		return nil, domain.ErrNotFound
 	}
 	return b, nil
 }
-func (m *mockBookingUsecase) Update(id uint, b *domain.Booking) (*domain.Booking, error) {
+func (m *mockBookingUsecase) Update(_ context.Context, id uint, b *domain.Booking) (*domain.Booking, error) {
 	if _, ok := m.bookings[id]; !ok {
 		return nil, domain.ErrNotFound
 	}
@@ -61,21 +67,44 @@ func (m *mockBookingUsecase) Update(id uint, b *domain.Booking) (*domain.Booking
 	m.bookings[id] = b
 	return b, nil
 }
-func (m *mockBookingUsecase) Delete(id uint) error {
+func (m *mockBookingUsecase) Release(_ context.Context, id uint) error {
+	b, ok := m.bookings[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	b.Quarantined = false
+	b.QuarantineReason = ""
+	return nil
+}
+func (m *mockBookingUsecase) Delete(_ context.Context, id uint) error {
 	if _, ok := m.bookings[id]; !ok {
 		return domain.ErrNotFound
 	}
 	delete(m.bookings, id)
 	return nil
 }
-func (m *mockBookingUsecase) Assign(id uint) error {
+func (m *mockBookingUsecase) Assign(_ context.Context, id uint, tutorID uint) error {
 	b, ok := m.bookings[id]
 	if !ok {
 		return domain.ErrNotFound
 	}
 	b.Assigned = true
+	if tutorID != 0 {
+		b.TutorID = &tutorID
+	}
 	return nil
 }
+func (m *mockBookingUsecase) Bulk(_ context.Context, req *domain.BookingBulkRequest) (*domain.BulkResult, error) {
+	result := &domain.BulkResult{Action: req.Action}
+	for _, id := range req.IDs {
+		var err error
+		if _, ok := m.bookings[id]; !ok {
+			err = domain.ErrNotFound
+		}
+		result.Add(id, err == nil, err)
+	}
+	return result, nil
+}
 
 func TestBookingController(t *testing.T) {
 	suite.Run(t, new(BookingControllerTestSuite))
@@ -90,6 +119,12 @@ func (s *BookingControllerTestSuite) SetupTest() {
 	s.engine.GET("/bookings", s.controller.GetAll)
 	s.engine.GET("/bookings/:id", s.controller.GetByID)
 	s.engine.PUT("/bookings/:id/assign", s.controller.Assign)
+	s.engine.GET("/bookings/quarantined", s.controller.GetQuarantined)
+	s.engine.PUT("/bookings/:id/release", s.controller.Release)
+	// Stands in for the spam guard flagging a submission
+	s.engine.POST("/flagged/bookings", func(ctx *gin.Context) {
+		ctx.Set(middlewares.QuarantineKey, "honeypot")
+	}, s.controller.Create)
 }
 
 func (s *BookingControllerTestSuite) TestCreateBooking() {
@@ -105,11 +140,24 @@ func (s *BookingControllerTestSuite) TestCreateBooking() {
 	s.Equal("Test User", resp.FirstName)
 }
 
+func (s *BookingControllerTestSuite) TestCreateBooking_LanguageFromHeader() {
+	body, _ := json.Marshal(&domain.Booking{FirstName: "Test User"})
+	req := httptest.NewRequest("POST", "/bookings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "am-ET;q=0.9, en;q=0.8")
+	w := httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+	s.Equal(http.StatusCreated, w.Code)
+	var resp domain.Booking
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	s.Equal("am-ET", resp.Language)
+}
+
 func (s *BookingControllerTestSuite) TestGetAllBookings() {
 	b1 := &domain.Booking{FirstName: "A"}
 	b2 := &domain.Booking{FirstName: "B"}
-	s.usecase.Create(b1)
-	s.usecase.Create(b2)
+	s.usecase.Create(context.Background(), b1)
+	s.usecase.Create(context.Background(), b2)
 	req := httptest.NewRequest("GET", "/bookings", nil)
 	w := httptest.NewRecorder()
 	s.engine.ServeHTTP(w, req)
@@ -121,7 +169,7 @@ func (s *BookingControllerTestSuite) TestGetAllBookings() {
 
 func (s *BookingControllerTestSuite) TestGetBookingByID() {
 	b := &domain.Booking{FirstName: "FindMe"}
-	created, _ := s.usecase.Create(b)
+	created, _ := s.usecase.Create(context.Background(), b)
 	req := httptest.NewRequest("GET", "/bookings/1", nil)
 	w := httptest.NewRecorder()
 	s.engine.ServeHTTP(w, req)
@@ -133,7 +181,7 @@ func (s *BookingControllerTestSuite) TestGetBookingByID() {
 
 func (s *BookingControllerTestSuite) TestAssignBooking() {
 	b := &domain.Booking{FirstName: "AssignMe", Assigned: false}
-	s.usecase.Create(b)
+	s.usecase.Create(context.Background(), b)
 	req := httptest.NewRequest("PUT", "/bookings/1/assign", nil)
 	w := httptest.NewRecorder()
 	s.engine.ServeHTTP(w, req)
@@ -143,6 +191,25 @@ func (s *BookingControllerTestSuite) TestAssignBooking() {
 	s.True(resp.Assigned)
 }
 
+func (s *BookingControllerTestSuite) TestAssignBookingWithTutor() {
+	s.usecase.Create(context.Background(), &domain.Booking{FirstName: "AssignMe"})
+	req := httptest.NewRequest("PUT", "/bookings/1/assign", bytes.NewBufferString(`{"tutor_id": 7}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	var resp domain.Booking
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	s.Require().NotNil(resp.TutorID)
+	s.Equal(uint(7), *resp.TutorID)
+
+	req = httptest.NewRequest("PUT", "/bookings/1/assign", bytes.NewBufferString(`{"tutor_id": "x"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (s *BookingControllerTestSuite) TestGetBookingNotFound() {
 	req := httptest.NewRequest("GET", "/bookings/999", nil)
 	w := httptest.NewRecorder()
@@ -156,3 +223,40 @@ func (s *BookingControllerTestSuite) TestAssignBookingNotFound() {
 	s.engine.ServeHTTP(w, req)
 	s.Equal(http.StatusNotFound, w.Code)
 }
+
+func (s *BookingControllerTestSuite) TestQuarantine() {
+	// Clients can't quarantine or un-quarantine themselves
+	req := httptest.NewRequest("POST", "/bookings", bytes.NewBufferString(`{"first_name": "Real", "quarantined": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+	s.Equal(http.StatusCreated, w.Code)
+
+	req = httptest.NewRequest("POST", "/flagged/bookings", bytes.NewBufferString(`{"first_name": "Bot"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+	s.Equal(http.StatusCreated, w.Code)
+	var flagged domain.Booking
+	json.Unmarshal(w.Body.Bytes(), &flagged)
+	s.True(flagged.Quarantined)
+	s.Equal("honeypot", flagged.QuarantineReason)
+
+	list := func(path string) []domain.Booking {
+		w := httptest.NewRecorder()
+		s.engine.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		s.Equal(http.StatusOK, w.Code)
+		var resp domain.MultipleBookingResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Data
+	}
+	s.Len(list("/bookings"), 1)
+	held := list("/bookings/quarantined")
+	s.Require().Len(held, 1)
+	s.Equal("Bot", held[0].FirstName)
+
+	w = httptest.NewRecorder()
+	s.engine.ServeHTTP(w, httptest.NewRequest("PUT", fmt.Sprintf("/bookings/%d/release", flagged.ID), nil))
+	s.Equal(http.StatusOK, w.Code)
+	s.Len(list("/bookings"), 2)
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/server/middlewares"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bulk applies one action to many bookings
+// @Summary Bulk action on bookings
+// @Description Assign, change the status of, delete or restore many bookings in one transaction. Pick them with ids or with a filter written like the list query string, e.g. "gender=female&min_grade=9", adding "deleted=true" to pick from the trash. Assign takes an optional tutor_id and notifies parents like the single assign; status takes pending or assigned. Delete moves bookings to the trash, from where restore brings them back until it is purged. The result lists every booking as updated, unchanged or failed with the reason; failures don't stop the others being saved, and the response is 207 when there are any (superadmin only)
+// @Tags Bookings
+// @Accept json
+// @Produce json
+// @Param request body domain.BulkActionRequest true "Action and selection"
+// @Success 200 {object} domain.BulkResult
+// @Success 207 {object} domain.BulkResult
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/bulk [post]
+func (c *BookingController) Bulk(ctx *gin.Context) {
+	req, query, ok := bindBulk(ctx)
+	if !ok {
+		return
+	}
+	var filter *domain.BookingFilter
+	if query != nil {
+		filter = bookingFilter(query, query.Get("quarantined") == "true")
+		filter.Deleted = query.Get("deleted") == "true"
+	}
+	result, err := c.u.Bulk(ctx.Request.Context(), &domain.BookingBulkRequest{
+		Action:  req.Action,
+		IDs:     req.IDs,
+		Filter:  filter,
+		TutorID: req.TutorID,
+		Status:  req.Status,
+	})
+	bulkResponse(ctx, result, err)
+}
+
+// Bulk applies one action to many tutors
+// @Summary Bulk action on tutors
+// @Description Verify, unverify, delete or restore many tutors in one transaction. Pick them with ids or with a filter written like the list query string, e.g. "education_level=BSc&min_day_per_week=3", adding "deleted=true" to pick from the trash. Verify notifies tutors like the single verify. Delete moves tutors to the trash, from where restore brings them back until it is purged. The result lists every tutor as updated, unchanged or failed with the reason; failures don't stop the others being saved, and the response is 207 when there are any (admin only)
+// @Tags Tutors
+// @Accept json
+// @Produce json
+// @Param request body domain.BulkActionRequest true "Action and selection"
+// @Success 200 {object} domain.BulkResult
+// @Success 207 {object} domain.BulkResult
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/bulk [post]
+func (c *TutorController) Bulk(ctx *gin.Context) {
+	req, query, ok := bindBulk(ctx)
+	if !ok {
+		return
+	}
+	var filter *domain.TutorFilter
+	if query != nil {
+		filter = tutorFilter(query, query.Get("quarantined") == "true")
+		filter.Deleted = query.Get("deleted") == "true"
+	}
+	result, err := c.u.Bulk(ctx.Request.Context(), &domain.TutorBulkRequest{
+		Action: req.Action,
+		IDs:    req.IDs,
+		Filter: filter,
+	})
+	bulkResponse(ctx, result, err)
+}
+
+// bindBulk reads a bulk action request along with its filter, which is
+// nil when none was given. It writes the error response itself.
+func bindBulk(ctx *gin.Context) (*domain.BulkActionRequest, url.Values, bool) {
+	var req domain.BulkActionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid request body"})
+		return nil, nil, false
+	}
+	if req.Filter == "" {
+		return &req, nil, true
+	}
+	query, err := url.ParseQuery(req.Filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid filter"})
+		return nil, nil, false
+	}
+	return &req, query, true
+}
+
+func bulkResponse(ctx *gin.Context, result *domain.BulkResult, err error) {
+	if result != nil {
+		ctx.Set(middlewares.AuditBulkKey, result)
+	}
+	switch {
+	case errors.Is(err, domain.ErrTooManyItems):
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{
+			Message: fmt.Sprintf("%s, at most %d can be changed at once", err, domain.MaxBulkItems),
+		})
+	case errors.Is(err, domain.ErrNoSelection), errors.Is(err, domain.ErrUnknownAction),
+		errors.Is(err, domain.ErrInvalidStatus), errors.Is(err, domain.ErrInvalidInput):
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, domain.ErrNotFound):
+		// Only the tutor to assign is looked up up front
+		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found"})
+	case err != nil:
+		internalError(ctx, "Failed to apply the bulk action", err)
+	case result.Failed > 0:
+		ctx.JSON(http.StatusMultiStatus, result)
+	default:
+		ctx.JSON(http.StatusOK, result)
+	}
+}
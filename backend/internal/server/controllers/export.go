@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/export"
+	"hiyab-tutor/internal/logging"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController struct {
+	u domain.ExportUsecase
+}
+
+func NewExportController(u domain.ExportUsecase) *ExportController {
+	return &ExportController{u: u}
+}
+
+// Bookings exports the bookings as a spreadsheet
+// @Summary Export bookings
+// @Description Download the bookings matching the list filters as CSV or XLSX, in ID order. Exports larger than EXPORT_SYNC_LIMIT rows, or any export with async=true, are generated in the background: the response is then 202 with the export, whose Location can be polled until it is ready to download (superadmin only)
+// @Tags Bookings
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param format query string false "csv or xlsx" default(csv)
+// @Param columns query string false "Comma separated columns, all of them by default"
+// @Param async query bool false "Always generate in the background"
+// @Param quarantined query bool false "Export the quarantined bookings instead"
+// @Param query query string false "Search name, phone or address"
+// @Param gender query string false "Gender"
+// @Param assigned query bool false "Assigned"
+// @Param address query string false "Address"
+// @Param min_grade query int false "Lowest grade"
+// @Param max_grade query int false "Highest grade"
+// @Success 200 {file} file
+// @Success 202 {object} domain.Export
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/export [get]
+func (c *ExportController) Bookings(ctx *gin.Context) {
+	req := exportRequest(ctx, domain.ExportBookings)
+	req.Bookings = bookingFilter(ctx.Request.URL.Query(), ctx.Query("quarantined") == "true")
+	c.export(ctx, req)
+}
+
+// Tutors exports the tutors as a spreadsheet
+// @Summary Export tutors
+// @Description Download the tutors matching the list filters as CSV or XLSX, in ID order. Exports larger than EXPORT_SYNC_LIMIT rows, or any export with async=true, are generated in the background: the response is then 202 with the export, whose Location can be polled until it is ready to download (admin only)
+// @Tags Tutors
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param format query string false "csv or xlsx" default(csv)
+// @Param columns query string false "Comma separated columns, all of them by default"
+// @Param async query bool false "Always generate in the background"
+// @Param quarantined query bool false "Export the quarantined tutors instead"
+// @Param query query string false "Search name, email or phone"
+// @Param education_level query string false "Education level"
+// @Param verified query bool false "Verified"
+// @Success 200 {file} file
+// @Success 202 {object} domain.Export
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/export [get]
+func (c *ExportController) Tutors(ctx *gin.Context) {
+	req := exportRequest(ctx, domain.ExportTutors)
+	req.Tutors = tutorFilter(ctx.Request.URL.Query(), ctx.Query("quarantined") == "true")
+	c.export(ctx, req)
+}
+
+// GetByID shows an asynchronous export
+// @Summary Get an export
+// @Description Get the status of an export generated in the background. Only the admin who asked for it and superadmins can see it (admin only)
+// @Tags Exports
+// @Produce json
+// @Param id path int true "Export ID"
+// @Success 200 {object} domain.Export
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /exports/{id} [get]
+func (c *ExportController) GetByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	e, err := c.u.GetByID(ctx.Request.Context(), uint(id))
+	if err == nil && !canDownload(ctx, e) {
+		err = domain.ErrNotFound
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Export not found"})
+			return
+		}
+		internalError(ctx, "Failed to fetch export", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, e)
+}
+
+// Download sends the file of an asynchronous export
+// @Summary Download an export
+// @Description Download the file of an export once it is ready, until it expires. Only the admin who asked for it and superadmins can download it (admin only)
+// @Tags Exports
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param id path int true "Export ID"
+// @Success 200 {file} file
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /exports/{id}/download [get]
+func (c *ExportController) Download(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	// Checked before opening so others can't tell whether it is ready
+	e, err := c.u.GetByID(ctx.Request.Context(), uint(id))
+	if err == nil && !canDownload(ctx, e) {
+		err = domain.ErrNotFound
+	}
+	var file io.ReadCloser
+	if err == nil {
+		e, file, err = c.u.Open(ctx.Request.Context(), uint(id))
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Export not found"})
+		case errors.Is(err, domain.ErrExportNotReady):
+			ctx.JSON(http.StatusConflict, domain.ErrorResponse{Message: err.Error()})
+		default:
+			internalError(ctx, "Failed to open export", err)
+		}
+		return
+	}
+	defer file.Close()
+	attachment(ctx, e.Entity, e.Format, e.CreatedAt)
+	ctx.Status(http.StatusOK)
+	if _, err := io.Copy(ctx.Writer, file); err != nil {
+		logging.FromContext(ctx.Request.Context()).Error("export download failed", "export_id", e.ID, "error", err)
+	}
+}
+
+// export streams req, or queues it when it is large or async is asked for.
+func (c *ExportController) export(ctx *gin.Context, req *domain.ExportRequest) {
+	// Also checks the request, so nothing is sent before it is known good
+	large, err := c.u.Large(ctx.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidFormat), errors.Is(err, domain.ErrUnknownColumn):
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		default:
+			internalError(ctx, "Failed to export", err)
+		}
+		return
+	}
+	if large || ctx.Query("async") == "true" {
+		e, err := c.u.Start(ctx.Request.Context(), req)
+		if err != nil {
+			internalError(ctx, "Failed to queue export", err)
+			return
+		}
+		ctx.Header("Location", fmt.Sprintf("/api/v1/exports/%d", e.ID))
+		ctx.JSON(http.StatusAccepted, e)
+		return
+	}
+
+	attachment(ctx, req.Entity, req.Format, time.Now())
+	ctx.Status(http.StatusOK)
+	if err := c.u.Write(ctx.Request.Context(), req, ctx.Writer); err != nil {
+		// Headers are already sent, so all we can do is log
+		logging.FromContext(ctx.Request.Context()).Error("export failed", "entity", req.Entity, "error", err)
+	}
+}
+
+// exportRequest reads the format and columns of an export of entity.
+func exportRequest(ctx *gin.Context, entity string) *domain.ExportRequest {
+	req := &domain.ExportRequest{Entity: entity, Format: strings.ToLower(ctx.DefaultQuery("format", domain.FormatCSV))}
+	for _, name := range strings.Split(ctx.Query("columns"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			req.Columns = append(req.Columns, name)
+		}
+	}
+	if v, ok := ctx.Get("userID"); ok {
+		req.RequestedBy, _ = v.(uint)
+	}
+	return req
+}
+
+// canDownload reports whether the caller asked for e or is a superadmin.
+func canDownload(ctx *gin.Context, e *domain.Export) bool {
+	if ctx.GetString("role") == "superadmin" {
+		return true
+	}
+	id, _ := ctx.Get("userID")
+	return id == e.RequestedBy
+}
+
+// attachment sets the headers of a file download named after entity and
+// the day it was made.
+func attachment(ctx *gin.Context, entity, format string, made time.Time) {
+	ctx.Header("Content-Type", export.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.%s", entity, made.UTC().Format(time.DateOnly), format))
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize is the largest sheet an import accepts
+const maxImportSize = 10 << 20 // 10 MiB
+
+type ImportController struct {
+	u domain.ImportUsecase
+}
+
+func NewImportController(u domain.ImportUsecase) *ImportController {
+	return &ImportController{u: u}
+}
+
+// Bookings imports bookings from a spreadsheet
+// @Summary Import bookings
+// @Description Add bookings in bulk from a CSV or XLSX sheet whose header row names the columns: first_name, grade and phone_number are required, last_name, gender, age, address, day_per_week and hr_per_day optional; the extra columns of an export are ignored. Every row is checked (phone number, grade 1-12, duplicates in the sheet and against open bookings) and the report lists the problems per row. By default this is a dry run; with dry_run=false all rows are saved in one transaction, or none of them if any is invalid (superadmin only)
+// @Tags Bookings
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX sheet"
+// @Param format query string false "csv or xlsx, taken from the file name by default"
+// @Param dry_run query bool false "Only check the rows" default(true)
+// @Success 200 {object} domain.ImportReport
+// @Success 201 {object} domain.ImportReport
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
+// @Failure 413 {object} domain.ErrorResponse
+// @Failure 422 {object} domain.ImportReport
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /bookings/import [post]
+func (c *ImportController) Bookings(ctx *gin.Context) {
+	c.importSheet(ctx, domain.ExportBookings)
+}
+
+// Tutors imports tutors from a spreadsheet
+// @Summary Import tutors
+// @Description Add tutors in bulk from a CSV or XLSX sheet whose header row names the columns: first_name, phone_number, email and education_level are required, last_name, address, day_per_week, hr_per_day and document_expires_at optional; the extra columns of an export are ignored. Documents and photos can't be imported. Every row is checked (phone number, email, duplicates in the sheet and against registered tutors) and the report lists the problems per row. By default this is a dry run; with dry_run=false all rows are saved in one transaction, or none of them if any is invalid (admin only)
+// @Tags Tutors
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX sheet"
+// @Param format query string false "csv or xlsx, taken from the file name by default"
+// @Param dry_run query bool false "Only check the rows" default(true)
+// @Success 200 {object} domain.ImportReport
+// @Success 201 {object} domain.ImportReport
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
+// @Failure 413 {object} domain.ErrorResponse
+// @Failure 422 {object} domain.ImportReport
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/import [post]
+func (c *ImportController) Tutors(ctx *gin.Context) {
+	c.importSheet(ctx, domain.ExportTutors)
+}
+
+func (c *ImportController) importSheet(ctx *gin.Context, entity string) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, domain.ErrorResponse{Message: "File is too large"})
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "File is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		internalError(ctx, "Failed to read file", err)
+		return
+	}
+	defer file.Close()
+
+	format := strings.ToLower(ctx.Query("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+	}
+	dryRun := ctx.Query("dry_run") != "false"
+	report, err := c.u.Import(ctx.Request.Context(), &domain.ImportRequest{Entity: entity, Format: format, File: file, DryRun: dryRun})
+	switch {
+	case errors.Is(err, domain.ErrImportRejected):
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+	case err != nil:
+		if errors.Is(err, domain.ErrInvalidFormat) || errors.Is(err, domain.ErrUnknownColumn) || errors.Is(err, domain.ErrMissingColumn) ||
+			errors.Is(err, domain.ErrEmptyImport) || errors.Is(err, domain.ErrTooManyRows) {
+			ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrDuplicateBooking) || errors.Is(err, domain.ErrDuplicateTutor) {
+			ctx.JSON(http.StatusConflict, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to import", err)
+	case dryRun:
+		ctx.JSON(http.StatusOK, report)
+	default:
+		ctx.JSON(http.StatusCreated, report)
+	}
+}
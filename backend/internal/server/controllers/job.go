@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobController struct {
+	u domain.JobUsecase
+}
+
+func NewJobController(u domain.JobUsecase) *JobController {
+	return &JobController{u: u}
+}
+
+// GetAll lists background jobs
+// @Summary List background jobs
+// @Description List background jobs with their state, newest first (superadmin only)
+// @Tags Jobs
+// @Produce json
+// @Param status query string false "Status (pending, running, done, dead)"
+// @Param type query string false "Job type, e.g. files.remove"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} domain.MultipleJobs
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /jobs [get]
+func (c *JobController) GetAll(ctx *gin.Context) {
+	filter := &domain.JobFilter{
+		Status: ctx.Query("status"),
+		Type:   ctx.Query("type"),
+	}
+	if v := ctx.Query("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Page = n
+		}
+	}
+	if v := ctx.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Limit = n
+		}
+	}
+	resp, err := c.u.GetAll(ctx.Request.Context(), filter)
+	if err != nil {
+		internalError(ctx, "Failed to fetch jobs", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// GetByID shows one background job
+// @Summary Get a background job
+// @Description Get a job with its payload and last error (superadmin only)
+// @Tags Jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} domain.Job
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /jobs/{id} [get]
+func (c *JobController) GetByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	job, err := c.u.GetByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Job not found"})
+			return
+		}
+		internalError(ctx, "Failed to fetch job", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, job)
+}
+
+// Retry queues a background job again
+// @Summary Retry a background job
+// @Description Queue a dead job again with fresh attempts, or run a pending one right away (superadmin only)
+// @Tags Jobs
+// @Produce json
+// @Param id path int true "Job ID"
+// @Success 200 {object} domain.Job
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /jobs/{id}/retry [post]
+func (c *JobController) Retry(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	job, err := c.u.Retry(ctx.Request.Context(), uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Job not found"})
+		case errors.Is(err, domain.ErrJobNotRetryable):
+			ctx.JSON(http.StatusConflict, domain.ErrorResponse{Message: err.Error()})
+		default:
+			internalError(ctx, "Failed to retry job", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, job)
+}
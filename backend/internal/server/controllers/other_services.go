@@ -15,10 +15,11 @@ import (
 
 type OtherServiceController struct {
 	usecase domain.OtherServiceUsecase
+	files   domain.FileStorage
 }
 
-func NewOtherServiceController(u domain.OtherServiceUsecase) *OtherServiceController {
-	return &OtherServiceController{usecase: u}
+func NewOtherServiceController(u domain.OtherServiceUsecase, files domain.FileStorage) *OtherServiceController {
+	return &OtherServiceController{usecase: u, files: files}
 }
 
 // Create creates a new other service
@@ -45,16 +46,16 @@ func (c *OtherServiceController) Create(ctx *gin.Context) {
 		return
 	}
 	fileName := fmt.Sprintf("services-%d%s", time.Now().Unix(), path.Ext(imageFile.Filename))
-	imageURL := path.Join("uploads", "images", fileName)
-	if err := ctx.SaveUploadedFile(imageFile, imageURL); err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error saving the image"})
+	imageURL, err := c.files.Save(imageFile, "images", fileName)
+	if err != nil {
+		internalError(ctx, "error saving the image", err)
 		return
 	}
 	service := &domain.OtherService{
 		WebsiteURL: req.WebsiteURL,
 		Image:      imageURL,
 	}
-	created, err := c.usecase.CreateService(service)
+	created, err := c.usecase.CreateService(ctx.Request.Context(), service)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service"})
 		return
@@ -86,7 +87,7 @@ func (c *OtherServiceController) GetByID(ctx *gin.Context) {
 			languages = strings.Split(raw, ",")
 		}
 	}
-	svc, err := c.usecase.GetServiceByID(uint(idUint), languages)
+	svc, err := c.usecase.GetServiceByID(ctx.Request.Context(), uint(idUint), languages)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
@@ -128,7 +129,7 @@ func (c *OtherServiceController) GetAll(ctx *gin.Context) {
 		SortOrder:     sortOrder,
 		LanguageCodes: languages,
 	}
-	result, err := c.usecase.GetAllServices(filter)
+	result, err := c.usecase.GetAllServices(ctx.Request.Context(), filter)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch services"})
 		return
@@ -162,7 +163,7 @@ func (c *OtherServiceController) Update(ctx *gin.Context) {
 		return
 	}
 	req.ID = uint(idUint)
-	updated, err := c.usecase.UpdateService(&req)
+	updated, err := c.usecase.UpdateService(ctx.Request.Context(), &req)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
@@ -187,7 +188,7 @@ func (c *OtherServiceController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	if err := c.usecase.DeleteService(uint(idUint)); err != nil {
+	if err := c.usecase.DeleteService(ctx.Request.Context(), uint(idUint)); err != nil {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
 	}
@@ -211,7 +212,7 @@ func (c *OtherServiceController) AddTranslation(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
-	_, err = c.usecase.GetServiceByID(uint(idUint), nil)
+	_, err = c.usecase.GetServiceByID(ctx.Request.Context(), uint(idUint), nil)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "service not found"})
 		return
@@ -221,9 +222,9 @@ func (c *OtherServiceController) AddTranslation(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "error parsing translation"})
 		return
 	}
-	s, err := c.usecase.AddTranslation(uint(idUint), t)
+	s, err := c.usecase.AddTranslation(ctx.Request.Context(), uint(idUint), t)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		internalError(ctx, err.Error(), err)
 		return
 	}
 	ctx.JSON(http.StatusOK, s)
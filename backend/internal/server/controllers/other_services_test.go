@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/storage"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -22,11 +24,11 @@ func TestOtherServicesController(t *testing.T) {
 
 func (s *TestOtherServicesSuite) TestCreate() {
 	mockUsecase := domain.OtherServiceUsecaseMock{
-		CreateServiceFunc: func(service *domain.OtherService) (*domain.OtherService, error) {
+		CreateServiceFunc: func(_ context.Context, service *domain.OtherService) (*domain.OtherService, error) {
 			return service, nil
 		},
 	}
-	controller := NewOtherServiceController(&mockUsecase)
+	controller := NewOtherServiceController(&mockUsecase, storage.NewLocal(s.T().TempDir()))
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	writer.WriteField("website_url", "https://somename.com")
@@ -12,13 +12,15 @@ import (
 )
 
 type PartnerController struct {
-	u domain.PartnerUsecase
+	u     domain.PartnerUsecase
+	files domain.FileStorage
 }
 
 // NewPartnerController creates a new PartnerController
-func NewPartnerController(u domain.PartnerUsecase) *PartnerController {
+func NewPartnerController(u domain.PartnerUsecase, files domain.FileStorage) *PartnerController {
 	return &PartnerController{
-		u: u,
+		u:     u,
+		files: files,
 	}
 }
 
@@ -66,10 +68,10 @@ func (c *PartnerController) GetAll(ctx *gin.Context) {
 		filter.Offset = offset
 	}
 
-	partners, err := c.u.GetAllPartners(filter)
+	partners, err := c.u.GetAllPartners(ctx.Request.Context(), filter)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		internalError(ctx, err.Error(), err)
 		return
 	}
 	resp := domain.MultiplePartnersResponse{
@@ -98,12 +100,12 @@ func (c *PartnerController) GetByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partner ID"})
 		return
 	}
-	partner, err := c.u.GetPartnerByID(uint(id))
+	partner, err := c.u.GetPartnerByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Partner not found"})
 		} else {
-			ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+			internalError(ctx, err.Error(), err)
 		}
 		return
 	}
@@ -133,18 +135,18 @@ func (c *PartnerController) Create(ctx *gin.Context) {
 		return
 	}
 	fileName := fmt.Sprintf("partners-%d%s", time.Now().Unix(), path.Ext(imageFile.Filename))
-	imageURL := path.Join("uploads", "images", fileName)
-	if err := ctx.SaveUploadedFile(imageFile, imageURL); err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error uploading the image"})
+	imageURL, err := c.files.Save(imageFile, "images", fileName)
+	if err != nil {
+		internalError(ctx, "error uploading the image", err)
 		return
 	}
-	createdPartner, err := c.u.CreatePartner(&domain.Partner{
+	createdPartner, err := c.u.CreatePartner(ctx.Request.Context(), &domain.Partner{
 		Name:       req.Name,
 		ImageURL:   imageURL,
 		WebsiteURL: req.WebsiteURL,
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to create partner"})
+		internalError(ctx, "Failed to create partner", err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, domain.PartnerResponse{ID: createdPartner.ID, Name: createdPartner.Name, ImageURL: createdPartner.ImageURL, WebsiteURL: createdPartner.WebsiteURL})
@@ -169,7 +171,7 @@ func (c *PartnerController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partner ID"})
 		return
 	}
-	_, err = c.u.GetPartnerByID(uint(id))
+	_, err = c.u.GetPartnerByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "partner not found"})
 		return
@@ -187,16 +189,16 @@ func (c *PartnerController) Update(ctx *gin.Context) {
 	}
 	if imageFile != nil {
 		fileName := fmt.Sprintf("partners-%d%s", time.Now().Unix(), path.Ext(imageFile.Filename))
-		imageURL := path.Join("uploads", "images", fileName)
-		if err := ctx.SaveUploadedFile(imageFile, imageURL); err != nil {
-			ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error uploading the image"})
+		imageURL, err := c.files.Save(imageFile, "images", fileName)
+		if err != nil {
+			internalError(ctx, "error uploading the image", err)
 			return
 		}
 		partner.ImageURL = imageURL
 	}
-	updatedPartner, err := c.u.UpdatePartner(partner)
+	updatedPartner, err := c.u.UpdatePartner(ctx.Request.Context(), partner)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to update partner"})
+		internalError(ctx, "Failed to update partner", err)
 		return
 	}
 	ctx.JSON(http.StatusOK, domain.PartnerResponse{ID: updatedPartner.ID, Name: updatedPartner.Name, ImageURL: updatedPartner.ImageURL, WebsiteURL: updatedPartner.WebsiteURL})
@@ -218,9 +220,9 @@ func (c *PartnerController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid partner ID"})
 		return
 	}
-	err = c.u.DeletePartner(uint(id))
+	err = c.u.DeletePartner(ctx.Request.Context(), uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to delete partner"})
+		internalError(ctx, "Failed to delete partner", err)
 		return
 	}
 	ctx.Status(http.StatusNoContent)
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"hiyab-tutor/internal/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerController struct {
+	u domain.SchedulerUsecase
+}
+
+func NewSchedulerController(u domain.SchedulerUsecase) *SchedulerController {
+	return &SchedulerController{u: u}
+}
+
+// GetAll lists the maintenance tasks
+// @Summary List scheduled tasks
+// @Description List the maintenance tasks with their schedule, next run and the outcome of their last run (superadmin only)
+// @Tags Scheduler
+// @Produce json
+// @Success 200 {array} domain.ScheduledTask
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /scheduler/tasks [get]
+func (c *SchedulerController) GetAll(ctx *gin.Context) {
+	tasks, err := c.u.GetAll(ctx.Request.Context())
+	if err != nil {
+		internalError(ctx, "Failed to fetch scheduled tasks", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tasks)
+}
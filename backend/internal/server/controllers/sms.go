@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SMSController struct {
+	u domain.SMSUsecase
+}
+
+func NewSMSController(u domain.SMSUsecase) *SMSController {
+	return &SMSController{u: u}
+}
+
+// GetAll lists outgoing text messages
+// @Summary List text messages
+// @Description List outgoing SMS with their delivery status, newest first (superadmin only)
+// @Tags SMS
+// @Produce json
+// @Param status query string false "Status (pending, sent, failed)"
+// @Param to query string false "Recipient phone number"
+// @Param page query int false "Page number"
+// @Param limit query int false "Number of results per page"
+// @Success 200 {object} domain.MultipleSMSMessages
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /sms [get]
+func (c *SMSController) GetAll(ctx *gin.Context) {
+	filter := &domain.SMSFilter{
+		Status: ctx.Query("status"),
+		To:     ctx.Query("to"),
+	}
+	if v := ctx.Query("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Page = n
+		}
+	}
+	if v := ctx.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			filter.Limit = n
+		}
+	}
+	resp, err := c.u.GetAll(ctx.Request.Context(), filter)
+	if err != nil {
+		internalError(ctx, "Failed to fetch messages", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// Retry sends a text message again
+// @Summary Retry a text message
+// @Description Send a pending or failed SMS again right away (superadmin only)
+// @Tags SMS
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} domain.SMSMessage
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /sms/{id}/retry [post]
+func (c *SMSController) Retry(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	msg, err := c.u.Retry(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Message not found"})
+			return
+		}
+		internalError(ctx, "Failed to retry message", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, msg)
+}
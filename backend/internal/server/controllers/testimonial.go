@@ -3,8 +3,8 @@ package controllers
 import (
 	"fmt"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/storage"
 	"net/http"
-	"os"
 	"path"
 	"strconv"
 	"time"
@@ -14,18 +14,19 @@ import (
 
 type TestimonialController struct {
 	// Add any dependencies needed for the controller
-	u domain.TestimonialUsecase
+	u     domain.TestimonialUsecase
+	files domain.FileStorage
 }
 
+// NewTestimonialController keeps uploads in ./uploads.
 func NewTestimonialController(u domain.TestimonialUsecase) *TestimonialController {
-	return &TestimonialController{
-		u: u,
-	}
+	return NewTestimonialControllerWithStorage(u, storage.NewLocal(storage.URLPrefix))
 }
 
-func NewTestimonialControllerWithStorage(u domain.TestimonialUsecase) *TestimonialController {
+func NewTestimonialControllerWithStorage(u domain.TestimonialUsecase, files domain.FileStorage) *TestimonialController {
 	return &TestimonialController{
-		u: u,
+		u:     u,
+		files: files,
 	}
 }
 
@@ -54,18 +55,18 @@ func (c *TestimonialController) Create(ctx *gin.Context) {
 	var videoURL string
 	if video, err := ctx.FormFile("video"); err == nil {
 		fileName := fmt.Sprintf("testimonials-%d%s", time.Now().Unix(), path.Ext(video.Filename))
-		videoURL = path.Join("uploads", "videos", fileName)
-		if err := ctx.SaveUploadedFile(video, videoURL); err != nil {
-			ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to save video"})
+		videoURL, err = c.files.Save(video, "videos", fileName)
+		if err != nil {
+			internalError(ctx, "Failed to save video", err)
 			return
 		}
 	}
 	var thumbnailURL string
 	if thumbnail, err := ctx.FormFile("thumbnail"); err == nil {
 		fileName := fmt.Sprintf("testimonials-%d%s", time.Now().Unix(), path.Ext(thumbnail.Filename))
-		thumbnailURL = path.Join("uploads", "thumbnails", fileName)
-		if err := ctx.SaveUploadedFile(thumbnail, thumbnailURL); err != nil {
-			ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to save thumbnail"})
+		thumbnailURL, err = c.files.Save(thumbnail, "thumbnails", fileName)
+		if err != nil {
+			internalError(ctx, "Failed to save thumbnail", err)
 			return
 		}
 	}
@@ -75,9 +76,9 @@ func (c *TestimonialController) Create(ctx *gin.Context) {
 		Video:     videoURL,
 		Thumbnail: thumbnailURL,
 	}
-	createdTestimonial, err := c.u.CreateTestimonial(testimonial)
+	createdTestimonial, err := c.u.CreateTestimonial(ctx.Request.Context(), testimonial)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to create testimonial"})
+		internalError(ctx, "Failed to create testimonial", err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, createdTestimonial)
@@ -102,7 +103,7 @@ func (c *TestimonialController) GetByID(ctx *gin.Context) {
 		return
 	}
 
-	testimonial, err := c.u.GetTestimonialByID(uint(id))
+	testimonial, err := c.u.GetTestimonialByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(404, domain.ErrorResponse{Message: "Testimonial not found"})
 		return
@@ -145,9 +146,9 @@ func (c *TestimonialController) GetAll(ctx *gin.Context) {
 	}
 	var err error
 	var resp *domain.MultipleTestimonialResponse
-	resp, err = c.u.GetAllTestimonials(filter)
+	resp, err = c.u.GetAllTestimonials(ctx.Request.Context(), filter)
 	if err != nil {
-		ctx.JSON(500, domain.ErrorResponse{Message: "Failed to fetch testimonials"})
+		internalError(ctx, "Failed to fetch testimonials", err)
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -184,7 +185,7 @@ func (c *TestimonialController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
 		return
 	}
-	testimonial, err := c.u.GetTestimonialByID(uint(id))
+	testimonial, err := c.u.GetTestimonialByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Testimonial not found"})
 		return
@@ -194,16 +195,16 @@ func (c *TestimonialController) Update(ctx *gin.Context) {
 		videoFile, err := ctx.FormFile("video")
 		if err == nil {
 			if testimonial.Video != "" {
-				err := os.Remove(testimonial.Video)
+				err := c.files.Remove(testimonial.Video)
 				if err != nil {
-					ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "failed to remove old video"})
+					internalError(ctx, "failed to remove old video", err)
 					return
 				}
 			}
 			fileName := fmt.Sprintf("%d%s", time.Now().Unix(), path.Ext(videoFile.Filename))
-			videoURL = path.Join("uploads", "videos", fileName)
-			if err = ctx.SaveUploadedFile(videoFile, videoURL); err != nil {
-				ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "failed to upload video"})
+			videoURL, err = c.files.Save(videoFile, "videos", fileName)
+			if err != nil {
+				internalError(ctx, "failed to upload video", err)
 				return
 			}
 		}
@@ -213,16 +214,16 @@ func (c *TestimonialController) Update(ctx *gin.Context) {
 		videoFile, err := ctx.FormFile("thumbnail")
 		if err == nil {
 			if testimonial.Thumbnail != "" {
-				err := os.Remove(testimonial.Thumbnail)
+				err := c.files.Remove(testimonial.Thumbnail)
 				if err != nil {
-					ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "failed to remove old thumbnail"})
+					internalError(ctx, "failed to remove old thumbnail", err)
 					return
 				}
 			}
 			fileName := fmt.Sprintf("%d%s", time.Now().Unix(), path.Ext(videoFile.Filename))
-			thumbnailURL = path.Join("uploads", "thumbnails", fileName)
-			if err = ctx.SaveUploadedFile(videoFile, thumbnailURL); err != nil {
-				ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "failed to upload thumbnail"})
+			thumbnailURL, err = c.files.Save(videoFile, "thumbnails", fileName)
+			if err != nil {
+				internalError(ctx, "failed to upload thumbnail", err)
 				return
 			}
 		}
@@ -234,7 +235,7 @@ func (c *TestimonialController) Update(ctx *gin.Context) {
 	if thumbnailURL != "" {
 		t.Thumbnail = thumbnailURL
 	}
-	updated, err := c.u.UpdateTestimonial(&t)
+	updated, err := c.u.UpdateTestimonial(ctx.Request.Context(), &t)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Testimonial not found"})
 		return
@@ -262,7 +263,7 @@ func (c *TestimonialController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	err = c.u.DeleteTestimonial(uint(id))
+	err = c.u.DeleteTestimonial(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(404, domain.ErrorResponse{Message: "Testimonial not found"})
 		return
@@ -297,7 +298,7 @@ func (c *TestimonialController) AddTranslation(ctx *gin.Context) {
 		return
 	}
 
-	updated, err := c.u.AddTranslation(uint(id), &translation)
+	updated, err := c.u.AddTranslation(ctx.Request.Context(), uint(id), &translation)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Testimonial not found"})
 		return
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"hiyab-tutor/internal/domain"
 	"log"
@@ -38,7 +39,7 @@ func (s *TestTestimonialControllerSuite) TestCreate() {
 		Role: "Test Role",
 	}
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		CreateTestimonialFunc: func(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+		CreateTestimonialFunc: func(_ context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
 			return testimonial, nil
 		},
 	} // Assuming you have a mock usecase
@@ -85,7 +86,7 @@ func (s *TestTestimonialControllerSuite) TestGetAll() {
 		{Model: domain.Model{ID: 2, CreatedAt: now, UpdatedAt: now}, Name: "Testimonial 2", Role: "Role 2", Video: "http://video2", Thumbnail: "http://thumb2"},
 	}
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		GetAllTestimonialsFunc: func(filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
+		GetAllTestimonialsFunc: func(_ context.Context, filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
 			return &domain.MultipleTestimonialResponse{
 				Testimonials: testimonials,
 			}, nil
@@ -115,7 +116,7 @@ func (s *TestTestimonialControllerSuite) TestGetByID() {
 	}
 	testimonialJSON, _ := json.Marshal(testimonial)
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		GetTestimonialByIDFunc: func(id uint) (*domain.Testimonial, error) {
+		GetTestimonialByIDFunc: func(_ context.Context, id uint) (*domain.Testimonial, error) {
 			return testimonial, nil
 		},
 	}
@@ -141,10 +142,10 @@ func (s *TestTestimonialControllerSuite) TestUpdate() {
 		Role: "updated Role",
 	}
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		UpdateTestimonialFunc: func(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+		UpdateTestimonialFunc: func(_ context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
 			return testimonial, nil
 		},
-		GetTestimonialByIDFunc: func(id uint) (*domain.Testimonial, error) {
+		GetTestimonialByIDFunc: func(_ context.Context, id uint) (*domain.Testimonial, error) {
 			return &domain.Testimonial{Name: "Name", Role: "Role"}, nil
 		},
 	}
@@ -180,7 +181,7 @@ func (s *TestTestimonialControllerSuite) TestUpdate() {
 func (s *TestTestimonialControllerSuite) TestDelete() {
 	strId := "1"
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		DeleteTestimonialFunc: func(id uint) error {
+		DeleteTestimonialFunc: func(_ context.Context, id uint) error {
 			return nil
 		},
 	}
@@ -203,7 +204,7 @@ func (s *TestTestimonialControllerSuite) TestAddTranslation() {
 	}
 	testimonialJSON, _ := json.Marshal(testimonial)
 	s.mockUsecase = &domain.TestimonialUsecaseMock{
-		AddTranslationFunc: func(testimonialID uint, translation *domain.TestimonialTranslation) (*domain.Testimonial, error) {
+		AddTranslationFunc: func(_ context.Context, testimonialID uint, translation *domain.TestimonialTranslation) (*domain.Testimonial, error) {
 			testimonial.Translations = append(testimonial.Translations, *translation)
 			return testimonial, nil
 		},
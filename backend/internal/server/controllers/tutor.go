@@ -3,7 +3,10 @@ package controllers
 import (
 	"fmt"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"hiyab-tutor/internal/server/middlewares"
 	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"time"
@@ -12,11 +15,12 @@ import (
 )
 
 type TutorController struct {
-	u domain.TutorUsecase
+	u     domain.TutorUsecase
+	files domain.FileStorage
 }
 
-func NewTutorController(u domain.TutorUsecase) *TutorController {
-	return &TutorController{u: u}
+func NewTutorController(u domain.TutorUsecase, files domain.FileStorage) *TutorController {
+	return &TutorController{u: u, files: files}
 }
 
 // Create handles the creation of a new tutor
@@ -28,6 +32,7 @@ func NewTutorController(u domain.TutorUsecase) *TutorController {
 // @Param tutor body domain.Tutor true "Tutor"
 // @Success 201 {object} domain.Tutor
 // @Failure 400 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Router /tutors [post]
 func (c *TutorController) Create(ctx *gin.Context) {
@@ -41,33 +46,54 @@ func (c *TutorController) Create(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotAcceptable, domain.ErrorResponse{Message: "Document is required"})
 		return
 	}
-	documentName := fmt.Sprintf("tutor-%d%s", time.Now().Unix(), path.Ext(document.Filename))
-	documentPath := path.Join("uploads", "documents", documentName)
-	if err := ctx.SaveUploadedFile(document, documentPath); err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to Upload the document"})
-		return
-	}
 	image, err := ctx.FormFile("image")
 	if err != nil {
 		ctx.JSON(http.StatusNotAcceptable, domain.ErrorResponse{Message: "Image is required"})
 		return
 	}
+	documentName := fmt.Sprintf("tutor-%d%s", time.Now().Unix(), path.Ext(document.Filename))
+	documentPath, err := c.files.Save(document, "documents", documentName)
+	if err != nil {
+		internalError(ctx, "Failed to Upload the document", err)
+		return
+	}
 	imageName := fmt.Sprintf("tutor-%d%s", time.Now().Unix(), path.Ext(image.Filename))
-	imagePath := path.Join("uploads", "images", imageName)
-	if err := ctx.SaveUploadedFile(image, imagePath); err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to Upload the document"})
+	imagePath, err := c.files.Save(image, "images", imageName)
+	if err != nil {
+		c.discard(ctx, documentPath)
+		internalError(ctx, "Failed to Upload the document", err)
 		return
 	}
 	req.Document = documentPath
 	req.Image = imagePath
-	created, err := c.u.Create(&req)
+	if req.Language == "" {
+		req.Language = requestLanguage(ctx)
+	}
+	// Set by the spam guard; never taken from the client
+	req.QuarantineReason = ctx.GetString(middlewares.QuarantineKey)
+	req.Quarantined = req.QuarantineReason != ""
+	created, err := c.u.Create(ctx.Request.Context(), &req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to create tutor"})
+		c.discard(ctx, documentPath, imagePath)
+		if status, ok := phoneErrorStatus(err); ok {
+			ctx.JSON(status, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		internalError(ctx, "Failed to create tutor", err)
 		return
 	}
 	ctx.JSON(http.StatusCreated, created)
 }
 
+// discard removes the uploads of a tutor that wasn't created.
+func (c *TutorController) discard(ctx *gin.Context, paths ...string) {
+	for _, p := range paths {
+		if err := c.files.Remove(p); err != nil {
+			logging.FromContext(ctx.Request.Context()).Warn("failed to remove upload", "path", p, "error", err)
+		}
+	}
+}
+
 // GetAll handles fetching all tutors with optional filters
 // @Summary Get all tutors
 // @Description Get all tutors with optional filters
@@ -76,7 +102,7 @@ func (c *TutorController) Create(ctx *gin.Context) {
 // @Produce json
 // @Param education_level query string false "Education Level"
 // @Param verified query bool false "Verified"
-// @Param query query string false "Search query"
+// @Param query query string false "Words to find in names, email or phone number, matched by prefix and best matches first unless sorted"
 // @Param min_day_per_week query int false "Min Day Per Week"
 // @Param max_day_per_week query int false "Max Day Per Week"
 // @Param min_hr_per_day query int false "Min Hr Per Day"
@@ -85,61 +111,43 @@ func (c *TutorController) Create(ctx *gin.Context) {
 // @Failure 500 {object} domain.ErrorResponse
 // @Router /tutors [get]
 func (c *TutorController) GetAll(ctx *gin.Context) {
-	filter := &domain.TutorFilter{}
-	if v := ctx.Query("education_level"); v != "" {
-		filter.EducationLevel = v
-	}
-	if v := ctx.Query("verified"); v != "" {
-		filter.Verified = v == "true"
-	}
-	// support both `query` and `search` from frontend
-	if v := ctx.Query("query"); v != "" {
-		filter.Query = v
-	}
-	if v := ctx.Query("search"); v != "" {
-		filter.Query = v
-	}
-	// pagination
-	if v := ctx.Query("page"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.Page = val
-		}
-	}
-	if v := ctx.Query("limit"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.Limit = val
-		}
-	}
-	// sorting
-	if v := ctx.Query("sort_by"); v != "" {
-		filter.SortBy = v
-	}
-	if v := ctx.Query("sort_order"); v != "" {
-		filter.SortOrder = v
-	}
-	if v := ctx.Query("min_day_per_week"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.MinDayPerWeek = val
-		}
-	}
-	if v := ctx.Query("max_day_per_week"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.MaxDayPerWeek = val
-		}
-	}
-	if v := ctx.Query("min_hr_per_day"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.MinHrPerDay = val
-		}
-	}
-	if v := ctx.Query("max_hr_per_day"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			filter.MaxHrPerDay = val
-		}
-	}
-	resp, err := c.u.GetAll(filter)
+	c.list(ctx, tutorFilter(ctx.Request.URL.Query(), false))
+}
+
+// GetQuarantined lists the tutor registrations held for review
+// @Summary Get quarantined tutors
+// @Description List tutor registrations held for review by the spam checks (protected). Accepts the same filters as the main list
+// @Tags Tutors
+// @Produce json
+// @Param page query int false "Page number"
+// @Success 200 {object} domain.MultipleTutorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/quarantined [get]
+func (c *TutorController) GetQuarantined(ctx *gin.Context) {
+	c.list(ctx, tutorFilter(ctx.Request.URL.Query(), true))
+}
+
+// GetTrash lists the tutors deleted in bulk
+// @Summary Get deleted tutors
+// @Description List the tutors moved to the trash by a delete, which can be restored until the trash is purged (protected). Accepts the same filters as the main list
+// @Tags Tutors
+// @Produce json
+// @Param page query int false "Page number"
+// @Success 200 {object} domain.MultipleTutorResponse
+// @Failure 500 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/trash [get]
+func (c *TutorController) GetTrash(ctx *gin.Context) {
+	filter := tutorFilter(ctx.Request.URL.Query(), ctx.Query("quarantined") == "true")
+	filter.Deleted = true
+	c.list(ctx, filter)
+}
+
+func (c *TutorController) list(ctx *gin.Context, filter *domain.TutorFilter) {
+	resp, err := c.u.GetAll(ctx.Request.Context(), filter)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "Failed to fetch tutors"})
+		internalError(ctx, "Failed to fetch tutors", err)
 		return
 	}
 	ctx.JSON(http.StatusOK, resp)
@@ -147,7 +155,7 @@ func (c *TutorController) GetAll(ctx *gin.Context) {
 
 // GetByID handles fetching a tutor by ID
 // @Summary Get a tutor by ID
-// @Description Get a tutor by ID
+// @Description Get a tutor by ID. Tutors held for review are only shown to admins
 // @Tags Tutors
 // @Accept json
 // @Produce json
@@ -163,8 +171,8 @@ func (c *TutorController) GetByID(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	tutor, err := c.u.GetByID(uint(id))
-	if err != nil {
+	tutor, err := c.u.GetByID(ctx.Request.Context(), uint(id))
+	if err != nil || (tutor.Quarantined && !middlewares.IsAdmin(ctx)) {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found"})
 		return
 	}
@@ -182,6 +190,7 @@ func (c *TutorController) GetByID(ctx *gin.Context) {
 // @Success 200 {object} domain.Tutor
 // @Failure 400 {object} domain.ErrorResponse
 // @Failure 404 {object} domain.ErrorResponse
+// @Failure 409 {object} domain.ErrorResponse
 // @Failure 500 {object} domain.ErrorResponse
 // @Router /tutors/{id} [put]
 func (c *TutorController) Update(ctx *gin.Context) {
@@ -191,13 +200,13 @@ func (c *TutorController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	_, err = c.u.GetByID(uint(id))
+	_, err = c.u.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: err.Error()})
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error finding the tutor"})
+		internalError(ctx, "error finding the tutor", err)
 		return
 	}
 
@@ -206,7 +215,7 @@ func (c *TutorController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid request"})
 		return
 	}
-	updated, err := c.u.Update(uint(id), &domain.Tutor{
+	updated, err := c.u.Update(ctx.Request.Context(), uint(id), &domain.Tutor{
 		FirstName:      req.FullName,
 		Email:          req.Email,
 		EducationLevel: req.EducationLevel,
@@ -214,8 +223,14 @@ func (c *TutorController) Update(ctx *gin.Context) {
 		HrPerDay:       req.HrPerDay,
 		Verified:       req.Verified,
 		PhoneNumber:    req.PhoneNumber,
+
+		DocumentExpiresAt: req.DocumentExpiresAt,
 	})
 	if err != nil {
+		if status, ok := phoneErrorStatus(err); ok {
+			ctx.JSON(status, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found"})
 		return
 	}
@@ -224,7 +239,7 @@ func (c *TutorController) Update(ctx *gin.Context) {
 
 // Delete handles deleting a tutor by ID
 // @Summary Delete a tutor by ID
-// @Description Delete a tutor by ID
+// @Description Move a tutor to the trash, from where it can be restored until the trash is purged
 // @Tags Tutors
 // @Accept json
 // @Produce json
@@ -240,7 +255,7 @@ func (c *TutorController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	err = c.u.Delete(uint(id))
+	err = c.u.Delete(ctx.Request.Context(), uint(id))
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found"})
 		return
@@ -266,19 +281,102 @@ func (c *TutorController) Verify(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
 		return
 	}
-	_, err = c.u.GetByID(uint(id))
+	_, err = c.u.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
 		if err == domain.ErrNotFound {
 			ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: err.Error()})
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "error finding the tutor"})
+		internalError(ctx, "error finding the tutor", err)
 		return
 	}
-	if err := c.u.Verify(uint(id)); err != nil {
+	if err := c.u.Verify(ctx.Request.Context(), uint(id)); err != nil {
 		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found or failed to verify"})
 		return
 	}
-	tutor, _ := c.u.GetByID(uint(id))
+	tutor, _ := c.u.GetByID(ctx.Request.Context(), uint(id))
+	ctx.JSON(http.StatusOK, tutor)
+}
+
+// Release lets a quarantined tutor registration through
+// @Summary Release a quarantined tutor
+// @Description Clear the quarantine flag so the tutor shows up in the main list and notifications are sent
+// @Tags Tutors
+// @Produce json
+// @Param id path int true "Tutor ID"
+// @Success 200 {object} domain.Tutor
+// @Failure 400 {object} domain.ErrorResponse
+// @Failure 404 {object} domain.ErrorResponse
+// @Security JWT
+// @Router /tutors/{id}/release [put]
+func (c *TutorController) Release(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+	if err := c.u.Release(ctx.Request.Context(), uint(id)); err != nil {
+		ctx.JSON(http.StatusNotFound, domain.ErrorResponse{Message: "Tutor not found or failed to release"})
+		return
+	}
+	tutor, _ := c.u.GetByID(ctx.Request.Context(), uint(id))
 	ctx.JSON(http.StatusOK, tutor)
 }
+
+// tutorFilter reads the list filters shared by the list, export and bulk
+// endpoints from query.
+func tutorFilter(query url.Values, quarantined bool) *domain.TutorFilter {
+	filter := &domain.TutorFilter{Quarantined: quarantined}
+	if v := query.Get("education_level"); v != "" {
+		filter.EducationLevel = v
+	}
+	if v := query.Get("verified"); v != "" {
+		filter.Verified = v == "true"
+	}
+	// support both `query` and `search` from frontend
+	if v := query.Get("query"); v != "" {
+		filter.Query = v
+	}
+	if v := query.Get("search"); v != "" {
+		filter.Query = v
+	}
+	// pagination
+	if v := query.Get("page"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.Page = val
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.Limit = val
+		}
+	}
+	// sorting
+	if v := query.Get("sort_by"); v != "" {
+		filter.SortBy = v
+	}
+	if v := query.Get("sort_order"); v != "" {
+		filter.SortOrder = v
+	}
+	if v := query.Get("min_day_per_week"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.MinDayPerWeek = val
+		}
+	}
+	if v := query.Get("max_day_per_week"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.MaxDayPerWeek = val
+		}
+	}
+	if v := query.Get("min_hr_per_day"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.MinHrPerDay = val
+		}
+	}
+	if v := query.Get("max_hr_per_day"); v != "" {
+		if val, err := strconv.Atoi(v); err == nil {
+			filter.MaxHrPerDay = val
+		}
+	}
+	return filter
+}
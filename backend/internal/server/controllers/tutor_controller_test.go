@@ -2,13 +2,16 @@ package controllers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/storage"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -21,6 +24,7 @@ type TutorControllerTestSuite struct {
 	router  *gin.Engine
 	usecase *mockTutorUsecase
 	ctrl    *TutorController
+	uploads string
 }
 
 type mockTutorUsecase struct {
@@ -28,7 +32,7 @@ type mockTutorUsecase struct {
 	lastID uint
 }
 
-func (m *mockTutorUsecase) Create(t *domain.Tutor) (*domain.Tutor, error) {
+func (m *mockTutorUsecase) Create(_ context.Context, t *domain.Tutor) (*domain.Tutor, error) {
 	if t == nil || t.FirstName == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -37,7 +41,7 @@ func (m *mockTutorUsecase) Create(t *domain.Tutor) (*domain.Tutor, error) {
 	m.tutors[t.ID] = t
 	return t, nil
 }
-func (m *mockTutorUsecase) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
+func (m *mockTutorUsecase) GetAll(_ context.Context, filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
 	var result []domain.Tutor
 	for _, t := range m.tutors {
 		if filter != nil && filter.EducationLevel != "" && t.EducationLevel != filter.EducationLevel {
@@ -47,14 +51,14 @@ func (m *mockTutorUsecase) GetAll(filter *domain.TutorFilter) (domain.MultipleTu
 	}
 	return domain.MultipleTutorResponse{Data: result, Pagination: domain.Pagination{Total: len(result)}}, nil
 }
-func (m *mockTutorUsecase) GetByID(id uint) (*domain.Tutor, error) {
+func (m *mockTutorUsecase) GetByID(_ context.Context, id uint) (*domain.Tutor, error) {
 	t, ok := m.tutors[id]
 	if !ok {
 		return nil, domain.ErrNotFound
 	}
 	return t, nil
 }
-func (m *mockTutorUsecase) Update(id uint, t *domain.Tutor) (*domain.Tutor, error) {
+func (m *mockTutorUsecase) Update(_ context.Context, id uint, t *domain.Tutor) (*domain.Tutor, error) {
 	if _, ok := m.tutors[id]; !ok {
 		return nil, domain.ErrNotFound
 	}
@@ -62,14 +66,22 @@ func (m *mockTutorUsecase) Update(id uint, t *domain.Tutor) (*domain.Tutor, erro
 	m.tutors[id] = t
 	return t, nil
 }
-func (m *mockTutorUsecase) Delete(id uint) error {
+func (m *mockTutorUsecase) Delete(_ context.Context, id uint) error {
 	if _, ok := m.tutors[id]; !ok {
 		return domain.ErrNotFound
 	}
 	delete(m.tutors, id)
 	return nil
 }
-func (m *mockTutorUsecase) Verify(id uint) error {
+func (m *mockTutorUsecase) Release(_ context.Context, id uint) error {
+	t, ok := m.tutors[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	t.Quarantined = false
+	return nil
+}
+func (m *mockTutorUsecase) Verify(_ context.Context, id uint) error {
 	t, ok := m.tutors[id]
 	if !ok {
 		return domain.ErrNotFound
@@ -77,6 +89,17 @@ func (m *mockTutorUsecase) Verify(id uint) error {
 	t.Verified = true
 	return nil
 }
+func (m *mockTutorUsecase) Bulk(_ context.Context, req *domain.TutorBulkRequest) (*domain.BulkResult, error) {
+	result := &domain.BulkResult{Action: req.Action}
+	for _, id := range req.IDs {
+		var err error
+		if _, ok := m.tutors[id]; !ok {
+			err = domain.ErrNotFound
+		}
+		result.Add(id, err == nil, err)
+	}
+	return result, nil
+}
 
 func TestTutorController(t *testing.T) {
 	suite.Run(t, new(TutorControllerTestSuite))
@@ -85,7 +108,8 @@ func TestTutorController(t *testing.T) {
 func (s *TutorControllerTestSuite) SetupTest() {
 	gin.SetMode(gin.TestMode)
 	s.usecase = &mockTutorUsecase{tutors: make(map[uint]*domain.Tutor)}
-	s.ctrl = NewTutorController(s.usecase)
+	s.uploads = s.T().TempDir()
+	s.ctrl = NewTutorController(s.usecase, storage.NewLocal(s.uploads))
 	s.router = gin.New()
 	s.router.POST("/tutors", s.ctrl.Create)
 	s.router.GET("/tutors", s.ctrl.GetAll)
@@ -93,6 +117,7 @@ func (s *TutorControllerTestSuite) SetupTest() {
 	s.router.PUT("/tutors/:id", s.ctrl.Update)
 	s.router.DELETE("/tutors/:id", s.ctrl.Delete)
 	s.router.PUT("/tutors/:id/verify", s.ctrl.Verify)
+	s.router.POST("/tutors/bulk", s.ctrl.Bulk)
 }
 
 func (s *TutorControllerTestSuite) TestCreateTutor() {
@@ -136,11 +161,33 @@ func (s *TutorControllerTestSuite) TestCreateTutor() {
 	s.Contains(resp.Document, "uploads/documents/")
 }
 
+func (s *TutorControllerTestSuite) TestCreateTutor_RemovesUploadsOnFailure() {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	// The mock refuses tutors without a first name
+	writer.WriteField("education_level", "Degree")
+	doc, _ := writer.CreateFormFile("document", "testdoc.pdf")
+	doc.Write([]byte("dummy pdf content"))
+	image, _ := writer.CreateFormFile("image", "image.png")
+	image.Write([]byte("dummy image"))
+	writer.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tutors", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusInternalServerError, w.Code)
+	for _, kind := range []string{"documents", "images"} {
+		entries, _ := os.ReadDir(filepath.Join(s.uploads, kind))
+		s.Empty(entries, kind)
+	}
+}
+
 func (s *TutorControllerTestSuite) TestGetAllTutors() {
 	t1 := &domain.Tutor{FirstName: "Alice", EducationLevel: "Degree", Email: "alice@example.com"}
 	t2 := &domain.Tutor{FirstName: "Bob", EducationLevel: "Diploma", Email: "bob@example.com"}
-	s.usecase.Create(t1)
-	s.usecase.Create(t2)
+	s.usecase.Create(context.Background(), t1)
+	s.usecase.Create(context.Background(), t2)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/tutors", nil)
 	s.router.ServeHTTP(w, req)
@@ -152,7 +199,7 @@ func (s *TutorControllerTestSuite) TestGetAllTutors() {
 
 func (s *TutorControllerTestSuite) TestGetTutorByID() {
 	t := &domain.Tutor{FirstName: "Test Tutor", EducationLevel: "Degree", Email: "test@example.com"}
-	created, _ := s.usecase.Create(t)
+	created, _ := s.usecase.Create(context.Background(), t)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/tutors/"+strconv.Itoa(int(created.ID)), nil)
 	s.router.ServeHTTP(w, req)
@@ -162,9 +209,25 @@ func (s *TutorControllerTestSuite) TestGetTutorByID() {
 	s.Equal(created.FirstName, resp.FirstName)
 }
 
+func (s *TutorControllerTestSuite) TestGetTutorByID_Quarantined() {
+	t := &domain.Tutor{FirstName: "Held", Quarantined: true, QuarantineReason: "honeypot"}
+	created, _ := s.usecase.Create(context.Background(), t)
+	path := "/tutors/" + strconv.Itoa(int(created.ID))
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	s.Equal(http.StatusNotFound, w.Code)
+
+	admin := gin.New()
+	admin.GET("/tutors/:id", func(ctx *gin.Context) { ctx.Set("role", "admin") }, s.ctrl.GetByID)
+	w = httptest.NewRecorder()
+	admin.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	s.Equal(http.StatusOK, w.Code)
+}
+
 func (s *TutorControllerTestSuite) TestUpdateTutor() {
 	t := &domain.Tutor{FirstName: "Old Name", EducationLevel: "Diploma", Email: "old@example.com"}
-	created, _ := s.usecase.Create(t)
+	created, _ := s.usecase.Create(context.Background(), t)
 	// send payload matching UpdateTutorRequest (uses full_name JSON key)
 	updated := map[string]interface{}{"full_name": "New Name", "education_level": "Degree", "email": "new@example.com"}
 	body, _ := json.Marshal(updated)
@@ -180,7 +243,7 @@ func (s *TutorControllerTestSuite) TestUpdateTutor() {
 
 func (s *TutorControllerTestSuite) TestDeleteTutor() {
 	t := &domain.Tutor{FirstName: "ToDelete", EducationLevel: "Degree", Email: "delete@example.com"}
-	created, _ := s.usecase.Create(t)
+	created, _ := s.usecase.Create(context.Background(), t)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("DELETE", "/tutors/"+strconv.Itoa(int(created.ID)), nil)
 	s.router.ServeHTTP(w, req)
@@ -189,7 +252,7 @@ func (s *TutorControllerTestSuite) TestDeleteTutor() {
 
 func (s *TutorControllerTestSuite) TestVerifyTutor() {
 	t := &domain.Tutor{FirstName: "VerifyMe", EducationLevel: "Degree", Verified: false, Email: "verify@example.com"}
-	created, _ := s.usecase.Create(t)
+	created, _ := s.usecase.Create(context.Background(), t)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/tutors/"+strconv.Itoa(int(created.ID))+"/verify", nil)
 	s.router.ServeHTTP(w, req)
@@ -198,3 +261,28 @@ func (s *TutorControllerTestSuite) TestVerifyTutor() {
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	s.True(resp.Verified)
 }
+
+func (s *TutorControllerTestSuite) TestBulk() {
+	created, _ := s.usecase.Create(context.Background(), &domain.Tutor{FirstName: "Alice", EducationLevel: "Degree", Email: "alice@example.com"})
+	bulk := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/tutors/bulk", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		s.router.ServeHTTP(w, req)
+		return w
+	}
+	w := bulk(`{"action":"verify","ids":[` + strconv.Itoa(int(created.ID)) + `]}`)
+	s.Equal(http.StatusOK, w.Code)
+
+	// One of the two is missing, so the result is partial
+	w = bulk(`{"action":"verify","ids":[` + strconv.Itoa(int(created.ID)) + `,99]}`)
+	s.Equal(http.StatusMultiStatus, w.Code)
+	var resp domain.BulkResult
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+	s.Equal(1, resp.Updated)
+	s.Equal(1, resp.Failed)
+	s.Equal(domain.ErrNotFound.Error(), resp.Items[1].Error)
+
+	s.Equal(http.StatusBadRequest, bulk(`{"ids":[1]}`).Code)
+	s.Equal(http.StatusBadRequest, bulk(`{"action":"verify","filter":"%zz"}`).Code)
+}
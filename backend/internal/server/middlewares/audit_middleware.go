@@ -0,0 +1,144 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLoader fetches the current state of an entity so it can be recorded
+// before and after a mutation.
+type AuditLoader func(ctx context.Context, id uint) (any, error)
+
+// bodyRecorder keeps a copy of the response body for the audit trail.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// AuditBulkKey is the context key a bulk action handler stores its
+// *domain.BulkResult under, so every record it updated is audited on its
+// own.
+const AuditBulkKey = "audit_bulk"
+
+// AuditMiddleware records every successful mutating request of the group in
+// the audit trail. It must run after AuthMiddleware so the actor is known.
+// The entity ID comes from the ":id" route parameter, or from the "id" field
+// of the response for creates. basePath is the group's path and is used to
+// name sub-resource actions. Bulk actions get one entry per updated record
+// instead, named like "bulk_verify".
+func AuditMiddleware(u domain.AuditUsecase, basePath, entityType string, load AuditLoader) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		var entityID uint
+		var before any
+		if id, err := strconv.ParseUint(ctx.Param("id"), 10, 64); err == nil {
+			entityID = uint(id)
+			if load != nil {
+				before, _ = load(ctx.Request.Context(), entityID)
+			}
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if ctx.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		if v, ok := ctx.Get(AuditBulkKey); ok {
+			if result, ok := v.(*domain.BulkResult); ok {
+				for _, item := range result.Items {
+					if item.Status == domain.BulkItemUpdated {
+						record(ctx, u, &domain.AuditLog{
+							Action:     auditAction(ctx, basePath) + "_" + result.Action,
+							EntityType: entityType,
+							EntityID:   item.ID,
+						}, item.Before, item.After)
+					}
+				}
+				return
+			}
+		}
+
+		var after any
+		switch {
+		case ctx.Request.Method == http.MethodDelete:
+		case entityID != 0 && load != nil:
+			after, _ = load(ctx.Request.Context(), entityID)
+		default:
+			var created map[string]any
+			if err := json.Unmarshal(recorder.body.Bytes(), &created); err == nil {
+				if id, ok := created["id"].(float64); ok {
+					entityID = uint(id)
+					after = created
+				}
+			}
+		}
+
+		record(ctx, u, &domain.AuditLog{
+			Action:     auditAction(ctx, basePath),
+			EntityType: entityType,
+			EntityID:   entityID,
+		}, before, after)
+	}
+}
+
+// record fills in the actor and address of entry and saves it.
+func record(ctx *gin.Context, u domain.AuditUsecase, entry *domain.AuditLog, before, after any) {
+	if v, ok := ctx.Get("userID"); ok {
+		entry.ActorID, _ = v.(uint)
+	}
+	entry.ActorUsername = ctx.GetString("username")
+	entry.ActorRole = ctx.GetString("role")
+	entry.IPAddress = ctx.ClientIP()
+	if err := u.Record(ctx.Request.Context(), entry, before, after); err != nil {
+		// The mutation already happened; don't turn it into a failure
+		logging.FromContext(ctx.Request.Context()).Error("failed to record audit entry", "action", entry.Action, "entity_type", entry.EntityType, "error", err)
+	}
+}
+
+// auditAction names the action after the static segments below the group,
+// e.g. "/:id/verify" becomes "verify" and "/change-password" becomes
+// "change_password". Plain resource routes fall back to create, update or
+// delete.
+func auditAction(ctx *gin.Context, basePath string) string {
+	var parts []string
+	for _, segment := range strings.Split(strings.TrimPrefix(ctx.FullPath(), basePath), "/") {
+		if segment != "" && !strings.HasPrefix(segment, ":") {
+			parts = append(parts, strings.ReplaceAll(segment, "-", "_"))
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, "_")
+	}
+	switch ctx.Request.Method {
+	case http.MethodPost:
+		return domain.AuditActionCreate
+	case http.MethodDelete:
+		return domain.AuditActionDelete
+	}
+	return domain.AuditActionUpdate
+}
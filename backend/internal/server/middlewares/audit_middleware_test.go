@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedAudit struct {
+	entry  domain.AuditLog
+	before any
+	after  any
+}
+
+type fakeAuditUsecase struct {
+	records []recordedAudit
+}
+
+func (f *fakeAuditUsecase) Record(_ context.Context, entry *domain.AuditLog, before, after any) error {
+	f.records = append(f.records, recordedAudit{*entry, before, after})
+	return nil
+}
+func (f *fakeAuditUsecase) GetAll(context.Context, *domain.AuditFilter) (*domain.MultipleAuditLogs, error) {
+	return nil, nil
+}
+func (f *fakeAuditUsecase) Export(context.Context, *domain.AuditFilter, func(*domain.AuditLog) error) error {
+	return nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verified := false
+	load := func(_ context.Context, id uint) (any, error) {
+		return map[string]any{"id": id, "verified": verified}, nil
+	}
+	audit := &fakeAuditUsecase{}
+
+	r := gin.New()
+	api := r.Group("/api/v1/tutors")
+	api.Use(func(ctx *gin.Context) {
+		ctx.Set("userID", uint(7))
+		ctx.Set("username", "coordinator")
+		ctx.Set("role", "admin")
+	}, AuditMiddleware(audit, api.BasePath(), domain.AuditEntityTutor, load))
+	api.GET("/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	api.POST("/", func(ctx *gin.Context) { ctx.JSON(http.StatusCreated, gin.H{"id": 12, "first_name": "Liya"}) })
+	api.PUT("/:id/verify", func(ctx *gin.Context) {
+		verified = true
+		ctx.Status(http.StatusOK)
+	})
+	api.DELETE("/:id", func(ctx *gin.Context) { ctx.Status(http.StatusNotFound) })
+	api.POST("/bulk", func(ctx *gin.Context) {
+		result := &domain.BulkResult{Action: domain.BulkVerify}
+		result.AddChange(4, true, nil, map[string]any{"verified": false}, map[string]any{"verified": true})
+		result.AddChange(5, false, nil, nil, nil)
+		result.Add(6, false, domain.ErrNotFound)
+		ctx.Set(AuditBulkKey, result)
+		ctx.JSON(http.StatusMultiStatus, result)
+	})
+
+	serve := func(method, path string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.5:1234"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	serve(http.MethodGet, "/api/v1/tutors/3")
+	require.Empty(t, audit.records, "reads are not audited")
+
+	serve(http.MethodPut, "/api/v1/tutors/3/verify")
+	require.Len(t, audit.records, 1)
+	rec := audit.records[0]
+	require.Equal(t, "verify", rec.entry.Action)
+	require.Equal(t, uint(3), rec.entry.EntityID)
+	require.Equal(t, uint(7), rec.entry.ActorID)
+	require.Equal(t, "coordinator", rec.entry.ActorUsername)
+	require.Equal(t, "10.0.0.5", rec.entry.IPAddress)
+	require.Equal(t, false, rec.before.(map[string]any)["verified"])
+	require.Equal(t, true, rec.after.(map[string]any)["verified"])
+
+	serve(http.MethodPost, "/api/v1/tutors/")
+	require.Len(t, audit.records, 2)
+	require.Equal(t, domain.AuditActionCreate, audit.records[1].entry.Action)
+	require.Equal(t, uint(12), audit.records[1].entry.EntityID)
+
+	serve(http.MethodDelete, "/api/v1/tutors/3")
+	require.Len(t, audit.records, 2, "failed mutations are not audited")
+
+	// Only the records a bulk action updated are audited, one by one
+	serve(http.MethodPost, "/api/v1/tutors/bulk")
+	require.Len(t, audit.records, 3)
+	rec = audit.records[2]
+	require.Equal(t, "bulk_verify", rec.entry.Action)
+	require.Equal(t, uint(4), rec.entry.EntityID)
+	require.Equal(t, uint(7), rec.entry.ActorID)
+	require.Equal(t, true, rec.after.(map[string]any)["verified"])
+}
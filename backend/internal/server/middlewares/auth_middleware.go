@@ -2,12 +2,21 @@ package middlewares
 
 import (
 	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// passwordChangeExempt lists the routes an admin can still reach while their
+// token is flagged with MustChangePassword.
+var passwordChangeExempt = map[string]bool{
+	"/api/v1/admin/change-password": true,
+	"/api/v1/admin/me":              true,
+}
+
+func AuthMiddleware(tokens *auth.Tokens) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Extract header from header
 		header := ctx.GetHeader("Authorization")
@@ -22,23 +31,55 @@ func AuthMiddleware() gin.HandlerFunc {
 			ctx.Abort()
 			return
 		}
-		claims, err := auth.ValidateToken(token, auth.TokenTypeAccess)
+		claims, err := tokens.Validate(token, auth.TokenTypeAccess)
 		if err != nil {
 			ctx.JSON(401, gin.H{"error": "Invalid or expired token"})
 			ctx.Abort()
 			return
 		}
-		ctx.Set("userID", claims.UserID)
-		ctx.Set("username", claims.Username)
-		ctx.Set("role", claims.Role)
+		if claims.MustChangePassword && !passwordChangeExempt[ctx.FullPath()] {
+			ctx.JSON(403, gin.H{"error": domain.ErrPasswordChangeRequired.Error()})
+			ctx.Abort()
+			return
+		}
+		setClaims(ctx, claims)
+		ctx.Next()
+	}
+}
+
+// OptionalAuthMiddleware identifies the admin behind a valid access token
+// like AuthMiddleware, but lets requests without one through anonymously.
+// It is for public routes that show admins more.
+func OptionalAuthMiddleware(tokens *auth.Tokens) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if ok && token != "" {
+			if claims, err := tokens.Validate(token, auth.TokenTypeAccess); err == nil && !claims.MustChangePassword {
+				setClaims(ctx, claims)
+			}
+		}
 		ctx.Next()
 	}
 }
 
+func setClaims(ctx *gin.Context, claims *auth.UserClaims) {
+	ctx.Set("userID", claims.UserID)
+	ctx.Set("username", claims.Username)
+	ctx.Set("role", claims.Role)
+	// Everything logged for the rest of the request names the admin
+	reqCtx := ctx.Request.Context()
+	ctx.Request = ctx.Request.WithContext(logging.WithLogger(reqCtx, logging.FromContext(reqCtx).With("user_id", claims.UserID)))
+}
+
+// IsAdmin reports whether the request was made by an admin or superadmin.
+func IsAdmin(ctx *gin.Context) bool {
+	role, exists := ctx.Get("role")
+	return exists && (role == "admin" || role == "superadmin")
+}
+
 func IsAdminMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		role, exists := ctx.Get("role")
-		if !exists || (role != "admin" && role != "superadmin") {
+		if !IsAdmin(ctx) {
 			ctx.JSON(403, gin.H{"error": "Admin access required"})
 			ctx.Abort()
 			return
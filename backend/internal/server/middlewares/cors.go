@@ -0,0 +1,135 @@
+package middlewares
+
+import (
+	"hiyab-tutor/internal/config"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// corsProfiles are the origins each APP_ENV allows on top of WEB_APP_URL
+// and CORS_ALLOWED_ORIGINS. A pattern may use "*." for any subdomain or
+// ":*" for any port.
+var corsProfiles = map[string][]string{
+	config.EnvDevelopment: {
+		"http://localhost:*",
+		"http://127.0.0.1:*",
+		"https://hiyab.org",
+		"https://*.hiyab.org",
+	},
+	config.EnvProduction: {
+		"https://hiyab.org",
+		"https://*.hiyab.org",
+	},
+}
+
+// corsExposedHeaders are response headers the dashboard needs to read.
+var corsExposedHeaders = []string{
+	"Content-Disposition",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"RateLimit-Policy",
+	"Retry-After",
+}
+
+// CORSPolicy says which browser origins may call the API and how.
+type CORSPolicy struct {
+	// Origins are exact origins or patterns with a "*." subdomain or ":*"
+	// port wildcard; "*" allows everyone but then without credentials
+	Origins []string
+	Methods []string
+	Headers []string
+	MaxAge  time.Duration
+}
+
+// CORSPolicyFromConfig combines the APP_ENV profile, the web app's origin
+// and CORS_ALLOWED_ORIGINS.
+func CORSPolicyFromConfig(c *config.Config) CORSPolicy {
+	origins := append([]string{}, corsProfiles[c.AppEnv]...)
+	if u, err := url.Parse(c.WebAppUrl); err == nil && u.Scheme != "" && u.Host != "" {
+		origins = append(origins, u.Scheme+"://"+u.Host)
+	}
+	origins = append(origins, c.CORSOrigins()...)
+	return CORSPolicy{
+		Origins: origins,
+		Methods: c.CORSMethods(),
+		Headers: c.CORSHeaders(),
+		MaxAge:  time.Duration(c.CORSMaxAgeSeconds) * time.Second,
+	}
+}
+
+// CORS answers preflight requests and adds the CORS headers for allowed
+// origins. Requests from other origins get 403.
+func CORS(p CORSPolicy) gin.HandlerFunc {
+	cfg := cors.Config{
+		AllowMethods:     p.Methods,
+		AllowHeaders:     p.Headers,
+		ExposeHeaders:    corsExposedHeaders,
+		AllowCredentials: true,
+		MaxAge:           p.MaxAge,
+	}
+	var patterns []string
+	for _, origin := range p.Origins {
+		if origin == "*" {
+			// Browsers refuse credentials with a wildcard origin
+			cfg.AllowAllOrigins = true
+			cfg.AllowCredentials = false
+		}
+		patterns = append(patterns, strings.ToLower(strings.TrimSuffix(origin, "/")))
+	}
+	if !cfg.AllowAllOrigins {
+		cfg.AllowOriginFunc = func(origin string) bool {
+			origin = strings.ToLower(origin)
+			for _, pattern := range patterns {
+				if matchOrigin(pattern, origin) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return cors.New(cfg)
+}
+
+// matchOrigin reports whether origin fits pattern. Patterns may start the
+// host with "*." to match any subdomain (not the bare domain) or end it
+// with ":*" to match any port.
+func matchOrigin(pattern, origin string) bool {
+	if pattern == origin {
+		return true
+	}
+	scheme, host, ok := strings.Cut(pattern, "://")
+	originScheme, originHost, ok2 := strings.Cut(origin, "://")
+	if !ok || !ok2 || scheme != originScheme {
+		return false
+	}
+	if base, ok := strings.CutSuffix(host, ":*"); ok {
+		name, port, hasPort := strings.Cut(originHost, ":")
+		if hasPort && !isDigits(port) {
+			return false
+		}
+		originHost = name
+		host = base
+	}
+	if domain, ok := strings.CutPrefix(host, "*."); ok {
+		sub, found := strings.CutSuffix(originHost, "."+domain)
+		return found && sub != "" && !strings.ContainsAny(sub, "/:@")
+	}
+	return host == originHost
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
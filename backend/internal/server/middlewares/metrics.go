@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"hiyab-tutor/internal/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests no route matched, so scanners probing
+// random paths don't create a series per path.
+const unmatchedRoute = "unmatched"
+
+// Metrics records the count and latency of every request by route pattern
+// and status. A nil m disables it.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	if m == nil {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.ObserveRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,126 @@
+package middlewares
+
+import (
+	"fmt"
+	"hiyab-tutor/internal/logging"
+	"hiyab-tutor/internal/ratelimit"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc picks the bucket a request is counted against.
+type KeyFunc func(ctx *gin.Context) string
+
+// KeyByIP counts requests per client IP.
+func KeyByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+// KeyByUser counts requests per authenticated user, so it has to run after
+// AuthMiddleware. Anonymous requests fall back to the client IP.
+func KeyByUser(ctx *gin.Context) string {
+	if id, ok := ctx.Get("userID"); ok {
+		return fmt.Sprintf("user:%v", id)
+	}
+	return KeyByIP(ctx)
+}
+
+// KeyByRoute counts every request to a route together, whoever sends it.
+func KeyByRoute(ctx *gin.Context) string {
+	return "route:" + ctx.Request.Method + " " + ctx.FullPath()
+}
+
+// CombineKeys counts requests per combination of the given keys, e.g. per
+// IP and route.
+func CombineKeys(keys ...KeyFunc) KeyFunc {
+	return func(ctx *gin.Context) string {
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			parts[i] = key(ctx)
+		}
+		return strings.Join(parts, "|")
+	}
+}
+
+// RateLimit takes a token from the request's bucket and answers 429 when it
+// is empty. Responses carry the RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy headers, plus Retry-After when
+// rejected. If the store fails the request is let through.
+func RateLimit(store ratelimit.Store, policy ratelimit.Policy, key KeyFunc) gin.HandlerFunc {
+	if store == nil || !policy.Enabled() {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+	return func(ctx *gin.Context) {
+		res, err := store.Take(ctx.Request.Context(), key(ctx), policy, time.Now())
+		if err != nil {
+			logging.FromContext(ctx.Request.Context()).Warn("rate limit unavailable", "policy", policy.Name, "error", err)
+			ctx.Next()
+			return
+		}
+		ctx.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		ctx.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+		ctx.Header("RateLimit-Policy", policy.String())
+		if !res.Allowed {
+			ctx.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			ctx.AbortWithStatusJSON(429, gin.H{"error": "Too many requests, please try again later"})
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// RateLimits holds the policy for each route group. A nil *RateLimits lets
+// every request through.
+type RateLimits struct {
+	Store       ratelimit.Store
+	PublicRead  ratelimit.Policy
+	PublicWrite ratelimit.Policy
+	Admin       ratelimit.Policy
+	// Submissions caps the public booking and tutor forms on top of
+	// PublicWrite
+	Submissions ratelimit.Policy
+}
+
+// Read limits anonymous reads per client IP.
+func (l *RateLimits) Read() gin.HandlerFunc {
+	if l == nil {
+		return RateLimit(nil, ratelimit.Policy{}, nil)
+	}
+	return RateLimit(l.Store, l.PublicRead, KeyByIP)
+}
+
+// Write limits anonymous writes per client IP and route, so a burst of
+// logins doesn't use up the allowance for the booking form.
+func (l *RateLimits) Write() gin.HandlerFunc {
+	if l == nil {
+		return RateLimit(nil, ratelimit.Policy{}, nil)
+	}
+	return RateLimit(l.Store, l.PublicWrite, CombineKeys(KeyByIP, KeyByRoute))
+}
+
+// Submit limits public form submissions per client IP and route, so each
+// form has its own allowance.
+func (l *RateLimits) Submit() gin.HandlerFunc {
+	if l == nil {
+		return RateLimit(nil, ratelimit.Policy{}, nil)
+	}
+	return RateLimit(l.Store, l.Submissions, CombineKeys(KeyByIP, KeyByRoute))
+}
+
+// Authenticated limits admin requests per user. Add it after
+// AuthMiddleware.
+func (l *RateLimits) Authenticated() gin.HandlerFunc {
+	if l == nil {
+		return RateLimit(nil, ratelimit.Policy{}, nil)
+	}
+	return RateLimit(l.Store, l.Admin, KeyByUser)
+}
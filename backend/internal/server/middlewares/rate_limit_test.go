@@ -0,0 +1,143 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func limitedRouter(limits *RateLimits) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	setUser := func(ctx *gin.Context) {
+		if id := ctx.GetHeader("X-Test-User"); id != "" {
+			ctx.Set("userID", id)
+		}
+	}
+	r.GET("/items", limits.Read(), ok)
+	r.POST("/items", limits.Write(), ok)
+	r.POST("/login", limits.Write(), ok)
+	r.POST("/bookings", limits.Write(), limits.Submit(), ok)
+	r.POST("/tutors", limits.Write(), limits.Submit(), ok)
+	r.GET("/admin", setUser, limits.Authenticated(), ok)
+	return r
+}
+
+func request(r http.Handler, method, path, ip, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_Headers(t *testing.T) {
+	r := limitedRouter(&RateLimits{
+		Store:      ratelimit.NewMemoryStore(),
+		PublicRead: ratelimit.Policy{Name: "read", Limit: 2, Period: time.Minute},
+	})
+
+	w := request(r, http.MethodGet, "/items", "10.0.0.1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	require.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+	require.Empty(t, w.Header().Get("Retry-After"))
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/items", "10.0.0.1", "").Code)
+	w = request(r, http.MethodGet, "/items", "10.0.0.1", "")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	// Keyed by IP
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/items", "10.0.0.2", "").Code)
+	// Groups without a policy are not limited
+	w = request(r, http.MethodPost, "/items", "10.0.0.1", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_WritesPerRoute(t *testing.T) {
+	r := limitedRouter(&RateLimits{
+		Store:       ratelimit.NewMemoryStore(),
+		PublicWrite: ratelimit.Policy{Name: "write", Limit: 1, Period: time.Minute},
+	})
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/login", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusTooManyRequests, request(r, http.MethodPost, "/login", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/items", "10.0.0.1", "").Code)
+}
+
+func TestRateLimit_Submissions(t *testing.T) {
+	r := limitedRouter(&RateLimits{
+		Store:       ratelimit.NewMemoryStore(),
+		Submissions: ratelimit.Policy{Name: "submissions", Limit: 2, Period: time.Hour},
+	})
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/bookings", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/bookings", "10.0.0.1", "").Code)
+	w := request(r, http.MethodPost, "/bookings", "10.0.0.1", "")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "1800", w.Header().Get("Retry-After"))
+	// Other clients and the other form are not affected
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/bookings", "10.0.0.2", "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/tutors", "10.0.0.1", "").Code)
+	// Nor are other writes
+	require.Equal(t, http.StatusOK, request(r, http.MethodPost, "/login", "10.0.0.1", "").Code)
+}
+
+func TestRateLimit_Admin(t *testing.T) {
+	r := limitedRouter(&RateLimits{
+		Store: ratelimit.NewMemoryStore(),
+		Admin: ratelimit.Policy{Name: "admin", Limit: 1, Period: time.Minute},
+	})
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/admin", "10.0.0.1", "1").Code)
+	require.Equal(t, http.StatusTooManyRequests, request(r, http.MethodGet, "/admin", "10.0.0.2", "1").Code)
+	// Another user behind the same IP has their own allowance
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/admin", "10.0.0.1", "2").Code)
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	r := limitedRouter(nil)
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/items", "10.0.0.1", "").Code)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Policy, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimit_StoreDown(t *testing.T) {
+	r := limitedRouter(&RateLimits{
+		Store:      failingStore{},
+		PublicRead: ratelimit.Policy{Name: "read", Limit: 1, Period: time.Minute},
+	})
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/items", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/items", "10.0.0.1", "").Code)
+}
+
+func TestKeyByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var key string
+	r := gin.New()
+	r.GET("/items/:id", func(ctx *gin.Context) { key = KeyByRoute(ctx) })
+	request(r, http.MethodGet, "/items/7", "10.0.0.1", "")
+	require.Equal(t, "route:GET /items/:id", key)
+}
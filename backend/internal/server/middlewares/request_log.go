@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"hiyab-tutor/internal/logging"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID in and out of the API.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the IDs accepted from clients and proxies
+const maxRequestIDLength = 128
+
+// RequestID tags every request with an ID, reusing a well formed
+// X-Request-ID from the client or proxy. The ID is echoed in the response
+// and attached to the request logger, which handlers and usecases get with
+// logging.FromContext, together with the trace ID when Tracing ran first.
+// A nil logger means slog.Default().
+func RequestID(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		base := logger
+		if base == nil {
+			base = slog.Default()
+		}
+		ctx.Set("requestID", id)
+		ctx.Header(RequestIDHeader, id)
+		reqCtx := logging.WithRequestID(ctx.Request.Context(), id)
+		reqLogger := base.With("request_id", id)
+		if sc := trace.SpanContextFromContext(reqCtx); sc.IsValid() {
+			reqLogger = reqLogger.With("trace_id", sc.TraceID().String())
+		}
+		reqCtx = logging.WithLogger(reqCtx, reqLogger)
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
+
+// RequestLogger writes one record per request once it is handled: errors
+// for 5xx responses, warnings for 4xx and info otherwise. It has to run
+// after RequestID; AuthMiddleware adds the admin's ID to the same logger.
+// Query strings are left out since they may carry tokens.
+func RequestLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+			slog.Int("bytes", ctx.Writer.Size()),
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", ctx.Errors.String()))
+		}
+		logging.FromContext(ctx.Request.Context()).LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// validRequestID accepts short IDs made of letters, digits and -_.:
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
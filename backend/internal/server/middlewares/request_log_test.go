@@ -0,0 +1,68 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"hiyab-tutor/internal/logging"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: logging.Redact}))
+
+	r := gin.New()
+	r.Use(RequestID(logger), RequestLogger())
+	r.GET("/bookings/:id", func(ctx *gin.Context) {
+		require.Equal(t, ctx.GetString("requestID"), logging.RequestID(ctx.Request.Context()))
+		logging.FromContext(ctx.Request.Context()).Info("handled", "phone_number", "+251911234567")
+		ctx.Status(http.StatusNotFound)
+	})
+	serve := func(id string) (*httptest.ResponseRecorder, []map[string]any) {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/bookings/7?token=secret", nil)
+		if id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var rec map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &rec))
+			records = append(records, rec)
+		}
+		return w, records
+	}
+
+	w, records := serve("edge-1234")
+	require.Equal(t, "edge-1234", w.Header().Get(RequestIDHeader))
+	require.Len(t, records, 2)
+	require.Equal(t, "handled", records[0]["msg"])
+	require.Equal(t, "edge-1234", records[0]["request_id"])
+	require.Equal(t, "+251*******67", records[0]["phone_number"])
+	require.Equal(t, "request", records[1]["msg"])
+	require.Equal(t, "WARN", records[1]["level"])
+	require.Equal(t, "edge-1234", records[1]["request_id"])
+	require.Equal(t, "/bookings/7", records[1]["path"])
+	require.Equal(t, "/bookings/:id", records[1]["route"])
+	require.EqualValues(t, 404, records[1]["status"])
+	require.NotContains(t, buf.String(), "secret")
+
+	// Missing or malformed IDs are replaced
+	for _, id := range []string{"", "bad id\n", strings.Repeat("a", maxRequestIDLength+1)} {
+		w, records = serve(id)
+		got := w.Header().Get(RequestIDHeader)
+		require.Len(t, got, 32)
+		require.NotEqual(t, id, got)
+		require.Equal(t, got, records[1]["request_id"])
+	}
+}
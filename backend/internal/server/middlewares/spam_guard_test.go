@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hiyab-tutor/internal/captcha"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type downVerifier struct{}
+
+func (downVerifier) Verify(context.Context, string, string) error {
+	return errors.New("connection refused")
+}
+
+// spamRouter echoes the quarantine reason and the bound JSON name.
+func spamRouter(cfg SpamGuardConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/bookings", SpamGuard(cfg), func(ctx *gin.Context) {
+		var body struct {
+			FirstName string `json:"first_name" form:"first_name"`
+		}
+		if err := ctx.ShouldBind(&body); err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		ctx.JSON(http.StatusCreated, gin.H{"name": body.FirstName, "reason": ctx.GetString(QuarantineKey)})
+	})
+	return r
+}
+
+func postJSON(r *gin.Engine, body, token, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(CaptchaHeader, token)
+	}
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSpamGuard_Honeypot(t *testing.T) {
+	r := spamRouter(SpamGuardConfig{HoneypotField: "website"})
+
+	w := postJSON(r, `{"first_name": "Liya", "website": ""}`, "", "10.0.0.1")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"name": "Liya", "reason": ""}`, w.Body.String())
+
+	// Bots are accepted but flagged, and the handler still sees the body
+	w = postJSON(r, `{"first_name": "Bot", "website": "http://spam.example"}`, "", "10.0.0.1")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"name": "Bot", "reason": "honeypot"}`, w.Body.String())
+
+	// Multipart forms, as used for tutor registration
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	mw.WriteField("first_name", "Bot")
+	mw.WriteField("website", "x")
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/bookings", body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.JSONEq(t, `{"name": "Bot", "reason": "honeypot"}`, w.Body.String())
+}
+
+func TestSpamGuard_Captcha(t *testing.T) {
+	r := spamRouter(SpamGuardConfig{Captcha: captcha.Fake{}})
+
+	require.Equal(t, http.StatusBadRequest, postJSON(r, `{"first_name": "Liya"}`, "", "10.0.0.1").Code)
+	require.Equal(t, http.StatusBadRequest, postJSON(r, `{"first_name": "Liya"}`, "wrong", "10.0.0.1").Code)
+	require.Equal(t, http.StatusCreated, postJSON(r, `{"first_name": "Liya"}`, "pass", "10.0.0.1").Code)
+	// The token may come in the body too
+	require.Equal(t, http.StatusCreated, postJSON(r, `{"first_name": "Liya", "captcha_token": "pass"}`, "", "10.0.0.1").Code)
+
+	// An unreachable provider holds the submission instead of rejecting it
+	r = spamRouter(SpamGuardConfig{Captcha: downVerifier{}})
+	w := postJSON(r, `{"first_name": "Liya"}`, "token", "10.0.0.1")
+	require.Equal(t, http.StatusCreated, w.Code)
+	require.JSONEq(t, `{"name": "Liya", "reason": "captcha_unavailable"}`, w.Body.String())
+}
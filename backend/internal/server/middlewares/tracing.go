@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"hiyab-tutor/internal/tracing"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for every request, continuing the trace
+// from the client's traceparent header when there is one. Scrapes of
+// /metrics and the probes are left out. Add it before RequestID so the request logger
+// carries the trace ID. A nil provider records nothing.
+func Tracing(tp trace.TracerProvider) gin.HandlerFunc {
+	if tp == nil {
+		return func(ctx *gin.Context) { ctx.Next() }
+	}
+	return otelgin.Middleware(tracing.ServiceName,
+		otelgin.WithTracerProvider(tp),
+		otelgin.WithPropagators(tracing.Propagator),
+		otelgin.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/metrics", "/livez", "/readyz":
+				return false
+			}
+			return true
+		}),
+	)
+}
@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"hiyab-tutor/internal/logging"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	r := gin.New()
+	r.Use(Tracing(tp), RequestID(logger))
+	var handled trace.SpanContext
+	r.GET("/bookings/:id", func(ctx *gin.Context) {
+		handled = trace.SpanContextFromContext(ctx.Request.Context())
+		logging.FromContext(ctx.Request.Context()).Info("handled")
+		ctx.Status(http.StatusOK)
+	})
+	r.GET("/metrics", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	// The caller's trace is continued
+	req := httptest.NewRequest(http.MethodGet, "/bookings/7", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "/bookings/:id", spans[0].Name())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	require.Equal(t, spans[0].SpanContext().SpanID(), handled.SpanID())
+
+	// Handlers log with the trace ID
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+
+	// Scrapes are left out
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Len(t, rec.Ended(), 1)
+}
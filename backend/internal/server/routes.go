@@ -2,24 +2,22 @@ package server
 
 import (
 	"hiyab-tutor/docs"
+	"hiyab-tutor/internal/server/middlewares"
 	"hiyab-tutor/internal/server/routes"
 	"net/http"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middlewares.Tracing(s.App.Tracing), middlewares.RequestID(s.App.Logger), middlewares.RequestLogger(),
+		// Metrics goes outside Recovery so requests that panic are counted
+		middlewares.Metrics(s.App.Metrics), gin.Recovery())
 
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Add your frontend URL
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type"},
-		AllowCredentials: true, // Enable cookies/auth
-	}))
+	r.Use(middlewares.CORS(middlewares.CORSPolicyFromConfig(s.App.Config)))
 
 	r.GET("/", s.HelloWorldHandler)
 
@@ -27,7 +25,7 @@ func (s *Server) RegisterRoutes() http.Handler {
 	api := r.Group("/api/v1")
 	{
 		api.GET("/health", s.healthHandler)
-		api.Static("/uploads/", "./uploads")
+		api.Static("/uploads/", s.App.Config.UploadDir)
 	}
 
 	// Swagger documentation
@@ -35,19 +33,33 @@ func (s *Server) RegisterRoutes() http.Handler {
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.DefaultModelsExpandDepth(10)))
 
 	// Admin routes
-	routes.SetupAdminRoutes(r, s.DB.Gorm())
+	routes.SetupAdminRoutes(r, s.App)
 	// Other services routes
-	routes.SetupOtherServiceRoutes(r, s.DB.Gorm())
+	routes.SetupOtherServiceRoutes(r, s.App)
 	// Partners routes
-	routes.SetupPartnerRoutes(r, s.DB.Gorm())
+	routes.SetupPartnerRoutes(r, s.App)
 	// Testimonials routes
-	routes.SetupTestimonialRoutes(r, s.DB.Gorm())
+	routes.SetupTestimonialRoutes(r, s.App)
 	// Booking routes
-	routes.SetupBookingRoutes(r, s.DB.Gorm())
+	routes.SetupBookingRoutes(r, s.App)
 	// Tutor routes
-	routes.SetupTutorRoutes(r, s.DB.Gorm())
+	routes.SetupTutorRoutes(r, s.App)
+	// SMS delivery routes
+	routes.SetupSMSRoutes(r, s.App)
+	// Background job routes
+	routes.SetupJobRoutes(r, s.App)
+	// Maintenance task routes
+	routes.SetupSchedulerRoutes(r, s.App)
+	// Background export routes
+	routes.SetupExportRoutes(r, s.App)
+	// Audit log routes
+	routes.SetupAuditRoutes(r, s.App)
 	// Analytics routes
-	routes.SetupAnalyticsRoutes(r, s.DB.Gorm())
+	routes.SetupAnalyticsRoutes(r, s.App)
+	// Prometheus metrics
+	routes.SetupMetricsRoutes(r, s.App)
+	// Liveness and readiness probes
+	routes.SetupHealthRoutes(r, s.App)
 
 	return r
 }
@@ -60,5 +72,10 @@ func (s *Server) HelloWorldHandler(c *gin.Context) {
 }
 
 func (s *Server) healthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, s.DB.Health())
+	stats := s.DB.Health()
+	status := http.StatusOK
+	if stats["status"] != "up" {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, stats)
 }
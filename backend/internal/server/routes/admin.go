@@ -1,45 +1,27 @@
 package routes
 
 import (
-	"hiyab-tutor/internal/config"
+	"context"
+	"hiyab-tutor/internal/app"
 	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
-	"log"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupAdminRoutes(r *gin.Engine, db *gorm.DB) {
-	db.AutoMigrate(&domain.Admin{})
-	adminUsecase := usecases.NewAdminUsecase(db)
-	adminController := controllers.NewAdminController(adminUsecase)
-	c, err := config.LoadConfig()
-	if err != nil {
-		panic("Failed to load config")
-	}
-	superAdmin, err := adminUsecase.GetByUsername(c.AdminUsername)
-	if err != nil && err != gorm.ErrRecordNotFound {
-		log.Fatal(err)
-	}
-	if superAdmin == nil {
-		superAdmin = &domain.Admin{
-			Username: c.AdminUsername,
-			Password: c.AdminPassword,
-			Role:     "superadmin",
-			Name:     "Super Admin",
-		}
-		_, err = adminUsecase.Create(superAdmin)
-		if err != nil {
-			panic("Failed to create super admin")
-		}
-	}
+func SetupAdminRoutes(r *gin.Engine, a *app.App) {
+	limits := a.Limits
+	adminController := controllers.NewAdminController(a.Admins, a.Tokens)
 	adminGroup := r.Group("/api/v1/admin")
-	adminGroup.POST("/refresh", adminController.RefreshToken)
-	adminGroup.POST("/login", adminController.Login)
-	adminGroup.Use(middlewares.AuthMiddleware(), middlewares.IsAdminMiddleware())
+	adminGroup.POST("/refresh", limits.Write(), adminController.RefreshToken)
+	adminGroup.POST("/login", limits.Write(), adminController.Login)
+	adminGroup.POST("/forgot-password", limits.Write(), adminController.ForgotPassword)
+	adminGroup.POST("/reset-password", limits.Write(), adminController.ConfirmPasswordReset)
+	adminGroup.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, adminGroup.BasePath(), domain.AuditEntityAdmin, func(ctx context.Context, id uint) (any, error) {
+			return a.Admins.GetByID(ctx, id)
+		}))
 	{
 		adminGroup.POST("/", middlewares.IsSuperAdminMiddleware(), adminController.Create)
 		adminGroup.GET("/:id", adminController.GetByID)
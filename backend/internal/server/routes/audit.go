@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/controllers"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAuditRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewAuditController(a.Audit)
+
+	api := r.Group("/api/v1/audit")
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsSuperAdminMiddleware(), a.Limits.Authenticated())
+	{
+		api.GET("/", controller.GetAll)
+		api.GET("/export", controller.Export)
+	}
+}
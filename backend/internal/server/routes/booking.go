@@ -1,28 +1,37 @@
 package routes
 
 import (
-	"hiyab-tutor/internal/repository"
+	"context"
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupBookingRoutes(r *gin.Engine, db *gorm.DB) {
-	bookingRepo := repository.NewBookingRepository(db)
-	bookingUsecase := usecases.NewBookingUsecase(bookingRepo)
-	controller := controllers.NewBookingController(bookingUsecase)
+func SetupBookingRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewBookingController(a.Bookings)
+	exports := controllers.NewExportController(a.Exports)
+	imports := controllers.NewImportController(a.Imports)
 
 	api := r.Group("/api/v1/bookings")
 	// Public route
-	api.POST("/", controller.Create)
+	api.POST("/", a.Limits.Write(), a.Limits.Submit(), spamGuard(a), controller.Create)
 	// Protected routes (add auth middleware as needed)
-	api.Use(middlewares.AuthMiddleware(), middlewares.IsSuperAdminMiddleware())
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsSuperAdminMiddleware(), a.Limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, api.BasePath(), domain.AuditEntityBooking, func(ctx context.Context, id uint) (any, error) {
+			return a.Bookings.GetByID(ctx, id)
+		}))
 	{
 		api.GET("/", controller.GetAll)
+		api.GET("/quarantined", controller.GetQuarantined)
+		api.GET("/trash", controller.GetTrash)
+		api.GET("/export", exports.Bookings)
+		api.POST("/import", imports.Bookings)
+		api.POST("/bulk", controller.Bulk)
 		api.GET("/:id", controller.GetByID)
 		api.PUT("/:id/assign", controller.Assign)
+		api.PUT("/:id/release", controller.Release)
 	}
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/controllers"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupExportRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewExportController(a.Exports)
+
+	api := r.Group("/api/v1/exports")
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), a.Limits.Authenticated())
+	{
+		api.GET("/:id", controller.GetByID)
+		api.GET("/:id/download", controller.Download)
+	}
+}
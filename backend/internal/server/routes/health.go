@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes serves the probes. /livez says whether the process
+// should be restarted and /readyz whether it should get traffic: it
+// answers 503 only when a critical check fails, and a degraded instance
+// stays in rotation. Neither ever stops the process.
+func SetupHealthRoutes(r *gin.Engine, a *app.App) {
+	r.GET("/livez", probe(a.Liveness))
+	r.GET("/readyz", probe(a.Readiness))
+}
+
+func probe(checker *health.Checker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		report := checker.Run(ctx.Request.Context())
+		status := http.StatusOK
+		if report.Status == health.StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		ctx.Header("Cache-Control", "no-store")
+		ctx.JSON(status, report)
+	}
+}
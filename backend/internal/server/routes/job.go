@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/controllers"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupJobRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewJobController(a.Jobs)
+
+	api := r.Group("/api/v1/jobs")
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsSuperAdminMiddleware(), a.Limits.Authenticated())
+	{
+		api.GET("/", controller.GetAll)
+		api.GET("/:id", controller.GetByID)
+		api.POST("/:id/retry", controller.Retry)
+	}
+}
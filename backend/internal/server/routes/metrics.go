@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"hiyab-tutor/internal/app"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMetricsRoutes serves the Prometheus metrics on /metrics. When
+// METRICS_TOKEN is set scrapers have to send it as a bearer token.
+func SetupMetricsRoutes(r *gin.Engine, a *app.App) {
+	if a.Metrics == nil {
+		return
+	}
+	handler := gin.WrapH(a.Metrics.Handler())
+	token := a.Config.MetricsToken
+	r.GET("/metrics", func(ctx *gin.Context) {
+		if token != "" {
+			got := ctx.GetHeader("Authorization")
+			if subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+token)) != 1 {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid metrics token"})
+				return
+			}
+		}
+		handler(ctx)
+	})
+}
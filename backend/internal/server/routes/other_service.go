@@ -1,25 +1,20 @@
 package routes
 
 import (
+	"context"
+	"hiyab-tutor/internal/app"
 	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupOtherServiceRoutes(r *gin.Engine, db *gorm.DB) {
-	// Auto-migrate service and translations
-	_ = db.AutoMigrate(&domain.OtherService{}, &domain.OtherServiceTranslation{})
-
-	// Initialize usecase and controller
-	usecase := usecases.NewOtherServiceService(db)
-	controller := controllers.NewOtherServiceController(usecase)
+func SetupOtherServiceRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewOtherServiceController(a.OtherServices, a.Files)
 
 	// Public endpoints
-	public := r.Group("/api/v1/other-services")
+	public := r.Group("/api/v1/other-services", a.Limits.Read())
 	{
 		public.GET("/", controller.GetAll)
 		public.GET("/:id", controller.GetByID)
@@ -27,7 +22,10 @@ func SetupOtherServiceRoutes(r *gin.Engine, db *gorm.DB) {
 
 	// Protected endpoints (admin/superadmin)
 	protected := r.Group("/api/v1/other-services")
-	protected.Use(middlewares.AuthMiddleware(), middlewares.IsAdminMiddleware())
+	protected.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), a.Limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, protected.BasePath(), domain.AuditEntityOtherService, func(ctx context.Context, id uint) (any, error) {
+			return a.OtherServices.GetServiceByID(ctx, id, nil)
+		}))
 	{
 		protected.POST("/", controller.Create)
 		protected.PUT("/:id", controller.Update)
@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/storage"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// The routes only wire handlers, so they can be exercised with fakes and
+// no database.
+func fakeApp(t *testing.T, services domain.OtherServiceUsecase) *app.App {
+	return &app.App{
+		Tokens:        auth.NewTokens(strings.Repeat("k", 32), time.Hour, time.Hour),
+		Files:         storage.NewLocal(t.TempDir()),
+		OtherServices: services,
+	}
+}
+
+func TestOtherServiceRoutes_WithFakes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	services := &domain.OtherServiceUsecaseMock{
+		GetServiceByIDFunc: func(_ context.Context, id uint, languageCodes []string) (*domain.OtherService, error) {
+			if id != 7 {
+				return nil, errors.New("not found")
+			}
+			return &domain.OtherService{Model: domain.Model{ID: 7}, WebsiteURL: "https://example.org"}, nil
+		},
+	}
+	r := gin.New()
+	SetupOtherServiceRoutes(r, fakeApp(t, services))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/other-services/7", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var got domain.OtherService
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	require.Equal(t, "https://example.org", got.WebsiteURL)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/other-services/8", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// Writes need a token and never reach the usecase without one
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/other-services/7", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Empty(t, services.DeleteServiceCalls())
+}
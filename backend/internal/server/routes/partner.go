@@ -1,29 +1,29 @@
 package routes
 
 import (
+	"context"
+	"hiyab-tutor/internal/app"
 	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupPartnerRoutes(r *gin.Engine, db *gorm.DB) {
-	_ = db.AutoMigrate(&domain.Partner{})
+func SetupPartnerRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewPartnerController(a.Partners, a.Files)
 
-	usecase := usecases.NewPartnerUsecase(db)
-	controller := controllers.NewPartnerController(usecase)
-
-	public := r.Group("/api/v1/partners")
+	public := r.Group("/api/v1/partners", a.Limits.Read())
 	{
 		public.GET("/", controller.GetAll)
 		public.GET("/:id", controller.GetByID)
 	}
 
 	protected := r.Group("/api/v1/partners")
-	protected.Use(middlewares.AuthMiddleware(), middlewares.IsAdminMiddleware())
+	protected.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), a.Limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, protected.BasePath(), domain.AuditEntityPartner, func(ctx context.Context, id uint) (any, error) {
+			return a.Partners.GetPartnerByID(ctx, id)
+		}))
 	{
 		protected.POST("/", controller.Create)
 		protected.PUT("/:id", controller.Update)
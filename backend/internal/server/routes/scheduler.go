@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/controllers"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSchedulerRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewSchedulerController(a.Scheduler)
+
+	api := r.Group("/api/v1/scheduler")
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsSuperAdminMiddleware(), a.Limits.Authenticated())
+	{
+		api.GET("/tasks", controller.GetAll)
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/controllers"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSMSRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewSMSController(a.SMS)
+
+	api := r.Group("/api/v1/sms")
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsSuperAdminMiddleware(), a.Limits.Authenticated())
+	{
+		api.GET("/", controller.GetAll)
+		api.POST("/:id/retry", controller.Retry)
+	}
+}
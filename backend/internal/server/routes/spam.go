@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/server/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// spamGuard builds the spam checks for a public form endpoint. The
+// submission allowance is counted by a.Limits.Submit, in the shared store.
+func spamGuard(a *app.App) gin.HandlerFunc {
+	return middlewares.SpamGuard(middlewares.SpamGuardConfig{
+		Captcha:       a.Captcha,
+		HoneypotField: a.Config.HoneypotField,
+	})
+}
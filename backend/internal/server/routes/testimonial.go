@@ -1,31 +1,32 @@
 package routes
 
 import (
+	"context"
+	"hiyab-tutor/internal/app"
 	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupTestimonialRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupTestimonialRoutes(r *gin.Engine, a *app.App) {
 	// Allow reasonably large multipart forms (e.g., video uploads)
 	r.MaxMultipartMemory = 128 << 20 // 128 MiB
-	_ = db.AutoMigrate(&domain.Testimonial{}, &domain.TestimonialTranslation{})
 
-	usecase := usecases.NewTestimonialService(db)
-	controller := controllers.NewTestimonialControllerWithStorage(usecase)
+	controller := controllers.NewTestimonialControllerWithStorage(a.Testimonials, a.Files)
 
-	public := r.Group("/api/v1/testimonials")
+	public := r.Group("/api/v1/testimonials", a.Limits.Read())
 	{
 		public.GET("/", controller.GetAll)
 		public.GET("/:id", controller.GetByID)
 	}
 
 	protected := r.Group("/api/v1/testimonials")
-	protected.Use(middlewares.AuthMiddleware(), middlewares.IsAdminMiddleware())
+	protected.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), a.Limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, protected.BasePath(), domain.AuditEntityTestimonial, func(ctx context.Context, id uint) (any, error) {
+			return a.Testimonials.GetTestimonialByID(ctx, id)
+		}))
 	{
 		protected.POST("/", controller.Create)
 		protected.PUT("/:id", controller.Update)
@@ -1,28 +1,37 @@
 package routes
 
 import (
-	"hiyab-tutor/internal/repository"
+	"context"
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/domain"
 	"hiyab-tutor/internal/server/controllers"
 	"hiyab-tutor/internal/server/middlewares"
-	"hiyab-tutor/internal/usecases"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
-func SetupTutorRoutes(r *gin.Engine, db *gorm.DB) {
-	tutorRepo := repository.NewTutorRepository(db)
-	tutorUsecase := usecases.NewTutorUsecase(tutorRepo)
-	controller := controllers.NewTutorController(tutorUsecase)
+func SetupTutorRoutes(r *gin.Engine, a *app.App) {
+	controller := controllers.NewTutorController(a.Tutors, a.Files)
+	exports := controllers.NewExportController(a.Exports)
+	imports := controllers.NewImportController(a.Imports)
 
 	api := r.Group("/api/v1/tutors")
-	api.POST("/", controller.Create)
-	api.GET("/", controller.GetAll)
-	api.GET("/:id", controller.GetByID)
-	api.Use(middlewares.AuthMiddleware(), middlewares.IsAdminMiddleware())
+	api.POST("/", a.Limits.Write(), a.Limits.Submit(), spamGuard(a), controller.Create)
+	api.GET("/", a.Limits.Read(), controller.GetAll)
+	api.GET("/:id", a.Limits.Read(), middlewares.OptionalAuthMiddleware(a.Tokens), controller.GetByID)
+	api.Use(middlewares.AuthMiddleware(a.Tokens), middlewares.IsAdminMiddleware(), a.Limits.Authenticated(),
+		middlewares.AuditMiddleware(a.Audit, api.BasePath(), domain.AuditEntityTutor, func(ctx context.Context, id uint) (any, error) {
+			return a.Tutors.GetByID(ctx, id)
+		}))
 	{
 		api.PUT("/:id", controller.Update)
 		api.DELETE("/:id", controller.Delete)
 		api.PUT("/:id/verify", controller.Verify)
+		api.GET("/quarantined", controller.GetQuarantined)
+		api.GET("/trash", controller.GetTrash)
+		api.GET("/export", exports.Tutors)
+		api.POST("/import", imports.Tutors)
+		api.POST("/bulk", controller.Bulk)
+		api.PUT("/:id/release", controller.Release)
 	}
 }
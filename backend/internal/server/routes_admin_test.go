@@ -2,19 +2,26 @@ package server
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"os"
 	"testing"
+	"time"
 
 	"net/http"
 	"net/http/httptest"
 
-	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
 	serverRoutes "hiyab-tutor/internal/server/routes"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 type loginResponse struct {
@@ -23,42 +30,54 @@ type loginResponse struct {
 	User         interface{} `json:"user"`
 }
 
-func writeTestConfig(t *testing.T, username, password, jwtSecret string) func() {
+// testConfig seeds the given super admin and signs tokens with a test secret.
+func testConfig(username, password string) *config.Config {
+	c := config.Defaults()
+	c.JwtSecret = "test-secret-test-secret-test-secret"
+	c.AdminUsername = username
+	c.AdminPassword = password
+	c.WebAppUrl = "http://localhost:3000"
+	return c
+}
+
+// testApp builds the application on db and seeds the super admin, like
+// NewServer does.
+func testApp(t *testing.T, db *gorm.DB, cfg *config.Config) *app.App {
 	t.Helper()
-	content := []byte("JWT_SECRET: \"" + jwtSecret + "\"\nADMIN_USERNAME: \"" + username + "\"\nADMIN_PASSWORD: \"" + password + "\"\n")
-	if err := os.WriteFile("config.yaml", content, 0644); err != nil {
-		t.Fatalf("failed to write config.yaml: %v", err)
-	}
-	return func() { _ = os.Remove("config.yaml") }
+	a, err := app.New(cfg, db)
+	require.NoError(t, err)
+	require.NoError(t, a.SeedSuperAdmin(context.Background()))
+	return a
 }
 
 func TestAdminRoutes_AuthorizedFlow(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	// Prepare config for LoadConfig (JWT + admin seed)
-	cleanup := writeTestConfig(t, "superadmin", "superpass123", "test-secret")
-	defer cleanup()
+	// JWT + admin seed
+	cfg := testConfig("superadmin", "superpass123")
 
 	// DB
-	db := database.TestDB()
+	db := dbtest.Open()
 	require.NotNil(t, db)
 
 	// Router and routes
 	r := gin.New()
-	serverRoutes.SetupAdminRoutes(r, db)
+	serverRoutes.SetupAdminRoutes(r, testApp(t, db, cfg))
 
 	// Login
-	creds := map[string]string{"username": "superadmin", "password": "superpass123"}
-	body, _ := json.Marshal(creds)
-	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/login", bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
-	rr := httptest.NewRecorder()
-	r.ServeHTTP(rr, req)
-	require.Equal(t, http.StatusOK, rr.Code)
-
 	var tokens loginResponse
-	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tokens))
-	require.NotEmpty(t, tokens.AccessToken)
+	login := func(password string) {
+		creds := map[string]string{"username": "superadmin", "password": password}
+		body, _ := json.Marshal(creds)
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tokens))
+		require.NotEmpty(t, tokens.AccessToken)
+	}
+	login("superpass123")
 
 	// Helper to auth request
 	authReq := func(method, path string, body []byte) *httptest.ResponseRecorder {
@@ -73,11 +92,27 @@ func TestAdminRoutes_AuthorizedFlow(t *testing.T) {
 	}
 
 	// Get current admin
-	rr = authReq(http.MethodGet, "/api/v1/admin/me", nil)
+	rr := authReq(http.MethodGet, "/api/v1/admin/me", nil)
 	require.Equal(t, http.StatusOK, rr.Code)
 
+	// The seeded superadmin has to replace the password before anything else
+	rr = authReq(http.MethodGet, "/api/v1/admin/", nil)
+	require.Equal(t, http.StatusForbidden, rr.Code)
+	require.Contains(t, rr.Body.String(), domain.ErrPasswordChangeRequired.Error())
+
+	weak := map[string]string{"old_password": "superpass123", "new_password": "password123"}
+	body, _ := json.Marshal(weak)
+	rr = authReq(http.MethodPut, "/api/v1/admin/change-password", body)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	change := map[string]string{"old_password": "superpass123", "new_password": "superpass456"}
+	body, _ = json.Marshal(change)
+	rr = authReq(http.MethodPut, "/api/v1/admin/change-password", body)
+	require.Equal(t, http.StatusOK, rr.Code)
+	login("superpass456")
+
 	// Create admin (superadmin-only)
-	newAdmin := map[string]string{"username": "admin1", "password": "pass123456", "role": "admin", "name": "Admin One"}
+	newAdmin := map[string]string{"username": "admin1", "password": "pass123456", "role": "admin", "name": "Admin One", "email": "admin1@example.com"}
 	body, _ = json.Marshal(newAdmin)
 	rr = authReq(http.MethodPost, "/api/v1/admin/", body)
 	require.Equal(t, http.StatusCreated, rr.Code)
@@ -108,6 +143,22 @@ func TestAdminRoutes_AuthorizedFlow(t *testing.T) {
 	body, _ = json.Marshal(upd)
 	rr = authReq(http.MethodPut, "/api/v1/admin/"+itoa(createdID), body)
 	require.Equal(t, http.StatusOK, rr.Code)
+	// Contact details left out of the update are kept
+	var updated struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	require.Equal(t, "Admin Uno", updated.Name)
+	require.Equal(t, "admin1@example.com", updated.Email)
+
+	upd = map[string]string{"name": "Admin Uno", "role": "admin", "email": ""}
+	body, _ = json.Marshal(upd)
+	rr = authReq(http.MethodPut, "/api/v1/admin/"+itoa(createdID), body)
+	require.Equal(t, http.StatusOK, rr.Code)
+	updated.Email = ""
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	require.Empty(t, updated.Email)
 
 	// Reset password (superadmin-only)
 	reset := map[string]string{"new_password": "anotherPass123"}
@@ -119,8 +170,13 @@ func TestAdminRoutes_AuthorizedFlow(t *testing.T) {
 	rr = authReq(http.MethodDelete, "/api/v1/admin/"+itoa(createdID), nil)
 	require.Equal(t, http.StatusNoContent, rr.Code)
 
-	// Change password (current user)
-	change := map[string]string{"old_password": "superpass123", "new_password": "superpass456"}
+	// Change password (current user); the previous password can't be reused
+	change = map[string]string{"old_password": "superpass456", "new_password": "superpass123"}
+	body, _ = json.Marshal(change)
+	rr = authReq(http.MethodPut, "/api/v1/admin/change-password", body)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	change = map[string]string{"old_password": "superpass456", "new_password": "superpass789"}
 	body, _ = json.Marshal(change)
 	rr = authReq(http.MethodPut, "/api/v1/admin/change-password", body)
 	require.Equal(t, http.StatusOK, rr.Code)
@@ -130,4 +186,83 @@ func TestAdminRoutes_AuthorizedFlow(t *testing.T) {
 	_ = sqlDB.Close()
 }
 
+func TestAdminRoutes_ForgotPasswordAnswersAlike(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := dbtest.Open()
+	r := gin.New()
+	serverRoutes.SetupAdminRoutes(r, testApp(t, db, testConfig("superadmin", "superpass123")))
+
+	for _, username := range []string{"superadmin", "nobody"} {
+		body, _ := json.Marshal(map[string]string{"username": username})
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/forgot-password", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusAccepted, rr.Code, username)
+	}
+	// Both are sent, or not, in the background
+	var queued int64
+	require.NoError(t, db.Model(&domain.Job{}).Where("type = ?", domain.JobSendPasswordReset).Count(&queued).Error)
+	require.EqualValues(t, 2, queued)
+}
+
+func TestNew_RejectsWeakAdminPassword(t *testing.T) {
+	_, err := app.New(testConfig("superadmin", "short1"), dbtest.Open())
+	require.ErrorIs(t, err, domain.ErrPasswordTooShort)
+}
+
+func TestSeedSuperAdmin_FlagsSeededPassword(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open()
+	a := testApp(t, db, testConfig("superadmin", "superpass123"))
+	mustChange := func() bool {
+		var admin domain.Admin
+		require.NoError(t, db.Where("username = ?", "superadmin").First(&admin).Error)
+		return admin.MustChangePassword
+	}
+	require.True(t, mustChange())
+
+	// Seeded before the flag existed, still on ADMIN_PASSWORD
+	require.NoError(t, db.Model(&domain.Admin{}).Where("username = ?", "superadmin").Update("must_change_password", false).Error)
+	require.NoError(t, a.SeedSuperAdmin(ctx))
+	require.True(t, mustChange())
+
+	// Once the password is changed, restarting leaves the account alone
+	admin, err := a.Admins.GetByUsername(ctx, "superadmin")
+	require.NoError(t, err)
+	require.NoError(t, a.Admins.ChangePassword(ctx, admin.ID, "superpass123", "Another-pass-2024"))
+	require.False(t, mustChange())
+	require.NoError(t, a.SeedSuperAdmin(ctx))
+	require.False(t, mustChange())
+}
+
+func TestAdminRoutes_ResetTokenWorksOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := dbtest.Open()
+	r := gin.New()
+	serverRoutes.SetupAdminRoutes(r, testApp(t, db, testConfig("superadmin", "superpass123")))
+
+	var admin domain.Admin
+	require.NoError(t, db.Where("username = ?", "superadmin").First(&admin).Error)
+	sum := sha256.Sum256([]byte("reset-token"))
+	require.NoError(t, db.Create(&domain.PasswordResetToken{
+		AdminID:   admin.ID,
+		TokenHash: hex.EncodeToString(sum[:]),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}).Error)
+
+	confirm := func(password string) int {
+		body, _ := json.Marshal(map[string]string{"token": "reset-token", "new_password": password})
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/reset-password", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	// A password the policy rejects leaves the token usable
+	require.Equal(t, http.StatusBadRequest, confirm("password123"))
+	require.Equal(t, http.StatusOK, confirm("superpass456"))
+	require.Equal(t, http.StatusBadRequest, confirm("superpass789"))
+}
+
 func itoa(u uint) string { return fmt.Sprintf("%d", u) }
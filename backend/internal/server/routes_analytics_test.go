@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := testApp(t, dbtest.Open(), testConfig("superadmin", "superpass123"))
+	r := (&Server{App: a}).RegisterRoutes()
+	token, err := a.Tokens.Generate(&domain.Admin{Model: domain.Model{ID: 7}, Username: "ops", Role: "admin"}, auth.TokenTypeAccess)
+	require.NoError(t, err)
+
+	serveIfNoneMatch := func(path, token, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		return serveIfNoneMatch(path, token, "")
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve("/api/v1/analytics", "").Code)
+	require.Equal(t, http.StatusUnauthorized, serve("/api/v1/analytics/bookings", "").Code)
+
+	// Totals come with an ETag that holds until a record changes
+	w := serve("/api/v1/analytics", token)
+	require.Equal(t, http.StatusOK, w.Code)
+	var snapshot domain.AnalyticsSnapshot
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	require.Zero(t, snapshot.Totals.Partners)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	w = serveIfNoneMatch("/api/v1/analytics", token, etag)
+	require.Equal(t, http.StatusNotModified, w.Code)
+	require.Empty(t, w.Body.String())
+
+	require.NoError(t, a.DB.Create(&domain.Partner{Name: "Acme"}).Error)
+	w = serveIfNoneMatch("/api/v1/analytics", token, etag)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotEqual(t, etag, w.Header().Get("ETag"))
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	require.EqualValues(t, 1, snapshot.Totals.Partners)
+
+	w = serve("/api/v1/analytics/bookings?from=2025-03-01&to=2025-03-31&granularity=week", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusNotModified, serveIfNoneMatch("/api/v1/analytics/bookings?from=2025-03-01&to=2025-03-31&granularity=week", token, w.Header().Get("ETag")).Code)
+	var trend domain.BookingTrend
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trend))
+	require.Equal(t, "week", trend.Granularity)
+	require.Equal(t, "2025-02-24", trend.Points[0].Period)
+	require.Len(t, trend.Points, 6)
+
+	for _, path := range []string{
+		"/api/v1/analytics/tutors",
+		"/api/v1/analytics/time-to-assignment?granularity=month",
+		"/api/v1/analytics/time-to-verification",
+		"/api/v1/analytics/bookings/breakdown/area",
+	} {
+		require.Equal(t, http.StatusOK, serve(path, token).Code, path)
+	}
+
+	for _, path := range []string{
+		"/api/v1/analytics/bookings?from=March",
+		"/api/v1/analytics/bookings?granularity=hour",
+		"/api/v1/analytics/bookings?from=2025-03-31&to=2025-03-01",
+		"/api/v1/analytics/bookings/breakdown/age",
+	} {
+		require.Equal(t, http.StatusBadRequest, serve(path, token).Code, path)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := testApp(t, dbtest.Open(), testConfig("superadmin", "superpass123"))
+	r := (&Server{App: a}).RegisterRoutes()
+	token := func(role string) string {
+		token, err := a.Tokens.Generate(&domain.Admin{Model: domain.Model{ID: 7}, Username: "ops", Role: role}, auth.TokenTypeAccess)
+		require.NoError(t, err)
+		return token
+	}
+	admin, superadmin := token("admin"), token("superadmin")
+	send := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	tutors := []*domain.Tutor{
+		{FirstName: "Hana", EducationLevel: "BSc", Email: "hana@example.com", DayPerWeek: 3},
+		{FirstName: "Kebede", EducationLevel: "BSc", Email: "kebede@example.com", DayPerWeek: 5},
+		{FirstName: "Lulit", EducationLevel: "MSc", Email: "lulit@example.com", DayPerWeek: 5},
+	}
+	for _, tutor := range tutors {
+		require.NoError(t, a.DB.Create(tutor).Error)
+	}
+
+	w := send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"verify","filter":"education_level=BSc"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result domain.BulkResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Equal(t, 2, result.Updated)
+	var verified int64
+	require.NoError(t, a.DB.Model(&domain.Tutor{}).Where("verified = ?", true).Count(&verified).Error)
+	require.EqualValues(t, 2, verified)
+	var audited int64
+	require.NoError(t, a.DB.Model(&domain.AuditLog{}).Where("action = ? AND entity_id IN ?", "bulk_verify", []uint{tutors[0].ID, tutors[1].ID}).Count(&audited).Error)
+	require.EqualValues(t, 2, audited)
+
+	// A missing ID fails on its own while the rest are deleted
+	w = send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"delete","ids":[`+strconv.Itoa(int(tutors[2].ID))+`,999]}`)
+	require.Equal(t, http.StatusMultiStatus, w.Code, w.Body.String())
+	w = send(http.MethodGet, "/api/v1/tutors/trash", admin, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var trash domain.MultipleTutorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	require.Len(t, trash.Data, 1)
+	require.Equal(t, "Lulit", trash.Data[0].FirstName)
+
+	w = send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"restore","filter":"deleted=true"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = send(http.MethodGet, "/api/v1/tutors/?limit=10", "", "")
+	var list domain.MultipleTutorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 3, list.Pagination.Total)
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"verify"}`).Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"assign","ids":[1]}`).Code)
+
+	booking := &domain.Booking{FirstName: "Abebe", Grade: 5}
+	require.NoError(t, a.DB.Create(booking).Error)
+	body := `{"action":"assign","tutor_id":` + strconv.Itoa(int(tutors[0].ID)) + `,"ids":[` + strconv.Itoa(int(booking.ID)) + `]}`
+	require.Equal(t, http.StatusForbidden, send(http.MethodPost, "/api/v1/bookings/bulk", admin, body).Code)
+	w = send(http.MethodPost, "/api/v1/bookings/bulk", superadmin, body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, a.DB.First(booking, booking.ID).Error)
+	require.True(t, booking.Assigned)
+	require.Equal(t, tutors[0].ID, *booking.TutorID)
+
+	w = send(http.MethodPost, "/api/v1/bookings/bulk", superadmin, `{"action":"assign","tutor_id":999,"ids":[1]}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	// Records in the trash are gone for everything but bulk restore
+	trashed := strconv.Itoa(int(tutors[1].ID))
+	w = send(http.MethodPost, "/api/v1/tutors/bulk", admin, `{"action":"delete","ids":[`+trashed+`]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/tutors/"+trashed, "", "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodPut, "/api/v1/tutors/"+trashed+"/verify", admin, "").Code)
+	w = send(http.MethodPost, "/api/v1/bookings/bulk", superadmin, `{"action":"assign","tutor_id":`+trashed+`,"ids":[`+strconv.Itoa(int(booking.ID))+`]}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	w = send(http.MethodPut, "/api/v1/bookings/"+strconv.Itoa(int(booking.ID))+"/assign", superadmin, `{"tutor_id":`+trashed+`}`)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.NoError(t, a.DB.First(booking, booking.ID).Error)
+	require.Equal(t, tutors[0].ID, *booking.TutorID)
+
+	w = send(http.MethodPost, "/api/v1/bookings/bulk", superadmin, `{"action":"delete","ids":[`+strconv.Itoa(int(booking.ID))+`]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusNotFound, send(http.MethodGet, "/api/v1/bookings/"+strconv.Itoa(int(booking.ID)), superadmin, "").Code)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig("superadmin", "superpass123")
+	cfg.ExportDir = t.TempDir()
+	a := testApp(t, dbtest.Open(), cfg)
+	r := (&Server{App: a}).RegisterRoutes()
+	token := func(id uint, role string) string {
+		token, err := a.Tokens.Generate(&domain.Admin{Model: domain.Model{ID: id}, Username: "ops", Role: role}, auth.TokenTypeAccess)
+		require.NoError(t, err)
+		return token
+	}
+	admin, other, superadmin := token(7, "admin"), token(8, "admin"), token(1, "superadmin")
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.NoError(t, a.DB.Create(&domain.Booking{FirstName: "Abebe", Grade: 5}).Error)
+	require.NoError(t, a.DB.Create(&domain.Booking{FirstName: "Sara", Grade: 9}).Error)
+	require.NoError(t, a.DB.Create(&domain.Tutor{FirstName: "Hana", Verified: true}).Error)
+
+	// Small exports stream straight away
+	w := serve("/api/v1/bookings/export?columns=id,first_name&min_grade=6", superadmin)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), "attachment; filename=bookings-")
+	require.Equal(t, "id,first_name\n2,Sara\n", w.Body.String())
+
+	require.Equal(t, http.StatusForbidden, serve("/api/v1/bookings/export", admin).Code)
+	require.Equal(t, http.StatusBadRequest, serve("/api/v1/tutors/export?columns=password", admin).Code)
+	require.Equal(t, http.StatusBadRequest, serve("/api/v1/tutors/export?format=pdf", admin).Code)
+
+	// Asked for in the background, it is fetched later
+	w = serve("/api/v1/tutors/export?async=true&format=xlsx&verified=true", admin)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var e domain.Export
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &e))
+	require.Equal(t, domain.ExportStatusPending, e.Status)
+	location := w.Header().Get("Location")
+	require.Equal(t, "/api/v1/exports/1", location)
+	require.Equal(t, http.StatusConflict, serve(location+"/download", admin).Code)
+
+	require.NoError(t, a.Exports.Generate(context.Background(), e.ID))
+	w = serve(location, admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &e))
+	require.Equal(t, domain.ExportStatusReady, e.Status)
+	require.Equal(t, 1, e.Rows)
+
+	w = serve(location+"/download", admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), ".xlsx")
+	require.NotZero(t, w.Body.Len())
+
+	// Other admins can't see it; superadmins can
+	require.Equal(t, http.StatusNotFound, serve(location, other).Code)
+	require.Equal(t, http.StatusNotFound, serve(location+"/download", other).Code)
+	require.Equal(t, http.StatusOK, serve(location+"/download", superadmin).Code)
+	require.Equal(t, http.StatusNotFound, serve("/api/v1/exports/99", admin).Code)
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/health"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig("superadmin", "superpass123")
+	cfg.UploadDir = t.TempDir()
+	db := dbtest.Open()
+	a := testApp(t, db, cfg)
+	r := (&Server{App: a}).RegisterRoutes()
+
+	probe := func(path string) (int, health.Report) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var report health.Report
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+
+	code, report := probe("/livez")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusUp, report.Status)
+
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusUp, report.Status, report)
+	require.ElementsMatch(t, []string{"database", "migrations", "storage", "disk"}, keys(report.Checks))
+
+	// Losing the database takes the instance out of rotation without
+	// stopping it
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	code, report = probe("/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, health.StatusDown, report.Checks["database"].Status)
+	code, _ = probe("/livez")
+	require.Equal(t, http.StatusOK, code)
+}
+
+func keys[V any](m map[string]V) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hiyab-tutor/internal/auth"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := testApp(t, dbtest.Open(), testConfig("superadmin", "superpass123"))
+	r := (&Server{App: a}).RegisterRoutes()
+	token := func(role string) string {
+		token, err := a.Tokens.Generate(&domain.Admin{Model: domain.Model{ID: 7}, Username: "ops", Role: role}, auth.TokenTypeAccess)
+		require.NoError(t, err)
+		return token
+	}
+	admin, superadmin := token("admin"), token("superadmin")
+	upload := func(path, token, name, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", name)
+		require.NoError(t, err)
+		part.Write([]byte(content))
+		require.NoError(t, form.Close())
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	count := func(model any) int64 {
+		var n int64
+		require.NoError(t, a.DB.Model(model).Count(&n).Error)
+		return n
+	}
+
+	sheet := "first_name,grade,phone_number\nAbebe,5,0911234567\nSara,14,0922222222\n"
+	require.Equal(t, http.StatusForbidden, upload("/api/v1/bookings/import", admin, "students.csv", sheet).Code)
+
+	// A dry run by default
+	w := upload("/api/v1/bookings/import", superadmin, "students.csv", sheet)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var report domain.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.True(t, report.DryRun)
+	require.Equal(t, 1, report.Valid)
+	require.Equal(t, 3, report.Errors[0].Row)
+	require.Zero(t, count(&domain.Booking{}))
+
+	w = upload("/api/v1/bookings/import?dry_run=false", superadmin, "students.csv", sheet)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Zero(t, count(&domain.Booking{}))
+
+	w = upload("/api/v1/bookings/import?dry_run=false", superadmin, "students.csv", "first_name,grade,phone_number\nAbebe,5,0911234567\n")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.EqualValues(t, 1, count(&domain.Booking{}))
+
+	require.Equal(t, http.StatusBadRequest, upload("/api/v1/tutors/import", admin, "tutors.pdf", "x").Code)
+	require.Equal(t, http.StatusBadRequest, upload("/api/v1/tutors/import", admin, "tutors.csv", "first_name\nAbebe\n").Code)
+	w = upload("/api/v1/tutors/import?dry_run=false", admin, "tutors.csv",
+		"first_name,phone_number,email,education_level\nHana,0933333333,hana@example.com,BSc\n")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.EqualValues(t, 1, count(&domain.Tutor{}))
+}
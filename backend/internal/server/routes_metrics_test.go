@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hiyab-tutor/internal/database/dbtest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := testConfig("superadmin", "superpass123")
+	cfg.MetricsToken = "scrape-me"
+	a := testApp(t, dbtest.Open(), cfg)
+	r := (&Server{App: a}).RegisterRoutes()
+	r.(*gin.Engine).GET("/panic", func(*gin.Context) { panic("boom") })
+
+	serve := func(path, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	require.Equal(t, http.StatusOK, serve("/api/v1/partners/", "").Code)
+	require.Equal(t, http.StatusNotFound, serve("/wp-login.php", "").Code)
+	require.Equal(t, http.StatusInternalServerError, serve("/panic", "").Code)
+
+	require.Equal(t, http.StatusUnauthorized, serve("/metrics", "").Code)
+	require.Equal(t, http.StatusUnauthorized, serve("/metrics", "Bearer wrong").Code)
+	w := serve("/metrics", "Bearer scrape-me")
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	require.Contains(t, body, `hiyab_http_requests_total{method="GET",route="/api/v1/partners/",status="200"} 1`)
+	require.Contains(t, body, `hiyab_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, body, `hiyab_http_requests_total{method="GET",route="/panic",status="500"} 1`)
+	require.Contains(t, body, `go_sql_open_connections{db_name="primary"}`)
+	require.Contains(t, body, "hiyab_tutors_registered_total 0")
+}
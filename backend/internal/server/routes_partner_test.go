@@ -6,7 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	serverRoutes "hiyab-tutor/internal/server/routes"
 
@@ -19,9 +19,9 @@ import (
 func TestPartnerRoutes_GetAllAndGetByID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	db := database.TestDB()
+	db := dbtest.Open()
 	require.NotNil(t, db)
-	require.NoError(t, db.AutoMigrate(&domain.Partner{}))
+	a := testApp(t, db, testConfig("superadmin", "superpass123"))
 
 	// Seed a partner
 	seed := &domain.Partner{Name: "Acme", ImageURL: "http://img", WebsiteURL: "http://acme"}
@@ -29,7 +29,7 @@ func TestPartnerRoutes_GetAllAndGetByID(t *testing.T) {
 	require.NoError(t, res.Error)
 
 	r := gin.New()
-	serverRoutes.SetupPartnerRoutes(r, db)
+	serverRoutes.SetupPartnerRoutes(r, a)
 
 	// GET /api/v1/partners/
 	t.Run("GetAll", func(t *testing.T) {
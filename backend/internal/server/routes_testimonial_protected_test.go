@@ -7,10 +7,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 
-	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	serverRoutes "hiyab-tutor/internal/server/routes"
 
@@ -18,43 +17,47 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func writeTestConfigFile(t *testing.T, username, password, jwtSecret string) func() {
-	t.Helper()
-	content := []byte("JWT_SECRET: \"" + jwtSecret + "\"\nADMIN_USERNAME: \"" + username + "\"\nADMIN_PASSWORD: \"" + password + "\"\nWEB_APP_URL: \"http://localhost:3000\"\n")
-	if err := os.WriteFile("config.yaml", content, 0644); err != nil {
-		t.Fatalf("failed to write config.yaml: %v", err)
-	}
-	return func() { _ = os.Remove("config.yaml") }
-}
-
 func TestTestimonialRoutes_ProtectedCRUD(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	cleanup := writeTestConfigFile(t, "superadmin", "superpass123", "test-secret")
-	defer cleanup()
+	cfg := testConfig("superadmin", "superpass123")
+	cfg.UploadDir = t.TempDir()
 
-	db := database.TestDB()
+	db := dbtest.Open()
 	require.NotNil(t, db)
-	require.NoError(t, db.AutoMigrate(&domain.Testimonial{}, &domain.TestimonialTranslation{}))
+	a := testApp(t, db, cfg)
 
 	r := gin.New()
 	// Register both admin and testimonial routes to obtain token and call protected endpoints
-	serverRoutes.SetupAdminRoutes(r, db)
-	serverRoutes.SetupTestimonialRoutes(r, db)
+	serverRoutes.SetupAdminRoutes(r, a)
+	serverRoutes.SetupTestimonialRoutes(r, a)
 
 	// Login to get access token
-	creds := map[string]string{"username": "superadmin", "password": "superpass123"}
-	body, _ := json.Marshal(creds)
-	req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/login", bytes.NewReader(body))
+	var loginResp struct {
+		AccessToken string `json:"access_token"`
+	}
+	login := func(password string) {
+		creds := map[string]string{"username": "superadmin", "password": password}
+		body, _ := json.Marshal(creds)
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/admin/login", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &loginResp))
+		require.NotEmpty(t, loginResp.AccessToken)
+	}
+	login("superpass123")
+
+	// The seeded superadmin has to replace the password first
+	body, _ := json.Marshal(map[string]string{"old_password": "superpass123", "new_password": "superpass456"})
+	req, _ := http.NewRequest(http.MethodPut, "/api/v1/admin/change-password", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+loginResp.AccessToken)
 	rr := httptest.NewRecorder()
 	r.ServeHTTP(rr, req)
 	require.Equal(t, http.StatusOK, rr.Code)
-	var loginResp struct {
-		AccessToken string `json:"access_token"`
-	}
-	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &loginResp))
-	require.NotEmpty(t, loginResp.AccessToken)
+	login("superpass456")
 
 	authReq := func(method, path string, payload any) *httptest.ResponseRecorder {
 		var body bytes.Buffer
@@ -107,7 +110,4 @@ func TestTestimonialRoutes_ProtectedCRUD(t *testing.T) {
 
 	sqlDB, _ := db.DB()
 	_ = sqlDB.Close()
-	if err := os.Remove("uploads"); err != nil {
-		t.Log(err)
-	}
 }
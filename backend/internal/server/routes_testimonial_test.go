@@ -6,7 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
-	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/database/dbtest"
 	"hiyab-tutor/internal/domain"
 	serverRoutes "hiyab-tutor/internal/server/routes"
 
@@ -19,12 +19,12 @@ import (
 func TestTestimonialRoutes_GetAllAndGetByID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	db := database.TestDB()
+	db := dbtest.Open()
 	require.NotNil(t, db)
-	require.NoError(t, db.AutoMigrate(&domain.Testimonial{}, &domain.TestimonialTranslation{}))
+	a := testApp(t, db, testConfig("superadmin", "superpass123"))
 
 	r := gin.New()
-	serverRoutes.SetupTestimonialRoutes(r, db)
+	serverRoutes.SetupTestimonialRoutes(r, a)
 
 	// Seed directly via DB to avoid auth in this public-routes test
 	seed := &domain.Testimonial{
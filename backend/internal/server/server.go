@@ -1,37 +1,68 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
+	"hiyab-tutor/internal/app"
+	"hiyab-tutor/internal/config"
 	"hiyab-tutor/internal/database"
+	"hiyab-tutor/internal/lifecycle"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Server struct {
-	port int
-
-	DB database.Service
+	App *app.App
+	DB  database.Service
 }
 
-func NewServer() *http.Server {
-	port, _ := strconv.Atoi(os.Getenv("SERVER_PORT"))
-	newServer := &Server{
-		port: port,
-
-		DB: database.New(),
+// NewServer wires the application from an already validated configuration.
+// Shutting the returned manager down stops the server and then the
+// application behind it.
+func NewServer(cfg *config.Config) (*http.Server, *lifecycle.Manager) {
+	if cfg.AppEnv == config.EnvProduction {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	db := database.New(cfg)
+	if err := os.MkdirAll(cfg.UploadDir, 0o755); err != nil {
+		fatal("failed to create upload directory", err)
 	}
+	a, err := app.New(cfg, db.Gorm())
+	if err != nil {
+		fatal("failed to set up the application", err)
+	}
+	if err := a.SeedSuperAdmin(context.Background()); err != nil {
+		fatal("failed to create super admin", err)
+	}
+	a.Start()
+	newServer := &Server{App: a, DB: db}
 
 	// Declare Server config
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", newServer.port),
+		Addr:         fmt.Sprintf(":%s", cfg.ServerPort),
 		Handler:      newServer.RegisterRoutes(),
 		IdleTimeout:  time.Minute,
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
+	// Requests in flight finish before anything they use is closed
+	a.Lifecycle.OnShutdown(lifecycle.PhaseStopTraffic, "http server", func(ctx context.Context) error {
+		server.SetKeepAlivesEnabled(false)
+		return server.Shutdown(ctx)
+	})
+	a.Lifecycle.OnShutdown(lifecycle.PhaseClose, "database", func(context.Context) error {
+		return db.Close()
+	})
+
+	return server, a.Lifecycle
+}
 
-	return server
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"mime/multipart"
+	"sync"
+)
+
+// ErrDraining is returned for uploads that start after Drain was called.
+var ErrDraining = errors.New("the server is shutting down")
+
+// Draining keeps track of the files being saved so shutdown can wait for
+// them instead of leaving half written uploads behind.
+type Draining struct {
+	domain.FileStorage
+
+	mu       sync.Mutex
+	draining bool
+	active   sync.WaitGroup
+}
+
+func NewDraining(next domain.FileStorage) *Draining {
+	return &Draining{FileStorage: next}
+}
+
+func (d *Draining) Save(file *multipart.FileHeader, kind, name string) (string, error) {
+	d.mu.Lock()
+	if d.draining {
+		d.mu.Unlock()
+		return "", ErrDraining
+	}
+	d.active.Add(1)
+	d.mu.Unlock()
+	defer d.active.Done()
+	return d.FileStorage.Save(file, kind, name)
+}
+
+// Drain refuses new uploads and waits until the ones being saved are done
+// or ctx is.
+func (d *Draining) Drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		d.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// slowStorage saves once release is closed.
+type slowStorage struct {
+	Local
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowStorage) Save(*multipart.FileHeader, string, string) (string, error) {
+	close(s.started)
+	<-s.release
+	return "uploads/images/a.png", nil
+}
+
+func TestDraining(t *testing.T) {
+	slow := &slowStorage{started: make(chan struct{}), release: make(chan struct{})}
+	d := NewDraining(slow)
+
+	saved := make(chan error, 1)
+	go func() {
+		_, err := d.Save(nil, "images", "a.png")
+		saved <- err
+	}()
+	<-slow.started
+
+	// Drain waits for the upload in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, d.Drain(ctx), context.DeadlineExceeded)
+
+	// and refuses new ones
+	_, err := d.Save(nil, "images", "b.png")
+	require.ErrorIs(t, err, ErrDraining)
+
+	close(slow.release)
+	require.NoError(t, <-saved)
+	require.NoError(t, d.Drain(context.Background()))
+}
@@ -0,0 +1,87 @@
+// Package storage keeps uploaded files.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// URLPrefix is where the API serves the upload directory, relative to
+// /api/v1. Stored paths start with it.
+const URLPrefix = "uploads"
+
+// ErrForeignPath is returned for paths Local did not hand out.
+var ErrForeignPath = errors.New("path is not in the upload directory")
+
+// Local writes uploads below Dir, one sub directory per kind (images,
+// documents, videos, ...).
+type Local struct {
+	Dir string
+}
+
+var _ domain.FileStorage = (*Local)(nil)
+
+func NewLocal(dir string) *Local {
+	return &Local{Dir: dir}
+}
+
+// Save writes file to <Dir>/<kind>/<name> and returns "uploads/<kind>/<name>".
+func (l *Local) Save(file *multipart.FileHeader, kind, name string) (string, error) {
+	stored := path.Join(URLPrefix, kind, path.Base(name))
+	dst, err := l.Path(stored)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return stored, nil
+}
+
+// Remove deletes a file returned by Save. Files that are already gone are
+// not an error, and paths Local did not hand out (e.g. external URLs) are
+// left alone.
+func (l *Local) Remove(stored string) error {
+	file, err := l.Path(stored)
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Path maps a stored path to the file on disk, refusing paths that would
+// leave the upload directory.
+func (l *Local) Path(stored string) (string, error) {
+	clean := path.Clean("/" + strings.TrimPrefix(stored, "/"))
+	rel, ok := strings.CutPrefix(clean, "/"+URLPrefix+"/")
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrForeignPath, stored)
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(rel)), nil
+}
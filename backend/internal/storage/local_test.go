@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fileHeader(t *testing.T, name, content string) *multipart.FileHeader {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	part, err := w.CreateFormFile("file", name)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest("POST", "/", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	return req.MultipartForm.File["file"][0]
+}
+
+func TestLocal_SaveAndRemove(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLocal(dir)
+
+	stored, err := l.Save(fileHeader(t, "cv.pdf", "pdf"), "documents", "../../tutor-1.pdf")
+	require.NoError(t, err)
+	require.Equal(t, "uploads/documents/tutor-1.pdf", stored)
+	got, err := os.ReadFile(filepath.Join(dir, "documents", "tutor-1.pdf"))
+	require.NoError(t, err)
+	require.Equal(t, "pdf", string(got))
+
+	require.NoError(t, l.Remove(stored))
+	_, err = os.Stat(filepath.Join(dir, "documents", "tutor-1.pdf"))
+	require.True(t, errors.Is(err, os.ErrNotExist))
+	// Already gone, and not ours
+	require.NoError(t, l.Remove(stored))
+	require.NoError(t, l.Remove("https://cdn.example.com/a.png"))
+}
+
+func TestLocal_Path(t *testing.T) {
+	l := NewLocal("/srv/uploads")
+
+	p, err := l.Path("/uploads/images/a.png")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join("/srv/uploads", "images", "a.png"), p)
+
+	for _, stored := range []string{"uploads/../etc/passwd", "images/a.png", "/etc/passwd", "uploads"} {
+		_, err := l.Path(stored)
+		require.ErrorIs(t, err, ErrForeignPath, stored)
+	}
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"errors"
+	"hiyab-tutor/internal/database"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey holds the open span on the statement
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM registers callbacks that put a span around every query db
+// runs. The span is a child of whatever span is in the statement's
+// context, so queries only join the request's trace when the repository
+// passes it on with WithContext.
+func InstrumentGORM(db *gorm.DB, tp trace.TracerProvider) error {
+	tracer := tp.Tracer(instrumentationName)
+	system := semconv.DBSystemSqlite
+	if database.IsPostgres(db) {
+		system = semconv.DBSystemPostgreSQL
+	}
+	before := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			name := operation
+			if tx.Statement.Table != "" {
+				name += " " + tx.Statement.Table
+			}
+			_, span := tracer.Start(tx.Statement.Context, name,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(system, semconv.DBOperationName(operation)))
+			tx.InstanceSet(gormSpanKey, span)
+		}
+	}
+	after := func(tx *gorm.DB) {
+		v, _ := tx.InstanceGet(gormSpanKey)
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+		// The SQL has placeholders in place of the values, so no personal
+		// data ends up in the trace
+		span.SetAttributes(
+			semconv.DBQueryText(tx.Statement.SQL.String()),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+		if tx.Statement.Table != "" {
+			span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+		}
+		if !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			recordError(span, tx.Error)
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", before("INSERT")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", after),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", before("SELECT")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", after),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", before("UPDATE")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("DELETE")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", after),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", before("SELECT")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("EXEC")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", after),
+	)
+}
@@ -0,0 +1,102 @@
+// Package tracing sets up OpenTelemetry: the tracer provider picked by
+// TRACING_EXPORTER, spans for GORM queries and spans around usecase calls.
+// HTTP spans come from otelgin in the middlewares package.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/config"
+	"io"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// ServiceName is reported as service.name unless OTEL_SERVICE_NAME is set.
+const ServiceName = "hiyab-tutor"
+
+// instrumentationName names the tracers this package creates
+const instrumentationName = "hiyab-tutor/internal/tracing"
+
+// Propagator reads and writes the W3C traceparent, tracestate and baggage
+// headers.
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{}, propagation.Baggage{},
+)
+
+// Provider is a tracer provider that has to be shut down to flush the
+// spans it still buffers.
+type Provider interface {
+	trace.TracerProvider
+	Shutdown(ctx context.Context) error
+}
+
+// nopProvider records nothing, for TRACING_EXPORTER=none.
+type nopProvider struct{ noop.TracerProvider }
+
+func (nopProvider) Shutdown(context.Context) error { return nil }
+
+// NewNop returns a provider that records nothing.
+func NewNop() Provider { return nopProvider{} }
+
+// New builds the provider for c.TracingExporter. The stdout exporter writes
+// every span to w as soon as it ends; the OTLP exporter sends them in
+// batches over HTTP.
+func New(ctx context.Context, c *config.Config, w io.Writer) (Provider, error) {
+	var processor sdktrace.SpanProcessor
+	switch c.TracingExporter {
+	case config.TracingNone, "":
+		return NewNop(), nil
+	case config.TracingStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, err
+		}
+		processor = sdktrace.NewSimpleSpanProcessor(exp)
+	case config.TracingOTLP:
+		var opts []otlptracehttp.Option
+		if c.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(c.OTLPEndpoint))
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		processor = sdktrace.NewBatchSpanProcessor(exp)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", c.TracingExporter)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName), semconv.DeploymentEnvironment(c.AppEnv)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil && !errors.Is(err, resource.ErrPartialResource) {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(res),
+		// Follow the caller's decision so a trace isn't cut in half
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.TracingSampleRatio))),
+	), nil
+}
+
+// recordError marks the span as failed.
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hiyab-tutor/internal/config"
+	"hiyab-tutor/internal/database/dbtest"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/repository"
+	"hiyab-tutor/internal/usecases"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recorder() (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	rec := tracetest.NewSpanRecorder()
+	return rec, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec))
+}
+
+func spanNamed(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name() == name {
+			return s
+		}
+	}
+	t.Fatalf("no span named %q", name)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	c := config.Defaults()
+	tp, err := New(context.Background(), c, nil)
+	require.NoError(t, err)
+	_, span := tp.Tracer("test").Start(context.Background(), "ignored")
+	require.False(t, span.IsRecording())
+
+	var out bytes.Buffer
+	c.TracingExporter = config.TracingStdout
+	tp, err = New(context.Background(), c, &out)
+	require.NoError(t, err)
+	_, span = tp.Tracer("test").Start(context.Background(), "printed")
+	span.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.Contains(t, out.String(), `"Name": "printed"`)
+	require.Contains(t, out.String(), ServiceName)
+
+	c.TracingExporter = "jaeger"
+	_, err = New(context.Background(), c, nil)
+	require.Error(t, err)
+}
+
+func TestUsecaseAndQuerySpans(t *testing.T) {
+	rec, tp := recorder()
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&domain.Booking{}))
+	require.NoError(t, InstrumentGORM(db, tp))
+	bookings := Bookings(usecases.NewBookingUsecase(repository.NewBookingRepository(db), nil, nil), tp)
+
+	ctx, root := tp.Tracer("test").Start(context.Background(), "GET /api/v1/bookings")
+	_, err := bookings.GetAll(ctx, &domain.BookingFilter{})
+	require.NoError(t, err)
+	root.End()
+
+	spans := rec.Ended()
+	usecase := spanNamed(t, spans, "BookingUsecase.GetAll")
+	require.Equal(t, root.SpanContext().SpanID(), usecase.Parent().SpanID())
+
+	query := spanNamed(t, spans, "SELECT bookings")
+	require.Equal(t, usecase.SpanContext().SpanID(), query.Parent().SpanID())
+	require.Equal(t, trace.SpanKindClient, query.SpanKind())
+	require.Contains(t, query.Attributes(), semconv.DBCollectionName("bookings"))
+	var text string
+	for _, attr := range query.Attributes() {
+		if attr.Key == semconv.DBQueryTextKey {
+			text = attr.Value.AsString()
+		}
+	}
+	require.Regexp(t, "^SELECT .* FROM .bookings.", text)
+}
+
+func TestUsecaseSpan_RecordsErrors(t *testing.T) {
+	rec, tp := recorder()
+	boom := errors.New("boom")
+	var got context.Context
+	partners := Partners(&fakePartners{err: boom, ctx: &got}, tp)
+
+	_, err := partners.GetPartnerByID(context.Background(), 7)
+	require.ErrorIs(t, err, boom)
+
+	span := spanNamed(t, rec.Ended(), "PartnerUsecase.GetPartnerByID")
+	require.Equal(t, codes.Error, span.Status().Code)
+	// The wrapped usecase runs inside the span
+	require.Equal(t, span.SpanContext().SpanID(), trace.SpanContextFromContext(got).SpanID())
+}
+
+func TestInstrumentGORM_NotFoundIsNoError(t *testing.T) {
+	rec, tp := recorder()
+	db := dbtest.Open()
+	require.NoError(t, db.AutoMigrate(&domain.Booking{}))
+	require.NoError(t, InstrumentGORM(db, tp))
+	repo := repository.NewBookingRepository(db)
+
+	_, err := repo.GetByID(context.Background(), 42)
+	require.Error(t, err)
+
+	span := spanNamed(t, rec.Ended(), "SELECT bookings")
+	require.NotEqual(t, codes.Error, span.Status().Code)
+}
+
+type fakePartners struct {
+	domain.PartnerUsecase
+	err error
+	ctx *context.Context
+}
+
+func (f *fakePartners) GetPartnerByID(ctx context.Context, id uint) (*domain.Partner, error) {
+	*f.ctx = ctx
+	return nil, f.err
+}
@@ -0,0 +1,571 @@
+package tracing
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"io"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// call runs fn in a span named name and marks the span failed when fn
+// returns an error. The usecase decorators below are all built on it.
+func call[T any](ctx context.Context, tracer trace.Tracer, name string, fn func(context.Context) (T, error)) (T, error) {
+	ctx, span := tracer.Start(ctx, name)
+	defer span.End()
+	v, err := fn(ctx)
+	recordError(span, err)
+	return v, err
+}
+
+// do is call for functions that only return an error.
+func do(ctx context.Context, tracer trace.Tracer, name string, fn func(context.Context) error) error {
+	_, err := call(ctx, tracer, name, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// Admins adds a span around every call to u.
+func Admins(u domain.AdminUsecase, tp trace.TracerProvider) domain.AdminUsecase {
+	return &admins{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type admins struct {
+	next   domain.AdminUsecase
+	tracer trace.Tracer
+}
+
+func (u *admins) Create(ctx context.Context, admin *domain.Admin) (*domain.Admin, error) {
+	return call(ctx, u.tracer, "AdminUsecase.Create", func(ctx context.Context) (*domain.Admin, error) {
+		return u.next.Create(ctx, admin)
+	})
+}
+
+func (u *admins) GetByID(ctx context.Context, id uint) (*domain.Admin, error) {
+	return call(ctx, u.tracer, "AdminUsecase.GetByID", func(ctx context.Context) (*domain.Admin, error) {
+		return u.next.GetByID(ctx, id)
+	})
+}
+
+func (u *admins) GetByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	return call(ctx, u.tracer, "AdminUsecase.GetByUsername", func(ctx context.Context) (*domain.Admin, error) {
+		return u.next.GetByUsername(ctx, username)
+	})
+}
+
+func (u *admins) GetAll(ctx context.Context, f *domain.AdminFilter) (*domain.MultipleAdmins, error) {
+	return call(ctx, u.tracer, "AdminUsecase.GetAll", func(ctx context.Context) (*domain.MultipleAdmins, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *admins) Update(ctx context.Context, id uint, req *domain.UpdateAdminRequest) (*domain.Admin, error) {
+	return call(ctx, u.tracer, "AdminUsecase.Update", func(ctx context.Context) (*domain.Admin, error) {
+		return u.next.Update(ctx, id, req)
+	})
+}
+
+func (u *admins) Delete(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "AdminUsecase.Delete", func(ctx context.Context) error {
+		return u.next.Delete(ctx, id)
+	})
+}
+
+func (u *admins) ResetPassword(ctx context.Context, id uint, newPassword string) error {
+	return do(ctx, u.tracer, "AdminUsecase.ResetPassword", func(ctx context.Context) error {
+		return u.next.ResetPassword(ctx, id, newPassword)
+	})
+}
+
+func (u *admins) ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error {
+	return do(ctx, u.tracer, "AdminUsecase.ChangePassword", func(ctx context.Context) error {
+		return u.next.ChangePassword(ctx, id, oldPassword, newPassword)
+	})
+}
+
+func (u *admins) Login(ctx context.Context, username, password string) (*domain.Admin, error) {
+	return call(ctx, u.tracer, "AdminUsecase.Login", func(ctx context.Context) (*domain.Admin, error) {
+		return u.next.Login(ctx, username, password)
+	})
+}
+
+func (u *admins) RequestPasswordReset(ctx context.Context, username string) error {
+	return do(ctx, u.tracer, "AdminUsecase.RequestPasswordReset", func(ctx context.Context) error {
+		return u.next.RequestPasswordReset(ctx, username)
+	})
+}
+
+func (u *admins) SendPasswordReset(ctx context.Context, username string) error {
+	return do(ctx, u.tracer, "AdminUsecase.SendPasswordReset", func(ctx context.Context) error {
+		return u.next.SendPasswordReset(ctx, username)
+	})
+}
+
+func (u *admins) ResetPasswordWithToken(ctx context.Context, token, newPassword string) error {
+	return do(ctx, u.tracer, "AdminUsecase.ResetPasswordWithToken", func(ctx context.Context) error {
+		return u.next.ResetPasswordWithToken(ctx, token, newPassword)
+	})
+}
+
+// Analytics adds a span around every call to u.
+func Analytics(u domain.AnalyticsUsecase, tp trace.TracerProvider) domain.AnalyticsUsecase {
+	return &analytics{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type analytics struct {
+	next   domain.AnalyticsUsecase
+	tracer trace.Tracer
+}
+
+func (u *analytics) Totals(ctx context.Context) (*domain.AnalyticsSnapshot, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.Totals", func(ctx context.Context) (*domain.AnalyticsSnapshot, error) {
+		return u.next.Totals(ctx)
+	})
+}
+
+// Invalidate runs on every write, so it gets no span
+func (u *analytics) Invalidate() {
+	u.next.Invalidate()
+}
+
+func (u *analytics) Bookings(ctx context.Context, q *domain.AnalyticsQuery) (*domain.BookingTrend, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.Bookings", func(ctx context.Context) (*domain.BookingTrend, error) {
+		return u.next.Bookings(ctx, q)
+	})
+}
+
+func (u *analytics) Tutors(ctx context.Context, q *domain.AnalyticsQuery) (*domain.TutorTrend, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.Tutors", func(ctx context.Context) (*domain.TutorTrend, error) {
+		return u.next.Tutors(ctx, q)
+	})
+}
+
+func (u *analytics) TimeToAssignment(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.TimeToAssignment", func(ctx context.Context) (*domain.DurationReport, error) {
+		return u.next.TimeToAssignment(ctx, q)
+	})
+}
+
+func (u *analytics) TimeToVerification(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.TimeToVerification", func(ctx context.Context) (*domain.DurationReport, error) {
+		return u.next.TimeToVerification(ctx, q)
+	})
+}
+
+func (u *analytics) BookingBreakdown(ctx context.Context, q *domain.AnalyticsQuery, dimension string) (*domain.BookingBreakdown, error) {
+	return call(ctx, u.tracer, "AnalyticsUsecase.BookingBreakdown", func(ctx context.Context) (*domain.BookingBreakdown, error) {
+		return u.next.BookingBreakdown(ctx, q, dimension)
+	})
+}
+
+// Audit adds a span around every call to u.
+func Audit(u domain.AuditUsecase, tp trace.TracerProvider) domain.AuditUsecase {
+	return &audit{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type audit struct {
+	next   domain.AuditUsecase
+	tracer trace.Tracer
+}
+
+func (u *audit) Record(ctx context.Context, entry *domain.AuditLog, before, after any) error {
+	return do(ctx, u.tracer, "AuditUsecase.Record", func(ctx context.Context) error {
+		return u.next.Record(ctx, entry, before, after)
+	})
+}
+
+func (u *audit) GetAll(ctx context.Context, f *domain.AuditFilter) (*domain.MultipleAuditLogs, error) {
+	return call(ctx, u.tracer, "AuditUsecase.GetAll", func(ctx context.Context) (*domain.MultipleAuditLogs, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *audit) Export(ctx context.Context, f *domain.AuditFilter, fn func(*domain.AuditLog) error) error {
+	return do(ctx, u.tracer, "AuditUsecase.Export", func(ctx context.Context) error {
+		return u.next.Export(ctx, f, fn)
+	})
+}
+
+// Bookings adds a span around every call to u.
+func Bookings(u domain.BookingUsecase, tp trace.TracerProvider) domain.BookingUsecase {
+	return &bookings{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type bookings struct {
+	next   domain.BookingUsecase
+	tracer trace.Tracer
+}
+
+func (u *bookings) Create(ctx context.Context, b *domain.Booking) (*domain.Booking, error) {
+	return call(ctx, u.tracer, "BookingUsecase.Create", func(ctx context.Context) (*domain.Booking, error) {
+		return u.next.Create(ctx, b)
+	})
+}
+
+func (u *bookings) GetAll(ctx context.Context, f *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
+	return call(ctx, u.tracer, "BookingUsecase.GetAll", func(ctx context.Context) (domain.MultipleBookingResponse, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *bookings) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
+	return call(ctx, u.tracer, "BookingUsecase.GetByID", func(ctx context.Context) (*domain.Booking, error) {
+		return u.next.GetByID(ctx, id)
+	})
+}
+
+func (u *bookings) Update(ctx context.Context, id uint, b *domain.Booking) (*domain.Booking, error) {
+	return call(ctx, u.tracer, "BookingUsecase.Update", func(ctx context.Context) (*domain.Booking, error) {
+		return u.next.Update(ctx, id, b)
+	})
+}
+
+func (u *bookings) Delete(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "BookingUsecase.Delete", func(ctx context.Context) error {
+		return u.next.Delete(ctx, id)
+	})
+}
+
+func (u *bookings) Assign(ctx context.Context, id uint, tutorID uint) error {
+	return do(ctx, u.tracer, "BookingUsecase.Assign", func(ctx context.Context) error {
+		return u.next.Assign(ctx, id, tutorID)
+	})
+}
+
+func (u *bookings) Release(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "BookingUsecase.Release", func(ctx context.Context) error {
+		return u.next.Release(ctx, id)
+	})
+}
+
+func (u *bookings) Bulk(ctx context.Context, req *domain.BookingBulkRequest) (*domain.BulkResult, error) {
+	return call(ctx, u.tracer, "BookingUsecase.Bulk", func(ctx context.Context) (*domain.BulkResult, error) {
+		return u.next.Bulk(ctx, req)
+	})
+}
+
+// Exports adds a span around every call to u.
+func Exports(u domain.ExportUsecase, tp trace.TracerProvider) domain.ExportUsecase {
+	return &exports{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type exports struct {
+	next   domain.ExportUsecase
+	tracer trace.Tracer
+}
+
+func (u *exports) Large(ctx context.Context, req *domain.ExportRequest) (bool, error) {
+	return call(ctx, u.tracer, "ExportUsecase.Large", func(ctx context.Context) (bool, error) {
+		return u.next.Large(ctx, req)
+	})
+}
+
+func (u *exports) Write(ctx context.Context, req *domain.ExportRequest, w io.Writer) error {
+	return do(ctx, u.tracer, "ExportUsecase.Write", func(ctx context.Context) error {
+		return u.next.Write(ctx, req, w)
+	})
+}
+
+func (u *exports) Start(ctx context.Context, req *domain.ExportRequest) (*domain.Export, error) {
+	return call(ctx, u.tracer, "ExportUsecase.Start", func(ctx context.Context) (*domain.Export, error) {
+		return u.next.Start(ctx, req)
+	})
+}
+
+func (u *exports) Generate(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "ExportUsecase.Generate", func(ctx context.Context) error {
+		return u.next.Generate(ctx, id)
+	})
+}
+
+func (u *exports) GetByID(ctx context.Context, id uint) (*domain.Export, error) {
+	return call(ctx, u.tracer, "ExportUsecase.GetByID", func(ctx context.Context) (*domain.Export, error) {
+		return u.next.GetByID(ctx, id)
+	})
+}
+
+func (u *exports) Open(ctx context.Context, id uint) (*domain.Export, io.ReadCloser, error) {
+	var e *domain.Export
+	f, err := call(ctx, u.tracer, "ExportUsecase.Open", func(ctx context.Context) (io.ReadCloser, error) {
+		var (
+			f   io.ReadCloser
+			err error
+		)
+		e, f, err = u.next.Open(ctx, id)
+		return f, err
+	})
+	return e, f, err
+}
+
+// Imports adds a span around every call to u.
+func Imports(u domain.ImportUsecase, tp trace.TracerProvider) domain.ImportUsecase {
+	return &imports{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type imports struct {
+	next   domain.ImportUsecase
+	tracer trace.Tracer
+}
+
+func (u *imports) Import(ctx context.Context, req *domain.ImportRequest) (*domain.ImportReport, error) {
+	return call(ctx, u.tracer, "ImportUsecase.Import", func(ctx context.Context) (*domain.ImportReport, error) {
+		return u.next.Import(ctx, req)
+	})
+}
+
+// Jobs adds a span around every call to u.
+func Jobs(u domain.JobUsecase, tp trace.TracerProvider) domain.JobUsecase {
+	return &jobs{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type jobs struct {
+	next   domain.JobUsecase
+	tracer trace.Tracer
+}
+
+func (u *jobs) GetAll(ctx context.Context, f *domain.JobFilter) (*domain.MultipleJobs, error) {
+	return call(ctx, u.tracer, "JobUsecase.GetAll", func(ctx context.Context) (*domain.MultipleJobs, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *jobs) GetByID(ctx context.Context, id uint) (*domain.Job, error) {
+	return call(ctx, u.tracer, "JobUsecase.GetByID", func(ctx context.Context) (*domain.Job, error) {
+		return u.next.GetByID(ctx, id)
+	})
+}
+
+func (u *jobs) Retry(ctx context.Context, id uint) (*domain.Job, error) {
+	return call(ctx, u.tracer, "JobUsecase.Retry", func(ctx context.Context) (*domain.Job, error) {
+		return u.next.Retry(ctx, id)
+	})
+}
+
+// OtherServices adds a span around every call to u.
+func OtherServices(u domain.OtherServiceUsecase, tp trace.TracerProvider) domain.OtherServiceUsecase {
+	return &otherServices{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type otherServices struct {
+	next   domain.OtherServiceUsecase
+	tracer trace.Tracer
+}
+
+func (u *otherServices) CreateService(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	return call(ctx, u.tracer, "OtherServiceUsecase.CreateService", func(ctx context.Context) (*domain.OtherService, error) {
+		return u.next.CreateService(ctx, service)
+	})
+}
+
+func (u *otherServices) GetAllServices(ctx context.Context, filter *domain.ServiceFilter) (*domain.MultipleOtherServices, error) {
+	return call(ctx, u.tracer, "OtherServiceUsecase.GetAllServices", func(ctx context.Context) (*domain.MultipleOtherServices, error) {
+		return u.next.GetAllServices(ctx, filter)
+	})
+}
+
+func (u *otherServices) GetServiceByID(ctx context.Context, id uint, languageCodes []string) (*domain.OtherService, error) {
+	return call(ctx, u.tracer, "OtherServiceUsecase.GetServiceByID", func(ctx context.Context) (*domain.OtherService, error) {
+		return u.next.GetServiceByID(ctx, id, languageCodes)
+	})
+}
+
+func (u *otherServices) DeleteService(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "OtherServiceUsecase.DeleteService", func(ctx context.Context) error {
+		return u.next.DeleteService(ctx, id)
+	})
+}
+
+func (u *otherServices) UpdateService(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	return call(ctx, u.tracer, "OtherServiceUsecase.UpdateService", func(ctx context.Context) (*domain.OtherService, error) {
+		return u.next.UpdateService(ctx, service)
+	})
+}
+
+func (u *otherServices) AddTranslation(ctx context.Context, serviceID uint, translation *domain.OtherServiceTranslation) (*domain.OtherService, error) {
+	return call(ctx, u.tracer, "OtherServiceUsecase.AddTranslation", func(ctx context.Context) (*domain.OtherService, error) {
+		return u.next.AddTranslation(ctx, serviceID, translation)
+	})
+}
+
+// Partners adds a span around every call to u.
+func Partners(u domain.PartnerUsecase, tp trace.TracerProvider) domain.PartnerUsecase {
+	return &partners{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type partners struct {
+	next   domain.PartnerUsecase
+	tracer trace.Tracer
+}
+
+func (u *partners) CreatePartner(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	return call(ctx, u.tracer, "PartnerUsecase.CreatePartner", func(ctx context.Context) (*domain.Partner, error) {
+		return u.next.CreatePartner(ctx, partner)
+	})
+}
+
+func (u *partners) GetAllPartners(ctx context.Context, filter *domain.PartnerFilter) (*domain.MultiplePartners, error) {
+	return call(ctx, u.tracer, "PartnerUsecase.GetAllPartners", func(ctx context.Context) (*domain.MultiplePartners, error) {
+		return u.next.GetAllPartners(ctx, filter)
+	})
+}
+
+func (u *partners) GetPartnerByID(ctx context.Context, id uint) (*domain.Partner, error) {
+	return call(ctx, u.tracer, "PartnerUsecase.GetPartnerByID", func(ctx context.Context) (*domain.Partner, error) {
+		return u.next.GetPartnerByID(ctx, id)
+	})
+}
+
+func (u *partners) DeletePartner(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "PartnerUsecase.DeletePartner", func(ctx context.Context) error {
+		return u.next.DeletePartner(ctx, id)
+	})
+}
+
+func (u *partners) UpdatePartner(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	return call(ctx, u.tracer, "PartnerUsecase.UpdatePartner", func(ctx context.Context) (*domain.Partner, error) {
+		return u.next.UpdatePartner(ctx, partner)
+	})
+}
+
+// Scheduler adds a span around every call to u.
+func Scheduler(u domain.SchedulerUsecase, tp trace.TracerProvider) domain.SchedulerUsecase {
+	return &schedulerUsecase{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type schedulerUsecase struct {
+	next   domain.SchedulerUsecase
+	tracer trace.Tracer
+}
+
+func (u *schedulerUsecase) GetAll(ctx context.Context) ([]domain.ScheduledTask, error) {
+	return call(ctx, u.tracer, "SchedulerUsecase.GetAll", func(ctx context.Context) ([]domain.ScheduledTask, error) {
+		return u.next.GetAll(ctx)
+	})
+}
+
+// SMS adds a span around every call to u.
+func SMS(u domain.SMSUsecase, tp trace.TracerProvider) domain.SMSUsecase {
+	return &sms{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type sms struct {
+	next   domain.SMSUsecase
+	tracer trace.Tracer
+}
+
+func (u *sms) GetAll(ctx context.Context, f *domain.SMSFilter) (*domain.MultipleSMSMessages, error) {
+	return call(ctx, u.tracer, "SMSUsecase.GetAll", func(ctx context.Context) (*domain.MultipleSMSMessages, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *sms) Retry(ctx context.Context, id uint) (*domain.SMSMessage, error) {
+	return call(ctx, u.tracer, "SMSUsecase.Retry", func(ctx context.Context) (*domain.SMSMessage, error) {
+		return u.next.Retry(ctx, id)
+	})
+}
+
+// Testimonials adds a span around every call to u.
+func Testimonials(u domain.TestimonialUsecase, tp trace.TracerProvider) domain.TestimonialUsecase {
+	return &testimonials{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type testimonials struct {
+	next   domain.TestimonialUsecase
+	tracer trace.Tracer
+}
+
+func (u *testimonials) CreateTestimonial(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+	return call(ctx, u.tracer, "TestimonialUsecase.CreateTestimonial", func(ctx context.Context) (*domain.Testimonial, error) {
+		return u.next.CreateTestimonial(ctx, testimonial)
+	})
+}
+
+func (u *testimonials) GetAllTestimonials(ctx context.Context, filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
+	return call(ctx, u.tracer, "TestimonialUsecase.GetAllTestimonials", func(ctx context.Context) (*domain.MultipleTestimonialResponse, error) {
+		return u.next.GetAllTestimonials(ctx, filter)
+	})
+}
+
+func (u *testimonials) GetTestimonialByID(ctx context.Context, id uint) (*domain.Testimonial, error) {
+	return call(ctx, u.tracer, "TestimonialUsecase.GetTestimonialByID", func(ctx context.Context) (*domain.Testimonial, error) {
+		return u.next.GetTestimonialByID(ctx, id)
+	})
+}
+
+func (u *testimonials) DeleteTestimonial(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "TestimonialUsecase.DeleteTestimonial", func(ctx context.Context) error {
+		return u.next.DeleteTestimonial(ctx, id)
+	})
+}
+
+func (u *testimonials) UpdateTestimonial(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+	return call(ctx, u.tracer, "TestimonialUsecase.UpdateTestimonial", func(ctx context.Context) (*domain.Testimonial, error) {
+		return u.next.UpdateTestimonial(ctx, testimonial)
+	})
+}
+
+func (u *testimonials) AddTranslation(ctx context.Context, testimonialID uint, translation *domain.TestimonialTranslation) (*domain.Testimonial, error) {
+	return call(ctx, u.tracer, "TestimonialUsecase.AddTranslation", func(ctx context.Context) (*domain.Testimonial, error) {
+		return u.next.AddTranslation(ctx, testimonialID, translation)
+	})
+}
+
+// Tutors adds a span around every call to u.
+func Tutors(u domain.TutorUsecase, tp trace.TracerProvider) domain.TutorUsecase {
+	return &tutors{next: u, tracer: tp.Tracer(instrumentationName)}
+}
+
+type tutors struct {
+	next   domain.TutorUsecase
+	tracer trace.Tracer
+}
+
+func (u *tutors) Create(ctx context.Context, t *domain.Tutor) (*domain.Tutor, error) {
+	return call(ctx, u.tracer, "TutorUsecase.Create", func(ctx context.Context) (*domain.Tutor, error) {
+		return u.next.Create(ctx, t)
+	})
+}
+
+func (u *tutors) GetAll(ctx context.Context, f *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
+	return call(ctx, u.tracer, "TutorUsecase.GetAll", func(ctx context.Context) (domain.MultipleTutorResponse, error) {
+		return u.next.GetAll(ctx, f)
+	})
+}
+
+func (u *tutors) GetByID(ctx context.Context, id uint) (*domain.Tutor, error) {
+	return call(ctx, u.tracer, "TutorUsecase.GetByID", func(ctx context.Context) (*domain.Tutor, error) {
+		return u.next.GetByID(ctx, id)
+	})
+}
+
+func (u *tutors) Update(ctx context.Context, id uint, t *domain.Tutor) (*domain.Tutor, error) {
+	return call(ctx, u.tracer, "TutorUsecase.Update", func(ctx context.Context) (*domain.Tutor, error) {
+		return u.next.Update(ctx, id, t)
+	})
+}
+
+func (u *tutors) Delete(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "TutorUsecase.Delete", func(ctx context.Context) error {
+		return u.next.Delete(ctx, id)
+	})
+}
+
+func (u *tutors) Verify(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "TutorUsecase.Verify", func(ctx context.Context) error {
+		return u.next.Verify(ctx, id)
+	})
+}
+
+func (u *tutors) Release(ctx context.Context, id uint) error {
+	return do(ctx, u.tracer, "TutorUsecase.Release", func(ctx context.Context) error {
+		return u.next.Release(ctx, id)
+	})
+}
+
+func (u *tutors) Bulk(ctx context.Context, req *domain.TutorBulkRequest) (*domain.BulkResult, error) {
+	return call(ctx, u.tracer, "TutorUsecase.Bulk", func(ctx context.Context) (*domain.BulkResult, error) {
+		return u.next.Bulk(ctx, req)
+	})
+}
@@ -1,61 +1,107 @@
 package usecases
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/auth"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"hiyab-tutor/internal/notify"
 	"hiyab-tutor/internal/repository"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+// PasswordResetSettings configures the forgot-password flow.
+type PasswordResetSettings struct {
+	// Jobs sends the reset links in the background
+	Jobs     domain.JobEnqueuer
+	Notifier notify.Channel
+	TokenTTL time.Duration
+	// URL is the web page that accepts the token. The token is appended as
+	// the "token" query parameter.
+	URL string
+}
+
 type adminUsecase struct {
-	repo domain.AdminRepository
+	db     *gorm.DB
+	repo   domain.AdminRepository
+	resets domain.PasswordResetRepository
+	policy auth.PasswordPolicy
+	reset  PasswordResetSettings
 }
 
-func NewAdminUsecase(db *gorm.DB) *adminUsecase {
+// NewAdminUsecase uses repo and resets, which must read db, outside
+// transactions; inside one it builds them again on the transaction.
+func NewAdminUsecase(db *gorm.DB, repo domain.AdminRepository, resets domain.PasswordResetRepository, policy auth.PasswordPolicy, reset PasswordResetSettings) *adminUsecase {
 	return &adminUsecase{
-		repo: repository.NewAdminRepository(db),
+		db:     db,
+		repo:   repo,
+		resets: resets,
+		policy: policy,
+		reset:  reset,
 	}
 }
-func (u *adminUsecase) Create(admin *domain.Admin) (*domain.Admin, error) {
-	return u.repo.Create(admin)
-
+func (u *adminUsecase) Create(ctx context.Context, admin *domain.Admin) (*domain.Admin, error) {
+	if err := u.policy.Validate(admin.Password); err != nil {
+		return nil, err
+	}
+	created, err := u.repo.Create(ctx, admin)
+	if err != nil {
+		return nil, err
+	}
+	// BeforeCreate has already replaced the plaintext with its hash
+	if err := u.rememberPassword(ctx, created.ID, created.Password); err != nil {
+		return nil, err
+	}
+	return created, nil
 }
-func (u *adminUsecase) GetByID(id uint) (*domain.Admin, error) {
-	return u.repo.GetByID(id)
+func (u *adminUsecase) GetByID(ctx context.Context, id uint) (*domain.Admin, error) {
+	return u.repo.GetByID(ctx, id)
 }
-func (u *adminUsecase) GetByUsername(username string) (*domain.Admin, error) {
-	return u.repo.GetByUsername(username)
+func (u *adminUsecase) GetByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	return u.repo.GetByUsername(ctx, username)
 }
-func (u *adminUsecase) GetAll(f *domain.AdminFilter) (*domain.MultipleAdmins, error) {
-	return u.repo.GetAll(f)
+func (u *adminUsecase) GetAll(ctx context.Context, f *domain.AdminFilter) (*domain.MultipleAdmins, error) {
+	return u.repo.GetAll(ctx, f)
 }
-func (u *adminUsecase) Update(admin *domain.Admin) (*domain.Admin, error) {
-	if admin.ID == 0 {
+func (u *adminUsecase) Update(ctx context.Context, id uint, req *domain.UpdateAdminRequest) (*domain.Admin, error) {
+	if id == 0 {
 		return nil, domain.ErrInvalidID
 	}
-	existingAdmin, err := u.repo.GetByID(admin.ID)
+	existingAdmin, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	existingAdmin.Name = admin.Name
-	existingAdmin.Role = admin.Role
-	return u.repo.Update(existingAdmin)
+	existingAdmin.Name = req.Name
+	existingAdmin.Role = req.Role
+	if req.Email != nil {
+		existingAdmin.Email = *req.Email
+	}
+	if req.PhoneNumber != nil {
+		existingAdmin.PhoneNumber = *req.PhoneNumber
+	}
+	return u.repo.Update(ctx, existingAdmin)
 }
-func (u *adminUsecase) Delete(id uint) error {
-	return u.repo.Delete(id)
+func (u *adminUsecase) Delete(ctx context.Context, id uint) error {
+	return u.repo.Delete(ctx, id)
 }
-func (u *adminUsecase) ResetPassword(id uint, newPassword string) error {
-	admin, err := u.repo.GetByID(id)
+func (u *adminUsecase) ResetPassword(ctx context.Context, id uint, newPassword string) error {
+	admin, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	admin.Password = newPassword
-	_, err = u.repo.Update(admin)
-	return err
+	// The superadmin chose this password, so the owner has to replace it
+	return u.setPassword(ctx, admin, newPassword, true)
 }
-func (u *adminUsecase) Login(username, password string) (*domain.Admin, error) {
-	admin, err := u.repo.GetByUsername(username)
+func (u *adminUsecase) Login(ctx context.Context, username, password string) (*domain.Admin, error) {
+	admin, err := u.repo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
@@ -64,19 +110,169 @@ func (u *adminUsecase) Login(username, password string) (*domain.Admin, error) {
 	}
 	return admin, nil
 }
-func (u *adminUsecase) ChangePassword(id uint, oldPassword, newPassword string) error {
-	admin, err := u.repo.GetByID(id)
+func (u *adminUsecase) ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error {
+	admin, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(oldPassword)); err != nil {
 		return domain.ErrInvalidCredentials
 	}
+	return u.setPassword(ctx, admin, newPassword, false)
+}
+
+// setPassword validates the new password against the policy and the admin's
+// recent passwords, then stores its hash.
+func (u *adminUsecase) setPassword(ctx context.Context, admin *domain.Admin, newPassword string, mustChange bool) error {
+	if err := u.policy.Validate(newPassword); err != nil {
+		return err
+	}
+	reused, err := u.isRecentPassword(ctx, admin, newPassword)
+	if err != nil {
+		return err
+	}
+	if reused {
+		return domain.ErrPasswordReused
+	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	admin.Password = string(hashedPassword)
-	_, err = u.repo.Update(admin)
+	admin.MustChangePassword = mustChange
+	if _, err := u.repo.Update(ctx, admin); err != nil {
+		return err
+	}
+	return u.rememberPassword(ctx, admin.ID, admin.Password)
+}
+
+// isRecentPassword compares the candidate with the current hash and the last
+// HistorySize hashes.
+func (u *adminUsecase) isRecentPassword(ctx context.Context, admin *domain.Admin, password string) (bool, error) {
+	if bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)) == nil {
+		return true, nil
+	}
+	history, err := u.repo.GetPasswordHistory(ctx, admin.ID, u.policy.HistorySize)
+	if err != nil {
+		return false, err
+	}
+	for _, h := range history {
+		if bcrypt.CompareHashAndPassword([]byte(h.Hash), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (u *adminUsecase) RequestPasswordReset(ctx context.Context, username string) error {
+	_, err := u.reset.Jobs.Enqueue(ctx, domain.JobSendPasswordReset, domain.SendPasswordResetPayload{Username: username}, time.Time{})
+	return err
+}
+
+// SendPasswordReset sends a one-time reset link to the admin. Unknown
+// usernames and admins without an address are only logged.
+func (u *adminUsecase) SendPasswordReset(ctx context.Context, username string) error {
+	admin, err := u.repo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.FromContext(ctx).Info("password reset not sent: unknown username")
+			return nil
+		}
+		return err
+	}
+	if u.reset.Notifier == nil {
+		return domain.ErrInternalServer
+	}
+	token, hash, err := newResetToken()
+	if err != nil {
+		return err
+	}
+	// Only the latest link should work
+	if err := u.resets.InvalidateForAdmin(ctx, admin.ID); err != nil {
+		return err
+	}
+	if err := u.resets.Create(ctx, &domain.PasswordResetToken{
+		AdminID:   admin.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(u.reset.TokenTTL),
+	}); err != nil {
+		return err
+	}
+	err = u.reset.Notifier.Send(ctx, notify.Message{
+		To:      notify.Recipient{Name: admin.Name, Email: admin.Email, Phone: admin.PhoneNumber},
+		Subject: "Reset your Hiyab Tutor password",
+		Body:    u.resetMessage(token),
+	})
+	if errors.Is(err, notify.ErrNoAddress) {
+		logging.FromContext(ctx).Warn("password reset not sent: no address on file", "admin_id", admin.ID, "channel", u.reset.Notifier.Name())
+		return nil
+	}
 	return err
 }
+
+// ResetPasswordWithToken exchanges a reset token for a new password.
+func (u *adminUsecase) ResetPasswordWithToken(ctx context.Context, token, newPassword string) error {
+	stored, err := u.resets.GetByHash(ctx, hashResetToken(token))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrResetTokenInvalid
+		}
+		return err
+	}
+	// Claiming the token and changing the password commit together, so of
+	// two requests with the same token only one gets through, and a password
+	// the policy rejects leaves the token usable.
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		u := u.withTx(tx)
+		claimed, err := u.resets.Claim(ctx, stored.ID, time.Now())
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return domain.ErrResetTokenInvalid
+		}
+		admin, err := u.repo.GetByID(ctx, stored.AdminID)
+		if err != nil {
+			return err
+		}
+		return u.setPassword(ctx, admin, newPassword, false)
+	})
+}
+
+// withTx is a copy of u whose repositories run in tx.
+func (u *adminUsecase) withTx(tx *gorm.DB) *adminUsecase {
+	c := *u
+	c.db = tx
+	c.repo = repository.NewAdminRepository(tx)
+	c.resets = repository.NewPasswordResetRepository(tx)
+	return &c
+}
+
+func (u *adminUsecase) resetMessage(token string) string {
+	minutes := int(u.reset.TokenTTL.Minutes())
+	if u.reset.URL == "" {
+		return fmt.Sprintf("Your Hiyab Tutor password reset code is %s. It expires in %d minutes.", token, minutes)
+	}
+	return fmt.Sprintf("Reset your Hiyab Tutor password here: %s?token=%s\nThe link expires in %d minutes.", u.reset.URL, token, minutes)
+}
+
+func newResetToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	return token, hashResetToken(token), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (u *adminUsecase) rememberPassword(ctx context.Context, adminID uint, hash string) error {
+	if u.policy.HistorySize <= 0 {
+		return nil
+	}
+	return u.repo.AddPasswordHistory(ctx, &domain.PasswordHistory{AdminID: adminID, Hash: hash})
+}
@@ -0,0 +1,250 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hiyab-tutor/internal/domain"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reports cover the last defaultAnalyticsDays days unless asked otherwise,
+// and at most maxAnalyticsPeriods periods
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsPeriods  = 400
+)
+
+type analyticsUsecase struct {
+	repo domain.AnalyticsRepository
+	now  func() time.Time
+
+	// The totals snapshot, kept for ttl unless stale is set
+	ttl      time.Duration
+	mu       sync.Mutex
+	snapshot *domain.AnalyticsSnapshot
+	stale    atomic.Bool
+}
+
+// NewAnalyticsUsecase keeps the totals for ttl. Reports are always
+// computed afresh.
+func NewAnalyticsUsecase(repo domain.AnalyticsRepository, ttl time.Duration) domain.AnalyticsUsecase {
+	return &analyticsUsecase{repo: repo, now: time.Now, ttl: ttl}
+}
+
+func (u *analyticsUsecase) Totals(ctx context.Context) (*domain.AnalyticsSnapshot, error) {
+	// Callers wait for one refresh rather than each counting
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := u.now()
+	if u.snapshot != nil && !u.stale.Load() && now.Sub(u.snapshot.ComputedAt) < u.ttl {
+		return u.snapshot, nil
+	}
+	// Cleared first so a write made while counting is not lost
+	u.stale.Store(false)
+	totals, err := u.repo.Totals(ctx)
+	if err != nil {
+		u.stale.Store(true)
+		return nil, err
+	}
+	b, err := json.Marshal(totals)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	u.snapshot = &domain.AnalyticsSnapshot{Totals: *totals, ComputedAt: now, Version: hex.EncodeToString(sum[:8])}
+	return u.snapshot, nil
+}
+
+func (u *analyticsUsecase) Invalidate() {
+	u.stale.Store(true)
+}
+
+func (u *analyticsUsecase) Bookings(ctx context.Context, q *domain.AnalyticsQuery) (*domain.BookingTrend, error) {
+	q, err := u.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repo.Bookings(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BookingTrend{
+		AnalyticsRange: analyticsRange(q),
+		Points: fillPeriods(q, points, func(p domain.BookingPoint) string { return p.Period },
+			func(period string) domain.BookingPoint { return domain.BookingPoint{Period: period} }),
+	}, nil
+}
+
+func (u *analyticsUsecase) Tutors(ctx context.Context, q *domain.AnalyticsQuery) (*domain.TutorTrend, error) {
+	q, err := u.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repo.Tutors(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TutorTrend{
+		AnalyticsRange: analyticsRange(q),
+		Points: fillPeriods(q, points, func(p domain.TutorPoint) string { return p.Period },
+			func(period string) domain.TutorPoint { return domain.TutorPoint{Period: period} }),
+	}, nil
+}
+
+func (u *analyticsUsecase) TimeToAssignment(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	return u.durations(ctx, q, u.repo.TimeToAssignment)
+}
+
+func (u *analyticsUsecase) TimeToVerification(ctx context.Context, q *domain.AnalyticsQuery) (*domain.DurationReport, error) {
+	return u.durations(ctx, q, u.repo.TimeToVerification)
+}
+
+func (u *analyticsUsecase) durations(ctx context.Context, q *domain.AnalyticsQuery, get func(context.Context, *domain.AnalyticsQuery) (*domain.DurationReport, error)) (*domain.DurationReport, error) {
+	q, err := u.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	report, err := get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	report.AnalyticsRange = analyticsRange(q)
+	report.Points = fillPeriods(q, report.Points, func(p domain.DurationPoint) string { return p.Period },
+		func(period string) domain.DurationPoint { return domain.DurationPoint{Period: period} })
+	return report, nil
+}
+
+func (u *analyticsUsecase) BookingBreakdown(ctx context.Context, q *domain.AnalyticsQuery, dimension string) (*domain.BookingBreakdown, error) {
+	switch dimension {
+	case domain.DimensionGrade, domain.DimensionGender, domain.DimensionArea:
+	default:
+		return nil, domain.ErrInvalidDimension
+	}
+	q, err := u.normalize(q)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repo.BookingBreakdown(ctx, q, dimension)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int64{}
+	for i := range points {
+		if points[i].Key == "" {
+			points[i].Key = "unknown"
+		}
+		totals[points[i].Key] += points[i].Count
+	}
+	breakdown := &domain.BookingBreakdown{
+		AnalyticsRange: analyticsRange(q),
+		Dimension:      dimension,
+		Totals:         make([]domain.GroupCount, 0, len(totals)),
+		Points:         points,
+	}
+	for key, count := range totals {
+		breakdown.Totals = append(breakdown.Totals, domain.GroupCount{Key: key, Count: count})
+	}
+	// Largest groups first
+	slices.SortFunc(breakdown.Totals, func(a, b domain.GroupCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Key, b.Key))
+	})
+	return breakdown, nil
+}
+
+// normalize fills in the defaults, the last defaultAnalyticsDays days by
+// day, and checks the query. The caller's query is left untouched.
+func (u *analyticsUsecase) normalize(q *domain.AnalyticsQuery) (*domain.AnalyticsQuery, error) {
+	var n domain.AnalyticsQuery
+	if q != nil {
+		n = *q
+	}
+	if n.Granularity == "" {
+		n.Granularity = domain.GranularityDay
+	}
+	switch n.Granularity {
+	case domain.GranularityDay, domain.GranularityWeek, domain.GranularityMonth:
+	default:
+		return nil, domain.ErrInvalidGranularity
+	}
+	if n.To.IsZero() {
+		n.To = u.now()
+	}
+	n.To = day(n.To)
+	if n.From.IsZero() {
+		n.From = n.To.AddDate(0, 0, 1-defaultAnalyticsDays)
+	}
+	n.From = day(n.From)
+	if n.To.Before(n.From) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	periods := 0
+	for p := periodStart(n.From, n.Granularity); !p.After(n.To); p = nextPeriod(p, n.Granularity) {
+		if periods++; periods > maxAnalyticsPeriods {
+			return nil, domain.ErrRangeTooLarge
+		}
+	}
+	return &n, nil
+}
+
+func analyticsRange(q *domain.AnalyticsQuery) domain.AnalyticsRange {
+	return domain.AnalyticsRange{
+		From:        q.From.Format(time.DateOnly),
+		To:          q.To.Format(time.DateOnly),
+		Granularity: q.Granularity,
+	}
+}
+
+// fillPeriods returns one point per period of the range, adding empty ones
+// for the periods points has nothing for.
+func fillPeriods[T any](q *domain.AnalyticsQuery, points []T, period func(T) string, empty func(string) T) []T {
+	byPeriod := make(map[string]T, len(points))
+	for _, p := range points {
+		byPeriod[period(p)] = p
+	}
+	filled := []T{}
+	for p := periodStart(q.From, q.Granularity); !p.After(q.To); p = nextPeriod(p, q.Granularity) {
+		key := p.Format(time.DateOnly)
+		if point, ok := byPeriod[key]; ok {
+			filled = append(filled, point)
+		} else {
+			filled = append(filled, empty(key))
+		}
+	}
+	return filled
+}
+
+// day is the start of t's day in UTC.
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// periodStart is the first day of the period t falls in, the way
+// database.PeriodStart computes it.
+func periodStart(t time.Time, granularity string) time.Time {
+	t = day(t)
+	switch granularity {
+	case domain.GranularityWeek:
+		return t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	case domain.GranularityMonth:
+		return t.AddDate(0, 0, 1-t.Day())
+	}
+	return t
+}
+
+func nextPeriod(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case domain.GranularityWeek:
+		return t.AddDate(0, 0, 7)
+	case domain.GranularityMonth:
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
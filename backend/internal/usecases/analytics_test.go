@@ -0,0 +1,150 @@
+package usecases
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAnalyticsRepository struct {
+	domain.AnalyticsRepository
+	query     *domain.AnalyticsQuery
+	totals    domain.AnalyticsTotals
+	counted   int
+	bookings  []domain.BookingPoint
+	breakdown []domain.GroupPoint
+}
+
+func (m *mockAnalyticsRepository) Totals(context.Context) (*domain.AnalyticsTotals, error) {
+	m.counted++
+	totals := m.totals
+	return &totals, nil
+}
+
+func (m *mockAnalyticsRepository) Bookings(_ context.Context, q *domain.AnalyticsQuery) ([]domain.BookingPoint, error) {
+	m.query = q
+	return m.bookings, nil
+}
+
+func (m *mockAnalyticsRepository) BookingBreakdown(_ context.Context, q *domain.AnalyticsQuery, dimension string) ([]domain.GroupPoint, error) {
+	m.query = q
+	return m.breakdown, nil
+}
+
+type AnalyticsUsecaseTestSuite struct {
+	suite.Suite
+	repo    *mockAnalyticsRepository
+	usecase *analyticsUsecase
+}
+
+func TestAnalyticsUsecase(t *testing.T) {
+	suite.Run(t, new(AnalyticsUsecaseTestSuite))
+}
+
+func (s *AnalyticsUsecaseTestSuite) SetupTest() {
+	s.repo = &mockAnalyticsRepository{}
+	s.usecase = NewAnalyticsUsecase(s.repo, 5*time.Minute).(*analyticsUsecase)
+	// Wednesday 19 March 2025, late in the evening in Addis Ababa
+	s.usecase.now = func() time.Time { return time.Date(2025, 3, 19, 23, 30, 0, 0, time.FixedZone("EAT", 3*60*60)) }
+}
+
+func (s *AnalyticsUsecaseTestSuite) TestTotalsSnapshot() {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 19, 12, 0, 0, 0, time.UTC)
+	s.usecase.now = func() time.Time { return now }
+	s.repo.totals.Bookings = 3
+
+	first, err := s.usecase.Totals(ctx)
+	s.NoError(err)
+	s.EqualValues(3, first.Totals.Bookings)
+	s.NotEmpty(first.Version)
+
+	// Cached until the TTL is up
+	now = now.Add(4 * time.Minute)
+	s.repo.totals.Bookings = 4
+	cached, err := s.usecase.Totals(ctx)
+	s.NoError(err)
+	s.Same(first, cached)
+	s.Equal(1, s.repo.counted)
+
+	// A write makes it stale straight away
+	s.usecase.Invalidate()
+	fresh, err := s.usecase.Totals(ctx)
+	s.NoError(err)
+	s.EqualValues(4, fresh.Totals.Bookings)
+	s.NotEqual(first.Version, fresh.Version)
+
+	// Unchanged totals keep their version
+	now = now.Add(10 * time.Minute)
+	again, err := s.usecase.Totals(ctx)
+	s.NoError(err)
+	s.Equal(3, s.repo.counted)
+	s.Equal(fresh.Version, again.Version)
+	s.Equal(now, again.ComputedAt)
+}
+
+func (s *AnalyticsUsecaseTestSuite) TestDefaults() {
+	trend, err := s.usecase.Bookings(context.Background(), nil)
+	s.NoError(err)
+	s.Equal(domain.AnalyticsRange{From: "2025-02-18", To: "2025-03-19", Granularity: domain.GranularityDay}, trend.AnalyticsRange)
+	s.Equal(time.Date(2025, 2, 18, 0, 0, 0, 0, time.UTC), s.repo.query.From)
+	s.Len(trend.Points, 30)
+}
+
+func (s *AnalyticsUsecaseTestSuite) TestFillsEmptyPeriods() {
+	s.repo.bookings = []domain.BookingPoint{{Period: "2025-03-10", Bookings: 4, Assigned: 1}}
+	trend, err := s.usecase.Bookings(context.Background(), &domain.AnalyticsQuery{
+		From:        time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC),
+		Granularity: domain.GranularityWeek,
+	})
+	s.NoError(err)
+	// The first week starts before the range does
+	s.Equal([]domain.BookingPoint{
+		{Period: "2025-03-03"},
+		{Period: "2025-03-10", Bookings: 4, Assigned: 1},
+		{Period: "2025-03-17"},
+	}, trend.Points)
+}
+
+func (s *AnalyticsUsecaseTestSuite) TestRejectsBadQueries() {
+	ctx := context.Background()
+	_, err := s.usecase.Bookings(ctx, &domain.AnalyticsQuery{Granularity: "hour"})
+	s.ErrorIs(err, domain.ErrInvalidGranularity)
+
+	_, err = s.usecase.Bookings(ctx, &domain.AnalyticsQuery{
+		From: time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+	s.ErrorIs(err, domain.ErrInvalidDateRange)
+
+	_, err = s.usecase.Bookings(ctx, &domain.AnalyticsQuery{From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)})
+	s.ErrorIs(err, domain.ErrRangeTooLarge)
+	// The same range is fine by month
+	_, err = s.usecase.Bookings(ctx, &domain.AnalyticsQuery{From: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Granularity: domain.GranularityMonth})
+	s.NoError(err)
+
+	_, err = s.usecase.BookingBreakdown(ctx, nil, "age")
+	s.ErrorIs(err, domain.ErrInvalidDimension)
+}
+
+func (s *AnalyticsUsecaseTestSuite) TestBookingBreakdownTotals() {
+	s.repo.breakdown = []domain.GroupPoint{
+		{Period: "2025-03-01", Key: "bole", Count: 1},
+		{Period: "2025-03-01", Key: "", Count: 2},
+		{Period: "2025-03-02", Key: "bole", Count: 2},
+		{Period: "2025-03-02", Key: "piassa", Count: 1},
+	}
+	breakdown, err := s.usecase.BookingBreakdown(context.Background(), nil, domain.DimensionArea)
+	s.NoError(err)
+	s.Equal(domain.DimensionArea, breakdown.Dimension)
+	s.Equal([]domain.GroupCount{
+		{Key: "bole", Count: 3},
+		{Key: "unknown", Count: 2},
+		{Key: "piassa", Count: 1},
+	}, breakdown.Totals)
+	s.Equal("unknown", breakdown.Points[1].Key)
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"hiyab-tutor/internal/domain"
+	"reflect"
+	"strings"
+)
+
+// auditIgnoredFields change on every write and would only add noise.
+var auditIgnoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+// auditRedactedFields are recorded as changed without their values.
+var auditRedactedFields = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"secret":        true,
+	"api_key":       true,
+}
+
+const auditRedacted = "[REDACTED]"
+
+type auditUsecase struct {
+	repo domain.AuditRepository
+}
+
+func NewAuditUsecase(repo domain.AuditRepository) domain.AuditUsecase {
+	return &auditUsecase{repo: repo}
+}
+
+func (u *auditUsecase) Record(ctx context.Context, entry *domain.AuditLog, before, after any) error {
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		return err
+	}
+	entry.Changes = changes
+	return u.repo.Create(ctx, entry)
+}
+
+func (u *auditUsecase) GetAll(ctx context.Context, f *domain.AuditFilter) (*domain.MultipleAuditLogs, error) {
+	if f != nil && !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	return u.repo.GetAll(ctx, f)
+}
+
+func (u *auditUsecase) Export(ctx context.Context, f *domain.AuditFilter, fn func(*domain.AuditLog) error) error {
+	if f != nil && !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return domain.ErrInvalidDateRange
+	}
+	return u.repo.Each(ctx, f, fn)
+}
+
+// diffSnapshots compares the JSON form of two snapshots field by field. Nested
+// values are compared as a whole.
+func diffSnapshots(before, after any) (map[string]domain.FieldChange, error) {
+	from, err := toFieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := toFieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[string]domain.FieldChange)
+	for key, old := range from {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if val, ok := to[key]; !ok || !reflect.DeepEqual(old, val) {
+			changes[key] = redact(key, domain.FieldChange{From: old, To: val})
+		}
+	}
+	for key, val := range to {
+		if _, ok := from[key]; ok || auditIgnoredFields[key] {
+			continue
+		}
+		changes[key] = redact(key, domain.FieldChange{From: nil, To: val})
+	}
+	return changes, nil
+}
+
+func toFieldMap(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return map[string]any{}, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object (e.g. a plain message); nothing to diff
+		return map[string]any{}, nil
+	}
+	return fields, nil
+}
+
+func redact(key string, change domain.FieldChange) domain.FieldChange {
+	if auditRedactedFields[strings.ToLower(key)] {
+		return domain.FieldChange{From: auditRedacted, To: auditRedacted}
+	}
+	return change
+}
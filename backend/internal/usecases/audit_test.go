@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type mockAuditRepository struct {
+	entries []domain.AuditLog
+}
+
+func (m *mockAuditRepository) Create(_ context.Context, entry *domain.AuditLog) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, *entry)
+	return nil
+}
+func (m *mockAuditRepository) GetAll(_ context.Context, f *domain.AuditFilter) (*domain.MultipleAuditLogs, error) {
+	return &domain.MultipleAuditLogs{Data: m.entries, Pagination: domain.Pagination{Total: len(m.entries)}}, nil
+}
+func (m *mockAuditRepository) Each(_ context.Context, f *domain.AuditFilter, fn func(*domain.AuditLog) error) error {
+	for i := range m.entries {
+		if err := fn(&m.entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type AuditUsecaseTestSuite struct {
+	suite.Suite
+	repo    *mockAuditRepository
+	usecase domain.AuditUsecase
+}
+
+func TestAuditUsecase(t *testing.T) {
+	suite.Run(t, new(AuditUsecaseTestSuite))
+}
+
+func (s *AuditUsecaseTestSuite) SetupTest() {
+	s.repo = &mockAuditRepository{}
+	s.usecase = NewAuditUsecase(s.repo)
+}
+
+func (s *AuditUsecaseTestSuite) TestRecordUpdateKeepsOnlyChangedFields() {
+	before := &domain.Tutor{Model: domain.Model{ID: 3}, FirstName: "Abebe", Verified: false}
+	after := &domain.Tutor{Model: domain.Model{ID: 3, UpdatedAt: time.Now()}, FirstName: "Abebe", Verified: true}
+
+	err := s.usecase.Record(context.Background(), &domain.AuditLog{Action: "verify", EntityType: domain.AuditEntityTutor, EntityID: 3}, before, after)
+	s.NoError(err)
+	s.Require().Len(s.repo.entries, 1)
+	changes := s.repo.entries[0].Changes
+	s.Len(changes, 1)
+	s.Equal(domain.FieldChange{From: nil, To: true}, changes["verified"])
+}
+
+func (s *AuditUsecaseTestSuite) TestRecordCreateAndDelete() {
+	booking := &domain.Booking{Model: domain.Model{ID: 9}, FirstName: "Sara", Grade: 5}
+
+	s.NoError(s.usecase.Record(context.Background(), &domain.AuditLog{Action: domain.AuditActionCreate}, nil, booking))
+	s.Equal(domain.FieldChange{From: nil, To: "Sara"}, s.repo.entries[0].Changes["first_name"])
+
+	s.NoError(s.usecase.Record(context.Background(), &domain.AuditLog{Action: domain.AuditActionDelete}, booking, nil))
+	s.Equal(domain.FieldChange{From: float64(5), To: nil}, s.repo.entries[1].Changes["grade"])
+}
+
+func (s *AuditUsecaseTestSuite) TestRecordRedactsSecrets() {
+	before := map[string]any{"password": "old-hash", "name": "A"}
+	after := map[string]any{"password": "new-hash", "name": "A"}
+
+	s.NoError(s.usecase.Record(context.Background(), &domain.AuditLog{}, before, after))
+	s.Equal(domain.FieldChange{From: "[REDACTED]", To: "[REDACTED]"}, s.repo.entries[0].Changes["password"])
+	s.NotContains(s.repo.entries[0].Changes, "name")
+}
+
+func (s *AuditUsecaseTestSuite) TestRejectsInvertedDateRange() {
+	now := time.Now()
+	_, err := s.usecase.GetAll(context.Background(), &domain.AuditFilter{From: now, To: now.Add(-time.Hour)})
+	s.ErrorIs(err, domain.ErrInvalidDateRange)
+	err = s.usecase.Export(context.Background(), &domain.AuditFilter{From: now, To: now.Add(-time.Hour)}, func(*domain.AuditLog) error { return nil })
+	s.ErrorIs(err, domain.ErrInvalidDateRange)
+}
@@ -1,43 +1,252 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"hiyab-tutor/internal/phone"
+	"strings"
+	"time"
 )
 
 type bookingUsecase struct {
-	repo domain.BookingRepository
+	repo   domain.BookingRepository
+	tutors domain.TutorRepository
+	events domain.EventPublisher
 }
 
-func NewBookingUsecase(repo domain.BookingRepository) domain.BookingUsecase {
-	return &bookingUsecase{repo: repo}
+func NewBookingUsecase(repo domain.BookingRepository, tutors domain.TutorRepository, events domain.EventPublisher) domain.BookingUsecase {
+	if events == nil {
+		events = domain.NopPublisher{}
+	}
+	return &bookingUsecase{repo: repo, tutors: tutors, events: events}
+}
+
+func (u *bookingUsecase) Create(ctx context.Context, b *domain.Booking) (*domain.Booking, error) {
+	if err := normalizePhone(&b.PhoneNumber); err != nil {
+		return nil, err
+	}
+	b.Language = normalizeLanguage(b.Language)
+	// A parent who submits the form twice shouldn't end up with two open
+	// requests; once the first one is assigned they may book again.
+	// Quarantined bookings don't count, or spam could lock a parent out.
+	if b.PhoneNumber != "" && !b.Quarantined {
+		existing, err := u.repo.GetByPhoneNumber(ctx, b.PhoneNumber)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range existing {
+			if !other.Assigned && !other.Quarantined {
+				return nil, domain.ErrDuplicateBooking
+			}
+		}
+	}
+	created, err := u.repo.Create(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	// Nobody is told about a quarantined booking until it is released
+	if !created.Quarantined {
+		u.publishCreated(created)
+	}
+	return created, nil
+}
+
+func (u *bookingUsecase) Release(ctx context.Context, id uint) error {
+	booking, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !booking.Quarantined {
+		return nil
+	}
+	if err := u.repo.Release(ctx, id); err != nil {
+		return err
+	}
+	booking.Quarantined = false
+	booking.QuarantineReason = ""
+	logging.FromContext(ctx).Info("booking released from quarantine", "booking_id", id)
+	u.publishCreated(booking)
+	return nil
 }
 
-func (u *bookingUsecase) Create(b *domain.Booking) (*domain.Booking, error) {
-	return u.repo.Create(b)
+func (u *bookingUsecase) publishCreated(b *domain.Booking) {
+	u.events.Publish(domain.Event{
+		Type:    domain.EventBookingCreated,
+		Subject: bookingContact(b),
+		Data:    map[string]any{"Booking": b},
+	})
 }
 
-func (u *bookingUsecase) GetAll(filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
-	return u.repo.GetAll(filter)
+func (u *bookingUsecase) GetAll(ctx context.Context, filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
+	return u.repo.GetAll(ctx, filter)
 }
 
-func (u *bookingUsecase) GetByID(id uint) (*domain.Booking, error) {
-	return u.repo.GetByID(id)
+func (u *bookingUsecase) GetByID(ctx context.Context, id uint) (*domain.Booking, error) {
+	return u.repo.GetByID(ctx, id)
 }
 
-func (u *bookingUsecase) Update(id uint, b *domain.Booking) (*domain.Booking, error) {
-	return u.repo.Update(id, b)
+func (u *bookingUsecase) Update(ctx context.Context, id uint, b *domain.Booking) (*domain.Booking, error) {
+	if err := normalizePhone(&b.PhoneNumber); err != nil {
+		return nil, err
+	}
+	b.Language = normalizeLanguage(b.Language)
+	return u.repo.Update(ctx, id, b)
 }
 
-func (u *bookingUsecase) Delete(id uint) error {
-	return u.repo.Delete(id)
+func (u *bookingUsecase) Delete(ctx context.Context, id uint) error {
+	return u.repo.Delete(ctx, id)
 }
 
-func (u *bookingUsecase) Assign(id uint) error {
-	booking, err := u.repo.GetByID(id)
+// Assign marks the booking as assigned. When tutorID is not zero the tutor
+// is recorded on the booking and their details are sent to the parent.
+func (u *bookingUsecase) Assign(ctx context.Context, id uint, tutorID uint) error {
+	booking, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
+	data := map[string]any{}
+	if tutorID != 0 {
+		tutor, err := u.tutors.GetByID(ctx, tutorID)
+		if err != nil {
+			return err
+		}
+		booking.TutorID = &tutor.ID
+		data["Tutor"] = tutor
+	}
 	booking.Assigned = true
-	_, err = u.repo.Update(id, booking)
-	return err
+	updated, err := u.repo.Update(ctx, id, booking)
+	if err != nil {
+		return err
+	}
+	data["Booking"] = updated
+	logging.FromContext(ctx).Info("booking assigned", "booking_id", id, "tutor_id", tutorID)
+	u.events.Publish(domain.Event{
+		Type:    domain.EventBookingAssigned,
+		Subject: bookingContact(updated),
+		Data:    data,
+	})
+	return nil
+}
+
+// Bulk saves the bookings in one transaction. Like Assign, the assign
+// action tells parents about their tutor, but only once every booking is
+// saved and only for those that weren't already assigned to them.
+func (u *bookingUsecase) Bulk(ctx context.Context, req *domain.BookingBulkRequest) (*domain.BulkResult, error) {
+	if req == nil {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := checkSelection(req.IDs, req.Filter != nil); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var tutor *domain.Tutor
+	var assigned []*domain.Booking
+	var fn domain.BulkFunc[domain.Booking]
+	switch req.Action {
+	case domain.BulkAssign:
+		if req.TutorID != 0 {
+			var err error
+			if tutor, err = u.tutors.GetByID(ctx, req.TutorID); err != nil {
+				return nil, err
+			}
+		}
+		fn = func(b *domain.Booking) (bool, error) {
+			if b.DeletedAt != nil {
+				return false, domain.ErrInTrash
+			}
+			changed := markAssigned(b, true, now)
+			if tutor != nil && (b.TutorID == nil || *b.TutorID != tutor.ID) {
+				b.TutorID = &tutor.ID
+				changed = true
+			}
+			if changed {
+				assigned = append(assigned, b)
+			}
+			return changed, nil
+		}
+	case domain.BulkStatus:
+		if req.Status != domain.BookingStatusPending && req.Status != domain.BookingStatusAssigned {
+			return nil, domain.ErrInvalidStatus
+		}
+		fn = func(b *domain.Booking) (bool, error) {
+			if b.DeletedAt != nil {
+				return false, domain.ErrInTrash
+			}
+			return markAssigned(b, req.Status == domain.BookingStatusAssigned, now), nil
+		}
+	case domain.BulkDelete:
+		fn = func(b *domain.Booking) (bool, error) { return trash(&b.Model, now), nil }
+	case domain.BulkRestore:
+		fn = func(b *domain.Booking) (bool, error) { return restore(&b.Model), nil }
+	default:
+		return nil, domain.ErrUnknownAction
+	}
+	result, err := u.repo.Bulk(ctx, req.IDs, req.Filter, fn)
+	if err != nil {
+		return nil, err
+	}
+	result.Action = req.Action
+	logBulk(ctx, "bookings", result)
+	for _, b := range assigned {
+		data := map[string]any{"Booking": b}
+		if tutor != nil {
+			data["Tutor"] = tutor
+		}
+		u.events.Publish(domain.Event{
+			Type:    domain.EventBookingAssigned,
+			Subject: bookingContact(b),
+			Data:    data,
+		})
+	}
+	return result, nil
+}
+
+// markAssigned sets whether b is assigned, keeping AssignedAt in step as
+// Update does. Taking a booking back to pending also drops its tutor.
+func markAssigned(b *domain.Booking, assigned bool, now time.Time) bool {
+	if b.Assigned == assigned {
+		return false
+	}
+	b.Assigned = assigned
+	if assigned {
+		b.AssignedAt = &now
+	} else {
+		b.AssignedAt = nil
+		b.TutorID = nil
+	}
+	return true
+}
+
+func bookingContact(b *domain.Booking) *domain.Contact {
+	return &domain.Contact{
+		Name:     b.FirstName + " " + b.LastName,
+		Phone:    b.PhoneNumber,
+		Language: b.Language,
+	}
+}
+
+// normalizeLanguage reduces a language tag such as "am-ET" to its
+// lower-case primary subtag, the form templates are named by. Anything
+// that isn't a two or three letter code is dropped.
+func normalizeLanguage(tag string) string {
+	tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+	tag = strings.ToLower(tag)
+	if len(tag) < 2 || len(tag) > 3 || strings.IndexFunc(tag, func(r rune) bool { return r < 'a' || r > 'z' }) >= 0 {
+		return ""
+	}
+	return tag
+}
+
+// normalizePhone rewrites a non-empty number to E.164 in place.
+func normalizePhone(number *string) error {
+	if *number == "" {
+		return nil
+	}
+	normalized, err := phone.Normalize(*number)
+	if err != nil {
+		return domain.ErrInvalidPhoneNumber
+	}
+	*number = normalized
+	return nil
 }
@@ -1,7 +1,10 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -11,6 +14,16 @@ type BookingUsecaseTestSuite struct {
 	suite.Suite
 	usecase domain.BookingUsecase
 	repo    *mockBookingRepository
+	tutors  *mockTutorRepository
+	events  *recordingPublisher
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(e domain.Event) {
+	p.events = append(p.events, e)
 }
 
 type mockBookingRepository struct {
@@ -18,13 +31,13 @@ type mockBookingRepository struct {
 	lastID   uint
 }
 
-func (m *mockBookingRepository) Create(b *domain.Booking) (*domain.Booking, error) {
+func (m *mockBookingRepository) Create(_ context.Context, b *domain.Booking) (*domain.Booking, error) {
 	m.lastID++
 	b.ID = m.lastID
 	m.bookings[b.ID] = b
 	return b, nil
 }
-func (m *mockBookingRepository) GetAll(filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
+func (m *mockBookingRepository) GetAll(_ context.Context, filter *domain.BookingFilter) (domain.MultipleBookingResponse, error) {
 	var result []domain.Booking
 	for _, b := range m.bookings {
 		result = append(result, *b)
@@ -40,14 +53,35 @@ func (m *mockBookingRepository) GetAll(filter *domain.BookingFilter) (domain.Mul
 	}
 	return resp, nil
 }
-func (m *mockBookingRepository) GetByID(id uint) (*domain.Booking, error) {
+func (m *mockBookingRepository) CreateMany(ctx context.Context, bookings []*domain.Booking, check func(domain.BookingRepository, *domain.Booking) error) error {
+	for _, b := range bookings {
+		if check != nil {
+			if err := check(m, b); err != nil {
+				return err
+			}
+		}
+	}
+	for _, b := range bookings {
+		m.Create(ctx, b)
+	}
+	return nil
+}
+func (m *mockBookingRepository) Each(_ context.Context, filter *domain.BookingFilter, fn func(*domain.Booking) error) error {
+	for _, id := range slices.Sorted(maps.Keys(m.bookings)) {
+		if err := fn(m.bookings[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (m *mockBookingRepository) GetByID(_ context.Context, id uint) (*domain.Booking, error) {
 	b, ok := m.bookings[id]
 	if !ok {
 		return nil, domain.ErrNotFound
 	}
 	return b, nil
 }
-func (m *mockBookingRepository) Update(id uint, b *domain.Booking) (*domain.Booking, error) {
+func (m *mockBookingRepository) Update(_ context.Context, id uint, b *domain.Booking) (*domain.Booking, error) {
 	if _, ok := m.bookings[id]; !ok {
 		return nil, domain.ErrNotFound
 	}
@@ -55,7 +89,42 @@ func (m *mockBookingRepository) Update(id uint, b *domain.Booking) (*domain.Book
 	m.bookings[id] = b
 	return b, nil
 }
-func (m *mockBookingRepository) Delete(id uint) error {
+func (m *mockBookingRepository) GetByPhoneNumber(_ context.Context, phone string) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, b := range m.bookings {
+		if b.PhoneNumber == phone {
+			out = append(out, *b)
+		}
+	}
+	return out, nil
+}
+func (m *mockBookingRepository) Release(_ context.Context, id uint) error {
+	b, ok := m.bookings[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	b.Quarantined = false
+	b.QuarantineReason = ""
+	return nil
+}
+func (m *mockBookingRepository) Bulk(_ context.Context, ids []uint, filter *domain.BookingFilter, fn domain.BulkFunc[domain.Booking]) (*domain.BulkResult, error) {
+	if len(ids) == 0 {
+		ids = slices.Sorted(maps.Keys(m.bookings))
+	}
+	result := &domain.BulkResult{}
+	for _, id := range ids {
+		b, ok := m.bookings[id]
+		if !ok {
+			result.Add(id, false, domain.ErrNotFound)
+			continue
+		}
+		result.Matched++
+		changed, err := fn(b)
+		result.Add(id, changed, err)
+	}
+	return result, nil
+}
+func (m *mockBookingRepository) Delete(_ context.Context, id uint) error {
 	if _, ok := m.bookings[id]; !ok {
 		return domain.ErrNotFound
 	}
@@ -69,15 +138,17 @@ func TestBookingUsecase(t *testing.T) {
 
 func (s *BookingUsecaseTestSuite) SetupTest() {
 	s.repo = &mockBookingRepository{bookings: make(map[uint]*domain.Booking)}
-	s.usecase = NewBookingUsecase(s.repo)
+	s.events = &recordingPublisher{}
+	s.tutors = &mockTutorRepository{tutors: make(map[uint]*domain.Tutor)}
+	s.usecase = NewBookingUsecase(s.repo, s.tutors, s.events)
 }
 
 func (s *BookingUsecaseTestSuite) TestCreateAndGetByID() {
 	b := &domain.Booking{FirstName: "Test"}
-	created, err := s.usecase.Create(b)
+	created, err := s.usecase.Create(context.Background(), b)
 	s.NoError(err)
 	s.NotNil(created)
-	fetched, err := s.usecase.GetByID(created.ID)
+	fetched, err := s.usecase.GetByID(context.Background(), created.ID)
 	s.NoError(err)
 	s.Equal(created.FirstName, fetched.FirstName)
 }
@@ -85,37 +156,151 @@ func (s *BookingUsecaseTestSuite) TestCreateAndGetByID() {
 func (s *BookingUsecaseTestSuite) TestGetAll() {
 	b1 := &domain.Booking{FirstName: "A"}
 	b2 := &domain.Booking{FirstName: "B"}
-	s.usecase.Create(b1)
-	s.usecase.Create(b2)
-	resp, err := s.usecase.GetAll(&domain.BookingFilter{})
+	s.usecase.Create(context.Background(), b1)
+	s.usecase.Create(context.Background(), b2)
+	resp, err := s.usecase.GetAll(context.Background(), &domain.BookingFilter{})
 	s.NoError(err)
 	s.Len(resp.Data, 2)
 }
 
 func (s *BookingUsecaseTestSuite) TestUpdate() {
 	b := &domain.Booking{FirstName: "Old"}
-	created, _ := s.usecase.Create(b)
+	created, _ := s.usecase.Create(context.Background(), b)
 	updated := &domain.Booking{FirstName: "New"}
-	result, err := s.usecase.Update(created.ID, updated)
+	result, err := s.usecase.Update(context.Background(), created.ID, updated)
 	s.NoError(err)
 	s.Equal("New", result.FirstName)
 }
 
 func (s *BookingUsecaseTestSuite) TestDelete() {
 	b := &domain.Booking{FirstName: "ToDelete"}
-	created, _ := s.usecase.Create(b)
-	err := s.usecase.Delete(created.ID)
+	created, _ := s.usecase.Create(context.Background(), b)
+	err := s.usecase.Delete(context.Background(), created.ID)
 	s.NoError(err)
-	fetched, err := s.usecase.GetByID(created.ID)
+	fetched, err := s.usecase.GetByID(context.Background(), created.ID)
 	s.Error(err)
 	s.Nil(fetched)
 }
 
 func (s *BookingUsecaseTestSuite) TestAssign() {
 	b := &domain.Booking{FirstName: "AssignMe", Assigned: false}
-	created, _ := s.usecase.Create(b)
-	err := s.usecase.Assign(created.ID)
+	created, _ := s.usecase.Create(context.Background(), b)
+	err := s.usecase.Assign(context.Background(), created.ID, 0)
 	s.NoError(err)
-	fetched, _ := s.usecase.GetByID(created.ID)
+	fetched, _ := s.usecase.GetByID(context.Background(), created.ID)
 	s.True(fetched.Assigned)
+	s.Nil(fetched.TutorID)
+}
+
+func (s *BookingUsecaseTestSuite) TestAssignTutor() {
+	created, _ := s.usecase.Create(context.Background(), &domain.Booking{FirstName: "AssignMe"})
+	tutor, _ := s.tutors.Create(context.Background(), &domain.Tutor{FirstName: "Abebe", PhoneNumber: "+251911000000"})
+	s.NoError(s.usecase.Assign(context.Background(), created.ID, tutor.ID))
+	fetched, _ := s.usecase.GetByID(context.Background(), created.ID)
+	s.Require().NotNil(fetched.TutorID)
+	s.Equal(tutor.ID, *fetched.TutorID)
+	s.Equal(tutor, s.events.events[1].Data["Tutor"])
+
+	s.ErrorIs(s.usecase.Assign(context.Background(), created.ID, 999), domain.ErrNotFound)
+}
+
+func (s *BookingUsecaseTestSuite) TestPublishesEvents() {
+	created, _ := s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Notify", PhoneNumber: "0911000000", Language: "AM-et"})
+	s.NoError(s.usecase.Assign(context.Background(), created.ID, 0))
+	s.Require().Len(s.events.events, 2)
+	s.Equal(domain.EventBookingCreated, s.events.events[0].Type)
+	s.Equal(domain.EventBookingAssigned, s.events.events[1].Type)
+	s.Equal(created, s.events.events[1].Data["Booking"])
+	s.Equal("+251911000000", s.events.events[0].Subject.Phone)
+	s.Equal("am", s.events.events[0].Subject.Language)
+	s.Equal("am", s.events.events[1].Subject.Language)
+}
+
+func (s *BookingUsecaseTestSuite) TestDuplicatePhoneNumber() {
+	created, err := s.usecase.Create(context.Background(), &domain.Booking{FirstName: "First", PhoneNumber: "0911234567"})
+	s.NoError(err)
+	s.Equal("+251911234567", created.PhoneNumber)
+
+	_, err = s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Again", PhoneNumber: "+251 911 234 567"})
+	s.ErrorIs(err, domain.ErrDuplicateBooking)
+
+	// Once the first request is assigned the parent can book again
+	s.NoError(s.usecase.Assign(context.Background(), created.ID, 0))
+	_, err = s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Again", PhoneNumber: "+251 911 234 567"})
+	s.NoError(err)
+
+	_, err = s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Bad", PhoneNumber: "call me"})
+	s.ErrorIs(err, domain.ErrInvalidPhoneNumber)
+}
+
+func (s *BookingUsecaseTestSuite) TestQuarantine() {
+	held, err := s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Bot", PhoneNumber: "0911234567", Quarantined: true, QuarantineReason: "honeypot"})
+	s.NoError(err)
+	s.Empty(s.events.events)
+
+	// A quarantined booking doesn't block the real parent
+	_, err = s.usecase.Create(context.Background(), &domain.Booking{FirstName: "Real", PhoneNumber: "0911234567"})
+	s.NoError(err)
+	s.Len(s.events.events, 1)
+
+	s.NoError(s.usecase.Release(context.Background(), held.ID))
+	s.Len(s.events.events, 2)
+	fetched, _ := s.usecase.GetByID(context.Background(), held.ID)
+	s.False(fetched.Quarantined)
+
+	// Releasing twice doesn't notify again
+	s.NoError(s.usecase.Release(context.Background(), held.ID))
+	s.Len(s.events.events, 2)
+}
+
+func (s *BookingUsecaseTestSuite) TestBulk() {
+	ctx := context.Background()
+	open, _ := s.usecase.Create(ctx, &domain.Booking{FirstName: "Open"})
+	done, _ := s.usecase.Create(ctx, &domain.Booking{FirstName: "Done"})
+	s.NoError(s.usecase.Assign(ctx, done.ID, 0))
+	tutor, _ := s.tutors.Create(ctx, &domain.Tutor{FirstName: "Abebe"})
+	s.events.events = nil
+
+	result, err := s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkAssign, IDs: []uint{open.ID, 99}, TutorID: tutor.ID})
+	s.Require().NoError(err)
+	s.Equal(domain.BulkAssign, result.Action)
+	s.Equal(1, result.Updated)
+	s.Equal(1, result.Failed)
+	s.Equal(tutor.ID, *open.TutorID)
+	s.NotNil(open.AssignedAt)
+	// Parents hear about their tutor only for the bookings that changed
+	s.Require().Len(s.events.events, 1)
+	s.Equal(domain.EventBookingAssigned, s.events.events[0].Type)
+	s.Equal(tutor, s.events.events[0].Data["Tutor"])
+
+	result, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkStatus, Status: domain.BookingStatusPending, IDs: []uint{open.ID}})
+	s.Require().NoError(err)
+	s.Equal(1, result.Updated)
+	s.False(open.Assigned)
+	s.Nil(open.TutorID)
+	s.Nil(open.AssignedAt)
+
+	// Bookings in the trash can only be restored
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkDelete, Filter: &domain.BookingFilter{}})
+	s.Require().NoError(err)
+	s.NotNil(open.DeletedAt)
+	result, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkAssign, IDs: []uint{open.ID}})
+	s.Require().NoError(err)
+	s.Equal(domain.ErrInTrash.Error(), result.Items[0].Error)
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkRestore, IDs: []uint{open.ID, open.ID}})
+	s.Require().NoError(err)
+	s.Nil(open.DeletedAt)
+
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkVerify, IDs: []uint{open.ID}})
+	s.ErrorIs(err, domain.ErrUnknownAction)
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkStatus, Status: "done", IDs: []uint{open.ID}})
+	s.ErrorIs(err, domain.ErrInvalidStatus)
+	_, err = s.usecase.Bulk(ctx, nil)
+	s.ErrorIs(err, domain.ErrInvalidInput)
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkDelete})
+	s.ErrorIs(err, domain.ErrNoSelection)
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkDelete, IDs: []uint{open.ID}, Filter: &domain.BookingFilter{}})
+	s.ErrorIs(err, domain.ErrNoSelection)
+	_, err = s.usecase.Bulk(ctx, &domain.BookingBulkRequest{Action: domain.BulkAssign, IDs: []uint{open.ID}, TutorID: 99})
+	s.ErrorIs(err, domain.ErrNotFound)
 }
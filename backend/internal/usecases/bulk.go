@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"time"
+)
+
+// checkSelection makes sure a bulk request picks its records either by ID
+// or by filter, and not too many of them.
+func checkSelection(ids []uint, filtered bool) error {
+	if (len(ids) > 0) == filtered {
+		return domain.ErrNoSelection
+	}
+	if len(ids) > domain.MaxBulkItems {
+		return domain.ErrTooManyItems
+	}
+	return nil
+}
+
+// logBulk records the outcome of a bulk action on entity.
+func logBulk(ctx context.Context, entity string, r *domain.BulkResult) {
+	logging.FromContext(ctx).Info("bulk action applied", "entity", entity, "action", r.Action,
+		"matched", r.Matched, "updated", r.Updated, "unchanged", r.Unchanged, "failed", r.Failed)
+}
+
+// trash moves m to the trash, where the purge task deletes it for good
+// once it has been there long enough.
+func trash(m *domain.Model, now time.Time) bool {
+	if m.DeletedAt != nil {
+		return false
+	}
+	m.DeletedAt = &now
+	return true
+}
+
+// restore takes m back out of the trash.
+func restore(m *domain.Model) bool {
+	if m.DeletedAt == nil {
+		return false
+	}
+	m.DeletedAt = nil
+	return true
+}
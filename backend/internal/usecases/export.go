@@ -0,0 +1,265 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/export"
+	"hiyab-tutor/internal/logging"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ExportSettings say where asynchronous exports go and when an export is
+// large enough to need one.
+type ExportSettings struct {
+	// Dir holds the generated files; workers and the API must share it
+	Dir string
+	// SyncLimit is the most rows streamed straight to the client
+	SyncLimit int
+	// Retention is how long a generated file can be downloaded
+	Retention time.Duration
+}
+
+type exportUsecase struct {
+	repo     domain.ExportRepository
+	bookings domain.BookingRepository
+	tutors   domain.TutorRepository
+	jobs     domain.JobEnqueuer
+	settings ExportSettings
+	now      func() time.Time
+}
+
+func NewExportUsecase(repo domain.ExportRepository, bookings domain.BookingRepository, tutors domain.TutorRepository, jobs domain.JobEnqueuer, settings ExportSettings) domain.ExportUsecase {
+	return &exportUsecase{repo: repo, bookings: bookings, tutors: tutors, jobs: jobs, settings: settings, now: time.Now}
+}
+
+// check fills in the defaults of req and rejects what can't be exported.
+func (u *exportUsecase) check(req *domain.ExportRequest) error {
+	if req == nil {
+		return domain.ErrInvalidInput
+	}
+	if req.Format == "" {
+		req.Format = domain.FormatCSV
+	}
+	if req.Format != domain.FormatCSV && req.Format != domain.FormatXLSX {
+		return domain.ErrInvalidFormat
+	}
+	var err error
+	switch req.Entity {
+	case domain.ExportBookings:
+		if req.Bookings == nil {
+			req.Bookings = &domain.BookingFilter{}
+		}
+		_, err = export.Select(export.BookingColumns, req.Columns)
+	case domain.ExportTutors:
+		if req.Tutors == nil {
+			req.Tutors = &domain.TutorFilter{}
+		}
+		_, err = export.Select(export.TutorColumns, req.Columns)
+	default:
+		err = domain.ErrUnknownExport
+	}
+	return err
+}
+
+func (u *exportUsecase) Large(ctx context.Context, req *domain.ExportRequest) (bool, error) {
+	if err := u.check(req); err != nil {
+		return false, err
+	}
+	var total int
+	if req.Entity == domain.ExportBookings {
+		filter := *req.Bookings
+		filter.Page, filter.Limit = 1, 1
+		resp, err := u.bookings.GetAll(ctx, &filter)
+		if err != nil {
+			return false, err
+		}
+		total = resp.Pagination.Total
+	} else {
+		filter := *req.Tutors
+		filter.Page, filter.Limit = 1, 1
+		resp, err := u.tutors.GetAll(ctx, &filter)
+		if err != nil {
+			return false, err
+		}
+		total = resp.Pagination.Total
+	}
+	return total > u.settings.SyncLimit, nil
+}
+
+func (u *exportUsecase) Write(ctx context.Context, req *domain.ExportRequest, w io.Writer) error {
+	_, err := u.write(ctx, req, w)
+	return err
+}
+
+// write writes the header and the matching rows to w and counts the rows.
+func (u *exportUsecase) write(ctx context.Context, req *domain.ExportRequest, w io.Writer) (int, error) {
+	if err := u.check(req); err != nil {
+		return 0, err
+	}
+	sheet, err := export.NewWriter(req.Format, w)
+	if err != nil {
+		return 0, err
+	}
+	rows := 0
+	if req.Entity == domain.ExportBookings {
+		columns, _ := export.Select(export.BookingColumns, req.Columns)
+		err = writeRows(sheet, columns, &rows, func(fn func(*domain.Booking) error) error {
+			return u.bookings.Each(ctx, req.Bookings, fn)
+		})
+	} else {
+		columns, _ := export.Select(export.TutorColumns, req.Columns)
+		err = writeRows(sheet, columns, &rows, func(fn func(*domain.Tutor) error) error {
+			return u.tutors.Each(ctx, req.Tutors, fn)
+		})
+	}
+	if err != nil {
+		return rows, err
+	}
+	return rows, sheet.Close()
+}
+
+// writeRows writes the header then a row for every record each yields.
+func writeRows[T any](sheet export.Writer, columns []export.Column[T], rows *int, each func(func(*T) error) error) error {
+	header := make([]any, len(columns))
+	for i, name := range export.Names(columns) {
+		header[i] = name
+	}
+	if err := sheet.Write(header); err != nil {
+		return err
+	}
+	return each(func(v *T) error {
+		*rows++
+		return sheet.Write(export.Row(columns, v))
+	})
+}
+
+func (u *exportUsecase) Start(ctx context.Context, req *domain.ExportRequest) (*domain.Export, error) {
+	if err := u.check(req); err != nil {
+		return nil, err
+	}
+	var filter any = req.Bookings
+	if req.Entity == domain.ExportTutors {
+		filter = req.Tutors
+	}
+	b, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	e := &domain.Export{
+		Entity:      req.Entity,
+		Format:      req.Format,
+		Columns:     req.Columns,
+		Filter:      string(b),
+		Status:      domain.ExportStatusPending,
+		RequestedBy: req.RequestedBy,
+	}
+	if err := u.repo.Create(ctx, e); err != nil {
+		return nil, err
+	}
+	if _, err := u.jobs.Enqueue(ctx, domain.JobGenerateExport, domain.GenerateExportPayload{ExportID: e.ID}, time.Time{}); err != nil {
+		// Nothing would ever pick a pending export up without its job
+		e.Status, e.Error = domain.ExportStatusFailed, "not queued: "+err.Error()
+		if uerr := u.repo.Update(ctx, e); uerr != nil {
+			return nil, uerr
+		}
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("export queued", "export_id", e.ID, "entity", e.Entity)
+	return e, nil
+}
+
+func (u *exportUsecase) Generate(ctx context.Context, id uint) error {
+	e, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status == domain.ExportStatusReady {
+		return nil
+	}
+	e.Status = domain.ExportStatusRunning
+	if err := u.repo.Update(ctx, e); err != nil {
+		return err
+	}
+
+	rows, file, err := u.generate(ctx, e)
+	if err != nil {
+		// Kept failed for the requester to see; the job retries it
+		e.Status, e.Error = domain.ExportStatusFailed, err.Error()
+		if uerr := u.repo.Update(ctx, e); uerr != nil {
+			return uerr
+		}
+		return err
+	}
+	now := u.now()
+	expires := now.Add(u.settings.Retention)
+	e.Status, e.Error, e.Rows, e.File = domain.ExportStatusReady, "", rows, file
+	e.FinishedAt, e.ExpiresAt = &now, &expires
+	return u.repo.Update(ctx, e)
+}
+
+// generate writes e's file into the export directory and returns its name
+// there. The file only appears once complete.
+func (u *exportUsecase) generate(ctx context.Context, e *domain.Export) (int, string, error) {
+	req := &domain.ExportRequest{Entity: e.Entity, Format: e.Format, Columns: e.Columns}
+	var filter any = &req.Bookings
+	if e.Entity == domain.ExportTutors {
+		filter = &req.Tutors
+	}
+	if err := json.Unmarshal([]byte(e.Filter), filter); err != nil {
+		return 0, "", err
+	}
+	if err := os.MkdirAll(u.settings.Dir, 0o750); err != nil {
+		return 0, "", err
+	}
+	tmp, err := os.CreateTemp(u.settings.Dir, ".export-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+	rows, err := u.write(ctx, req, tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	name := fmt.Sprintf("%s-%d.%s", e.Entity, e.ID, e.Format)
+	if err := os.Rename(tmp.Name(), filepath.Join(u.settings.Dir, name)); err != nil {
+		return 0, "", err
+	}
+	return rows, name, nil
+}
+
+func (u *exportUsecase) GetByID(ctx context.Context, id uint) (*domain.Export, error) {
+	if id == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return u.repo.GetByID(ctx, id)
+}
+
+func (u *exportUsecase) Open(ctx context.Context, id uint) (*domain.Export, io.ReadCloser, error) {
+	e, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if e.Status != domain.ExportStatusReady {
+		return e, nil, domain.ErrExportNotReady
+	}
+	if e.ExpiresAt != nil && !u.now().Before(*e.ExpiresAt) {
+		return nil, nil, domain.ErrNotFound
+	}
+	f, err := os.Open(filepath.Join(u.settings.Dir, e.File))
+	if os.IsNotExist(err) {
+		// Cleaned up already
+		return nil, nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, f, nil
+}
@@ -0,0 +1,183 @@
+package usecases
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"hiyab-tutor/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type mockExportRepository struct {
+	exports map[uint]*domain.Export
+}
+
+func (m *mockExportRepository) Create(_ context.Context, e *domain.Export) error {
+	e.ID = uint(len(m.exports) + 1)
+	copied := *e
+	m.exports[e.ID] = &copied
+	return nil
+}
+
+func (m *mockExportRepository) Update(_ context.Context, e *domain.Export) error {
+	copied := *e
+	m.exports[e.ID] = &copied
+	return nil
+}
+
+func (m *mockExportRepository) GetByID(_ context.Context, id uint) (*domain.Export, error) {
+	e, ok := m.exports[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *e
+	return &copied, nil
+}
+
+type mockEnqueuer struct {
+	payloads []any
+	err      error
+}
+
+func (m *mockEnqueuer) Enqueue(_ context.Context, jobType string, payload any, _ time.Time) (*domain.Job, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.payloads = append(m.payloads, payload)
+	return &domain.Job{Type: jobType}, nil
+}
+
+type ExportUsecaseTestSuite struct {
+	suite.Suite
+	repo    *mockExportRepository
+	tutors  *mockTutorRepository
+	jobs    *mockEnqueuer
+	dir     string
+	now     time.Time
+	usecase *exportUsecase
+}
+
+func TestExportUsecase(t *testing.T) {
+	suite.Run(t, new(ExportUsecaseTestSuite))
+}
+
+func (s *ExportUsecaseTestSuite) SetupTest() {
+	s.repo = &mockExportRepository{exports: map[uint]*domain.Export{}}
+	s.tutors = &mockTutorRepository{tutors: map[uint]*domain.Tutor{}}
+	s.jobs = &mockEnqueuer{}
+	s.dir = s.T().TempDir()
+	bookings := &mockBookingRepository{bookings: map[uint]*domain.Booking{}}
+	s.usecase = NewExportUsecase(s.repo, bookings, s.tutors, s.jobs, ExportSettings{
+		Dir: s.dir, SyncLimit: 2, Retention: time.Hour,
+	}).(*exportUsecase)
+	s.now = time.Date(2025, 3, 19, 12, 0, 0, 0, time.UTC)
+	s.usecase.now = func() time.Time { return s.now }
+	for _, t := range []domain.Tutor{
+		{FirstName: "Abebe", Verified: true},
+		{FirstName: "Sara"},
+		{FirstName: "Hana", Verified: true},
+	} {
+		s.tutors.Create(context.Background(), &t)
+	}
+}
+
+func (s *ExportUsecaseTestSuite) TestLarge() {
+	ctx := context.Background()
+	large, err := s.usecase.Large(ctx, &domain.ExportRequest{Entity: domain.ExportTutors})
+	s.NoError(err)
+	s.True(large)
+
+	large, err = s.usecase.Large(ctx, &domain.ExportRequest{Entity: domain.ExportTutors, Tutors: &domain.TutorFilter{Verified: true}})
+	s.NoError(err)
+	s.False(large)
+
+	_, err = s.usecase.Large(ctx, &domain.ExportRequest{Entity: "admins"})
+	s.ErrorIs(err, domain.ErrUnknownExport)
+	_, err = s.usecase.Large(ctx, &domain.ExportRequest{Entity: domain.ExportTutors, Format: "pdf"})
+	s.ErrorIs(err, domain.ErrInvalidFormat)
+	_, err = s.usecase.Large(ctx, &domain.ExportRequest{Entity: domain.ExportBookings, Columns: []string{"email"}})
+	s.ErrorIs(err, domain.ErrUnknownColumn)
+}
+
+func (s *ExportUsecaseTestSuite) TestWrite() {
+	var b strings.Builder
+	err := s.usecase.Write(context.Background(), &domain.ExportRequest{
+		Entity:  domain.ExportTutors,
+		Columns: []string{"first_name", "id"},
+		Tutors:  &domain.TutorFilter{Verified: true},
+	}, &b)
+	s.NoError(err)
+	s.Equal("first_name,id\nAbebe,1\nHana,3\n", b.String())
+}
+
+func (s *ExportUsecaseTestSuite) TestStart_EnqueueFails() {
+	s.jobs.err = errors.New("queue down")
+	_, err := s.usecase.Start(context.Background(), &domain.ExportRequest{Entity: domain.ExportTutors})
+	s.ErrorContains(err, "queue down")
+
+	s.Require().Len(s.repo.exports, 1)
+	for _, e := range s.repo.exports {
+		s.Equal(domain.ExportStatusFailed, e.Status)
+		s.Equal("not queued: queue down", e.Error)
+	}
+}
+
+func (s *ExportUsecaseTestSuite) TestStartAndGenerate() {
+	ctx := context.Background()
+	e, err := s.usecase.Start(ctx, &domain.ExportRequest{
+		Entity:      domain.ExportTutors,
+		Columns:     []string{"id", "first_name"},
+		Tutors:      &domain.TutorFilter{Verified: true},
+		RequestedBy: 7,
+	})
+	s.NoError(err)
+	s.Equal(domain.ExportStatusPending, e.Status)
+	s.Equal([]any{domain.GenerateExportPayload{ExportID: e.ID}}, s.jobs.payloads)
+
+	_, _, err = s.usecase.Open(ctx, e.ID)
+	s.ErrorIs(err, domain.ErrExportNotReady)
+
+	s.NoError(s.usecase.Generate(ctx, e.ID))
+	e, f, err := s.usecase.Open(ctx, e.ID)
+	s.Require().NoError(err)
+	s.Equal(domain.ExportStatusReady, e.Status)
+	s.Equal(2, e.Rows)
+	s.Equal(s.now.Add(time.Hour), *e.ExpiresAt)
+	records, err := csv.NewReader(f).ReadAll()
+	s.NoError(err)
+	s.NoError(f.Close())
+	// The filter was kept with the export
+	s.Equal([][]string{{"id", "first_name"}, {"1", "Abebe"}, {"3", "Hana"}}, records)
+
+	// Only the finished file is left in the directory
+	entries, err := os.ReadDir(s.dir)
+	s.NoError(err)
+	s.Len(entries, 1)
+
+	s.now = s.now.Add(time.Hour)
+	_, _, err = s.usecase.Open(ctx, e.ID)
+	s.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (s *ExportUsecaseTestSuite) TestGenerateFailure() {
+	ctx := context.Background()
+	e, err := s.usecase.Start(ctx, &domain.ExportRequest{Entity: domain.ExportTutors})
+	s.NoError(err)
+	// A file where the directory should be
+	s.usecase.settings.Dir = filepath.Join(s.dir, "file")
+	s.NoError(os.WriteFile(s.usecase.settings.Dir, nil, 0o600))
+
+	s.Error(s.usecase.Generate(ctx, e.ID))
+	e, err = s.usecase.GetByID(ctx, e.ID)
+	s.NoError(err)
+	s.Equal(domain.ExportStatusFailed, e.Status)
+	s.NotEmpty(e.Error)
+	_, _, err = s.usecase.Open(ctx, e.ID)
+	s.ErrorIs(err, domain.ErrExportNotReady)
+}
@@ -0,0 +1,282 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/export"
+	"hiyab-tutor/internal/importer"
+	"hiyab-tutor/internal/logging"
+	"io"
+	"net/mail"
+	"strings"
+)
+
+// maxImportRows caps the rows of one import, which is checked and saved
+// in a single request
+const maxImportRows = 5000
+
+type importUsecase struct {
+	bookings domain.BookingRepository
+	tutors   domain.TutorRepository
+}
+
+// NewImportUsecase adds bookings and tutors in bulk. Unlike the public
+// forms, imports publish no events, so admins aren't sent a notification
+// per row.
+func NewImportUsecase(bookings domain.BookingRepository, tutors domain.TutorRepository) domain.ImportUsecase {
+	return &importUsecase{bookings: bookings, tutors: tutors}
+}
+
+func (u *importUsecase) Import(ctx context.Context, req *domain.ImportRequest) (*domain.ImportReport, error) {
+	if req == nil || req.File == nil {
+		return nil, domain.ErrInvalidInput
+	}
+	if req.Format == "" {
+		req.Format = domain.FormatCSV
+	}
+	switch req.Entity {
+	case domain.ExportBookings:
+		// The same phone number may not be waiting twice
+		phones := map[string]int{}
+		return importSheet(ctx, req, importer.BookingFields, export.Names(export.BookingColumns),
+			func(row int, b *domain.Booking) ([]domain.ImportFieldError, error) {
+				errs := checkBooking(b)
+				if hasError(errs, "phone_number") {
+					return errs, nil
+				}
+				dup, err := u.bookingDuplicate(ctx, phones, row, b.PhoneNumber)
+				return append(errs, dup...), err
+			}, func(ctx context.Context, bookings []*domain.Booking) error {
+				// Checked again as they are saved, in case a parent booked
+				// in the meantime
+				return u.bookings.CreateMany(ctx, bookings, func(repo domain.BookingRepository, b *domain.Booking) error {
+					return bookingTaken(ctx, repo, b.PhoneNumber)
+				})
+			})
+	case domain.ExportTutors:
+		phones := map[string]int{}
+		return importSheet(ctx, req, importer.TutorFields, export.Names(export.TutorColumns),
+			func(row int, t *domain.Tutor) ([]domain.ImportFieldError, error) {
+				errs := checkTutor(t)
+				if hasError(errs, "phone_number") {
+					return errs, nil
+				}
+				dup, err := u.tutorDuplicate(ctx, phones, row, t.PhoneNumber)
+				return append(errs, dup...), err
+			}, func(ctx context.Context, tutors []*domain.Tutor) error {
+				return u.tutors.CreateMany(ctx, tutors, func(repo domain.TutorRepository, t *domain.Tutor) error {
+					return tutorTaken(ctx, repo, t.PhoneNumber)
+				})
+			})
+	}
+	return nil, domain.ErrUnknownExport
+}
+
+// importSheet reads req's sheet into Ts, checking each with check, and
+// saves them with create unless it is a dry run or a row is invalid.
+func importSheet[T any](ctx context.Context, req *domain.ImportRequest, fields []importer.Field[T], ignored []string,
+	check func(row int, v *T) ([]domain.ImportFieldError, error), create func(context.Context, []*T) error) (*domain.ImportReport, error) {
+	r, err := importer.NewReader(req.Format, req.File)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, domain.ErrEmptyImport
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns, err := importer.Header(fields, header, ignored)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{Entity: req.Entity, DryRun: req.DryRun, Errors: []domain.ImportRowError{}}
+	var records []*T
+	for line := 2; ; line++ {
+		cells, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(cells) {
+			continue
+		}
+		if report.Rows++; report.Rows > maxImportRows {
+			return nil, fmt.Errorf("%w, the most is %d", domain.ErrTooManyRows, maxImportRows)
+		}
+
+		v := new(T)
+		var errs []domain.ImportFieldError
+		filled := map[string]bool{}
+		for i, f := range columns {
+			if f == nil || i >= len(cells) || strings.TrimSpace(cells[i]) == "" {
+				continue
+			}
+			filled[f.Name] = true
+			if err := f.Set(v, strings.TrimSpace(cells[i])); err != nil {
+				errs = append(errs, domain.ImportFieldError{Column: f.Name, Message: err.Error()})
+			}
+		}
+		for _, f := range fields {
+			if f.Required && !filled[f.Name] {
+				errs = append(errs, domain.ImportFieldError{Column: f.Name, Message: "is required"})
+			}
+		}
+		if len(errs) == 0 {
+			if errs, err = check(line, v); err != nil {
+				return nil, err
+			}
+		}
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, domain.ImportRowError{Row: line, Errors: errs})
+			continue
+		}
+		records = append(records, v)
+	}
+	report.Valid = len(records)
+	if report.Rows == 0 {
+		return nil, domain.ErrEmptyImport
+	}
+	if len(report.Errors) > 0 {
+		if req.DryRun {
+			return report, nil
+		}
+		return report, domain.ErrImportRejected
+	}
+	if req.DryRun {
+		return report, nil
+	}
+	if err := create(ctx, records); err != nil {
+		return nil, err
+	}
+	report.Imported = len(records)
+	logging.FromContext(ctx).Info("import saved", "entity", req.Entity, "rows", report.Imported)
+	return report, nil
+}
+
+// checkBooking normalizes b's phone number and checks its ranges.
+func checkBooking(b *domain.Booking) []domain.ImportFieldError {
+	var errs []domain.ImportFieldError
+	if b.Grade < domain.MinGrade || b.Grade > domain.MaxGrade {
+		errs = append(errs, domain.ImportFieldError{Column: "grade", Message: fmt.Sprintf("must be between %d and %d", domain.MinGrade, domain.MaxGrade)})
+	}
+	if b.Age < 0 {
+		errs = append(errs, domain.ImportFieldError{Column: "age", Message: "must not be negative"})
+	}
+	errs = append(errs, checkSchedule(b.DayPerWeek, b.HrPerDay)...)
+	if err := normalizePhone(&b.PhoneNumber); err != nil {
+		errs = append(errs, domain.ImportFieldError{Column: "phone_number", Message: err.Error()})
+	}
+	return errs
+}
+
+// checkTutor normalizes t's phone number and checks its email and ranges.
+func checkTutor(t *domain.Tutor) []domain.ImportFieldError {
+	var errs []domain.ImportFieldError
+	if addr, err := mail.ParseAddress(t.Email); err != nil || addr.Address != t.Email {
+		errs = append(errs, domain.ImportFieldError{Column: "email", Message: "invalid email address"})
+	}
+	errs = append(errs, checkSchedule(t.DayPerWeek, t.HrPerDay)...)
+	if err := normalizePhone(&t.PhoneNumber); err != nil {
+		errs = append(errs, domain.ImportFieldError{Column: "phone_number", Message: err.Error()})
+	}
+	return errs
+}
+
+func checkSchedule(dayPerWeek, hrPerDay int) []domain.ImportFieldError {
+	var errs []domain.ImportFieldError
+	if dayPerWeek < 0 || dayPerWeek > 7 {
+		errs = append(errs, domain.ImportFieldError{Column: "day_per_week", Message: "must be between 0 and 7"})
+	}
+	if hrPerDay < 0 || hrPerDay > 24 {
+		errs = append(errs, domain.ImportFieldError{Column: "hr_per_day", Message: "must be between 0 and 24"})
+	}
+	return errs
+}
+
+// bookingDuplicate applies the rule Create does: one open booking per
+// phone number, counting the rows above.
+func (u *importUsecase) bookingDuplicate(ctx context.Context, phones map[string]int, row int, phone string) ([]domain.ImportFieldError, error) {
+	if first, ok := phones[phone]; ok {
+		return []domain.ImportFieldError{{Column: "phone_number", Message: fmt.Sprintf("same phone number as row %d", first)}}, nil
+	}
+	phones[phone] = row
+	if err := bookingTaken(ctx, u.bookings, phone); err != nil {
+		if errors.Is(err, domain.ErrDuplicateBooking) {
+			return []domain.ImportFieldError{{Column: "phone_number", Message: err.Error()}}, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// bookingTaken returns ErrDuplicateBooking when a booking stored in repo
+// is still open for phone.
+func bookingTaken(ctx context.Context, repo domain.BookingRepository, phone string) error {
+	existing, err := repo.GetByPhoneNumber(ctx, phone)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if !other.Assigned && !other.Quarantined {
+			return domain.ErrDuplicateBooking
+		}
+	}
+	return nil
+}
+
+// tutorDuplicate applies the rule registration does: one tutor per phone
+// number, counting the rows above.
+func (u *importUsecase) tutorDuplicate(ctx context.Context, phones map[string]int, row int, phone string) ([]domain.ImportFieldError, error) {
+	if first, ok := phones[phone]; ok {
+		return []domain.ImportFieldError{{Column: "phone_number", Message: fmt.Sprintf("same phone number as row %d", first)}}, nil
+	}
+	phones[phone] = row
+	if err := tutorTaken(ctx, u.tutors, phone); err != nil {
+		if errors.Is(err, domain.ErrDuplicateTutor) {
+			return []domain.ImportFieldError{{Column: "phone_number", Message: err.Error()}}, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// tutorTaken returns ErrDuplicateTutor when a tutor stored in repo has
+// registered with phone.
+func tutorTaken(ctx context.Context, repo domain.TutorRepository, phone string) error {
+	existing, err := repo.GetByPhoneNumber(ctx, phone)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if !other.Quarantined {
+			return domain.ErrDuplicateTutor
+		}
+	}
+	return nil
+}
+
+func hasError(errs []domain.ImportFieldError, column string) bool {
+	for _, e := range errs {
+		if e.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+func blank(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
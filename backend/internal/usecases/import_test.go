@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ImportUsecaseTestSuite struct {
+	suite.Suite
+	bookings *mockBookingRepository
+	tutors   *mockTutorRepository
+	usecase  domain.ImportUsecase
+}
+
+func TestImportUsecase(t *testing.T) {
+	suite.Run(t, new(ImportUsecaseTestSuite))
+}
+
+func (s *ImportUsecaseTestSuite) SetupTest() {
+	s.bookings = &mockBookingRepository{bookings: map[uint]*domain.Booking{}}
+	s.tutors = &mockTutorRepository{tutors: map[uint]*domain.Tutor{}}
+	s.usecase = NewImportUsecase(s.bookings, s.tutors)
+}
+
+func (s *ImportUsecaseTestSuite) importCSV(entity, sheet string, dryRun bool) (*domain.ImportReport, error) {
+	return s.usecase.Import(context.Background(), &domain.ImportRequest{
+		Entity: entity, Format: domain.FormatCSV, File: strings.NewReader(sheet), DryRun: dryRun,
+	})
+}
+
+func (s *ImportUsecaseTestSuite) TestBookingsDryRun() {
+	s.bookings.Create(context.Background(), &domain.Booking{FirstName: "Open", PhoneNumber: "+251933333333"})
+	sheet := "id,first_name,grade,phone_number,day_per_week\n" +
+		"1,Abebe,5,0911234567,3\n" +
+		",,,,\n" +
+		"2,Sara,13,0944 44 44 44,9\n" +
+		"3,Hana,x,12345,\n" +
+		"4,Kebede,8,+251 911 234 567,\n" +
+		"5,,8,0922222222\n" +
+		"6,Lulit,2,0933333333\n"
+	report, err := s.importCSV(domain.ExportBookings, sheet, true)
+	s.NoError(err)
+	s.True(report.DryRun)
+	s.Equal(6, report.Rows)
+	s.Equal(1, report.Valid)
+	s.Zero(report.Imported)
+	s.Equal([]domain.ImportRowError{
+		{Row: 4, Errors: []domain.ImportFieldError{
+			{Column: "grade", Message: "must be between 1 and 12"},
+			{Column: "day_per_week", Message: "must be between 0 and 7"},
+		}},
+		{Row: 5, Errors: []domain.ImportFieldError{{Column: "grade", Message: "must be a whole number"}}},
+		{Row: 6, Errors: []domain.ImportFieldError{{Column: "phone_number", Message: "same phone number as row 2"}}},
+		{Row: 7, Errors: []domain.ImportFieldError{{Column: "first_name", Message: "is required"}}},
+		{Row: 8, Errors: []domain.ImportFieldError{{Column: "phone_number", Message: domain.ErrDuplicateBooking.Error()}}},
+	}, report.Errors)
+	// Nothing saved
+	s.Len(s.bookings.bookings, 1)
+
+	// Committing the same sheet is refused as a whole
+	report, err = s.importCSV(domain.ExportBookings, sheet, false)
+	s.ErrorIs(err, domain.ErrImportRejected)
+	s.Len(report.Errors, 5)
+	s.Len(s.bookings.bookings, 1)
+}
+
+// racingBookingRepository books the phone number of the first row right
+// before the import saves its rows.
+type racingBookingRepository struct {
+	*mockBookingRepository
+}
+
+func (r racingBookingRepository) CreateMany(ctx context.Context, bookings []*domain.Booking, check func(domain.BookingRepository, *domain.Booking) error) error {
+	r.Create(ctx, &domain.Booking{FirstName: "Late", PhoneNumber: bookings[0].PhoneNumber})
+	return r.mockBookingRepository.CreateMany(ctx, bookings, check)
+}
+
+func (s *ImportUsecaseTestSuite) TestBookingsCheckedOnSave() {
+	usecase := NewImportUsecase(racingBookingRepository{s.bookings}, s.tutors)
+	_, err := usecase.Import(context.Background(), &domain.ImportRequest{
+		Entity: domain.ExportBookings, Format: domain.FormatCSV, File: strings.NewReader("first_name,grade,phone_number\nAbebe,5,0911234567\n"),
+	})
+	s.ErrorIs(err, domain.ErrDuplicateBooking)
+	// Only the late booking is there
+	s.Len(s.bookings.bookings, 1)
+}
+
+func (s *ImportUsecaseTestSuite) TestTutorsCommit() {
+	sheet := "First Name,Phone Number,Email,Education Level,Hr Per Day\n" +
+		"Abebe,0911234567,abebe@example.com,BSc,2\n" +
+		"Sara,0922222222,sara@example.com,MSc,\n"
+	report, err := s.importCSV(domain.ExportTutors, sheet, false)
+	s.NoError(err)
+	s.Equal(2, report.Imported)
+	s.Len(s.tutors.tutors, 2)
+	s.Equal("+251911234567", s.tutors.tutors[1].PhoneNumber)
+	s.Equal(2, s.tutors.tutors[1].HrPerDay)
+
+	// Now they are registered
+	report, err = s.importCSV(domain.ExportTutors, sheet, true)
+	s.NoError(err)
+	s.Equal(0, report.Valid)
+	s.Equal(domain.ErrDuplicateTutor.Error(), report.Errors[1].Errors[0].Message)
+}
+
+func (s *ImportUsecaseTestSuite) TestTutorEmail() {
+	report, err := s.importCSV(domain.ExportTutors, "first_name,phone_number,email,education_level\nAbebe,0911234567,Abebe <abebe@example.com>,BSc\n", true)
+	s.NoError(err)
+	s.Equal([]domain.ImportFieldError{{Column: "email", Message: "invalid email address"}}, report.Errors[0].Errors)
+}
+
+func (s *ImportUsecaseTestSuite) TestBadSheets() {
+	_, err := s.importCSV(domain.ExportBookings, "", true)
+	s.ErrorIs(err, domain.ErrEmptyImport)
+	_, err = s.importCSV(domain.ExportBookings, "first_name,grade,phone_number\n", true)
+	s.ErrorIs(err, domain.ErrEmptyImport)
+	_, err = s.importCSV(domain.ExportBookings, "first_name,grade\nAbebe,5\n", true)
+	s.ErrorIs(err, domain.ErrMissingColumn)
+	_, err = s.importCSV(domain.ExportTutors, "first_name,phone_number,email,education_level,password\n", true)
+	s.ErrorIs(err, domain.ErrUnknownColumn)
+	_, err = s.importCSV("admins", "first_name\n", true)
+	s.ErrorIs(err, domain.ErrUnknownExport)
+
+	var sheet strings.Builder
+	sheet.WriteString("first_name,grade,phone_number\n")
+	for i := 0; i <= maxImportRows; i++ {
+		sheet.WriteString("A,5,0911234567\n")
+	}
+	_, err = s.importCSV(domain.ExportBookings, sheet.String(), true)
+	s.ErrorIs(err, domain.ErrTooManyRows)
+}
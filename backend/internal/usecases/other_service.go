@@ -1,44 +1,42 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
-	"hiyab-tutor/internal/repository"
-
-	"gorm.io/gorm"
 )
 
 type otherServiceService struct {
 	repo domain.OtherServiceRepository
 }
 
-func NewOtherServiceService(db *gorm.DB) domain.OtherServiceUsecase {
+func NewOtherServiceService(repo domain.OtherServiceRepository) domain.OtherServiceUsecase {
 	return &otherServiceService{
-		repo: repository.NewServiceRepository(db),
+		repo: repo,
 	}
 }
 
-func (s *otherServiceService) CreateService(service *domain.OtherService) (*domain.OtherService, error) {
-	return s.repo.Create(service)
+func (s *otherServiceService) CreateService(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	return s.repo.Create(ctx, service)
 }
-func (s *otherServiceService) GetAllServices(filter *domain.ServiceFilter) (*domain.MultipleOtherServices, error) {
-	return s.repo.GetAll(filter)
+func (s *otherServiceService) GetAllServices(ctx context.Context, filter *domain.ServiceFilter) (*domain.MultipleOtherServices, error) {
+	return s.repo.GetAll(ctx, filter)
 }
-func (s *otherServiceService) GetServiceByID(id uint, languageCodes []string) (*domain.OtherService, error) {
-	return s.repo.GetByID(id, languageCodes)
+func (s *otherServiceService) GetServiceByID(ctx context.Context, id uint, languageCodes []string) (*domain.OtherService, error) {
+	return s.repo.GetByID(ctx, id, languageCodes)
 }
-func (s *otherServiceService) DeleteService(id uint) error {
-	return s.repo.Delete(id)
+func (s *otherServiceService) DeleteService(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
 }
-func (s *otherServiceService) UpdateService(service *domain.OtherService) (*domain.OtherService, error) {
-	return s.repo.Update(service)
+func (s *otherServiceService) UpdateService(ctx context.Context, service *domain.OtherService) (*domain.OtherService, error) {
+	return s.repo.Update(ctx, service)
 }
-func (s *otherServiceService) AddTranslation(serviceID uint, translation *domain.OtherServiceTranslation) (*domain.OtherService, error) {
-	service, err := s.repo.GetByID(serviceID, nil)
+func (s *otherServiceService) AddTranslation(ctx context.Context, serviceID uint, translation *domain.OtherServiceTranslation) (*domain.OtherService, error) {
+	service, err := s.repo.GetByID(ctx, serviceID, nil)
 	if err != nil {
 		return nil, err
 	}
 	translation.ServiceID = service.ID
-	if err = s.repo.AddTranslation(translation); err != nil {
+	if err = s.repo.AddTranslation(ctx, translation); err != nil {
 		return nil, err
 	}
 	service.Translations = append(service.Translations, *translation)
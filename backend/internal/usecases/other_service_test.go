@@ -1,23 +1,24 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
 	"testing"
 )
 
 func TestAddOtherServiceTranslation(t *testing.T) {
 	mockRepo := &domain.OtherServiceRepositoryMock{
-		UpdateFunc: func(service *domain.OtherService) (*domain.OtherService, error) {
+		UpdateFunc: func(_ context.Context, service *domain.OtherService) (*domain.OtherService, error) {
 			return service, nil
 		},
-		GetByIDFunc: func(id uint, languageCodes []string) (*domain.OtherService, error) {
+		GetByIDFunc: func(_ context.Context, id uint, languageCodes []string) (*domain.OtherService, error) {
 			return &domain.OtherService{
 				Model: domain.Model{
 					ID: id,
 				},
 			}, nil
 		},
-		AddTranslationFunc: func(translation *domain.OtherServiceTranslation) error { return nil },
+		AddTranslationFunc: func(_ context.Context, translation *domain.OtherServiceTranslation) error { return nil },
 	}
 	service := &otherServiceService{repo: mockRepo}
 	translation := &domain.OtherServiceTranslation{
@@ -27,7 +28,7 @@ func TestAddOtherServiceTranslation(t *testing.T) {
 		TagLine:      "Slogan de test",
 	}
 	serviceID := uint(1)
-	updatedService, err := service.AddTranslation(serviceID, translation)
+	updatedService, err := service.AddTranslation(context.Background(), serviceID, translation)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
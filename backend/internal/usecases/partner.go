@@ -1,33 +1,31 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
-	"hiyab-tutor/internal/repository"
-
-	"gorm.io/gorm"
 )
 
 type partnerUsecase struct {
 	repo domain.PartnerRepository
 }
 
-func NewPartnerUsecase(db *gorm.DB) domain.PartnerUsecase {
+func NewPartnerUsecase(repo domain.PartnerRepository) domain.PartnerUsecase {
 	return &partnerUsecase{
-		repo: repository.NewPartnerRepository(db),
+		repo: repo,
 	}
 }
-func (u *partnerUsecase) CreatePartner(partner *domain.Partner) (*domain.Partner, error) {
-	return u.repo.Create(partner)
+func (u *partnerUsecase) CreatePartner(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	return u.repo.Create(ctx, partner)
 }
-func (u *partnerUsecase) GetAllPartners(filter *domain.PartnerFilter) (*domain.MultiplePartners, error) {
-	return u.repo.GetAll(filter)
+func (u *partnerUsecase) GetAllPartners(ctx context.Context, filter *domain.PartnerFilter) (*domain.MultiplePartners, error) {
+	return u.repo.GetAll(ctx, filter)
 }
-func (u *partnerUsecase) GetPartnerByID(id uint) (*domain.Partner, error) {
-	return u.repo.GetByID(id)
+func (u *partnerUsecase) GetPartnerByID(ctx context.Context, id uint) (*domain.Partner, error) {
+	return u.repo.GetByID(ctx, id)
 }
-func (u *partnerUsecase) DeletePartner(id uint) error {
-	return u.repo.Delete(id)
+func (u *partnerUsecase) DeletePartner(ctx context.Context, id uint) error {
+	return u.repo.Delete(ctx, id)
 }
-func (u *partnerUsecase) UpdatePartner(partner *domain.Partner) (*domain.Partner, error) {
-	return u.repo.Update(partner)
+func (u *partnerUsecase) UpdatePartner(ctx context.Context, partner *domain.Partner) (*domain.Partner, error) {
+	return u.repo.Update(ctx, partner)
 }
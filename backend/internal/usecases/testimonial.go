@@ -1,44 +1,42 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
-	"hiyab-tutor/internal/repository"
-
-	"gorm.io/gorm"
 )
 
 type testimonialService struct {
 	repo domain.TestimonialRepository
 }
 
-func NewTestimonialService(db *gorm.DB) domain.TestimonialUsecase {
+func NewTestimonialService(repo domain.TestimonialRepository) domain.TestimonialUsecase {
 	return &testimonialService{
-		repo: repository.NewTestimonialRepository(db),
+		repo: repo,
 	}
 }
 
-func (s *testimonialService) CreateTestimonial(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
-	return s.repo.Create(testimonial)
+func (s *testimonialService) CreateTestimonial(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+	return s.repo.Create(ctx, testimonial)
 }
-func (s *testimonialService) GetAllTestimonials(filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
-	return s.repo.GetAll(filter)
+func (s *testimonialService) GetAllTestimonials(ctx context.Context, filter *domain.TestimonialFilter) (*domain.MultipleTestimonialResponse, error) {
+	return s.repo.GetAll(ctx, filter)
 }
-func (s *testimonialService) GetTestimonialByID(id uint) (*domain.Testimonial, error) {
-	return s.repo.GetByID(id, nil)
+func (s *testimonialService) GetTestimonialByID(ctx context.Context, id uint) (*domain.Testimonial, error) {
+	return s.repo.GetByID(ctx, id, nil)
 }
-func (s *testimonialService) DeleteTestimonial(id uint) error {
-	return s.repo.Delete(id)
+func (s *testimonialService) DeleteTestimonial(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
 }
-func (s *testimonialService) UpdateTestimonial(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
-	return s.repo.Update(testimonial)
+func (s *testimonialService) UpdateTestimonial(ctx context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+	return s.repo.Update(ctx, testimonial)
 }
-func (s *testimonialService) AddTranslation(testimonialID uint, translation *domain.TestimonialTranslation) (*domain.Testimonial, error) {
-	testimonial, err := s.repo.GetByID(testimonialID, nil)
+func (s *testimonialService) AddTranslation(ctx context.Context, testimonialID uint, translation *domain.TestimonialTranslation) (*domain.Testimonial, error) {
+	testimonial, err := s.repo.GetByID(ctx, testimonialID, nil)
 	if err != nil {
 		return nil, err
 	}
 	translation.TestimonialID = testimonial.ID
-	err = s.repo.AddTranslation(translation)
+	err = s.repo.AddTranslation(ctx, translation)
 	if err != nil {
 		return nil, err
 	}
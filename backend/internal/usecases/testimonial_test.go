@@ -1,23 +1,24 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
 	"testing"
 )
 
 func TestAddTestimonialTranslation(t *testing.T) {
 	mockRepo := &domain.TestimonialRepositoryMock{
-		UpdateFunc: func(testimonial *domain.Testimonial) (*domain.Testimonial, error) {
+		UpdateFunc: func(_ context.Context, testimonial *domain.Testimonial) (*domain.Testimonial, error) {
 			return testimonial, nil
 		},
-		GetByIDFunc: func(id uint, languageCodes []string) (*domain.Testimonial, error) {
+		GetByIDFunc: func(_ context.Context, id uint, languageCodes []string) (*domain.Testimonial, error) {
 			return &domain.Testimonial{
 				Model: domain.Model{
 					ID: id,
 				},
 			}, nil
 		},
-		AddTranslationFunc: func(translation *domain.TestimonialTranslation) error { return nil },
+		AddTranslationFunc: func(_ context.Context, translation *domain.TestimonialTranslation) error { return nil },
 	}
 	service := &testimonialService{repo: mockRepo}
 	translation := &domain.TestimonialTranslation{
@@ -25,7 +26,7 @@ func TestAddTestimonialTranslation(t *testing.T) {
 		Text:         "Ceci est un témoignage.",
 	}
 	testimonialID := uint(1)
-	updatedTestimonial, err := service.AddTranslation(testimonialID, translation)
+	updatedTestimonial, err := service.AddTranslation(context.Background(), testimonialID, translation)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
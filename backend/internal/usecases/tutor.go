@@ -1,62 +1,206 @@
 package usecases
 
-import "hiyab-tutor/internal/domain"
+import (
+	"context"
+	"hiyab-tutor/internal/domain"
+	"hiyab-tutor/internal/logging"
+	"time"
+)
 
 type tutorUsecase struct {
-	repo domain.TutorRepository
+	repo   domain.TutorRepository
+	events domain.EventPublisher
 }
 
-func NewTutorUsecase(repo domain.TutorRepository) domain.TutorUsecase {
-	return &tutorUsecase{repo: repo}
+func NewTutorUsecase(repo domain.TutorRepository, events domain.EventPublisher) domain.TutorUsecase {
+	if events == nil {
+		events = domain.NopPublisher{}
+	}
+	return &tutorUsecase{repo: repo, events: events}
 }
 
-func (u *tutorUsecase) Create(t *domain.Tutor) (*domain.Tutor, error) {
+func (u *tutorUsecase) Create(ctx context.Context, t *domain.Tutor) (*domain.Tutor, error) {
 	if t == nil {
 		return nil, domain.ErrInvalidInput
 	}
 	if t.FirstName == "" || t.EducationLevel == "" || t.Email == "" {
 		return nil, domain.ErrInvalidInput
 	}
-	return u.repo.Create(t)
+	if err := u.checkPhone(ctx, 0, t); err != nil {
+		return nil, err
+	}
+	t.Language = normalizeLanguage(t.Language)
+	created, err := u.repo.Create(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	// Nobody is told about a quarantined registration until it is released
+	if !created.Quarantined {
+		u.publishRegistered(created)
+	}
+	return created, nil
 }
 
-func (u *tutorUsecase) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
+func (u *tutorUsecase) Release(ctx context.Context, id uint) error {
+	if id == 0 {
+		return domain.ErrInvalidInput
+	}
+	tutor, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !tutor.Quarantined {
+		return nil
+	}
+	if err := u.repo.Release(ctx, id); err != nil {
+		return err
+	}
+	tutor.Quarantined = false
+	tutor.QuarantineReason = ""
+	logging.FromContext(ctx).Info("tutor released from quarantine", "tutor_id", id)
+	u.publishRegistered(tutor)
+	return nil
+}
+
+func (u *tutorUsecase) publishRegistered(t *domain.Tutor) {
+	u.events.Publish(domain.Event{
+		Type: domain.EventTutorRegistered,
+		Data: map[string]any{"Tutor": t},
+	})
+}
+
+func (u *tutorUsecase) GetAll(ctx context.Context, filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
 	if filter == nil {
 		filter = &domain.TutorFilter{}
 	}
-	return u.repo.GetAll(filter)
+	return u.repo.GetAll(ctx, filter)
 }
 
-func (u *tutorUsecase) GetByID(id uint) (*domain.Tutor, error) {
+func (u *tutorUsecase) GetByID(ctx context.Context, id uint) (*domain.Tutor, error) {
 	if id == 0 {
 		return nil, domain.ErrInvalidInput
 	}
-	return u.repo.GetByID(id)
+	return u.repo.GetByID(ctx, id)
 }
 
-func (u *tutorUsecase) Update(id uint, t *domain.Tutor) (*domain.Tutor, error) {
+func (u *tutorUsecase) Update(ctx context.Context, id uint, t *domain.Tutor) (*domain.Tutor, error) {
 	if id == 0 || t == nil {
 		return nil, domain.ErrInvalidInput
 	}
-	return u.repo.Update(id, t)
+	if err := u.checkPhone(ctx, id, t); err != nil {
+		return nil, err
+	}
+	t.Language = normalizeLanguage(t.Language)
+	return u.repo.Update(ctx, id, t)
+}
+
+// checkPhone normalizes the tutor's number and makes sure no other tutor
+// (id being the tutor itself on update) registered with it. Quarantined
+// registrations are left out on both sides so spam can't lock anyone out.
+func (u *tutorUsecase) checkPhone(ctx context.Context, id uint, t *domain.Tutor) error {
+	if err := normalizePhone(&t.PhoneNumber); err != nil || t.PhoneNumber == "" || t.Quarantined {
+		return err
+	}
+	existing, err := u.repo.GetByPhoneNumber(ctx, t.PhoneNumber)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID != id && !other.Quarantined {
+			return domain.ErrDuplicateTutor
+		}
+	}
+	return nil
 }
 
-func (u *tutorUsecase) Delete(id uint) error {
+func (u *tutorUsecase) Delete(ctx context.Context, id uint) error {
 	if id == 0 {
 		return domain.ErrInvalidInput
 	}
-	return u.repo.Delete(id)
+	return u.repo.Delete(ctx, id)
 }
 
-func (u *tutorUsecase) Verify(id uint) error {
+func (u *tutorUsecase) Verify(ctx context.Context, id uint) error {
 	if id == 0 {
 		return domain.ErrInvalidInput
 	}
-	tutor, err := u.repo.GetByID(id)
+	tutor, err := u.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	tutor.Verified = true
-	_, err = u.repo.Update(id, tutor)
-	return err
+	updated, err := u.repo.Update(ctx, id, tutor)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("tutor verified", "tutor_id", id)
+	u.events.Publish(domain.Event{
+		Type:    domain.EventTutorVerified,
+		Subject: tutorContact(updated),
+		Data:    map[string]any{"Tutor": updated},
+	})
+	return nil
+}
+
+// Bulk saves the tutors in one transaction. Like Verify, the verify action
+// tells tutors they were verified, but only once every tutor is saved and
+// only those that weren't verified already.
+func (u *tutorUsecase) Bulk(ctx context.Context, req *domain.TutorBulkRequest) (*domain.BulkResult, error) {
+	if req == nil {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := checkSelection(req.IDs, req.Filter != nil); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var verified []*domain.Tutor
+	var fn domain.BulkFunc[domain.Tutor]
+	switch req.Action {
+	case domain.BulkVerify, domain.BulkUnverify:
+		verify := req.Action == domain.BulkVerify
+		fn = func(t *domain.Tutor) (bool, error) {
+			if t.DeletedAt != nil {
+				return false, domain.ErrInTrash
+			}
+			if t.Verified == verify {
+				return false, nil
+			}
+			t.Verified = verify
+			t.VerifiedAt = nil
+			if verify {
+				t.VerifiedAt = &now
+				verified = append(verified, t)
+			}
+			return true, nil
+		}
+	case domain.BulkDelete:
+		fn = func(t *domain.Tutor) (bool, error) { return trash(&t.Model, now), nil }
+	case domain.BulkRestore:
+		fn = func(t *domain.Tutor) (bool, error) { return restore(&t.Model), nil }
+	default:
+		return nil, domain.ErrUnknownAction
+	}
+	result, err := u.repo.Bulk(ctx, req.IDs, req.Filter, fn)
+	if err != nil {
+		return nil, err
+	}
+	result.Action = req.Action
+	logBulk(ctx, "tutors", result)
+	for _, t := range verified {
+		u.events.Publish(domain.Event{
+			Type:    domain.EventTutorVerified,
+			Subject: tutorContact(t),
+			Data:    map[string]any{"Tutor": t},
+		})
+	}
+	return result, nil
+}
+
+func tutorContact(t *domain.Tutor) *domain.Contact {
+	return &domain.Contact{
+		Name:     t.FirstName + " " + t.LastName,
+		Email:    t.Email,
+		Phone:    t.PhoneNumber,
+		Language: t.Language,
+	}
 }
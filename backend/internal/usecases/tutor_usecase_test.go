@@ -1,7 +1,10 @@
 package usecases
 
 import (
+	"context"
 	"hiyab-tutor/internal/domain"
+	"maps"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -18,7 +21,7 @@ type mockTutorRepository struct {
 	lastID uint
 }
 
-func (m *mockTutorRepository) Create(t *domain.Tutor) (*domain.Tutor, error) {
+func (m *mockTutorRepository) Create(_ context.Context, t *domain.Tutor) (*domain.Tutor, error) {
 	if t == nil {
 		return nil, domain.ErrInvalidInput
 	}
@@ -27,7 +30,7 @@ func (m *mockTutorRepository) Create(t *domain.Tutor) (*domain.Tutor, error) {
 	m.tutors[t.ID] = t
 	return t, nil
 }
-func (m *mockTutorRepository) GetAll(filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
+func (m *mockTutorRepository) GetAll(_ context.Context, filter *domain.TutorFilter) (domain.MultipleTutorResponse, error) {
 	var result []domain.Tutor
 	for _, t := range m.tutors {
 		if filter != nil {
@@ -48,14 +51,39 @@ func (m *mockTutorRepository) GetAll(filter *domain.TutorFilter) (domain.Multipl
 		Pagination: domain.Pagination{Total: len(result)},
 	}, nil
 }
-func (m *mockTutorRepository) GetByID(id uint) (*domain.Tutor, error) {
+func (m *mockTutorRepository) CreateMany(ctx context.Context, tutors []*domain.Tutor, check func(domain.TutorRepository, *domain.Tutor) error) error {
+	for _, t := range tutors {
+		if check != nil {
+			if err := check(m, t); err != nil {
+				return err
+			}
+		}
+	}
+	for _, t := range tutors {
+		m.Create(ctx, t)
+	}
+	return nil
+}
+func (m *mockTutorRepository) Each(_ context.Context, filter *domain.TutorFilter, fn func(*domain.Tutor) error) error {
+	for _, id := range slices.Sorted(maps.Keys(m.tutors)) {
+		t := m.tutors[id]
+		if filter != nil && filter.Verified && !t.Verified {
+			continue
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (m *mockTutorRepository) GetByID(_ context.Context, id uint) (*domain.Tutor, error) {
 	t, ok := m.tutors[id]
 	if !ok {
 		return nil, domain.ErrNotFound
 	}
 	return t, nil
 }
-func (m *mockTutorRepository) Update(id uint, t *domain.Tutor) (*domain.Tutor, error) {
+func (m *mockTutorRepository) Update(_ context.Context, id uint, t *domain.Tutor) (*domain.Tutor, error) {
 	if _, ok := m.tutors[id]; !ok {
 		return nil, domain.ErrNotFound
 	}
@@ -63,13 +91,48 @@ func (m *mockTutorRepository) Update(id uint, t *domain.Tutor) (*domain.Tutor, e
 	m.tutors[id] = t
 	return t, nil
 }
-func (m *mockTutorRepository) Delete(id uint) error {
+func (m *mockTutorRepository) Bulk(_ context.Context, ids []uint, filter *domain.TutorFilter, fn domain.BulkFunc[domain.Tutor]) (*domain.BulkResult, error) {
+	if len(ids) == 0 {
+		ids = slices.Sorted(maps.Keys(m.tutors))
+	}
+	result := &domain.BulkResult{}
+	for _, id := range ids {
+		t, ok := m.tutors[id]
+		if !ok {
+			result.Add(id, false, domain.ErrNotFound)
+			continue
+		}
+		result.Matched++
+		changed, err := fn(t)
+		result.Add(id, changed, err)
+	}
+	return result, nil
+}
+func (m *mockTutorRepository) Delete(_ context.Context, id uint) error {
 	if _, ok := m.tutors[id]; !ok {
 		return domain.ErrNotFound
 	}
 	delete(m.tutors, id)
 	return nil
 }
+func (m *mockTutorRepository) GetByPhoneNumber(_ context.Context, phone string) ([]domain.Tutor, error) {
+	var out []domain.Tutor
+	for _, t := range m.tutors {
+		if t.PhoneNumber == phone {
+			out = append(out, *t)
+		}
+	}
+	return out, nil
+}
+func (m *mockTutorRepository) Release(_ context.Context, id uint) error {
+	t, ok := m.tutors[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	t.Quarantined = false
+	t.QuarantineReason = ""
+	return nil
+}
 func (m *mockTutorRepository) Verify(id uint) error {
 	t, ok := m.tutors[id]
 	if !ok {
@@ -85,21 +148,21 @@ func TestTutorUsecase(t *testing.T) {
 
 func (s *TutorUsecaseTestSuite) SetupTest() {
 	s.repo = &mockTutorRepository{tutors: make(map[uint]*domain.Tutor)}
-	s.usecase = NewTutorUsecase(s.repo)
+	s.usecase = NewTutorUsecase(s.repo, nil)
 }
 
 func (s *TutorUsecaseTestSuite) TestCreate_Valid() {
 	t := &domain.Tutor{FirstName: "Test Tutor", EducationLevel: "Degree", Email: "test@example.com"}
-	created, err := s.usecase.Create(t)
+	created, err := s.usecase.Create(context.Background(), t)
 	s.NoError(err)
 	s.NotNil(created)
 	s.Equal("Test Tutor", created.FirstName)
 }
 
 func (s *TutorUsecaseTestSuite) TestCreate_Invalid() {
-	_, err := s.usecase.Create(nil)
+	_, err := s.usecase.Create(context.Background(), nil)
 	s.Error(err)
-	_, err = s.usecase.Create(&domain.Tutor{FirstName: "", EducationLevel: "", Email: ""})
+	_, err = s.usecase.Create(context.Background(), &domain.Tutor{FirstName: "", EducationLevel: "", Email: ""})
 	s.Error(err)
 }
 
@@ -107,53 +170,111 @@ func (s *TutorUsecaseTestSuite) TestGetAll_Filtered() {
 	t1 := &domain.Tutor{FirstName: "Alice", EducationLevel: "Degree", Verified: true, Email: "alice@example.com"}
 	t2 := &domain.Tutor{FirstName: "Bob", EducationLevel: "Diploma", Verified: false, Email: "bob@example.com"}
 	t3 := &domain.Tutor{FirstName: "Charlie", EducationLevel: "Degree", Verified: true, Email: "charlie@example.com"}
-	s.usecase.Create(t1)
-	s.usecase.Create(t2)
-	s.usecase.Create(t3)
+	s.usecase.Create(context.Background(), t1)
+	s.usecase.Create(context.Background(), t2)
+	s.usecase.Create(context.Background(), t3)
 	filter := &domain.TutorFilter{EducationLevel: "Degree", Verified: true}
-	resp, err := s.usecase.GetAll(filter)
+	resp, err := s.usecase.GetAll(context.Background(), filter)
 	s.NoError(err)
 	s.Len(resp.Data, 2)
 }
 
 func (s *TutorUsecaseTestSuite) TestGetByID_ValidAndInvalid() {
 	t := &domain.Tutor{FirstName: "Test Tutor", EducationLevel: "Degree", Email: "test@example.com"}
-	created, _ := s.usecase.Create(t)
-	fetched, err := s.usecase.GetByID(created.ID)
+	created, _ := s.usecase.Create(context.Background(), t)
+	fetched, err := s.usecase.GetByID(context.Background(), created.ID)
 	s.NoError(err)
 	s.Equal(created.FirstName, fetched.FirstName)
-	fetched, err = s.usecase.GetByID(999)
+	fetched, err = s.usecase.GetByID(context.Background(), 999)
 	s.Error(err)
 	s.Nil(fetched)
 }
 
 func (s *TutorUsecaseTestSuite) TestUpdate_ValidAndInvalid() {
 	t := &domain.Tutor{FirstName: "Old Name", EducationLevel: "Diploma", Email: "old@example.com"}
-	created, _ := s.usecase.Create(t)
+	created, _ := s.usecase.Create(context.Background(), t)
 	updated := &domain.Tutor{FirstName: "New Name", EducationLevel: "Degree", Email: "new@example.com"}
-	result, err := s.usecase.Update(created.ID, updated)
+	result, err := s.usecase.Update(context.Background(), created.ID, updated)
 	s.NoError(err)
 	s.Equal("New Name", result.FirstName)
-	_, err = s.usecase.Update(999, updated)
+	_, err = s.usecase.Update(context.Background(), 999, updated)
 	s.Error(err)
 }
 
 func (s *TutorUsecaseTestSuite) TestDelete_ValidAndInvalid() {
 	t := &domain.Tutor{FirstName: "ToDelete", EducationLevel: "Degree", Email: "delete@example.com"}
-	created, _ := s.usecase.Create(t)
-	err := s.usecase.Delete(created.ID)
+	created, _ := s.usecase.Create(context.Background(), t)
+	err := s.usecase.Delete(context.Background(), created.ID)
 	s.NoError(err)
-	err = s.usecase.Delete(999)
+	err = s.usecase.Delete(context.Background(), 999)
 	s.Error(err)
 }
 
 func (s *TutorUsecaseTestSuite) TestVerify_ValidAndInvalid() {
 	t := &domain.Tutor{FirstName: "VerifyMe", EducationLevel: "Degree", Verified: false, Email: "verify@example.com"}
-	created, _ := s.usecase.Create(t)
-	err := s.usecase.Verify(created.ID)
+	created, _ := s.usecase.Create(context.Background(), t)
+	err := s.usecase.Verify(context.Background(), created.ID)
 	s.NoError(err)
-	fetched, _ := s.usecase.GetByID(created.ID)
+	fetched, _ := s.usecase.GetByID(context.Background(), created.ID)
 	s.True(fetched.Verified)
-	err = s.usecase.Verify(999)
+	err = s.usecase.Verify(context.Background(), 999)
 	s.Error(err)
 }
+
+func (s *TutorUsecaseTestSuite) TestPhoneNormalizationAndDuplicates() {
+	t := &domain.Tutor{FirstName: "Abebe", EducationLevel: "Degree", Email: "abebe@example.com", PhoneNumber: "0911 23 45 67"}
+	created, err := s.usecase.Create(context.Background(), t)
+	s.NoError(err)
+	s.Equal("+251911234567", created.PhoneNumber)
+
+	// Same number written differently
+	_, err = s.usecase.Create(context.Background(), &domain.Tutor{FirstName: "Other", EducationLevel: "Degree", Email: "other@example.com", PhoneNumber: "251911234567"})
+	s.ErrorIs(err, domain.ErrDuplicateTutor)
+
+	_, err = s.usecase.Create(context.Background(), &domain.Tutor{FirstName: "Other", EducationLevel: "Degree", Email: "other@example.com", PhoneNumber: "12345"})
+	s.ErrorIs(err, domain.ErrInvalidPhoneNumber)
+
+	// Keeping your own number on update is fine
+	updated, err := s.usecase.Update(context.Background(), created.ID, &domain.Tutor{FirstName: "Abebe", EducationLevel: "Degree", Email: "abebe@example.com", PhoneNumber: "+251 911 234 567"})
+	s.NoError(err)
+	s.Equal("+251911234567", updated.PhoneNumber)
+}
+
+func (s *TutorUsecaseTestSuite) TestBulk() {
+	ctx := context.Background()
+	events := &recordingPublisher{}
+	s.usecase = NewTutorUsecase(s.repo, events)
+	a, _ := s.usecase.Create(ctx, &domain.Tutor{FirstName: "A", EducationLevel: "Degree", Email: "a@example.com", Language: "am"})
+	b, _ := s.usecase.Create(ctx, &domain.Tutor{FirstName: "B", EducationLevel: "Degree", Email: "b@example.com", Verified: true})
+	events.events = nil
+
+	result, err := s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkVerify, IDs: []uint{a.ID, b.ID}})
+	s.Require().NoError(err)
+	s.Equal(1, result.Updated)
+	s.Equal(1, result.Unchanged)
+	s.Equal(domain.BulkItemUnchanged, result.Items[1].Status)
+	s.True(a.Verified)
+	s.NotNil(a.VerifiedAt)
+	s.Require().Len(events.events, 1)
+	s.Equal(domain.EventTutorVerified, events.events[0].Type)
+	s.Equal("am", events.events[0].Subject.Language)
+
+	result, err = s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkUnverify, Filter: &domain.TutorFilter{}})
+	s.Require().NoError(err)
+	s.Equal(2, result.Updated)
+	s.False(b.Verified)
+	s.Nil(b.VerifiedAt)
+
+	_, err = s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkDelete, IDs: []uint{a.ID}})
+	s.Require().NoError(err)
+	s.NotNil(a.DeletedAt)
+	result, err = s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkVerify, IDs: []uint{a.ID}})
+	s.Require().NoError(err)
+	s.Equal(1, result.Failed)
+	s.False(a.Verified)
+
+	_, err = s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkAssign, IDs: []uint{a.ID}})
+	s.ErrorIs(err, domain.ErrUnknownAction)
+	_, err = s.usecase.Bulk(ctx, &domain.TutorBulkRequest{Action: domain.BulkVerify, IDs: make([]uint, domain.MaxBulkItems+1)})
+	s.ErrorIs(err, domain.ErrTooManyItems)
+}